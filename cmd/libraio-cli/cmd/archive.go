@@ -21,7 +21,8 @@ Archiving a category moves all its items to the archive.
 Examples:
   libraio-cli archive S01.11.15    # Archive single item
   libraio-cli archive S01.11       # Archive all items in category`,
-	Args: cobra.ExactArgs(1),
+	Args:        cobra.ExactArgs(1),
+	Annotations: mutating,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		id := args[0]
 		ctx := context.Background()
@@ -35,7 +36,7 @@ Examples:
 			if err != nil {
 				return err
 			}
-			fmt.Println(result.Message)
+			return printOperation(result.Message, result.Report)
 
 		case application.IDTypeCategory:
 			archiveCmd := commands.NewArchiveCategoryCommand(GetRepo(), id)
@@ -43,13 +44,11 @@ Examples:
 			if err != nil {
 				return err
 			}
-			fmt.Println(result.Message)
+			return printOperation(result.Message, result.Report)
 
 		default:
 			return fmt.Errorf("can only archive items or categories, got: %s", idType)
 		}
-
-		return nil
 	},
 }
 
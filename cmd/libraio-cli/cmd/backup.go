@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"libraio/internal/application/commands"
+)
+
+var backupCmd = &cobra.Command{
+	Use:   "backup [list|restore]",
+	Short: "Manage snapshots taken before bulk operations",
+	Long: `List or restore snapshots taken before bulk operations.
+
+With --backup (or LIBRAIO_BACKUP=1), moving or archiving a category and
+deleting an entity first saves the affected subtree, plus every markdown
+file whose links point into it, as a compressed tarball; fixing broken
+links first saves the files it rewrites. Old snapshots are
+pruned automatically: by default the 20 most recent from the last 30 days
+are kept (see LIBRAIO_BACKUP_KEEP and LIBRAIO_BACKUP_MAX_AGE).
+
+Restoring moves the entities the operation moved back to where they were,
+then writes the files back to their original locations, overwriting current
+versions. It refuses when a moved entity has been moved again since.
+
+Examples:
+  libraio-cli backup list
+  libraio-cli backup restore 20250102T150405-archive-S01.11`,
+}
+
+var backupListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List snapshots",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		snapshots, err := commands.NewListBackupsCommand(GetRepo()).Execute(ctx)
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			return printJSON(snapshots)
+		}
+
+		if len(snapshots) == 0 {
+			fmt.Println("No snapshots")
+			return nil
+		}
+		for _, s := range snapshots {
+			fmt.Printf("%s  %d files, %s\n", s.ID, len(s.Files), formatSize(s.Size))
+		}
+		return nil
+	},
+}
+
+var backupRestoreCmd = &cobra.Command{
+	Use:         "restore <snapshot-id>",
+	Short:       "Restore the files in a snapshot",
+	Args:        cobra.ExactArgs(1),
+	Annotations: mutating,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		result, err := commands.NewRestoreBackupCommand(GetRepo(), args[0]).Execute(ctx)
+		if err != nil {
+			return err
+		}
+		return printOperation(result.Message, result.Report)
+	},
+}
+
+// formatSize renders a byte count in human-readable units
+func formatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func init() {
+	backupCmd.AddCommand(backupListCmd)
+	backupCmd.AddCommand(backupRestoreCmd)
+	rootCmd.AddCommand(backupCmd)
+}
@@ -24,7 +24,8 @@ Examples:
   libraio-cli create S01 "New Area"
   libraio-cli create S01.10-19 "New Category"
   libraio-cli create S01.11 "New Item"`,
-	Args: cobra.ExactArgs(2),
+	Args:        cobra.ExactArgs(2),
+	Annotations: mutating,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		parentID := args[0]
 		description := args[1]
@@ -40,6 +41,7 @@ Examples:
 				return err
 			}
 			fmt.Println(result.Message)
+			printReport(result.Report)
 
 		case application.IDTypeArea:
 			createCmd := commands.NewCreateCategoryCommand(GetRepo(), parentID, description)
@@ -48,6 +50,7 @@ Examples:
 				return err
 			}
 			fmt.Println(result.Message)
+			printReport(result.Report)
 
 		case application.IDTypeCategory:
 			createCmd := commands.NewCreateItemCommand(GetRepo(), parentID, description)
@@ -56,6 +59,7 @@ Examples:
 				return err
 			}
 			fmt.Println(result.Message)
+			printReport(result.Report)
 
 		default:
 			return fmt.Errorf("invalid parent type: %s (expected scope, area, or category)", parentType)
@@ -73,7 +77,8 @@ var createScopeCmd = &cobra.Command{
 Examples:
   libraio-cli create scope "Me"
   libraio-cli create scope "Work"`,
-	Args: cobra.ExactArgs(1),
+	Args:        cobra.ExactArgs(1),
+	Annotations: mutating,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		description := args[0]
 		ctx := context.Background()
@@ -84,6 +89,7 @@ Examples:
 			return err
 		}
 		fmt.Println(result.Message)
+		printReport(result.Report)
 		return nil
 	},
 }
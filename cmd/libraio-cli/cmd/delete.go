@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/spf13/cobra"
 
@@ -11,16 +10,17 @@ import (
 
 var deleteCmd = &cobra.Command{
 	Use:   "delete <id>",
-	Short: "Delete an entity",
+	Short: "Move an entity to the trash",
 	Long: `Delete an item, category, area, or scope from the vault.
 
-Warning: This operation cannot be undone. Deleting a container
-(scope, area, or category) will also delete all its contents.
+Deleted entities are moved to the vault trash together with all their
+contents, and can be brought back with "libraio-cli trash restore".
 
 Examples:
   libraio-cli delete S01.11.15    # Delete item
   libraio-cli delete S01.11       # Delete category and all items`,
-	Args: cobra.ExactArgs(1),
+	Args:        cobra.ExactArgs(1),
+	Annotations: mutating,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		id := args[0]
 		ctx := context.Background()
@@ -30,8 +30,7 @@ Examples:
 		if err != nil {
 			return err
 		}
-		fmt.Println(result.Message)
-		return nil
+		return printOperation(result.Message, result.Report)
 	},
 }
 
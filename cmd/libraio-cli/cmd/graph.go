@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"libraio/internal/adapters/filesystem"
+	"libraio/internal/application/commands"
+	"libraio/internal/domain"
+)
+
+var (
+	graphFormat    string
+	graphLevel     string
+	graphScope     string
+	graphMinWeight int
+)
+
+var graphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Export the link graph between JD entities",
+	Long: `Export the links between the scopes, areas, categories and items of the
+vault, as recorded in the index. Each edge counts the links from notes in one
+entity to another; links within an entity are left out.
+
+--level collapses entities to their category, area or scope. --scope keeps
+only links with both ends inside a JD ID, and --min-weight drops edges with
+fewer links.
+
+Formats:
+  dot      Graphviz DOT
+  graphml  GraphML, for Gephi, yEd or Cytoscape
+  json     {"nodes": [...], "edges": [...]}
+
+Examples:
+  libraio-cli graph | dot -Tsvg > graph.svg
+  libraio-cli graph --level category --format graphml > graph.graphml
+  libraio-cli graph --scope S01 --min-weight 2 --format json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		level, err := domain.ParseGraphLevel(graphLevel)
+		if err != nil {
+			return err
+		}
+		format := graphFormat
+		if jsonOutput {
+			format = "json"
+		}
+		if format != "dot" && format != "graphml" && format != "json" {
+			return fmt.Errorf("unknown format %q: want dot, graphml or json", format)
+		}
+
+		index, err := openIndex()
+		if err != nil {
+			return err
+		}
+		defer index.Close()
+		repo := filesystem.NewRepository(vaultPath, append(repoOptions(), filesystem.WithIndex(index))...)
+
+		opts := domain.GraphOptions{Level: level, Scope: graphScope, MinWeight: graphMinWeight}
+		graph, err := commands.NewLinkGraphCommand(repo, opts).Execute(context.Background())
+		if err != nil {
+			return err
+		}
+
+		switch format {
+		case "json":
+			return printJSON(graph)
+		case "graphml":
+			return writeGraphML(os.Stdout, graph)
+		default:
+			return writeDOT(os.Stdout, graph)
+		}
+	},
+}
+
+// writeDOT writes the graph in Graphviz DOT, labelling edges with their weight.
+// Each node's type is its class, for styling with a stylesheet.
+func writeDOT(w io.Writer, graph *domain.LinkGraph) error {
+	fmt.Fprintln(w, "digraph libraio {")
+	for _, node := range graph.Nodes {
+		fmt.Fprintf(w, "  %s [label=%s, class=%s];\n", dotQuote(node.ID), dotQuote(node.Label()), dotQuote(node.Type))
+	}
+	for _, edge := range graph.Edges {
+		fmt.Fprintf(w, "  %s -> %s [weight=%d, label=\"%d\"];\n", dotQuote(edge.Source), dotQuote(edge.Target), edge.Weight, edge.Weight)
+	}
+	_, err := fmt.Fprintln(w, "}")
+	return err
+}
+
+// dotEscaper escapes the characters DOT interprets in a quoted string; any
+// other text, UTF-8 included, is written as is
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// dotQuote returns s as a DOT quoted string
+func dotQuote(s string) string {
+	return `"` + dotEscaper.Replace(s) + `"`
+}
+
+// GraphML document structure; node and edge data refer to the keys by ID
+type (
+	graphML struct {
+		XMLName xml.Name     `xml:"graphml"`
+		Xmlns   string       `xml:"xmlns,attr"`
+		Keys    []graphMLKey `xml:"key"`
+		Graph   graphMLGraph `xml:"graph"`
+	}
+	graphMLKey struct {
+		ID   string `xml:"id,attr"`
+		For  string `xml:"for,attr"`
+		Name string `xml:"attr.name,attr"`
+		Type string `xml:"attr.type,attr"`
+	}
+	graphMLGraph struct {
+		ID          string        `xml:"id,attr"`
+		EdgeDefault string        `xml:"edgedefault,attr"`
+		Nodes       []graphMLNode `xml:"node"`
+		Edges       []graphMLEdge `xml:"edge"`
+	}
+	graphMLNode struct {
+		ID   string        `xml:"id,attr"`
+		Data []graphMLData `xml:"data"`
+	}
+	graphMLEdge struct {
+		Source string        `xml:"source,attr"`
+		Target string        `xml:"target,attr"`
+		Data   []graphMLData `xml:"data"`
+	}
+	graphMLData struct {
+		Key   string `xml:"key,attr"`
+		Value string `xml:",chardata"`
+	}
+)
+
+// writeGraphML writes the graph as a GraphML document with name, type and
+// weight attributes
+func writeGraphML(w io.Writer, graph *domain.LinkGraph) error {
+	doc := graphML{
+		Xmlns: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "name", For: "node", Name: "name", Type: "string"},
+			{ID: "type", For: "node", Name: "type", Type: "string"},
+			{ID: "weight", For: "edge", Name: "weight", Type: "int"},
+		},
+		Graph: graphMLGraph{ID: "libraio", EdgeDefault: "directed"},
+	}
+	for _, node := range graph.Nodes {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{ID: node.ID, Data: []graphMLData{
+			{Key: "name", Value: node.Label()},
+			{Key: "type", Value: node.Type},
+		}})
+	}
+	for _, edge := range graph.Edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{Source: edge.Source, Target: edge.Target, Data: []graphMLData{
+			{Key: "weight", Value: strconv.Itoa(edge.Weight)},
+		}})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(w)
+	return err
+}
+
+func init() {
+	graphCmd.Flags().StringVar(&graphFormat, "format", "dot", "output format: dot, graphml or json")
+	graphCmd.Flags().StringVar(&graphLevel, "level", "item", "collapse entities to: item, category, area or scope")
+	graphCmd.Flags().StringVar(&graphScope, "scope", "", "only links with both ends within this JD ID")
+	graphCmd.Flags().IntVar(&graphMinWeight, "min-weight", 1, "drop edges with fewer links")
+
+	rootCmd.AddCommand(graphCmd)
+}
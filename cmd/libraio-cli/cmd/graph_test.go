@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"libraio/internal/domain"
+)
+
+func TestWriteDOT_EscapesOnlyQuotesAndBackslashes(t *testing.T) {
+	graph := &domain.LinkGraph{
+		Nodes: []domain.GraphNode{
+			{ID: "S01.11.15", Name: `Café "Überraschung" \ Noël`, Type: "Item"},
+			{ID: "S01.12", Name: "日本旅行", Type: "Category"},
+		},
+		Edges: []domain.GraphEdge{{Source: "S01.11.15", Target: "S01.12", Weight: 2}},
+	}
+
+	var b strings.Builder
+	if err := writeDOT(&b, graph); err != nil {
+		t.Fatalf("writeDOT failed: %v", err)
+	}
+
+	want := `digraph libraio {
+  "S01.11.15" [label="S01.11.15 Café \"Überraschung\" \\ Noël", class="Item"];
+  "S01.12" [label="S01.12 日本旅行", class="Category"];
+  "S01.11.15" -> "S01.12" [weight=2, label="2"];
+}
+`
+	if got := b.String(); got != want {
+		t.Errorf("writeDOT =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"libraio/internal/adapters/git"
+	"libraio/internal/application/commands"
+)
+
+var historyLimit int
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show commits created by vault operations",
+	Long: `Show the git commits libraio created for vault changes, most recent first.
+
+With --git (or LIBRAIO_GIT=1), every create, move, archive, unarchive, rename,
+delete and restore stages exactly the paths it touched, including files whose
+links were rewritten, and commits them with a descriptive message. Other
+uncommitted changes in the work tree are left alone.
+
+Examples:
+  libraio-cli history
+  libraio-cli history -n 5
+  libraio-cli --json history`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		vc, err := git.Open(GetRepo().VaultPath())
+		if err != nil {
+			return err
+		}
+
+		ctx := context.Background()
+		entries, err := commands.NewHistoryCommand(vc, historyLimit).Execute(ctx)
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			return printJSON(entries)
+		}
+
+		if len(entries) == 0 {
+			fmt.Println("No libraio commits")
+			return nil
+		}
+		for _, e := range entries {
+			fmt.Printf("%s  %s  %s\n", e.ShortHash(), e.Date.Local().Format("2006-01-02 15:04"), e.Subject)
+		}
+		return nil
+	},
+}
+
+func init() {
+	historyCmd.Flags().IntVarP(&historyLimit, "limit", "n", 20, "maximum number of commits to show (0 for all)")
+	rootCmd.AddCommand(historyCmd)
+}
@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"libraio/internal/adapters/filesystem"
+	"libraio/internal/adapters/sqlite"
+	"libraio/internal/application/commands"
+	"libraio/internal/domain"
+)
+
+var (
+	indexSyncFull   bool
+	indexSyncRelink bool
+)
+
+var indexCmd = &cobra.Command{
+	Use:   "index [status|migrate|sync]",
+	Short: "Inspect and upgrade the vault index",
+	Long: `Inspect and upgrade the SQLite index that caches the vault structure,
+links and contents.
+
+Derived tables mirror the vault and are rebuilt from it with a full sync when
+their layout changes. Durable tables hold data found nowhere else and are
+upgraded in place by ordered migrations. Both happen automatically whenever the
+index is opened; these commands show and apply them explicitly.
+
+Syncing also follows folders and notes moved or renamed outside libraio, for
+example in Finder or Obsidian. Links that still use a moved folder's old JD
+name can then be rewritten with --relink.
+
+Examples:
+  libraio-cli index status
+  libraio-cli index migrate
+  libraio-cli index sync --relink`,
+}
+
+var indexStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the index schema version and pending migrations",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		index := sqlite.NewIndex(sqlite.WithManualMigrations())
+		if err := index.Open(vaultPath); err != nil {
+			return err
+		}
+		defer index.Close()
+
+		status, err := index.Status()
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			return printJSON(status)
+		}
+
+		fmt.Printf("Database: %s\n", status.DBPath)
+		fmt.Printf("Schema:   version %d of %d\n", status.Version, status.LatestVersion)
+		for _, m := range status.Pending {
+			fmt.Printf("  pending  %3d  %s\n", m.Version, m.Name)
+		}
+		if status.DerivedVersion == status.DerivedLatest {
+			fmt.Printf("Derived:  current (%d nodes, %d edges)\n", status.Nodes, status.Edges)
+		} else {
+			fmt.Printf("Derived:  layout %d, needs rebuild to %d\n", status.DerivedVersion, status.DerivedLatest)
+		}
+		if !status.LastSync.IsZero() {
+			fmt.Printf("Synced:   %s\n", status.LastSync.Local().Format("2006-01-02 15:04"))
+		}
+		if !status.UpToDate() {
+			fmt.Println("Run 'libraio-cli index migrate' to upgrade")
+		}
+		return nil
+	},
+}
+
+var indexMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Apply pending migrations and rebuild outdated derived tables",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		index := sqlite.NewIndex(sqlite.WithManualMigrations())
+		if err := index.Open(vaultPath); err != nil {
+			return err
+		}
+		defer index.Close()
+
+		result, err := index.Migrate()
+		if err != nil {
+			return err
+		}
+		if result.DerivedRebuilt {
+			if _, err := syncRepo(index).SyncFull(); err != nil {
+				return fmt.Errorf("failed to rebuild index: %w", err)
+			}
+		}
+
+		if jsonOutput {
+			return printJSON(result)
+		}
+
+		for _, m := range result.Applied {
+			fmt.Printf("  applied  %3d  %s\n", m.Version, m.Name)
+		}
+		switch {
+		case result.DerivedRebuilt:
+			fmt.Printf("Index migrated (%s) and rebuilt\n", domain.Pluralize(len(result.Applied), "migration"))
+		case len(result.Applied) > 0:
+			fmt.Printf("Index migrated (%s)\n", domain.Pluralize(len(result.Applied), "migration"))
+		default:
+			fmt.Println("Index is up to date")
+		}
+		return nil
+	},
+}
+
+var indexSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Bring the index up to date and report external moves",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		index := sqlite.NewIndex()
+		if err := index.Open(vaultPath); err != nil {
+			return err
+		}
+		defer index.Close()
+
+		var stats *domain.SyncStats
+		var err error
+		if indexSyncFull {
+			stats, err = syncRepo(index).SyncFull()
+		} else {
+			stats, err = syncRepo(index).Sync(nil)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to sync index: %w", err)
+		}
+
+		// Moves found by earlier syncs, e.g. at TUI startup, are kept until relinked
+		relinkable, err := index.PendingMoves()
+		if err != nil {
+			return err
+		}
+
+		var result *commands.RelinkResult
+		if indexSyncRelink && len(relinkable) > 0 {
+			relinkRepo := filesystem.NewRepository(vaultPath, append(repoOptions(), filesystem.WithIndex(index))...)
+			result, err = commands.NewRelinkCommand(relinkRepo, relinkable).Execute(context.Background())
+			if err != nil {
+				return err
+			}
+			if err := index.ClearPendingMoves(); err != nil {
+				return err
+			}
+		}
+
+		if jsonOutput {
+			output := struct {
+				Stats  *domain.SyncStats       `json:"stats"`
+				Report *domain.OperationReport `json:"report,omitempty"`
+			}{Stats: stats}
+			if result != nil {
+				output.Report = result.Report
+			}
+			return printJSON(output)
+		}
+
+		fmt.Printf("Index synced: +%d/~%d/-%d nodes, %s in %v\n",
+			stats.NodesAdded, stats.NodesUpdated, stats.NodesDeleted,
+			domain.Pluralize(stats.NodesMoved, "move"), stats.Duration.Round(time.Millisecond))
+		for _, m := range stats.Moves {
+			fmt.Printf("  moved  %s -> %s\n", m.OldPath, m.NewPath)
+		}
+		switch {
+		case result != nil:
+			return printOperation(result.Message, result.Report)
+		case len(relinkable) > 0:
+			fmt.Printf("%s still named by old links; run with --relink to update them\n",
+				domain.Pluralize(len(relinkable), "moved folder"))
+		}
+		return nil
+	},
+}
+
+func init() {
+	indexSyncCmd.Flags().BoolVar(&indexSyncFull, "full", false, "rebuild the index from scratch")
+	indexSyncCmd.Flags().BoolVar(&indexSyncRelink, "relink", false, "rewrite links that use the old names of moved folders")
+
+	indexCmd.AddCommand(indexStatusCmd)
+	indexCmd.AddCommand(indexMigrateCmd)
+	indexCmd.AddCommand(indexSyncCmd)
+	rootCmd.AddCommand(indexCmd)
+}
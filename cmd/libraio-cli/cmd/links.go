@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"libraio/internal/adapters/filesystem"
+	"libraio/internal/application/commands"
+	"libraio/internal/domain"
+)
+
+var linksBrokenFix bool
+
+var linksCmd = &cobra.Command{
+	Use:   "links [broken]",
+	Short: "Check links between notes",
+	Long: `Check the wiki links, Markdown links and canvas file nodes in the vault
+against the index.
+
+Examples:
+  libraio-cli links broken
+  libraio-cli links broken --fix`,
+}
+
+var linksBrokenCmd = &cobra.Command{
+	Use:   "broken",
+	Short: "Report links whose target is gone or has changed",
+	Long: `Report links that no longer lead where they were written to:
+
+  missing   nothing has the JD ID or vault path linked to
+  archived  the [Archived] folder linked to is gone
+  reused    the JD ID now belongs to a folder with another description
+
+Where the rename history kept by the index, or the only folder with the
+description linked to, tells where a target went, the report shows a fix.
+--fix repoints every link that has one.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		index, err := openIndex()
+		if err != nil {
+			return err
+		}
+		defer index.Close()
+		repo := filesystem.NewRepository(vaultPath, append(repoOptions(), filesystem.WithIndex(index))...)
+
+		ctx := context.Background()
+		links, err := commands.NewBrokenLinksCommand(repo).Execute(ctx)
+		if err != nil {
+			return err
+		}
+
+		if linksBrokenFix {
+			result, err := commands.NewFixLinksCommand(repo, links).Execute(ctx)
+			if err != nil {
+				return err
+			}
+			return printOperation(result.Message, result.Report)
+		}
+
+		if jsonOutput {
+			return printJSON(links)
+		}
+
+		if len(links) == 0 {
+			fmt.Println("No broken links")
+			return nil
+		}
+		for _, link := range links {
+			fmt.Printf("%s:%d: %s  (%s)\n", link.SourcePath, link.Line, link.LinkText, describeBrokenLink(link))
+		}
+		return nil
+	},
+}
+
+// describeBrokenLink explains why a link is broken and how it would be fixed
+func describeBrokenLink(link domain.BrokenLink) string {
+	var why string
+	switch link.Kind {
+	case domain.BrokenLinkReused:
+		why = "now " + link.Current
+	case domain.BrokenLinkArchived:
+		why = "archive gone"
+	default:
+		why = "missing"
+	}
+	if link.Fixable() {
+		return why + ", fix: " + link.Fix
+	}
+	return why + ", no fix known"
+}
+
+func init() {
+	linksBrokenCmd.Flags().BoolVar(&linksBrokenFix, "fix", false, "repoint the links that have a known fix")
+
+	linksCmd.AddCommand(linksBrokenCmd)
+	rootCmd.AddCommand(linksCmd)
+}
@@ -19,10 +19,14 @@ Rules:
 - Items can only be moved to categories
 - Categories can only be moved to areas
 
+Links to the moved entity are rewritten throughout the vault. The files
+touched are listed after the move; use --json for a machine-readable report.
+
 Examples:
   libraio-cli move S01.11.15 S01.12      # Move item to category
   libraio-cli move S01.11 S01.20-29      # Move category to area`,
-	Args: cobra.ExactArgs(2),
+	Args:        cobra.ExactArgs(2),
+	Annotations: mutating,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		sourceID := args[0]
 		destID := args[1]
@@ -37,7 +41,7 @@ Examples:
 			if err != nil {
 				return err
 			}
-			fmt.Println(result.Message)
+			return printOperation(result.Message, result.Report)
 
 		case application.IDTypeCategory:
 			moveCmd := commands.NewMoveCategoryCommand(GetRepo(), sourceID, destID)
@@ -45,13 +49,11 @@ Examples:
 			if err != nil {
 				return err
 			}
-			fmt.Println(result.Message)
+			return printOperation(result.Message, result.Report)
 
 		default:
 			return fmt.Errorf("can only move items or categories, got: %s", sourceType)
 		}
-
-		return nil
 	},
 }
 
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"libraio/internal/application"
+)
+
+// operationOutput is the JSON document emitted by mutating commands with --json
+type operationOutput struct {
+	Message string                       `json:"message"`
+	Report  *application.OperationReport `json:"report"`
+}
+
+// printJSON writes v to stdout as indented JSON
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// printOperation prints the outcome of a mutating command and its report.
+// It returns an error if the report recorded errors, so the process exits non-zero.
+func printOperation(message string, report *application.OperationReport) error {
+	if jsonOutput {
+		if err := printJSON(operationOutput{Message: message, Report: report}); err != nil {
+			return err
+		}
+	} else {
+		fmt.Println(message)
+		printReport(report)
+	}
+
+	if report.HasErrors() {
+		return fmt.Errorf("completed with %d error(s)", len(report.Errors))
+	}
+	return nil
+}
+
+// printReport prints rewritten files to stdout and warnings/errors to stderr
+func printReport(report *application.OperationReport) {
+	if report == nil {
+		return
+	}
+
+	for _, fr := range report.FilesRewritten {
+		fmt.Printf("  %3d  %s\n", fr.Links, fr.Path)
+	}
+	if len(report.FilesRewritten) > 0 {
+		fmt.Printf("Links: %s\n", report.Summary())
+	}
+	if report.Snapshot != "" {
+		fmt.Printf("Snapshot: %s\n", report.Snapshot)
+	}
+	for _, w := range report.Warnings {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+	}
+	for _, e := range report.Errors {
+		fmt.Fprintf(os.Stderr, "error: %s\n", e)
+	}
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"libraio/internal/application/commands"
+)
+
+var renameCmd = &cobra.Command{
+	Use:   "rename <id> <new-description>",
+	Short: "Rename an item, category, or area",
+	Long: `Rename the description of an item, category, or area.
+
+The ID is kept; only the folder name changes. Links to the renamed
+entity are rewritten throughout the vault.
+
+Examples:
+  libraio-cli rename S01.11.15 "Theatre, 2026 Season"
+  libraio-cli rename S01.11 Entertainment`,
+	Args:        cobra.MinimumNArgs(2),
+	Annotations: mutating,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id := args[0]
+		description := strings.Join(args[1:], " ")
+		ctx := context.Background()
+
+		renameCmd := commands.NewRenameCommand(GetRepo(), id, description)
+		result, err := renameCmd.Execute(ctx)
+		if err != nil {
+			return err
+		}
+		return printOperation(result.Message, result.Report)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(renameCmd)
+}
@@ -1,42 +1,135 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"libraio/internal/adapters/filesystem"
+	"libraio/internal/adapters/git"
+	"libraio/internal/adapters/sqlite"
 	"libraio/internal/config"
+	"libraio/internal/domain"
 	"libraio/internal/ports"
 )
 
 var (
-	vaultPath string
-	repo      ports.VaultRepository
+	vaultPath  string
+	jsonOutput bool
+	waitLock   bool
+	noWaitLock bool
+	backup     bool
+	backupDir  string
+	gitCommit  bool
+	keepMtime  bool
+	repo       ports.VaultRepository
+	repoIndex  *sqlite.Index // Kept up to date by repo for mutating commands, nil otherwise
 )
 
+// mutatesAnnotation marks the commands that change the vault. Their repository
+// updates the index as it goes, so links, rename history and pending moves
+// reflect the change without waiting for the next sync.
+const mutatesAnnotation = "mutates"
+
+// mutating is the Annotations of a command that changes the vault
+var mutating = map[string]string{mutatesAnnotation: "true"}
+
 var rootCmd = &cobra.Command{
 	Use:   "libraio-cli",
 	Short: "CLI for managing Johnny Decimal vaults",
 	Long: `libraio-cli is a command-line interface for managing Obsidian vaults
 organized with the Johnny Decimal system.
 
-It provides commands to list, create, move, archive, delete, and search
-items within your vault.`,
+It provides commands to list, create, move, rename, archive, unarchive,
+delete, restore, and search items within your vault.`,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 		// Skip initialization for help commands
 		if cmd.Name() == "help" || cmd.Name() == "completion" {
 			return nil
 		}
-		repo = filesystem.NewRepository(vaultPath)
+		opts := repoOptions()
+		if cmd.Annotations[mutatesAnnotation] != "" {
+			index, err := openIndex()
+			switch {
+			case errors.Is(err, domain.ErrVaultLocked):
+				return err
+			case err != nil:
+				fmt.Fprintf(os.Stderr, "Warning: index unavailable, the next sync will catch it up: %v\n", err)
+			default:
+				repoIndex = index
+				opts = append(opts, filesystem.WithIndex(index))
+			}
+		}
+		repo = filesystem.NewRepository(vaultPath, opts...)
 		return nil
 	},
+	PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
+		return closeRepoIndex()
+	},
+}
+
+// closeRepoIndex closes the index opened for a mutating command, if any
+func closeRepoIndex() error {
+	if repoIndex == nil {
+		return nil
+	}
+	err := repoIndex.Close()
+	repoIndex = nil
+	return err
+}
+
+// repoOptions returns the repository options selected by the global flags
+func repoOptions() []filesystem.RepoOption {
+	opts := []filesystem.RepoOption{
+		filesystem.WithLockTimeout(lockTimeout()),
+		filesystem.WithBackupDir(backupDir),
+	}
+	if backup {
+		opts = append(opts, filesystem.WithAutoBackup(config.BackupRetention()))
+	}
+	if keepMtime {
+		opts = append(opts, filesystem.WithKeepMtime())
+	}
+	if gitCommit {
+		// Committing is best effort: vaults outside a work tree are left alone
+		if vc, err := git.Open(vaultPath); err == nil {
+			opts = append(opts, filesystem.WithVersionControl(vc))
+		}
+	}
+	return opts
+}
+
+// openIndex opens the vault's SQLite index and brings it up to date. The sync
+// holds the vault lock, waiting as the global flags say, so it never writes
+// the index during another process's mutation. The caller must close the
+// returned index.
+func openIndex() (*sqlite.Index, error) {
+	index := sqlite.NewIndex()
+	if err := index.Open(vaultPath); err != nil {
+		return nil, err
+	}
+
+	if _, err := syncRepo(index).Sync(nil); err != nil {
+		index.Close()
+		return nil, fmt.Errorf("failed to sync index: %w", err)
+	}
+	return index, nil
+}
+
+// syncRepo returns a repository that syncs index under the vault lock
+func syncRepo(index *sqlite.Index) *filesystem.Repository {
+	return filesystem.NewRepository(vaultPath, filesystem.WithIndex(index), filesystem.WithLockTimeout(lockTimeout()))
 }
 
 // Execute runs the root command
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	err := rootCmd.Execute()
+	// Cobra skips the post-run hooks of a command that failed
+	_ = closeRepoIndex()
+	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
@@ -44,6 +137,26 @@ func Execute() {
 
 func init() {
 	rootCmd.PersistentFlags().StringVarP(&vaultPath, "vault", "v", config.VaultPath(), "path to the vault")
+	rootCmd.PersistentFlags().BoolVar(&jsonOutput, "json", false, "emit results as JSON")
+	rootCmd.PersistentFlags().BoolVar(&waitLock, "wait", false, "wait indefinitely if another process is modifying the vault")
+	rootCmd.PersistentFlags().BoolVar(&noWaitLock, "no-wait", false, "fail immediately if another process is modifying the vault")
+	rootCmd.MarkFlagsMutuallyExclusive("wait", "no-wait")
+	rootCmd.PersistentFlags().BoolVar(&backup, "backup", config.BackupsEnabled(), "snapshot affected files before bulk operations")
+	rootCmd.PersistentFlags().StringVar(&backupDir, "backup-dir", config.BackupDir(), "directory for snapshots (default: .libraio/backups in the vault)")
+	rootCmd.PersistentFlags().BoolVar(&gitCommit, "git", config.GitEnabled(), "commit each change when the vault is inside a git work tree")
+	rootCmd.PersistentFlags().BoolVar(&keepMtime, "keep-mtime", config.KeepMtime(), "keep the modification time of files whose links are rewritten")
+}
+
+// lockTimeout returns how long mutating commands wait for the vault lock
+func lockTimeout() time.Duration {
+	switch {
+	case waitLock:
+		return filesystem.WaitForever
+	case noWaitLock:
+		return 0
+	default:
+		return filesystem.DefaultLockTimeout
+	}
 }
 
 // GetRepo returns the initialized repository
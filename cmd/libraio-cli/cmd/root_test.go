@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"libraio/internal/adapters/sqlite"
+	"libraio/internal/domain"
+)
+
+func TestMove_KeepsIndexCurrent(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	vaultPath := t.TempDir()
+	area := filepath.Join("S01 Personal", "S01.10-19 Lifestyle")
+	files := map[string]string{
+		filepath.Join(area, "S01.11 Entertainment", "S01.11.15 Theatre", "README.md"): "# Theatre",
+		filepath.Join(area, "S01.12 Travel", "S01.12.11 Japan", "plan.md"):            "[Theatre](../../S01.11%20Entertainment/S01.11.15%20Theatre/README.md)",
+	}
+	for name, content := range files {
+		path := filepath.Join(vaultPath, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rootCmd.SetArgs([]string{"--vault", vaultPath, "--no-wait", "move", "S01.11.15", "S01.12"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("move failed: %v", err)
+	}
+	if repoIndex != nil {
+		t.Error("the index opened for the move should be closed")
+	}
+	moved := filepath.Join(area, "S01.12 Travel", "S01.12.12 Theatre")
+	if _, err := os.Stat(filepath.Join(vaultPath, moved)); err != nil {
+		t.Fatalf("item should be at %s: %v", moved, err)
+	}
+
+	// Opened without syncing, the index must already know about the move
+	index := sqlite.NewIndex()
+	if err := index.Open(vaultPath); err != nil {
+		t.Fatalf("failed to open index: %v", err)
+	}
+	defer index.Close()
+
+	links, err := index.FindLinksToPath(moved)
+	if err != nil {
+		t.Fatalf("FindLinksToPath failed: %v", err)
+	}
+	if len(links) != 1 || filepath.Base(links[0].SourcePath) != "plan.md" {
+		t.Errorf("expected the rewritten link from plan.md, got %+v", links)
+	}
+
+	// A link written by another tool, which the move could not rewrite, is
+	// fixed from the rename history
+	tx, err := index.BeginTx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	edge := &domain.Edge{SourcePath: "notes.md", TargetJDID: "S01.11.15", LinkText: "[[S01.11.15 Theatre]]", Kind: domain.LinkWiki, Line: 1, Column: 1}
+	if err := tx.InsertEdge(edge); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	broken, err := index.FindBrokenLinks()
+	if err != nil {
+		t.Fatalf("FindBrokenLinks failed: %v", err)
+	}
+	if len(broken) != 1 || broken[0].Fix != moved {
+		t.Errorf("expected a fix to %s from the rename history, got %+v", moved, broken)
+	}
+}
@@ -3,47 +3,215 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/spf13/cobra"
 
+	"libraio/internal/adapters/filesystem"
+	"libraio/internal/application"
 	"libraio/internal/application/commands"
+	"libraio/internal/domain"
+)
+
+var (
+	searchContent    bool
+	searchLimit      int
+	searchTags       []string
+	searchProperties []string
+	searchScope      string
+	searchSaved      string
 )
 
 var searchCmd = &cobra.Command{
-	Use:   "search <query>",
+	Use:   "search [query]",
 	Short: "Search the vault",
-	Long: `Search for items in the vault by ID, name, or content.
+	Long: `Search for items in the vault by ID or name, or by content with --content.
+
+Name results are ranked by relevance using fuzzy matching. Content results
+come from the full-text index of markdown bodies, best match first, each with
+a snippet around the match. Every word must appear; the last word also
+matches as a prefix.
+
+A query may also use filters, quoted phrases, negation and OR, and is then
+run against the index:
+
+  type:item       scope, area, category, item or file
+  in:S01.11       within the folder of a JD ID
+  scope:S02       within a scope
+  ext:pdf         files with an extension
+  tag:finance     tagged, or tagged with a tag nested under it
+  modified:>DATE  modified after (>), from (>=), before (<), up to (<=) or on
+                  a date: 2025-06-01, today, yesterday, or 7d, 2w, 3m, 1y ago
+  has:backlinks   linked to from elsewhere; also has:tags, has:properties
+  key:value       a frontmatter property, such as status:active
+  "a phrase"      names holding the phrase as written
+  -term, NOT term entries the term does not match
+  a OR b          entries either matches; group with parentheses
 
-Results are ranked by relevance using fuzzy matching.
+Words such as 10:30 or "Meeting:" stay words, and a query that does not
+parse is searched as plain words.
+
+--tag and --property find items through the tags and frontmatter properties
+of their notes, as recorded in the index; every one given must be found on
+some note of the item. A tag also matches the tags nested under it. --scope
+narrows the items to a JD ID, and a query narrows them by name.
+
+--saved runs a search saved with "libraio-cli searches save".
 
 Examples:
   libraio-cli search theatre
-  libraio-cli search S01.11`,
-	Args: cobra.ExactArgs(1),
+  libraio-cli search S01.11
+  libraio-cli search --content "opening night"
+  libraio-cli search --tag project --scope S01
+  libraio-cli search --property status=active --tag idea
+  libraio-cli search 'type:file ext:pdf in:S01.11 modified:>2025-06-01'
+  libraio-cli search 'tag:finance (scope:S01 OR scope:S02) -has:backlinks'
+  libraio-cli search --saved "Tagged #todo"`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		query := args[0]
+		query := ""
+		if len(args) > 0 {
+			query = args[0]
+		}
 		ctx := context.Background()
 
-		searchCmd := commands.NewSearchCommand(GetRepo(), query)
+		if searchSaved != "" {
+			if query != "" || searchContent || len(searchTags) > 0 || len(searchProperties) > 0 || searchScope != "" {
+				return fmt.Errorf("--saved cannot be combined with a query or other search flags")
+			}
+			return searchSavedQuery(ctx, searchSaved)
+		}
+		if len(searchTags) > 0 || len(searchProperties) > 0 || searchScope != "" {
+			if searchContent {
+				return fmt.Errorf("--content cannot be combined with --tag, --property or --scope")
+			}
+			return searchItems(ctx, query)
+		}
+		if query == "" {
+			return fmt.Errorf("give a query, or --tag, --property or --scope")
+		}
+		if !searchContent {
+			// A query that does not parse is searched as plain words
+			parsed, err := application.ParseQuery(query)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v; searching names as written\n", err)
+			} else if !parsed.Plain() {
+				return searchQuery(ctx, query)
+			}
+		}
+
+		var searchCmd *commands.SearchCommand
+		if searchContent {
+			searchRepo := GetRepo()
+			index, err := openIndex()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: index unavailable, scanning files: %v\n", err)
+			} else {
+				defer index.Close()
+				searchRepo = filesystem.NewRepository(vaultPath, append(repoOptions(), filesystem.WithIndex(index))...)
+			}
+			searchCmd = commands.NewContentSearchCommand(searchRepo, query, searchLimit)
+		} else {
+			searchCmd = commands.NewSearchCommand(GetRepo(), query)
+		}
+
 		results, err := searchCmd.Execute(ctx)
 		if err != nil {
 			return err
 		}
 
-		if len(results) == 0 {
-			fmt.Println("No results found")
-			return nil
+		printSearchResults(results)
+		return nil
+	},
+}
+
+// searchItems finds items by the tags and properties of their notes
+func searchItems(ctx context.Context, query string) error {
+	itemQuery := domain.ItemQuery{Scope: searchScope, Tags: searchTags}
+	for _, s := range searchProperties {
+		property, err := domain.ParseProperty(s)
+		if err != nil {
+			return err
 		}
+		itemQuery.Properties = append(itemQuery.Properties, property)
+	}
+
+	index, err := openIndex()
+	if err != nil {
+		return err
+	}
+	defer index.Close()
+	repo := filesystem.NewRepository(vaultPath, append(repoOptions(), filesystem.WithIndex(index))...)
 
-		for _, r := range results {
-			typeStr := strings.ToLower(r.Type.String())
-			fmt.Printf("[%s] %s %s\n", typeStr, r.ID, r.Name)
+	results, err := commands.NewItemSearchCommand(repo, itemQuery, query).Execute(ctx)
+	if err != nil {
+		return err
+	}
+	printSearchResults(results)
+	return nil
+}
+
+// searchQuery runs a query using filters against the index
+func searchQuery(ctx context.Context, query string) error {
+	index, err := openIndex()
+	if err != nil {
+		return err
+	}
+	defer index.Close()
+	repo := filesystem.NewRepository(vaultPath, append(repoOptions(), filesystem.WithIndex(index))...)
+
+	results, err := commands.NewQuerySearchCommand(repo, query, 0).Execute(ctx)
+	if err != nil {
+		return err
+	}
+	printSearchResults(results)
+	return nil
+}
+
+// searchSavedQuery runs a saved search against the index
+func searchSavedQuery(ctx context.Context, name string) error {
+	index, err := openIndex()
+	if err != nil {
+		return err
+	}
+	defer index.Close()
+	repo := filesystem.NewRepository(vaultPath, append(repoOptions(), filesystem.WithIndex(index))...)
+
+	results, err := commands.NewRunSavedSearchCommand(repo, name, 0).Execute(ctx)
+	if err != nil {
+		return err
+	}
+	printSearchResults(results)
+	return nil
+}
+
+// printSearchResults lists search results, with their snippet for content
+// searches
+func printSearchResults(results []commands.SearchResult) {
+	if len(results) == 0 {
+		fmt.Println("No results found")
+		return
+	}
+
+	for _, r := range results {
+		typeStr := strings.ToLower(r.Type.String())
+		if r.Type == domain.IDTypeUnknown {
+			typeStr = "folder"
 		}
-		return nil
-	},
+		fmt.Printf("[%s] %s %s\n", typeStr, r.ID, r.Name)
+		if searchContent && r.MatchedText != "" {
+			fmt.Printf("    %s\n", domain.HighlightSnippet(r.MatchedText, "**", "**"))
+		}
+	}
 }
 
 func init() {
+	searchCmd.Flags().BoolVarP(&searchContent, "content", "c", false, "search markdown contents instead of names")
+	searchCmd.Flags().IntVarP(&searchLimit, "limit", "n", 50, "maximum number of content results")
+	searchCmd.Flags().StringArrayVarP(&searchTags, "tag", "t", nil, "only items with a note tagged this (repeatable)")
+	searchCmd.Flags().StringArrayVarP(&searchProperties, "property", "p", nil, "only items with a note whose frontmatter has key=value (repeatable)")
+	searchCmd.Flags().StringVar(&searchScope, "scope", "", "only items within this JD ID")
+	searchCmd.Flags().StringVar(&searchSaved, "saved", "", "run the saved search with this name")
 	rootCmd.AddCommand(searchCmd)
 }
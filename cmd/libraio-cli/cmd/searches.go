@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"libraio/internal/application/commands"
+)
+
+var searchesCmd = &cobra.Command{
+	Use:   "searches [list|save|delete]",
+	Short: "Manage saved searches",
+	Long: `List, save, or delete the named search queries of the vault.
+
+Saved searches are kept in .libraio/searches.json inside the vault. They are
+run with "libraio-cli search --saved NAME" and shown at the top of the tree
+in the TUI. Names ignore case; saving under an existing name replaces its
+query. See "libraio-cli search --help" for the query language.
+
+Examples:
+  libraio-cli searches list
+  libraio-cli searches save "Inbox everywhere" "type:item inbox"
+  libraio-cli searches save "Tagged #todo" "tag:todo"
+  libraio-cli searches save "Modified this week" "modified:>7d"
+  libraio-cli searches delete "Tagged #todo"`,
+}
+
+var searchesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved searches",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		searches, err := commands.NewListSavedSearchesCommand(GetRepo()).Execute(ctx)
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			return printJSON(searches)
+		}
+
+		if len(searches) == 0 {
+			fmt.Println("No saved searches")
+			return nil
+		}
+		for _, s := range searches {
+			fmt.Printf("%s  %s\n", s.Name, s.Query)
+		}
+		return nil
+	},
+}
+
+var searchesSaveCmd = &cobra.Command{
+	Use:   "save <name> <query>",
+	Short: "Save a search query under a name",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		result, err := commands.NewSaveSearchCommand(GetRepo(), args[0], args[1]).Execute(ctx)
+		if err != nil {
+			return err
+		}
+		return printOperation(result.Message, nil)
+	},
+}
+
+var searchesDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a saved search",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		result, err := commands.NewDeleteSavedSearchCommand(GetRepo(), args[0]).Execute(ctx)
+		if err != nil {
+			return err
+		}
+		return printOperation(result.Message, nil)
+	},
+}
+
+func init() {
+	searchesCmd.AddCommand(searchesListCmd)
+	searchesCmd.AddCommand(searchesSaveCmd)
+	searchesCmd.AddCommand(searchesDeleteCmd)
+	rootCmd.AddCommand(searchesCmd)
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"libraio/internal/adapters/filesystem"
+	"libraio/internal/application/commands"
+	"libraio/internal/domain"
+)
+
+var (
+	staleMonths  int
+	staleScope   string
+	staleReasons []string
+)
+
+var staleCmd = &cobra.Command{
+	Use:   "stale",
+	Short: "Report items, categories and areas due for a clean-up",
+	Long: `Review the vault, as recorded in the index, for entries a clean-up should
+look at:
+
+  orphan     item no note outside it links to
+  jdex-only  item holding no files besides its JDex note
+  untouched  item with nothing in it modified for --months months
+  empty      category with no items, or area with no categories
+
+Standard zero items and management categories and areas are left out.
+--reason narrows the review to some reasons; --scope to a JD ID.
+
+Examples:
+  libraio-cli stale
+  libraio-cli stale --months 24 --reason untouched
+  libraio-cli stale --scope S01.10-19 --reason orphan --reason empty`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if staleMonths < 0 {
+			return fmt.Errorf("--months cannot be negative")
+		}
+		opts := domain.StaleOptions{Scope: staleScope}
+		if staleMonths > 0 {
+			opts.Before = time.Now().AddDate(0, -staleMonths, 0)
+		}
+		for _, name := range staleReasons {
+			reason, err := domain.ParseStaleReason(name)
+			if err != nil {
+				return err
+			}
+			opts.Reasons = append(opts.Reasons, reason)
+		}
+
+		index, err := openIndex()
+		if err != nil {
+			return err
+		}
+		defer index.Close()
+		repo := filesystem.NewRepository(vaultPath, append(repoOptions(), filesystem.WithIndex(index))...)
+
+		entries, err := commands.NewFindStaleCommand(repo, opts).Execute(context.Background())
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			return printJSON(entries)
+		}
+
+		if len(entries) == 0 {
+			fmt.Println("Nothing to clean up")
+			return nil
+		}
+		for _, entry := range entries {
+			reasons := make([]string, len(entry.Reasons))
+			for i, reason := range entry.Reasons {
+				reasons[i] = string(reason)
+			}
+			fmt.Printf("%s  %s  (last modified %s)\n",
+				entry.FolderName(), strings.Join(reasons, ", "), entry.Modified.Local().Format("2006-01-02"))
+		}
+		return nil
+	},
+}
+
+func init() {
+	staleCmd.Flags().IntVar(&staleMonths, "months", 12, "months without changes after which an item is untouched (0 to skip)")
+	staleCmd.Flags().StringVar(&staleScope, "scope", "", "only review entries within this JD ID")
+	staleCmd.Flags().StringArrayVar(&staleReasons, "reason", nil, "only look for this reason: orphan, jdex-only, untouched or empty (repeatable)")
+
+	rootCmd.AddCommand(staleCmd)
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"libraio/internal/application/commands"
+)
+
+var trashOlderThan string
+
+var trashCmd = &cobra.Command{
+	Use:   "trash [list|restore|empty]",
+	Short: "Manage deleted entities",
+	Long: `List, restore, or permanently remove entities in the vault trash.
+
+Deleted entities are kept in .libraio/trash inside the vault together with
+their original location and the links that pointed at them.
+
+Examples:
+  libraio-cli trash list
+  libraio-cli trash restore 20250102T150405-S01.11.15
+  libraio-cli trash empty                   # Remove everything
+  libraio-cli trash empty --older-than 30d  # Remove entries older than 30 days`,
+}
+
+var trashListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List trashed entities",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		entries, err := commands.NewListTrashCommand(GetRepo()).Execute(ctx)
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			return printJSON(entries)
+		}
+
+		if len(entries) == 0 {
+			fmt.Println("Trash is empty")
+			return nil
+		}
+		for _, e := range entries {
+			fmt.Printf("%s  %s  (%s, %d incoming links)\n",
+				e.TrashID, e.OriginalPath, e.DeletedAt.Local().Format("2006-01-02 15:04"), len(e.IncomingLinks))
+		}
+		return nil
+	},
+}
+
+var trashRestoreCmd = &cobra.Command{
+	Use:         "restore <trash-id>",
+	Short:       "Restore a trashed entity to its original location",
+	Args:        cobra.ExactArgs(1),
+	Annotations: mutating,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		result, err := commands.NewRestoreTrashCommand(GetRepo(), args[0]).Execute(ctx)
+		if err != nil {
+			return err
+		}
+		return printOperation(result.Message, result.Report)
+	},
+}
+
+var trashEmptyCmd = &cobra.Command{
+	Use:   "empty",
+	Short: "Permanently delete trashed entities",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		olderThan, err := parseAge(trashOlderThan)
+		if err != nil {
+			return err
+		}
+
+		ctx := context.Background()
+		result, err := commands.NewEmptyTrashCommand(GetRepo(), olderThan).Execute(ctx)
+		if err != nil {
+			return err
+		}
+		return printOperation(result.Message, nil)
+	},
+}
+
+// parseAge parses a duration that may use a "d" suffix for days (e.g. "30d", "12h")
+func parseAge(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid age %q: %w", s, err)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid age %q: %w", s, err)
+	}
+	return d, nil
+}
+
+func init() {
+	trashEmptyCmd.Flags().StringVar(&trashOlderThan, "older-than", "", "only remove entries older than this age (e.g. 30d, 12h)")
+
+	trashCmd.AddCommand(trashListCmd)
+	trashCmd.AddCommand(trashRestoreCmd)
+	trashCmd.AddCommand(trashEmptyCmd)
+	rootCmd.AddCommand(trashCmd)
+}
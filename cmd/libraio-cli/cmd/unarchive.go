@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"libraio/internal/application/commands"
+)
+
+var unarchiveCmd = &cobra.Command{
+	Use:   "unarchive <archive-item-id>",
+	Short: "Restore archived items",
+	Long: `Restore all archived items from a category's .09 archive item.
+
+Each "[Archived] Name" folder gets a new ID in the parent category, and
+links to it are rewritten throughout the vault.
+
+Example:
+  libraio-cli unarchive S01.11.09`,
+	Args:        cobra.ExactArgs(1),
+	Annotations: mutating,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		unarchiveCmd := commands.NewUnarchiveItemCommand(GetRepo(), args[0])
+		result, err := unarchiveCmd.Execute(ctx)
+		if err != nil {
+			return err
+		}
+		return printOperation(result.Message, result.Report)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(unarchiveCmd)
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"libraio/internal/adapters/filesystem"
+	"libraio/internal/adapters/sqlite"
+	"libraio/internal/adapters/watcher"
+	"libraio/internal/config"
+	"libraio/internal/domain"
+)
+
+var watchInterval time.Duration
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Keep the index up to date with changes made outside libraio",
+	Long: `Watch the vault and keep its index up to date with changes made in other
+apps, such as Finder or Obsidian, until interrupted.
+
+The vault is polled every --interval, so watching works on every platform and
+filesystem. Folders whose modification time is unchanged are not listed again,
+which keeps each check cheap. Run it as a background service so the TUI and
+the CLI always start with a current index.
+
+Every check that finds changes prints one line; with --json, one JSON object
+per line. Moved folders still named by old links can be relinked with
+'libraio-cli index sync --relink'.
+
+Examples:
+  libraio-cli watch
+  libraio-cli watch --interval 30s
+  libraio-cli --json watch`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if watchInterval <= 0 {
+			return fmt.Errorf("interval must be positive, got %v", watchInterval)
+		}
+
+		index := sqlite.NewIndex()
+		if err := index.Open(vaultPath); err != nil {
+			return err
+		}
+		defer index.Close()
+		watchRepo := filesystem.NewRepository(vaultPath, append(repoOptions(), filesystem.WithIndex(index))...)
+
+		// Catch up with changes made while nothing was watching
+		stats, err := watchRepo.Sync(nil)
+		if err != nil {
+			return fmt.Errorf("failed to sync index: %w", err)
+		}
+		if stats.HasChanges() {
+			printWatchEvent(domain.WatchEvent{Stats: stats})
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		if !jsonOutput {
+			fmt.Fprintf(os.Stderr, "Watching %s every %v (Ctrl+C to stop)\n", watchRepo.VaultPath(), watchInterval)
+		}
+
+		err = watcher.NewPoller(watchRepo, watchInterval).Watch(ctx, printWatchEvent)
+		if errors.Is(err, context.Canceled) {
+			return nil
+		}
+		return err
+	},
+}
+
+// watchOutput is one line of JSON output from watch
+type watchOutput struct {
+	Time  time.Time         `json:"time"`
+	Stats *domain.SyncStats `json:"stats,omitempty"`
+	Error string            `json:"error,omitempty"`
+}
+
+// printWatchEvent prints a check that found changes or failed. Failures are
+// reported but do not stop the watch.
+func printWatchEvent(event domain.WatchEvent) {
+	now := time.Now()
+	if jsonOutput {
+		output := watchOutput{Time: now, Stats: event.Stats}
+		if event.Err != nil {
+			output.Error = event.Err.Error()
+		}
+		_ = json.NewEncoder(os.Stdout).Encode(output)
+		return
+	}
+
+	stamp := now.Format("2006-01-02 15:04:05")
+	if event.Err != nil {
+		fmt.Fprintf(os.Stderr, "%s  check failed: %v\n", stamp, event.Err)
+		return
+	}
+	stats := event.Stats
+	if stats.Rebuilt {
+		fmt.Printf("%s  index rebuilt: %s\n", stamp, domain.Pluralize(stats.NodesAdded, "node"))
+		return
+	}
+	fmt.Printf("%s  +%d/~%d/-%d nodes, %s\n", stamp,
+		stats.NodesAdded, stats.NodesUpdated, stats.NodesDeleted, domain.Pluralize(stats.NodesMoved, "move"))
+	for _, m := range stats.Moves {
+		fmt.Printf("  moved  %s -> %s\n", m.OldPath, m.NewPath)
+	}
+}
+
+func init() {
+	watchCmd.Flags().DurationVar(&watchInterval, "interval", config.DefaultWatchInterval, "how often to check the vault for changes")
+	rootCmd.AddCommand(watchCmd)
+}
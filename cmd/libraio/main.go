@@ -10,9 +10,11 @@ import (
 	"libraio/internal/adapters/claudecli"
 	"libraio/internal/adapters/editor"
 	"libraio/internal/adapters/filesystem"
+	"libraio/internal/adapters/git"
 	"libraio/internal/adapters/obsidian"
 	"libraio/internal/adapters/sqlite"
 	"libraio/internal/adapters/tui"
+	"libraio/internal/adapters/watcher"
 	"libraio/internal/config"
 )
 
@@ -25,44 +27,46 @@ func main() {
 		index = nil
 	} else {
 		defer index.Close()
-
-		// Sync index on startup
-		if index.NeedsFullRebuild() {
-			stats, err := index.SyncFull()
-			if err != nil {
-				log.Printf("Warning: full sync failed: %v", err)
-			} else {
-				log.Printf("Index rebuilt: %d nodes, %d edges in %v",
-					stats.NodesAdded, stats.EdgesAdded, stats.Duration)
-			}
-		} else {
-			stats, err := index.SyncIncremental()
-			if err != nil {
-				log.Printf("Warning: incremental sync failed: %v", err)
-			} else if stats.NodesAdded > 0 || stats.NodesUpdated > 0 || stats.NodesDeleted > 0 {
-				log.Printf("Index updated: +%d/~%d/-%d nodes in %v",
-					stats.NodesAdded, stats.NodesUpdated, stats.NodesDeleted, stats.Duration)
-			}
-		}
 	}
 
 	// Initialize adapters
-	var repo *filesystem.Repository
+	opts := []filesystem.RepoOption{filesystem.WithBackupDir(config.BackupDir())}
 	if index != nil {
-		repo = filesystem.NewRepository(vaultPath, filesystem.WithIndex(index))
-	} else {
-		repo = filesystem.NewRepository(vaultPath)
+		opts = append(opts, filesystem.WithIndex(index))
+	}
+	if config.BackupsEnabled() {
+		opts = append(opts, filesystem.WithAutoBackup(config.BackupRetention()))
+	}
+	if config.KeepMtime() {
+		opts = append(opts, filesystem.WithKeepMtime())
 	}
+	if config.GitEnabled() {
+		if vc, err := git.Open(vaultPath); err == nil {
+			opts = append(opts, filesystem.WithVersionControl(vc))
+		} else {
+			log.Printf("Warning: git integration disabled: %v", err)
+		}
+	}
+	repo := filesystem.NewRepository(vaultPath, opts...)
 	editorOpener := editor.NewOpener()
 	obsidianOpener := obsidian.NewOpener(repo.VaultPath())
 	aiAssistant := claudecli.NewAssistant()
 
 	// Create and run TUI app
 	app := tui.NewApp(repo, editorOpener, obsidianOpener, aiAssistant)
+	if index != nil {
+		// Sync index on startup, showing progress in the TUI, then keep it live
+		app.SetStartupSync(repo)
+		if interval := config.WatchInterval(); interval > 0 {
+			app.SetWatcher(watcher.NewPoller(repo, interval))
+		}
+	}
 
 	p := tea.NewProgram(app, tea.WithAltScreen())
 
-	if _, err := p.Run(); err != nil {
+	_, err := p.Run()
+	app.Close()
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
@@ -0,0 +1,61 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// WithKeepMtime keeps the modification time of notes and canvases whose links
+// are rewritten, so link upkeep does not reorder "recently modified" views.
+// By default a rewritten file gets the current time, like any other edit.
+func WithKeepMtime() RepoOption {
+	return func(r *Repository) {
+		r.keepMtime = true
+	}
+}
+
+// writeFileAtomic replaces the file at path with data: it writes a temporary
+// file in the same folder and renames it into place, so readers and a crash
+// mid-write see the old content or the new, never a mix. The file keeps its
+// permissions, and its modification time if keepMtime is set. A symlink is
+// followed, and the file it points to replaced.
+func writeFileAtomic(path string, data []byte, keepMtime bool) (err error) {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	if _, err = tmp.Write(data); err != nil {
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Chmod(tmp.Name(), info.Mode().Perm()); err != nil {
+		return err
+	}
+	if keepMtime {
+		if err = os.Chtimes(tmp.Name(), time.Time{}, info.ModTime()); err != nil {
+			return err
+		}
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,62 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	old := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		keepMtime bool
+	}{
+		{"default mtime", false},
+		{"keep mtime", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			target := filepath.Join(dir, "note.md")
+			if err := os.WriteFile(target, []byte("old"), 0600); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Chtimes(target, old, old); err != nil {
+				t.Fatal(err)
+			}
+			link := filepath.Join(dir, "link.md")
+			if err := os.Symlink(target, link); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := writeFileAtomic(link, []byte("new"), tt.keepMtime); err != nil {
+				t.Fatalf("writeFileAtomic failed: %v", err)
+			}
+
+			if info, err := os.Lstat(link); err != nil || info.Mode()&os.ModeSymlink == 0 {
+				t.Errorf("link.md is no longer a symlink: %v", err)
+			}
+			content, _ := os.ReadFile(target)
+			if string(content) != "new" {
+				t.Errorf("content = %q, want %q", content, "new")
+			}
+			info, err := os.Stat(target)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Mode().Perm() != 0600 {
+				t.Errorf("permissions = %v, want 0600", info.Mode().Perm())
+			}
+			if kept := info.ModTime().Equal(old); kept != tt.keepMtime {
+				t.Errorf("mtime = %v, kept = %v, want kept = %v", info.ModTime(), kept, tt.keepMtime)
+			}
+			entries, _ := os.ReadDir(dir)
+			if len(entries) != 2 {
+				t.Errorf("expected only note.md and link.md, found %d entries", len(entries))
+			}
+		})
+	}
+}
@@ -0,0 +1,439 @@
+package filesystem
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"libraio/internal/domain"
+)
+
+// backupsDir is the vault-relative default folder for snapshots
+var backupsDir = filepath.Join(appDir, "backups")
+
+const (
+	snapshotArchiveExt = ".tar.gz"
+	snapshotMetaExt    = ".json"
+)
+
+// WithBackupDir sets the folder where snapshots are stored and looked up.
+// An empty dir keeps the default inside the vault.
+func WithBackupDir(dir string) RepoOption {
+	return func(r *Repository) {
+		if dir == "" {
+			return
+		}
+		if strings.HasPrefix(dir, "~") {
+			home, _ := os.UserHomeDir()
+			dir = filepath.Join(home, dir[1:])
+		}
+		r.backupDir = dir
+	}
+}
+
+// WithAutoBackup enables snapshots before bulk operations (MoveCategory,
+// ArchiveCategory, ArchiveCategoryToArea, Delete and FixLinks), pruned by the
+// given policy
+func WithAutoBackup(retention domain.RetentionPolicy) RepoOption {
+	return func(r *Repository) {
+		r.autoBackup = true
+		r.retention = retention
+	}
+}
+
+// snapshotBefore archives the subtree at path, plus every markdown file linking
+// into it, before a bulk operation. It returns the snapshot ID, or "" if automatic
+// backups are disabled. Pruning old snapshots is best effort.
+func (r *Repository) snapshotBefore(operation, id, path string) (string, error) {
+	if !r.autoBackup {
+		return "", nil
+	}
+	return r.snapshotWithLinks(operation, id, path, r.findIncomingLinks(id, path))
+}
+
+// snapshotWithLinks is snapshotBefore for callers that already collected the incoming links
+func (r *Repository) snapshotWithLinks(operation, id, path string, links []domain.TrashedLink) (string, error) {
+	if !r.autoBackup {
+		return "", nil
+	}
+
+	files := []string{path}
+	for _, link := range links {
+		source := filepath.Join(r.vaultPath, link.SourcePath)
+		if !slices.Contains(files, source) {
+			files = append(files, source)
+		}
+	}
+	return r.snapshotFiles(operation, id, files)
+}
+
+// snapshotFiles archives files and directory trees before a bulk operation,
+// see snapshotBefore
+func (r *Repository) snapshotFiles(operation, id string, paths []string) (string, error) {
+	if !r.autoBackup {
+		return "", nil
+	}
+
+	snapshot, err := r.writeSnapshot(operation, id, paths)
+	if err != nil {
+		return "", fmt.Errorf("failed to snapshot %s before %s: %w", id, operation, err)
+	}
+
+	if snapshots, err := r.ListBackups(); err == nil {
+		for _, s := range r.retention.Expired(snapshots, time.Now()) {
+			if s.ID != snapshot.ID {
+				r.removeSnapshot(s.ID)
+			}
+		}
+	}
+
+	return snapshot.ID, nil
+}
+
+// writeSnapshot writes the given files and directory trees to a new compressed archive
+func (r *Repository) writeSnapshot(operation, id string, paths []string) (*domain.Snapshot, error) {
+	if err := os.MkdirAll(r.backupDir, 0755); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	snapshot := &domain.Snapshot{
+		ID:        r.uniqueSnapshotID(domain.NewSnapshotID(operation, id, now)),
+		Operation: operation,
+		TargetID:  id,
+		CreatedAt: now,
+		Files:     []string{},
+	}
+
+	archivePath := filepath.Join(r.backupDir, snapshot.ID+snapshotArchiveExt)
+	tmpPath := archivePath + ".tmp"
+	if err := r.writeTarball(tmpPath, paths, snapshot); err != nil {
+		os.Remove(tmpPath)
+		return nil, err
+	}
+
+	info, err := os.Stat(tmpPath)
+	if err != nil {
+		os.Remove(tmpPath)
+		return nil, err
+	}
+	snapshot.Size = info.Size()
+
+	if err := os.Rename(tmpPath, archivePath); err != nil {
+		os.Remove(tmpPath)
+		return nil, err
+	}
+
+	if err := r.writeSnapshotMeta(snapshot); err != nil {
+		os.Remove(archivePath)
+		return nil, fmt.Errorf("failed to write snapshot metadata: %w", err)
+	}
+
+	return snapshot, nil
+}
+
+// writeSnapshotMeta writes the metadata file describing a snapshot
+func (r *Repository) writeSnapshotMeta(snapshot *domain.Snapshot) error {
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(r.backupDir, snapshot.ID+snapshotMetaExt), data, 0644)
+}
+
+// recordSnapshotMoves adds the moves an operation made after its snapshot was
+// taken to the snapshot's metadata, so restoring it can move the entries back
+// rather than write a second copy at their old paths
+func (r *Repository) recordSnapshotMoves(snapshotID string, moves []domain.NodeMove, report *domain.OperationReport) {
+	if snapshotID == "" || len(moves) == 0 {
+		return
+	}
+	snapshot, err := r.findSnapshot(snapshotID)
+	if err == nil {
+		snapshot.Moves = moves
+		err = r.writeSnapshotMeta(snapshot)
+	}
+	if err != nil {
+		report.Warnf("failed to record moves in snapshot %s, restoring it will not move entries back: %v", snapshotID, err)
+	}
+}
+
+// writeTarball writes paths (files or directory trees) to a gzip-compressed tar
+// with vault-relative names, recording regular files in snapshot.Files
+func (r *Repository) writeTarball(dst string, paths []string, snapshot *domain.Snapshot) error {
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	for _, root := range paths {
+		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.Mode().IsRegular() && !info.IsDir() {
+				return nil // Skip symlinks, sockets and the like
+			}
+
+			header, err := tar.FileInfoHeader(info, "")
+			if err != nil {
+				return err
+			}
+			header.Name = filepath.ToSlash(r.relPath(path))
+			if info.IsDir() {
+				header.Name += "/"
+			}
+			if err := tw.WriteHeader(header); err != nil {
+				return err
+			}
+			if info.IsDir() {
+				return nil
+			}
+
+			snapshot.Files = append(snapshot.Files, r.relPath(path))
+			src, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer src.Close()
+			_, err = io.Copy(tw, src)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// ListBackups returns all snapshots, most recent first
+func (r *Repository) ListBackups() ([]domain.Snapshot, error) {
+	entries, err := os.ReadDir(r.backupDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup directory: %w", err)
+	}
+
+	var snapshots []domain.Snapshot
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), snapshotMetaExt) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(r.backupDir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		var s domain.Snapshot
+		if err := json.Unmarshal(data, &s); err != nil || s.ID == "" {
+			continue // Not a snapshot we wrote
+		}
+		snapshots = append(snapshots, s)
+	}
+
+	slices.SortFunc(snapshots, func(a, b domain.Snapshot) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return snapshots, nil
+}
+
+// RestoreBackup moves the entries the snapshotted operation moved back to
+// their old paths, then writes every file in the snapshot back to its original
+// location, overwriting current versions. It refuses to restore when a moved
+// entry is no longer where the operation left it, as restoring would leave a
+// second copy behind; entries deleted to the trash may since have been purged
+// or restored.
+func (r *Repository) RestoreBackup(snapshotID string) (*domain.Snapshot, *domain.OperationReport, error) {
+	unlock, err := r.lockVault()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer unlock()
+
+	snapshot, err := r.findSnapshot(snapshotID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	moves, err := r.movesToUndo(snapshot)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	f, err := os.Open(filepath.Join(r.backupDir, snapshot.ID+snapshotArchiveExt))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open snapshot: %w", err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	defer gz.Close()
+
+	report := domain.NewOperationReport()
+	moved, err := r.undoMoves(moves, report)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var written []string
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read snapshot: %w", err)
+		}
+
+		name := filepath.FromSlash(strings.TrimSuffix(header.Name, "/"))
+		if !filepath.IsLocal(name) {
+			return nil, nil, fmt.Errorf("snapshot contains unsafe path: %s", header.Name)
+		}
+		target := filepath.Join(r.vaultPath, name)
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return nil, nil, fmt.Errorf("failed to restore %s: %w", name, err)
+			}
+		case tar.TypeReg:
+			if err := restoreFile(target, tr, header.FileInfo().Mode().Perm()); err != nil {
+				return nil, nil, fmt.Errorf("failed to restore %s: %w", name, err)
+			}
+		}
+		written = append(written, target)
+	}
+
+	restored := moved
+	for _, f := range snapshot.Files {
+		restored = append(restored, filepath.Join(r.vaultPath, f))
+	}
+	r.indexRestored(written, report)
+	if err := r.commitChange("restore", "restore snapshot "+snapshot.ID, restored...); err != nil {
+		return snapshot, report, fmt.Errorf("restored %s but failed to commit: %w", snapshot.ID, err)
+	}
+	return snapshot, report, nil
+}
+
+// movesToUndo returns the moves of a snapshot to undo before restoring it, last
+// move first, and fails if any moved entry is no longer where the move left it
+func (r *Repository) movesToUndo(snapshot *domain.Snapshot) ([]domain.NodeMove, error) {
+	var undo []domain.NodeMove
+	for _, move := range slices.Backward(snapshot.Moves) {
+		_, newErr := os.Stat(filepath.Join(r.vaultPath, move.NewPath))
+		_, oldErr := os.Stat(filepath.Join(r.vaultPath, move.OldPath))
+		switch {
+		case newErr == nil && os.IsNotExist(oldErr):
+			undo = append(undo, move.Reverse())
+		case newErr == nil:
+			return nil, fmt.Errorf("cannot restore %s: %s already exists", snapshot.ID, move.OldPath)
+		case os.IsNotExist(newErr) && inTrash(move.NewPath):
+			// Purged or restored from the trash since, restoring in place is safe
+		default:
+			return nil, fmt.Errorf("cannot restore %s: %s is no longer at %s", snapshot.ID, move.OldPath, move.NewPath)
+		}
+	}
+	return undo, nil
+}
+
+// undoMoves moves entries along the reversed moves, in order, and returns the
+// absolute paths it changed in the vault. An entry moved back out of the trash
+// takes its trash folder with it.
+func (r *Repository) undoMoves(moves []domain.NodeMove, report *domain.OperationReport) ([]string, error) {
+	var changed []string
+	for _, move := range moves {
+		src := filepath.Join(r.vaultPath, move.OldPath)
+		dst := filepath.Join(r.vaultPath, move.NewPath)
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return changed, fmt.Errorf("failed to move %s back to %s: %w", move.OldPath, move.NewPath, err)
+		}
+		if err := os.Rename(src, dst); err != nil {
+			return changed, fmt.Errorf("failed to move %s back to %s: %w", move.OldPath, move.NewPath, err)
+		}
+		changed = append(changed, dst)
+
+		if inTrash(move.OldPath) {
+			os.RemoveAll(filepath.Dir(src))
+			r.indexAdd(dst, report)
+		} else {
+			changed = append(changed, src)
+			r.indexMoveReport(src, dst, report)
+		}
+	}
+	return changed, nil
+}
+
+// inTrash reports whether a vault-relative path lies inside the vault trash
+func inTrash(relPath string) bool {
+	return strings.HasPrefix(relPath, trashDir+string(filepath.Separator))
+}
+
+// restoreFile writes the contents of src to path, creating parent folders as needed
+func restoreFile(path string, src io.Reader, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	dst, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+	return dst.Close()
+}
+
+// findSnapshot returns the metadata of a snapshot by ID
+func (r *Repository) findSnapshot(snapshotID string) (*domain.Snapshot, error) {
+	snapshots, err := r.ListBackups()
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range snapshots {
+		if s.ID == snapshotID {
+			return &s, nil
+		}
+	}
+	return nil, fmt.Errorf("snapshot not found: %s", snapshotID)
+}
+
+// uniqueSnapshotID returns a snapshot ID that does not collide with an existing one
+func (r *Repository) uniqueSnapshotID(base string) string {
+	id := base
+	for n := 2; ; n++ {
+		if _, err := os.Stat(filepath.Join(r.backupDir, id+snapshotMetaExt)); os.IsNotExist(err) {
+			return id
+		}
+		id = fmt.Sprintf("%s-%d", base, n)
+	}
+}
+
+// removeSnapshot deletes a snapshot archive and its metadata
+func (r *Repository) removeSnapshot(snapshotID string) {
+	os.Remove(filepath.Join(r.backupDir, snapshotID+snapshotArchiveExt))
+	os.Remove(filepath.Join(r.backupDir, snapshotID+snapshotMetaExt))
+}
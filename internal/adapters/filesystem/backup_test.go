@@ -0,0 +1,225 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"libraio/internal/domain"
+)
+
+func TestArchiveCategory_SnapshotsAffectedFiles(t *testing.T) {
+	vaultPath, cleanup := setupLinkTestVault(t)
+	defer cleanup()
+
+	repo := NewRepository(vaultPath, WithAutoBackup(domain.DefaultRetentionPolicy))
+
+	_, report, err := repo.ArchiveCategory("S01.11")
+	if err != nil {
+		t.Fatalf("ArchiveCategory failed: %v", err)
+	}
+	if report.Snapshot == "" {
+		t.Fatal("expected the report to reference a snapshot")
+	}
+
+	snapshots, err := repo.ListBackups()
+	if err != nil {
+		t.Fatalf("ListBackups failed: %v", err)
+	}
+	if len(snapshots) != 1 || snapshots[0].ID != report.Snapshot {
+		t.Fatalf("expected snapshot %s, got %+v", report.Snapshot, snapshots)
+	}
+
+	files := snapshots[0].Files
+	readme := filepath.Join("S01 Personal", "S01.10-19 Lifestyle", "S01.11 Entertainment", "S01.11.15 Theatre", "README.md")
+	if !slices.Contains(files, readme) {
+		t.Errorf("snapshot should contain %s, got %v", readme, files)
+	}
+	if !slices.Contains(files, "notes.md") {
+		t.Errorf("snapshot should contain notes.md, whose links are rewritten, got %v", files)
+	}
+}
+
+func TestRestoreBackup_RestoresFilesAndLinks(t *testing.T) {
+	vaultPath, cleanup := setupLinkTestVault(t)
+	defer cleanup()
+
+	repo := NewRepository(vaultPath, WithAutoBackup(domain.DefaultRetentionPolicy))
+
+	_, report, err := repo.ArchiveCategory("S01.11")
+	if err != nil {
+		t.Fatalf("ArchiveCategory failed: %v", err)
+	}
+
+	if _, _, err := repo.RestoreBackup(report.Snapshot); err != nil {
+		t.Fatalf("RestoreBackup failed: %v", err)
+	}
+
+	if _, err := repo.GetPath("S01.11.15"); err != nil {
+		t.Errorf("archived item should be restored: %v", err)
+	}
+
+	notes, err := os.ReadFile(filepath.Join(vaultPath, "notes.md"))
+	if err != nil {
+		t.Fatalf("failed to read notes.md: %v", err)
+	}
+	if !strings.Contains(string(notes), "[[S01.11.15 Theatre]]") {
+		t.Errorf("notes.md should have its original link back, got: %s", notes)
+	}
+}
+
+func TestRestoreBackup_MovesEntriesBack(t *testing.T) {
+	vaultPath, cleanup := setupLinkTestVault(t)
+	defer cleanup()
+
+	repo := NewRepository(vaultPath, WithAutoBackup(domain.DefaultRetentionPolicy))
+	if _, _, err := repo.CreateArea("S01", "Work"); err != nil {
+		t.Fatalf("CreateArea failed: %v", err)
+	}
+	originalPath, err := repo.GetPath("S01.11.15")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	moved, report, err := repo.MoveCategory("S01.11", "S01.20-29")
+	if err != nil {
+		t.Fatalf("MoveCategory failed: %v", err)
+	}
+	if _, _, err := repo.RestoreBackup(report.Snapshot); err != nil {
+		t.Fatalf("RestoreBackup failed: %v", err)
+	}
+
+	if _, err := os.Stat(originalPath); err != nil {
+		t.Errorf("item should be back at its original path: %v", err)
+	}
+	if _, err := os.Stat(moved.Path); !os.IsNotExist(err) {
+		t.Errorf("moved category should be gone from %s, got %v", moved.Path, err)
+	}
+	var copies []string
+	filepath.WalkDir(vaultPath, func(path string, d os.DirEntry, err error) error {
+		if d.IsDir() && strings.HasSuffix(d.Name(), " Theatre") {
+			copies = append(copies, path)
+		}
+		return err
+	})
+	if len(copies) != 1 {
+		t.Errorf("expected one copy of the item, got %v", copies)
+	}
+}
+
+func TestRestoreBackup_TakesEntryOutOfTrash(t *testing.T) {
+	vaultPath, cleanup := setupLinkTestVault(t)
+	defer cleanup()
+
+	repo := NewRepository(vaultPath, WithAutoBackup(domain.DefaultRetentionPolicy))
+	entry, _, err := repo.Delete("S01.11.15")
+	if err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, _, err := repo.RestoreBackup(entry.Snapshot); err != nil {
+		t.Fatalf("RestoreBackup failed: %v", err)
+	}
+
+	if _, err := repo.GetPath("S01.11.15"); err != nil {
+		t.Errorf("deleted item should be restored: %v", err)
+	}
+	trash, err := repo.ListTrash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trash) != 0 {
+		t.Errorf("restored item should leave the trash, got %+v", trash)
+	}
+}
+
+func TestRestoreBackup_RefusesWhenMovedAgain(t *testing.T) {
+	vaultPath, cleanup := setupLinkTestVault(t)
+	defer cleanup()
+
+	repo := NewRepository(vaultPath, WithAutoBackup(domain.DefaultRetentionPolicy))
+	if _, _, err := repo.CreateArea("S01", "Work"); err != nil {
+		t.Fatalf("CreateArea failed: %v", err)
+	}
+	originalPath, err := repo.GetPath("S01.11")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	moved, report, err := repo.MoveCategory("S01.11", "S01.20-29")
+	if err != nil {
+		t.Fatalf("MoveCategory failed: %v", err)
+	}
+	elsewhere := filepath.Join(vaultPath, "Elsewhere")
+	if err := os.Rename(moved.Path, elsewhere); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := repo.RestoreBackup(report.Snapshot); err == nil {
+		t.Fatal("expected restore to refuse a category moved again since")
+	}
+	if _, err := os.Stat(originalPath); !os.IsNotExist(err) {
+		t.Errorf("refused restore should not write %s, got %v", originalPath, err)
+	}
+}
+
+func TestSnapshots_DisabledByDefault(t *testing.T) {
+	vaultPath, cleanup := setupLinkTestVault(t)
+	defer cleanup()
+
+	repo := NewRepository(vaultPath)
+
+	if _, _, err := repo.Delete("S01.11.15"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	snapshots, err := repo.ListBackups()
+	if err != nil {
+		t.Fatalf("ListBackups failed: %v", err)
+	}
+	if len(snapshots) != 0 {
+		t.Errorf("expected no snapshots without WithAutoBackup, got %d", len(snapshots))
+	}
+}
+
+func TestSnapshots_RetentionAndBackupDir(t *testing.T) {
+	vaultPath, cleanup := setupLinkTestVault(t)
+	defer cleanup()
+
+	backupDir := t.TempDir()
+	repo := NewRepository(vaultPath,
+		WithBackupDir(backupDir),
+		WithAutoBackup(domain.RetentionPolicy{Keep: 1}),
+	)
+
+	if _, _, err := repo.Delete("S01.11.15"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	second, _, err := repo.Delete("S01.11.16")
+	if err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	snapshots, err := repo.ListBackups()
+	if err != nil {
+		t.Fatalf("ListBackups failed: %v", err)
+	}
+	if len(snapshots) != 1 || snapshots[0].ID != second.Snapshot {
+		t.Fatalf("expected only the latest snapshot %s, got %+v", second.Snapshot, snapshots)
+	}
+
+	if _, err := os.Stat(filepath.Join(backupDir, second.Snapshot+snapshotArchiveExt)); err != nil {
+		t.Errorf("snapshot should be stored in the configured directory: %v", err)
+	}
+}
+
+func TestRestoreBackup_UnknownSnapshot(t *testing.T) {
+	vaultPath, cleanup := setupLinkTestVault(t)
+	defer cleanup()
+
+	repo := NewRepository(vaultPath)
+	if _, _, err := repo.RestoreBackup("missing"); err == nil {
+		t.Error("expected error for unknown snapshot")
+	}
+}
@@ -0,0 +1,336 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"libraio/internal/domain"
+	"libraio/internal/ports"
+)
+
+// dirEntry is an entry of a folder listing, read from the index or from disk
+type dirEntry struct {
+	name  string
+	isDir bool
+}
+
+// readDir lists a folder, sorted by name. The index is used when the folder's
+// modification time still matches the indexed one; otherwise the disk is read.
+func (r *Repository) readDir(dirPath string) ([]dirEntry, error) {
+	if nodes, ok := r.indexedChildren(dirPath); ok {
+		entries := make([]dirEntry, len(nodes))
+		for i, node := range nodes {
+			entries[i] = dirEntry{name: filepath.Base(node.Path), isDir: node.IsDir}
+		}
+		return entries, nil
+	}
+
+	osEntries, err := os.ReadDir(dirPath)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]dirEntry, len(osEntries))
+	for i, e := range osEntries {
+		entries[i] = dirEntry{name: e.Name(), isDir: e.IsDir()}
+	}
+	return entries, nil
+}
+
+// indexedChildren returns the indexed entries of a folder, or false if there is
+// no index or the folder gained, lost or renamed entries since it was indexed
+func (r *Repository) indexedChildren(dirPath string) ([]domain.IndexNode, bool) {
+	if r.index == nil {
+		return nil, false
+	}
+
+	info, err := os.Stat(dirPath)
+	if err != nil {
+		return nil, false
+	}
+	rel := r.relPath(dirPath)
+	node, err := r.index.GetNode(rel)
+	if err != nil || node == nil || !node.IsDir || node.Mtime != info.ModTime().UnixNano() {
+		return nil, false
+	}
+
+	nodes, err := r.index.ListChildren(rel)
+	if err != nil {
+		return nil, false
+	}
+	return nodes, true
+}
+
+// indexedPath looks up the folder of a JD ID in the index, or returns false if
+// there is no index, the ID is not indexed in its place in the hierarchy, or
+// the folder is no longer there. A category or item archived with its ID
+// inside an archive item never stands in for the live one.
+func (r *Repository) indexedPath(id string) (string, bool) {
+	if r.index == nil {
+		return "", false
+	}
+
+	node, err := r.index.GetNodeByJDID(id)
+	if err != nil || node == nil || !node.IsDir || !inHierarchyPlace(node.Path, id) {
+		return "", false
+	}
+
+	path := filepath.Join(r.vaultPath, node.Path)
+	if info, err := os.Stat(path); err != nil || !info.IsDir() {
+		return "", false
+	}
+	return path, true
+}
+
+// inHierarchyPlace reports whether the relative path holds one folder per level
+// of the hierarchy of id, each named after its ID, as the disk lookup expects
+func inHierarchyPlace(path, id string) bool {
+	ids := domain.GetIDHierarchy(id)
+	parts := splitPath(path)
+	if len(parts) != len(ids) {
+		return false
+	}
+	for i, part := range parts {
+		if !strings.HasPrefix(part, ids[i]+" ") {
+			return false
+		}
+	}
+	return true
+}
+
+// searchIndex is Search backed by the index. Entries removed since the last
+// sync are dropped; entries added since then are missing until the next sync.
+func (r *Repository) searchIndex(query string) ([]domain.SearchResult, error) {
+	nodes, err := r.index.SearchNames(query)
+	if err != nil {
+		return nil, err
+	}
+
+	// Visit matches in the same order as a walk so the first match per ID wins
+	slices.SortFunc(nodes, func(a, b domain.IndexNode) int {
+		return slices.Compare(splitPath(a.Path), splitPath(b.Path))
+	})
+
+	c := newSearchCollector()
+	for _, node := range nodes {
+		path := filepath.Join(r.vaultPath, node.Path)
+		if _, err := os.Lstat(path); err != nil {
+			continue
+		}
+		c.add(r, path, filepath.Base(path), node.IsDir)
+	}
+	return c.results, nil
+}
+
+// splitPath splits a relative path into its components
+func splitPath(path string) []string {
+	return strings.Split(path, string(filepath.Separator))
+}
+
+// Sync brings the index up to date with changes made outside libraio. It holds
+// the vault lock meanwhile, so it never sees one of libraio's own operations
+// half done. Without an index there is nothing to sync.
+func (r *Repository) Sync(progress domain.SyncProgressFunc) (*domain.SyncStats, error) {
+	if r.index == nil {
+		return &domain.SyncStats{}, nil
+	}
+	unlock, err := r.lockVault()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	return r.index.Sync(progress)
+}
+
+// SyncFull rebuilds the index from scratch, holding the vault lock like Sync.
+// Without an index there is nothing to rebuild.
+func (r *Repository) SyncFull() (*domain.SyncStats, error) {
+	if r.index == nil {
+		return &domain.SyncStats{}, nil
+	}
+	unlock, err := r.lockVault()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	return r.index.SyncFull()
+}
+
+// withIndexTx runs fn in an index transaction; it does nothing without an index
+func (r *Repository) withIndexTx(fn func(tx ports.IndexTx) error) error {
+	if r.index == nil {
+		return nil
+	}
+	tx, err := r.index.BeginTx()
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// indexMove records that the entry at oldPath, with everything beneath it, now
+// lives at newPath. The new entry and both parent folders are refreshed from disk.
+func (r *Repository) indexMove(oldPath, newPath string) error {
+	return r.withIndexTx(func(tx ports.IndexTx) error {
+		if err := tx.RenameNode(r.relPath(oldPath), r.relPath(newPath)); err != nil {
+			return err
+		}
+		return r.indexEntries(tx, newPath, filepath.Dir(oldPath), filepath.Dir(newPath))
+	})
+}
+
+// indexMoveReport is indexMove for operations that report failures as warnings
+func (r *Repository) indexMoveReport(oldPath, newPath string, report *domain.OperationReport) {
+	if err := r.indexMove(oldPath, newPath); err != nil {
+		report.Warnf("failed to update index for %s: %v", r.relPath(newPath), err)
+	}
+}
+
+// indexAdd indexes a new entry with everything beneath it, and refreshes its
+// parent. Failures are reported as warnings, like indexMoveReport.
+func (r *Repository) indexAdd(path string, report *domain.OperationReport) {
+	err := r.withIndexTx(func(tx ports.IndexTx) error {
+		err := filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() && strings.HasPrefix(info.Name(), ".") && p != path {
+				return filepath.SkipDir
+			}
+			return r.indexEntry(tx, p, info)
+		})
+		if err != nil {
+			return err
+		}
+		return r.indexEntries(tx, filepath.Dir(path))
+	})
+	if err != nil {
+		report.Warnf("failed to update index for %s: %v", r.relPath(path), err)
+	}
+}
+
+// indexRestored refreshes files written back in place, along with every folder
+// above them, since restoring may have recreated folders. Failures are reported
+// as warnings.
+func (r *Repository) indexRestored(paths []string, report *domain.OperationReport) {
+	err := r.withIndexTx(func(tx ports.IndexTx) error {
+		seen := make(map[string]bool)
+		for _, path := range paths {
+			for p := path; !seen[p]; p = filepath.Dir(p) {
+				seen[p] = true
+				if err := r.indexEntries(tx, p); err != nil {
+					return err
+				}
+				if p == r.vaultPath || p == filepath.Dir(p) {
+					break
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		report.Warnf("failed to update index for restored files: %v", err)
+	}
+}
+
+// indexRemove drops an entry and everything beneath it, and refreshes its
+// parent. Failures are reported as warnings.
+func (r *Repository) indexRemove(path string, report *domain.OperationReport) {
+	err := r.withIndexTx(func(tx ports.IndexTx) error {
+		if err := tx.DeleteNode(r.relPath(path)); err != nil {
+			return err
+		}
+		return r.indexEntries(tx, filepath.Dir(path))
+	})
+	if err != nil {
+		report.Warnf("failed to update index for %s: %v", r.relPath(path), err)
+	}
+}
+
+// indexRewrites refreshes the links and content of every file whose links an
+// operation rewrote. The index is a cache, so failures are only warnings.
+func (r *Repository) indexRewrites(report *domain.OperationReport) {
+	err := r.withIndexTx(func(tx ports.IndexTx) error {
+		// Files are replaced by rename, which changes their folder's
+		// modification time; refresh the folders too so listings stay cached
+		var paths []string
+		seen := make(map[string]bool)
+		for _, fr := range report.FilesRewritten {
+			paths = append(paths, filepath.Join(r.vaultPath, fr.Path))
+			if dir := filepath.Dir(fr.Path); !seen[dir] {
+				seen[dir] = true
+				paths = append(paths, filepath.Join(r.vaultPath, dir))
+			}
+		}
+		return r.indexEntries(tx, paths...)
+	})
+	if err != nil {
+		report.Warnf("failed to update index: %v", err)
+	}
+}
+
+// indexEntries refreshes entries from disk, dropping those that no longer exist
+func (r *Repository) indexEntries(tx ports.IndexTx, paths ...string) error {
+	for _, path := range paths {
+		info, err := os.Lstat(path)
+		if os.IsNotExist(err) {
+			if err := tx.DeleteNode(r.relPath(path)); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := r.indexEntry(tx, path, info); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// indexEntry writes a single entry, as a full sync would: folders with a JD ID
+// are stored by ID and description, and notes and canvases with links and content
+func (r *Repository) indexEntry(tx ports.IndexTx, path string, info os.FileInfo) error {
+	node := &domain.IndexNode{
+		Path:  r.relPath(path),
+		Name:  info.Name(),
+		IsDir: info.IsDir(),
+		Mtime: info.ModTime().UnixNano(),
+	}
+	if node.IsDir && node.Path != "." {
+		if id := domain.ExtractID(info.Name()); domain.ParseIDType(id) != domain.IDTypeUnknown {
+			node.JDID = id
+			node.JDType = domain.ParseIDType(id)
+			if description := domain.ExtractDescription(info.Name()); description != "" {
+				node.Name = description
+			}
+		}
+	}
+	if err := tx.UpsertNode(node); err != nil {
+		return err
+	}
+
+	if node.IsDir || !domain.IsLinkSource(info.Name()) {
+		return nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := tx.DeleteEdgesFromFile(node.Path); err != nil {
+		return err
+	}
+	for _, edge := range domain.ParseLinks(content, node.Path) {
+		if err := tx.InsertEdge(&edge); err != nil {
+			return err
+		}
+	}
+	return tx.UpdateContent(node.Path, content)
+}
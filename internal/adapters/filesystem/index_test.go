@@ -0,0 +1,189 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"libraio/internal/adapters/sqlite"
+	"libraio/internal/domain"
+)
+
+// setupIndexedRepo opens a fully synced index for vaultPath, stored in a temp data dir
+func setupIndexedRepo(t *testing.T, vaultPath string) (*Repository, *sqlite.Index) {
+	t.Helper()
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	index := sqlite.NewIndex()
+	if err := index.Open(vaultPath); err != nil {
+		t.Fatalf("failed to open index: %v", err)
+	}
+	t.Cleanup(func() { index.Close() })
+
+	if _, err := index.SyncFull(); err != nil {
+		t.Fatalf("SyncFull failed: %v", err)
+	}
+	return NewRepository(vaultPath, WithIndex(index)), index
+}
+
+// upsertIndexNode writes a node to the index without touching the disk
+func upsertIndexNode(t *testing.T, index *sqlite.Index, node *domain.IndexNode) {
+	t.Helper()
+	tx, err := index.BeginTx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.UpsertNode(node); err != nil {
+		tx.Rollback()
+		t.Fatal(err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestListItems_UsesIndexUntilFolderChanges(t *testing.T) {
+	vaultPath, cleanup := setupLinkTestVault(t)
+	defer cleanup()
+	repo, index := setupIndexedRepo(t, vaultPath)
+
+	// A node only the index knows about shows the listing comes from the index
+	categoryRel := filepath.Join("S01 Personal", "S01.10-19 Lifestyle", "S01.11 Entertainment")
+	upsertIndexNode(t, index, &domain.IndexNode{
+		Path:   filepath.Join(categoryRel, "S01.11.20 Phantom"),
+		JDID:   "S01.11.20",
+		JDType: domain.IDTypeItem,
+		Name:   "Phantom",
+		IsDir:  true,
+	})
+
+	items, err := repo.ListItems("S01.11")
+	if err != nil {
+		t.Fatalf("ListItems failed: %v", err)
+	}
+	if !containsItem(items, "S01.11.20") {
+		t.Errorf("fresh category should be listed from the index, got %+v", items)
+	}
+
+	// Adding a folder on disk changes the category's mtime, so the disk is read instead
+	if err := os.Mkdir(filepath.Join(vaultPath, categoryRel, "S01.11.17 Opera"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	items, err = repo.ListItems("S01.11")
+	if err != nil {
+		t.Fatalf("ListItems failed: %v", err)
+	}
+	if containsItem(items, "S01.11.20") || !containsItem(items, "S01.11.17") {
+		t.Errorf("stale category should be listed from disk, got %+v", items)
+	}
+}
+
+func containsItem(items []domain.Item, id string) bool {
+	for _, item := range items {
+		if item.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func TestGetPath_FallsBackAfterExternalRename(t *testing.T) {
+	vaultPath, cleanup := setupLinkTestVault(t)
+	defer cleanup()
+	repo, _ := setupIndexedRepo(t, vaultPath)
+
+	categoryPath := filepath.Join(vaultPath, "S01 Personal", "S01.10-19 Lifestyle", "S01.11 Entertainment")
+	path, err := repo.GetPath("S01.11.15")
+	if err != nil || path != filepath.Join(categoryPath, "S01.11.15 Theatre") {
+		t.Fatalf("GetPath = %q, %v", path, err)
+	}
+
+	renamed := filepath.Join(categoryPath, "S01.11.15 Drama")
+	if err := os.Rename(filepath.Join(categoryPath, "S01.11.15 Theatre"), renamed); err != nil {
+		t.Fatal(err)
+	}
+	path, err = repo.GetPath("S01.11.15")
+	if err != nil || path != renamed {
+		t.Errorf("GetPath after rename = %q, %v; want %q", path, err, renamed)
+	}
+
+	if _, err := repo.GetPath("S01.11.99"); err == nil {
+		t.Error("expected error for missing item")
+	}
+}
+
+func TestLoadChildren_ItemFilesFromIndex(t *testing.T) {
+	vaultPath, cleanup := setupLinkTestVault(t)
+	defer cleanup()
+	itemPath := filepath.Join(vaultPath, "S01 Personal", "S01.10-19 Lifestyle", "S01.11 Entertainment", "S01.11.15 Theatre")
+	os.WriteFile(filepath.Join(itemPath, "poster.png"), []byte("png"), 0644)
+	os.Mkdir(filepath.Join(itemPath, "attachments"), 0755)
+	repo, _ := setupIndexedRepo(t, vaultPath)
+
+	node := &domain.TreeNode{Type: domain.IDTypeItem, ID: "S01.11.15", Path: itemPath}
+	if err := repo.LoadChildren(node); err != nil {
+		t.Fatalf("LoadChildren failed: %v", err)
+	}
+
+	var names []string
+	for _, child := range node.Children {
+		names = append(names, child.Name)
+	}
+	if len(names) != 2 || names[0] != "README.md" || names[1] != "poster.png" {
+		t.Errorf("expected README.md and poster.png, got %v", names)
+	}
+}
+
+func TestSearch_IndexMatchesDisk(t *testing.T) {
+	vaultPath, cleanup := setupLinkTestVault(t)
+	defer cleanup()
+	indexed, _ := setupIndexedRepo(t, vaultPath)
+	disk := NewRepository(vaultPath)
+
+	for _, query := range []string{"theatre", "S01.11", "links", "README", "notes", "archive", "zzz"} {
+		t.Run(query, func(t *testing.T) {
+			want, err := disk.Search(query)
+			if err != nil {
+				t.Fatalf("disk Search failed: %v", err)
+			}
+			got, err := indexed.Search(query)
+			if err != nil {
+				t.Fatalf("indexed Search failed: %v", err)
+			}
+			if len(got) != len(want) {
+				t.Fatalf("got %d results %+v, want %d %+v", len(got), got, len(want), want)
+			}
+			for i := range want {
+				if got[i] != want[i] {
+					t.Errorf("result %d: got %+v, want %+v", i, got[i], want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGetPath_IgnoresArchivedCopyOfReusedID(t *testing.T) {
+	vaultPath, cleanup := setupCategoryToAreaArchiveVault(t)
+	defer cleanup()
+	repo, _ := setupIndexedRepo(t, vaultPath)
+
+	if _, _, err := repo.ArchiveCategoryToArea("S01.11"); err != nil {
+		t.Fatalf("ArchiveCategoryToArea failed: %v", err)
+	}
+	live := filepath.Join(vaultPath, "S01 Personal", "S01.10-19 Lifestyle", "S01.11 Games")
+	if err := os.Mkdir(live, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.Sync(nil); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	// The archived category and its items keep their IDs in the index
+	path, err := repo.GetPath("S01.11")
+	if err != nil || path != live {
+		t.Errorf("GetPath(S01.11) = %q, %v; want the reused category %q", path, err, live)
+	}
+	if path, err := repo.GetPath("S01.11.15"); err == nil {
+		t.Errorf("GetPath(S01.11.15) = %q, want the archived item not found", path)
+	}
+}
@@ -0,0 +1,231 @@
+package filesystem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"libraio/internal/domain"
+)
+
+const (
+	// DefaultLockTimeout is how long a mutation waits for another process to release the vault lock
+	DefaultLockTimeout = 5 * time.Second
+
+	// WaitForever makes mutations wait indefinitely for the vault lock
+	WaitForever time.Duration = -1
+
+	// lockStaleAfter is the age after which a lock is considered abandoned
+	// when its holder cannot be checked, e.g. it was taken on another host.
+	// A live holder on this host keeps its lock however long it runs.
+	lockStaleAfter = 10 * time.Minute
+
+	lockPollInterval = 100 * time.Millisecond
+)
+
+// appDir is the vault-relative folder holding libraio's own state.
+// It is hidden so that listing, search and link rewriting skip it.
+var appDir = ".libraio"
+
+// lockFile is the vault-relative path of the advisory lock file
+var lockFile = filepath.Join(appDir, "lock")
+
+// makeAppDir creates the folder holding libraio's state, with a .gitignore
+// so that committing a vault kept in git never stages the lock, snapshots or
+// trash. Vaults whose folder predates the .gitignore get one on next use.
+func makeAppDir(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	ignore := filepath.Join(dir, ".gitignore")
+	if _, err := os.Stat(ignore); !os.IsNotExist(err) {
+		return err
+	}
+	return os.WriteFile(ignore, []byte("*\n"), 0644)
+}
+
+// WithLockTimeout sets how long mutating operations wait for the vault lock.
+// Zero fails immediately if another process holds it; WaitForever never gives up.
+func WithLockTimeout(timeout time.Duration) RepoOption {
+	return func(r *Repository) {
+		r.lock.timeout = timeout
+	}
+}
+
+// vaultLock is an advisory lock shared by every process that mutates a vault.
+// Within a process, mutations are serialized by a mutex; across processes,
+// by the exclusive creation of a lock file recording the holder.
+type vaultLock struct {
+	path    string
+	timeout time.Duration
+	mu      sync.Mutex
+	holder  atomic.Pointer[domain.LockInfo] // Written to the lock file by this process, nil when not held
+}
+
+func newVaultLock(vaultPath string) *vaultLock {
+	return &vaultLock{
+		path:    filepath.Join(vaultPath, lockFile),
+		timeout: DefaultLockTimeout,
+	}
+}
+
+// lockVault acquires the vault lock and returns a function that releases it
+func (r *Repository) lockVault() (func(), error) {
+	return r.lock.acquire()
+}
+
+// acquire takes the lock, waiting up to the configured timeout.
+// Stale locks left behind by dead processes are broken automatically.
+func (l *vaultLock) acquire() (func(), error) {
+	var deadline time.Time
+	if l.timeout > 0 {
+		deadline = time.Now().Add(l.timeout)
+	}
+
+	for !l.mu.TryLock() {
+		if !l.canWait(deadline) {
+			return nil, &domain.VaultLockedError{Holder: l.busyHolder()}
+		}
+		time.Sleep(lockPollInterval)
+	}
+
+	for {
+		err := l.create()
+		if err == nil {
+			return l.release, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			l.mu.Unlock()
+			return nil, fmt.Errorf("failed to acquire vault lock: %w", err)
+		}
+
+		holder, err := readLockInfo(l.path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue // Released between our attempt and the read
+		}
+		if err != nil {
+			// Being written right now, or corrupt: judge it by the file's age
+			info, statErr := os.Stat(l.path)
+			if statErr != nil {
+				continue
+			}
+			holder = domain.LockInfo{Command: "unknown process", AcquiredAt: info.ModTime()}
+		}
+
+		if isStaleLock(holder) && l.breakStale() == nil {
+			continue
+		}
+
+		if !l.canWait(deadline) {
+			l.mu.Unlock()
+			return nil, &domain.VaultLockedError{Holder: holder}
+		}
+		time.Sleep(lockPollInterval)
+	}
+}
+
+// canWait reports whether there is time left before the deadline
+func (l *vaultLock) canWait(deadline time.Time) bool {
+	if l.timeout < 0 {
+		return true
+	}
+	return l.timeout > 0 && time.Now().Before(deadline)
+}
+
+// busyHolder describes who keeps the lock from another operation in this
+// process: the holder this process recorded, or the one a pending operation
+// of this process is waiting on
+func (l *vaultLock) busyHolder() domain.LockInfo {
+	if holder := l.holder.Load(); holder != nil {
+		return *holder
+	}
+	if holder, err := readLockInfo(l.path); err == nil {
+		return holder
+	}
+	return domain.LockInfo{PID: os.Getpid(), Command: "another operation in this process"}
+}
+
+// create exclusively creates the lock file and records this process as the holder
+func (l *vaultLock) create() error {
+	if err := makeAppDir(filepath.Dir(l.path)); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(l.path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+
+	holder := currentLockInfo()
+	err = json.NewEncoder(f).Encode(holder)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(l.path)
+		return err
+	}
+	l.holder.Store(&holder)
+	return nil
+}
+
+// release removes the lock file and lets other goroutines in this process proceed
+func (l *vaultLock) release() {
+	l.holder.Store(nil)
+	os.Remove(l.path)
+	l.mu.Unlock()
+}
+
+// breakStale removes an abandoned lock file. Renaming it first ensures that
+// only one of several competing processes removes it, and never a fresh lock.
+func (l *vaultLock) breakStale() error {
+	stale := fmt.Sprintf("%s.stale-%d-%d", l.path, os.Getpid(), time.Now().UnixNano())
+	if err := os.Rename(l.path, stale); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil // Someone else already broke or released it
+		}
+		return err
+	}
+	return os.Remove(stale)
+}
+
+// isStaleLock reports whether the holder is gone. A holder on this host is
+// checked directly; one that cannot be checked, on another host or without a
+// PID, is judged by how long it has held the lock.
+func isStaleLock(holder domain.LockInfo) bool {
+	host, _ := os.Hostname()
+	if canProbeProcesses && holder.PID > 0 && holder.Host == host {
+		return !processAlive(holder.PID)
+	}
+	return time.Since(holder.AcquiredAt) > lockStaleAfter
+}
+
+// currentLockInfo describes this process as a lock holder
+func currentLockInfo() domain.LockInfo {
+	host, _ := os.Hostname()
+	return domain.LockInfo{
+		PID:        os.Getpid(),
+		Host:       host,
+		Command:    filepath.Base(os.Args[0]),
+		AcquiredAt: time.Now(),
+	}
+}
+
+// readLockInfo reads the holder recorded in a lock file
+func readLockInfo(path string) (domain.LockInfo, error) {
+	var info domain.LockInfo
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return info, err
+	}
+	if err := json.Unmarshal(data, &info); err != nil {
+		return info, fmt.Errorf("failed to parse lock file: %w", err)
+	}
+	return info, nil
+}
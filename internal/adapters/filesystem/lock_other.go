@@ -0,0 +1,12 @@
+//go:build !unix
+
+package filesystem
+
+// canProbeProcesses reports whether processAlive can tell a dead process
+const canProbeProcesses = false
+
+// processAlive cannot probe processes on this platform, so it assumes the
+// holder is alive and leaves stale detection to the lock's age
+func processAlive(pid int) bool {
+	return true
+}
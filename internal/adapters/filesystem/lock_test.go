@@ -0,0 +1,201 @@
+package filesystem
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"libraio/internal/domain"
+)
+
+func TestLock_ReleasedAfterMutation(t *testing.T) {
+	vaultPath, cleanup := setupTestVault(t)
+	defer cleanup()
+
+	repo := NewRepository(vaultPath)
+
+	if _, _, err := repo.CreateCategory("S01.10-19", "Entertainment"); err != nil {
+		t.Fatalf("CreateCategory failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(vaultPath, lockFile)); !os.IsNotExist(err) {
+		t.Error("lock file should be removed after the operation")
+	}
+}
+
+func TestLock_NoWaitFailsWhileHeld(t *testing.T) {
+	vaultPath, cleanup := setupTestVault(t)
+	defer cleanup()
+
+	holder := NewRepository(vaultPath)
+	unlock, err := holder.lockVault()
+	if err != nil {
+		t.Fatalf("lockVault failed: %v", err)
+	}
+	defer unlock()
+
+	// A second repository stands in for another process
+	other := NewRepository(vaultPath, WithLockTimeout(0))
+	_, _, err = other.CreateCategory("S01.10-19", "Entertainment")
+	if !errors.Is(err, domain.ErrVaultLocked) {
+		t.Fatalf("expected ErrVaultLocked, got %v", err)
+	}
+
+	var lockedErr *domain.VaultLockedError
+	if !errors.As(err, &lockedErr) || lockedErr.Holder.PID != os.Getpid() {
+		t.Errorf("expected holder PID %d in error, got %v", os.Getpid(), err)
+	}
+
+	if _, err := os.Stat(filepath.Join(vaultPath, "S01 Test", "S01.10-19 TestArea", "S01.11 Entertainment")); !os.IsNotExist(err) {
+		t.Error("category should not be created while the vault is locked")
+	}
+}
+
+func TestLock_WaitsForRelease(t *testing.T) {
+	vaultPath, cleanup := setupTestVault(t)
+	defer cleanup()
+
+	holder := NewRepository(vaultPath)
+	unlock, err := holder.lockVault()
+	if err != nil {
+		t.Fatalf("lockVault failed: %v", err)
+	}
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		unlock()
+	}()
+
+	other := NewRepository(vaultPath, WithLockTimeout(5*time.Second))
+	if _, _, err := other.CreateCategory("S01.10-19", "Entertainment"); err != nil {
+		t.Fatalf("CreateCategory should succeed once the lock is released: %v", err)
+	}
+}
+
+func TestLock_BreaksStaleLocks(t *testing.T) {
+	host, _ := os.Hostname()
+
+	// Run a short-lived process so we have a PID that is known to be dead
+	proc := exec.Command("true")
+	if err := proc.Run(); err != nil {
+		t.Skipf("cannot run helper process: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		holder domain.LockInfo
+	}{
+		{
+			name:   "dead process on this host",
+			holder: domain.LockInfo{PID: proc.Process.Pid, Host: host, Command: "true", AcquiredAt: time.Now()},
+		},
+		{
+			name:   "abandoned on another host",
+			holder: domain.LockInfo{PID: 1, Host: "elsewhere", Command: "libraio", AcquiredAt: time.Now().Add(-time.Hour)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vaultPath, cleanup := setupTestVault(t)
+			defer cleanup()
+
+			lockPath := filepath.Join(vaultPath, lockFile)
+			if err := os.MkdirAll(filepath.Dir(lockPath), 0755); err != nil {
+				t.Fatalf("failed to create lock dir: %v", err)
+			}
+			data, _ := json.Marshal(tt.holder)
+			if err := os.WriteFile(lockPath, data, 0644); err != nil {
+				t.Fatalf("failed to write lock file: %v", err)
+			}
+
+			repo := NewRepository(vaultPath, WithLockTimeout(0))
+			if _, _, err := repo.CreateCategory("S01.10-19", "Entertainment"); err != nil {
+				t.Fatalf("stale lock should be broken, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestLock_LiveLockOnAnotherHostIsRespected(t *testing.T) {
+	vaultPath, cleanup := setupTestVault(t)
+	defer cleanup()
+
+	lockPath := filepath.Join(vaultPath, lockFile)
+	os.MkdirAll(filepath.Dir(lockPath), 0755)
+	data, _ := json.Marshal(domain.LockInfo{PID: 1, Host: "elsewhere", Command: "libraio", AcquiredAt: time.Now()})
+	if err := os.WriteFile(lockPath, data, 0644); err != nil {
+		t.Fatalf("failed to write lock file: %v", err)
+	}
+
+	repo := NewRepository(vaultPath, WithLockTimeout(0))
+	if _, _, err := repo.CreateCategory("S01.10-19", "Entertainment"); !errors.Is(err, domain.ErrVaultLocked) {
+		t.Fatalf("expected ErrVaultLocked, got %v", err)
+	}
+}
+
+func TestLock_LiveLocalHolderIsNeverStale(t *testing.T) {
+	if !canProbeProcesses {
+		t.Skip("cannot probe processes on this platform")
+	}
+	host, _ := os.Hostname()
+
+	// A long sync on a large vault holds the lock well past lockStaleAfter
+	holder := domain.LockInfo{PID: os.Getpid(), Host: host, Command: "libraio", AcquiredAt: time.Now().Add(-time.Hour)}
+	if isStaleLock(holder) {
+		t.Error("a live process on this host should keep its lock however old")
+	}
+	if holder.PID = 0; !isStaleLock(holder) {
+		t.Error("an old lock without a PID should be stale")
+	}
+}
+
+func TestLock_BusyInProcessReportsRecordedHolder(t *testing.T) {
+	vaultPath, cleanup := setupTestVault(t)
+	defer cleanup()
+
+	repo := NewRepository(vaultPath, WithLockTimeout(0))
+	unlock, err := repo.lockVault()
+	if err != nil {
+		t.Fatalf("lockVault failed: %v", err)
+	}
+	defer unlock()
+	recorded, err := readLockInfo(filepath.Join(vaultPath, lockFile))
+	if err != nil {
+		t.Fatalf("failed to read lock file: %v", err)
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	_, err = repo.lockVault()
+	var lockedErr *domain.VaultLockedError
+	if !errors.As(err, &lockedErr) {
+		t.Fatalf("expected VaultLockedError, got %v", err)
+	}
+	if !lockedErr.Holder.AcquiredAt.Equal(recorded.AcquiredAt) || lockedErr.Holder.Command != recorded.Command {
+		t.Errorf("holder = %+v, want the one that took the lock, %+v", lockedErr.Holder, recorded)
+	}
+}
+
+func TestLock_SyncsWaitForMutations(t *testing.T) {
+	vaultPath, cleanup := setupTestVault(t)
+	defer cleanup()
+	_, index := setupIndexedRepo(t, vaultPath)
+
+	holder := NewRepository(vaultPath)
+	unlock, err := holder.lockVault()
+	if err != nil {
+		t.Fatalf("lockVault failed: %v", err)
+	}
+	defer unlock()
+
+	repo := NewRepository(vaultPath, WithIndex(index), WithLockTimeout(0))
+	if _, err := repo.Sync(nil); !errors.Is(err, domain.ErrVaultLocked) {
+		t.Errorf("Sync: expected ErrVaultLocked, got %v", err)
+	}
+	if _, err := repo.SyncFull(); !errors.Is(err, domain.ErrVaultLocked) {
+		t.Errorf("SyncFull: expected ErrVaultLocked, got %v", err)
+	}
+}
@@ -0,0 +1,17 @@
+//go:build unix
+
+package filesystem
+
+import (
+	"errors"
+	"syscall"
+)
+
+// canProbeProcesses reports whether processAlive can tell a dead process
+const canProbeProcesses = true
+
+// processAlive reports whether a process with the given PID exists
+func processAlive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
@@ -1,11 +1,15 @@
 package filesystem
 
 import (
+	"cmp"
 	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"libraio/internal/domain"
 	"libraio/internal/ports"
@@ -14,7 +18,14 @@ import (
 // Repository implements ports.VaultRepository using the filesystem
 type Repository struct {
 	vaultPath string
-	index     ports.VaultIndex // Optional cache for faster operations
+	index     ports.VaultIndex     // Optional cache for faster operations
+	lock      *vaultLock           // Serializes mutations across processes
+	vcs       ports.VersionControl // Optional; records each mutation as a commit
+
+	backupDir  string                 // Where snapshots are stored
+	autoBackup bool                   // Snapshot before bulk operations
+	retention  domain.RetentionPolicy // Pruning policy for automatic snapshots
+	keepMtime  bool                   // Keep the modification time of files whose links are rewritten
 }
 
 // RepoOption is a functional option for configuring Repository
@@ -27,39 +38,37 @@ func WithIndex(index ports.VaultIndex) RepoOption {
 	}
 }
 
-// LinkReplacement defines a single link transformation for vault-wide updates
-type LinkReplacement struct {
-	Old     string // Pattern to find (literal string or regex if IsRegex is true)
-	New     string // Replacement string
-	IsRegex bool   // If true, Old is treated as a regex pattern
-}
+// LinkRenames maps the note names that wiki links may use, in lower case since
+// Obsidian resolves names case-insensitively, to the name they should use instead
+type LinkRenames map[string]string
 
 // listEntities is a generic helper that lists entities from a directory.
 // It reads the directory, filters by regex, maps matches to domain objects, and sorts.
 // This eliminates duplication across ListScopes, ListAreas, ListCategories, and ListItems.
 func listEntities[T domain.IDGetter](
+	r *Repository,
 	dirPath string,
 	folderRegex *regexp.Regexp,
 	mapper func(matches []string, entryName string, fullPath string) T,
 ) ([]T, error) {
-	entries, err := os.ReadDir(dirPath)
+	entries, err := r.readDir(dirPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read directory %s: %w", dirPath, err)
 	}
 
 	var entities []T
 	for _, entry := range entries {
-		if !entry.IsDir() {
+		if !entry.isDir {
 			continue
 		}
 
-		matches := folderRegex.FindStringSubmatch(entry.Name())
+		matches := folderRegex.FindStringSubmatch(entry.name)
 		if matches == nil {
 			continue
 		}
 
-		fullPath := filepath.Join(dirPath, entry.Name())
-		entity := mapper(matches, entry.Name(), fullPath)
+		fullPath := filepath.Join(dirPath, entry.name)
+		entity := mapper(matches, entry.name, fullPath)
 		entities = append(entities, entity)
 	}
 
@@ -128,7 +137,11 @@ func NewRepository(vaultPath string, opts ...RepoOption) *Repository {
 		home, _ := os.UserHomeDir()
 		vaultPath = filepath.Join(home, vaultPath[1:])
 	}
-	r := &Repository{vaultPath: vaultPath}
+	r := &Repository{
+		vaultPath: vaultPath,
+		lock:      newVaultLock(vaultPath),
+		backupDir: filepath.Join(vaultPath, backupsDir),
+	}
 	for _, opt := range opts {
 		opt(r)
 	}
@@ -143,6 +156,7 @@ func (r *Repository) VaultPath() string {
 // ListScopes returns all scopes in the vault
 func (r *Repository) ListScopes() ([]domain.Scope, error) {
 	return listEntities(
+		r,
 		r.vaultPath,
 		domain.ScopeFolderRegex,
 		func(matches []string, entryName string, fullPath string) domain.Scope {
@@ -163,6 +177,7 @@ func (r *Repository) ListAreas(scopeID string) ([]domain.Area, error) {
 	}
 
 	return listEntities(
+		r,
 		scopePath,
 		domain.AreaFolderRegex,
 		func(matches []string, entryName string, fullPath string) domain.Area {
@@ -184,6 +199,7 @@ func (r *Repository) ListCategories(areaID string) ([]domain.Category, error) {
 	}
 
 	return listEntities(
+		r,
 		areaPath,
 		domain.CategoryFolderRegex,
 		func(matches []string, entryName string, fullPath string) domain.Category {
@@ -205,6 +221,7 @@ func (r *Repository) ListItems(categoryID string) ([]domain.Item, error) {
 	}
 
 	return listEntities(
+		r,
 		categoryPath,
 		domain.ItemFolderRegex,
 		func(matches []string, entryName string, fullPath string) domain.Item {
@@ -219,84 +236,120 @@ func (r *Repository) ListItems(categoryID string) ([]domain.Item, error) {
 }
 
 // CreateScope creates a new scope in the vault
-func (r *Repository) CreateScope(description string) (*domain.Scope, error) {
+func (r *Repository) CreateScope(description string) (*domain.Scope, *domain.OperationReport, error) {
+	unlock, err := r.lockVault()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer unlock()
+
 	newID, err := r.nextAvailableScopeID()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	folderName := domain.FormatFolderName(newID, description)
 	scopePath := filepath.Join(r.vaultPath, folderName)
 
 	if err := os.MkdirAll(scopePath, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create scope: %w", err)
+		return nil, nil, fmt.Errorf("failed to create scope: %w", err)
 	}
 
-	return &domain.Scope{
+	scope := &domain.Scope{
 		ID:   newID,
 		Name: description,
 		Path: scopePath,
-	}, nil
+	}
+	report := domain.NewOperationReport()
+	r.indexAdd(scopePath, report)
+	if err := r.commitChange("create", "create "+folderName, scopePath); err != nil {
+		return scope, report, fmt.Errorf("created %s but failed to commit: %w", newID, err)
+	}
+	return scope, report, nil
 }
 
 // CreateArea creates a new area in a scope
-func (r *Repository) CreateArea(scopeID, description string) (*domain.Area, error) {
+func (r *Repository) CreateArea(scopeID, description string) (*domain.Area, *domain.OperationReport, error) {
+	unlock, err := r.lockVault()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer unlock()
+
 	scopePath, err := r.findScopePath(scopeID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	newID, err := r.nextAvailableAreaID(scopeID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	folderName := domain.FormatFolderName(newID, description)
 	areaPath := filepath.Join(scopePath, folderName)
 
 	if err := os.MkdirAll(areaPath, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create area: %w", err)
+		return nil, nil, fmt.Errorf("failed to create area: %w", err)
 	}
 
-	return &domain.Area{
+	area := &domain.Area{
 		ID:      newID,
 		Name:    description,
 		Path:    areaPath,
 		ScopeID: scopeID,
-	}, nil
+	}
+	report := domain.NewOperationReport()
+	r.indexAdd(areaPath, report)
+	if err := r.commitChange("create", "create "+folderName, areaPath); err != nil {
+		return area, report, fmt.Errorf("created %s but failed to commit: %w", newID, err)
+	}
+	return area, report, nil
 }
 
 // CreateCategory creates a new category in an area with standard zero items
-func (r *Repository) CreateCategory(areaID, description string) (*domain.Category, error) {
+func (r *Repository) CreateCategory(areaID, description string) (*domain.Category, *domain.OperationReport, error) {
+	unlock, err := r.lockVault()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer unlock()
+
 	areaPath, err := r.findAreaPath(areaID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	newID, err := r.nextAvailableCategoryID(areaID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	folderName := domain.FormatFolderName(newID, description)
 	categoryPath := filepath.Join(areaPath, folderName)
 
 	if err := os.MkdirAll(categoryPath, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create category: %w", err)
+		return nil, nil, fmt.Errorf("failed to create category: %w", err)
 	}
 
 	// Create standard zero items with rollback on failure
 	if err := r.CreateStandardZeros(newID, categoryPath); err != nil {
 		os.RemoveAll(categoryPath)
-		return nil, fmt.Errorf("failed to create standard zeros: %w", err)
+		return nil, nil, fmt.Errorf("failed to create standard zeros: %w", err)
 	}
 
-	return &domain.Category{
+	category := &domain.Category{
 		ID:     newID,
 		Name:   description,
 		Path:   categoryPath,
 		AreaID: areaID,
-	}, nil
+	}
+	report := domain.NewOperationReport()
+	r.indexAdd(categoryPath, report)
+	if err := r.commitChange("create", "create "+folderName, categoryPath); err != nil {
+		return category, report, fmt.Errorf("created %s but failed to commit: %w", newID, err)
+	}
+	return category, report, nil
 }
 
 // CreateStandardZeros creates all standard zero items in a category
@@ -316,66 +369,84 @@ func (r *Repository) CreateStandardZeros(categoryID, categoryPath string) error
 }
 
 // CreateItem creates a new item in a category with a JDex file
-func (r *Repository) CreateItem(categoryID, description string) (*domain.Item, error) {
+func (r *Repository) CreateItem(categoryID, description string) (*domain.Item, *domain.OperationReport, error) {
+	unlock, err := r.lockVault()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer unlock()
+
 	categoryPath, err := r.findCategoryPath(categoryID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	newID, err := r.nextAvailableItemID(categoryID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	folderName := domain.FormatFolderName(newID, description)
 	itemPath := filepath.Join(categoryPath, folderName)
 
 	if err := os.MkdirAll(itemPath, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create item: %w", err)
+		return nil, nil, fmt.Errorf("failed to create item: %w", err)
 	}
 
-	return &domain.Item{
+	item := &domain.Item{
 		ID:         newID,
 		Name:       description,
 		Path:       itemPath,
 		CategoryID: categoryID,
-	}, nil
+	}
+	report := domain.NewOperationReport()
+	r.indexAdd(itemPath, report)
+	if err := r.commitChange("create", "create "+folderName, itemPath); err != nil {
+		return item, report, fmt.Errorf("created %s but failed to commit: %w", newID, err)
+	}
+	return item, report, nil
 }
 
 // MoveItem moves an item to a different category
-func (r *Repository) MoveItem(srcItemID, dstCategoryID string) (*domain.Item, error) {
+func (r *Repository) MoveItem(srcItemID, dstCategoryID string) (*domain.Item, *domain.OperationReport, error) {
+	unlock, err := r.lockVault()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer unlock()
+
 	// Validate source is an item
 	if domain.ParseIDType(srcItemID) != domain.IDTypeItem {
-		return nil, fmt.Errorf("source must be an item, got: %s", srcItemID)
+		return nil, nil, fmt.Errorf("source must be an item, got: %s", srcItemID)
 	}
 
 	// Validate destination is a category
 	if domain.ParseIDType(dstCategoryID) != domain.IDTypeCategory {
-		return nil, fmt.Errorf("destination must be a category, got: %s", dstCategoryID)
+		return nil, nil, fmt.Errorf("destination must be a category, got: %s", dstCategoryID)
 	}
 
 	// Check not moving to same category
 	srcCategoryID, _ := domain.ParseCategory(srcItemID)
 	if srcCategoryID == dstCategoryID {
-		return nil, fmt.Errorf("item is already in category %s", dstCategoryID)
+		return nil, nil, fmt.Errorf("item is already in category %s", dstCategoryID)
 	}
 
 	// Get source path and description
 	srcPath, err := r.GetPath(srcItemID)
 	if err != nil {
-		return nil, fmt.Errorf("source item not found: %w", err)
+		return nil, nil, fmt.Errorf("source item not found: %w", err)
 	}
 	description := domain.ExtractDescription(filepath.Base(srcPath))
 
 	// Get destination category path
 	dstCategoryPath, err := r.findCategoryPath(dstCategoryID)
 	if err != nil {
-		return nil, fmt.Errorf("destination category not found: %w", err)
+		return nil, nil, fmt.Errorf("destination category not found: %w", err)
 	}
 
 	newID, err := r.nextAvailableItemID(dstCategoryID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Create new folder name and path
@@ -384,86 +455,117 @@ func (r *Repository) MoveItem(srcItemID, dstCategoryID string) (*domain.Item, er
 
 	// Move the directory
 	if err := os.Rename(srcPath, dstPath); err != nil {
-		return nil, fmt.Errorf("failed to move item: %w", err)
+		return nil, nil, fmt.Errorf("failed to move item: %w", err)
 	}
 
 	// Update Obsidian links throughout the vault
-	r.updateObsidianLinksWithCache(srcItemID, newID, description)
+	report := domain.NewOperationReport()
+	r.indexMoveReport(srcPath, dstPath, report)
+	r.relink([]domain.NodeMove{r.nodeMove(srcPath, dstPath)}, moveLinkRenames(srcItemID, description, newID, description), report)
+	r.indexRewrites(report)
+	r.commitReport("move", fmt.Sprintf("move %s -> %s", filepath.Base(srcPath), newID), report, srcPath, dstPath)
 
 	return &domain.Item{
 		ID:         newID,
 		Name:       description,
 		Path:       dstPath,
 		CategoryID: dstCategoryID,
-	}, nil
+	}, report, nil
 }
 
 // MoveCategory moves a category to a different area
-func (r *Repository) MoveCategory(srcCategoryID, dstAreaID string) (*domain.Category, error) {
+func (r *Repository) MoveCategory(srcCategoryID, dstAreaID string) (*domain.Category, *domain.OperationReport, error) {
+	unlock, err := r.lockVault()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer unlock()
+
 	// Validate source is a category
 	if domain.ParseIDType(srcCategoryID) != domain.IDTypeCategory {
-		return nil, fmt.Errorf("source must be a category, got: %s", srcCategoryID)
+		return nil, nil, fmt.Errorf("source must be a category, got: %s", srcCategoryID)
 	}
 
 	// Validate destination is an area
 	if domain.ParseIDType(dstAreaID) != domain.IDTypeArea {
-		return nil, fmt.Errorf("destination must be an area, got: %s", dstAreaID)
+		return nil, nil, fmt.Errorf("destination must be an area, got: %s", dstAreaID)
 	}
 
 	// Check not moving to same area
 	srcAreaID, _ := domain.ParseArea(srcCategoryID)
 	if srcAreaID == dstAreaID {
-		return nil, fmt.Errorf("category is already in area %s", dstAreaID)
+		return nil, nil, fmt.Errorf("category is already in area %s", dstAreaID)
 	}
 
 	// Get source path and description
 	srcPath, err := r.GetPath(srcCategoryID)
 	if err != nil {
-		return nil, fmt.Errorf("source category not found: %w", err)
+		return nil, nil, fmt.Errorf("source category not found: %w", err)
 	}
 	description := domain.ExtractDescription(filepath.Base(srcPath))
 
 	// Get destination area path
 	dstAreaPath, err := r.findAreaPath(dstAreaID)
 	if err != nil {
-		return nil, fmt.Errorf("destination area not found: %w", err)
+		return nil, nil, fmt.Errorf("destination area not found: %w", err)
 	}
 
 	newID, err := r.nextAvailableCategoryID(dstAreaID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Create new folder name and path
 	newFolderName := domain.FormatFolderName(newID, description)
 	dstPath := filepath.Join(dstAreaPath, newFolderName)
 
+	snapshotID, err := r.snapshotBefore("move", srcCategoryID, srcPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// Move the directory
 	if err := os.Rename(srcPath, dstPath); err != nil {
-		return nil, fmt.Errorf("failed to move category: %w", err)
+		return nil, nil, fmt.Errorf("failed to move category: %w", err)
 	}
 
-	// Update all item IDs within the category (also updates Obsidian links)
-	r.updateItemIDsInCategory(dstPath, srcCategoryID, newID)
+	report := domain.NewOperationReport()
+	report.Snapshot = snapshotID
+	r.indexMoveReport(srcPath, dstPath, report)
 
-	// Update links to the category itself
-	r.updateObsidianLinksWithCache(srcCategoryID, newID, description)
+	// Update all item IDs within the category, then the links to the category
+	// and its items in one pass
+	moves := []domain.NodeMove{r.nodeMove(srcPath, dstPath)}
+	renames := moveLinkRenames(srcCategoryID, description, newID, description)
+	itemMoves, itemRenames := r.updateItemIDsInCategory(dstPath, newID, report)
+	renames.chain(itemRenames)
+	moves = append(moves, itemMoves...)
+	r.recordSnapshotMoves(snapshotID, moves, report)
+	r.relink(moves, renames, report)
+	r.indexRewrites(report)
+	r.commitReport("move", fmt.Sprintf("move %s -> %s", filepath.Base(srcPath), newID), report, srcPath, dstPath)
 
 	return &domain.Category{
 		ID:     newID,
 		Name:   description,
 		Path:   dstPath,
 		AreaID: dstAreaID,
-	}, nil
+	}, report, nil
 }
 
-// updateItemIDsInCategory updates all item IDs when a category is moved
-func (r *Repository) updateItemIDsInCategory(categoryPath, _, newCategoryID string) {
+// updateItemIDsInCategory updates all item IDs when a category is moved. It
+// returns the renumbering moves and the link renames they need, for the
+// caller to relink with the category's own.
+func (r *Repository) updateItemIDsInCategory(categoryPath, newCategoryID string, report *domain.OperationReport) ([]domain.NodeMove, LinkRenames) {
 	entries, err := os.ReadDir(categoryPath)
 	if err != nil {
-		return
+		report.Errorf("failed to read moved category %s: %v", r.relPath(categoryPath), err)
+		return nil, nil
 	}
 
+	var moves []domain.NodeMove
+	renames := LinkRenames{}
+
 	for _, entry := range entries {
 		if !entry.IsDir() {
 			continue
@@ -493,60 +595,86 @@ func (r *Repository) updateItemIDsInCategory(categoryPath, _, newCategoryID stri
 		newPath := filepath.Join(categoryPath, newFolderName)
 
 		if err := os.Rename(oldPath, newPath); err != nil {
+			report.Errorf("failed to renumber %s to %s: %v", oldItemID, newItemID, err)
 			continue
 		}
-
-		// Update Obsidian links for this item
-		r.updateObsidianLinksWithCache(oldItemID, newItemID, description)
+		r.indexMoveReport(oldPath, newPath, report)
+		moves = append(moves, r.nodeMove(oldPath, newPath))
+		renames.chain(moveLinkRenames(oldItemID, description, newItemID, description))
 	}
+	return moves, renames
 }
 
 // ArchiveItem moves an item to the category's .09 Archive folder
-func (r *Repository) ArchiveItem(srcItemID string) (*domain.Item, error) {
+func (r *Repository) ArchiveItem(srcItemID string) (*domain.Item, *domain.OperationReport, error) {
+	unlock, err := r.lockVault()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer unlock()
+
+	srcPath, err := r.GetPath(srcItemID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("source item not found: %w", err)
+	}
+
+	report := domain.NewOperationReport()
+	item, move, renames, err := r.archiveItem(srcItemID, report)
+	if err != nil {
+		return nil, nil, err
+	}
+	r.relink([]domain.NodeMove{move}, renames, report)
+	r.indexRewrites(report)
+	r.commitReport("archive", "archive "+filepath.Base(srcPath), report, srcPath, item.Path)
+	return item, report, nil
+}
+
+// archiveItem moves an item to its category's archive and returns the move
+// and link renames for the caller to relink; the caller must hold the vault lock
+func (r *Repository) archiveItem(srcItemID string, report *domain.OperationReport) (*domain.Item, domain.NodeMove, LinkRenames, error) {
 	// Validate source is an item
 	if domain.ParseIDType(srcItemID) != domain.IDTypeItem {
-		return nil, fmt.Errorf("source must be an item, got: %s", srcItemID)
+		return nil, domain.NodeMove{}, nil, fmt.Errorf("source must be an item, got: %s", srcItemID)
 	}
 
 	// Check if already an archive item
 	if domain.IsArchiveItem(srcItemID) {
-		return nil, fmt.Errorf("item %s is already an archive item", srcItemID)
+		return nil, domain.NodeMove{}, nil, fmt.Errorf("item %s is already an archive item", srcItemID)
 	}
 
 	// Get the item's category
 	srcCategoryID, err := domain.ParseCategory(srcItemID)
 	if err != nil {
-		return nil, err
+		return nil, domain.NodeMove{}, nil, err
 	}
 
 	// Get archive item ID for this category (.09)
 	archiveItemID, err := domain.ArchiveItemID(srcCategoryID)
 	if err != nil {
-		return nil, err
+		return nil, domain.NodeMove{}, nil, err
 	}
 
 	// Get source path
 	srcPath, err := r.GetPath(srcItemID)
 	if err != nil {
-		return nil, fmt.Errorf("source item not found: %w", err)
+		return nil, domain.NodeMove{}, nil, fmt.Errorf("source item not found: %w", err)
 	}
 	description := domain.ExtractDescription(filepath.Base(srcPath))
 
 	// Get archive item path
 	archivePath, err := r.findItemPath(archiveItemID)
 	if err != nil {
-		return nil, fmt.Errorf("archive item %s not found: %w", archiveItemID, err)
+		return nil, domain.NodeMove{}, nil, fmt.Errorf("archive item %s not found: %w", archiveItemID, err)
 	}
 
 	// Archived items lose their ID - folder is renamed with [Archived] prefix
 	archivedFolderName := "[Archived] " + description
 	dstPath := filepath.Join(archivePath, archivedFolderName)
 	if err := os.Rename(srcPath, dstPath); err != nil {
-		return nil, fmt.Errorf("failed to move item to archive: %w", err)
+		return nil, domain.NodeMove{}, nil, fmt.Errorf("failed to move item to archive: %w", err)
 	}
 
-	// Update Obsidian links throughout the vault
-	r.updateObsidianLinksForArchive(srcItemID, description)
+	r.indexMoveReport(srcPath, dstPath, report)
 
 	// Return the archived item (ID is now empty since it's archived)
 	return &domain.Item{
@@ -554,35 +682,55 @@ func (r *Repository) ArchiveItem(srcItemID string) (*domain.Item, error) {
 		Name:       description,
 		Path:       dstPath,
 		CategoryID: srcCategoryID,
-	}, nil
+	}, r.nodeMove(srcPath, dstPath), archiveLinkRenames(srcItemID, description), nil
 }
 
 // ArchiveCategory moves all non-standard-zero items to the category's .09 Archive folder
-func (r *Repository) ArchiveCategory(srcCategoryID string) ([]*domain.Item, error) {
+func (r *Repository) ArchiveCategory(srcCategoryID string) ([]*domain.Item, *domain.OperationReport, error) {
+	unlock, err := r.lockVault()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer unlock()
+
 	// Validate source is a category
 	if domain.ParseIDType(srcCategoryID) != domain.IDTypeCategory {
-		return nil, fmt.Errorf("source must be a category, got: %s", srcCategoryID)
+		return nil, nil, fmt.Errorf("source must be a category, got: %s", srcCategoryID)
 	}
 
 	// Get archive item ID (.09) for this category
 	archiveItemID, err := domain.ArchiveItemID(srcCategoryID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Verify archive item exists
 	_, err = r.findItemPath(archiveItemID)
 	if err != nil {
-		return nil, fmt.Errorf("archive item %s not found: %w", archiveItemID, err)
+		return nil, nil, fmt.Errorf("archive item %s not found: %w", archiveItemID, err)
 	}
 
 	// Get all items in the category
 	items, err := r.ListItems(srcCategoryID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	categoryPath, err := r.findCategoryPath(srcCategoryID)
+	if err != nil {
+		return nil, nil, err
+	}
+	snapshotID, err := r.snapshotBefore("archive", srcCategoryID, categoryPath)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	var archivedItems []*domain.Item
+	var touched []string
+	var moves []domain.NodeMove
+	renames := LinkRenames{}
+	report := domain.NewOperationReport()
+	report.Snapshot = snapshotID
 
 	// Archive each non-standard-zero item
 	for _, item := range items {
@@ -596,39 +744,57 @@ func (r *Repository) ArchiveCategory(srcCategoryID string) ([]*domain.Item, erro
 		}
 
 		// Archive this item
-		archivedItem, err := r.ArchiveItem(item.ID)
+		archivedItem, move, itemRenames, err := r.archiveItem(item.ID, report)
 		if err != nil {
 			// Continue with other items even if one fails
+			report.Warnf("skipped %s %s: %v", item.ID, item.Name, err)
 			continue
 		}
 		archivedItems = append(archivedItems, archivedItem)
+		touched = append(touched, item.Path, archivedItem.Path)
+		moves = append(moves, move)
+		renames.chain(itemRenames)
 	}
 
-	return archivedItems, nil
+	// Update Obsidian links to every archived item in one pass
+	r.recordSnapshotMoves(snapshotID, moves, report)
+	r.relink(moves, renames, report)
+	r.indexRewrites(report)
+
+	message := fmt.Sprintf("archive %s (%s)", filepath.Base(categoryPath), domain.Pluralize(len(archivedItems), "item"))
+	r.commitReport("archive", message, report, touched...)
+
+	return archivedItems, report, nil
 }
 
 // ArchiveCategoryToArea moves a category to the area's .X0.09 Archive folder
-func (r *Repository) ArchiveCategoryToArea(srcCategoryID string) (*domain.Category, error) {
+func (r *Repository) ArchiveCategoryToArea(srcCategoryID string) (*domain.Category, *domain.OperationReport, error) {
+	unlock, err := r.lockVault()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer unlock()
+
 	// Validate source is a category
 	if domain.ParseIDType(srcCategoryID) != domain.IDTypeCategory {
-		return nil, fmt.Errorf("source must be a category, got: %s", srcCategoryID)
+		return nil, nil, fmt.Errorf("source must be a category, got: %s", srcCategoryID)
 	}
 
 	// Management categories can't be archived
 	if domain.IsAreaManagementCategory(srcCategoryID) {
-		return nil, fmt.Errorf("cannot archive management category %s", srcCategoryID)
+		return nil, nil, fmt.Errorf("cannot archive management category %s", srcCategoryID)
 	}
 
 	// Get the area archive item ID (.X0.09)
 	areaArchiveItemID, err := domain.AreaArchiveItemID(srcCategoryID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Get source category path
 	srcPath, err := r.GetPath(srcCategoryID)
 	if err != nil {
-		return nil, fmt.Errorf("source category not found: %w", err)
+		return nil, nil, fmt.Errorf("source category not found: %w", err)
 	}
 	description := domain.ExtractDescription(filepath.Base(srcPath))
 	folderName := filepath.Base(srcPath)
@@ -636,162 +802,372 @@ func (r *Repository) ArchiveCategoryToArea(srcCategoryID string) (*domain.Catego
 	// Get area archive item path
 	archivePath, err := r.findItemPath(areaArchiveItemID)
 	if err != nil {
-		return nil, fmt.Errorf("area archive item %s not found: %w", areaArchiveItemID, err)
+		return nil, nil, fmt.Errorf("area archive item %s not found: %w", areaArchiveItemID, err)
+	}
+
+	snapshotID, err := r.snapshotBefore("archive", srcCategoryID, srcPath)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	// Move the category folder into the area archive folder
 	dstPath := filepath.Join(archivePath, folderName)
 	if err := os.Rename(srcPath, dstPath); err != nil {
-		return nil, fmt.Errorf("failed to move category to area archive: %w", err)
+		return nil, nil, fmt.Errorf("failed to move category to area archive: %w", err)
 	}
 
 	// Update Obsidian links throughout the vault
-	r.updateObsidianLinks(srcCategoryID, srcCategoryID, description)
+	report := domain.NewOperationReport()
+	report.Snapshot = snapshotID
+	r.indexMoveReport(srcPath, dstPath, report)
+	moves := []domain.NodeMove{r.nodeMove(srcPath, dstPath)}
+	r.recordSnapshotMoves(snapshotID, moves, report)
+	r.relink(moves, moveLinkRenames(srcCategoryID, description, srcCategoryID, description), report)
+	r.indexRewrites(report)
+	r.commitReport("archive", "archive "+folderName, report, srcPath, dstPath)
 
 	return &domain.Category{
 		ID:     srcCategoryID,
 		Name:   description,
 		Path:   dstPath,
 		AreaID: "", // No longer has a direct area parent
-	}, nil
+	}, report, nil
 }
 
-// updateVaultLinks walks the vault and applies link replacements to all markdown files
-func (r *Repository) updateVaultLinks(replacements []LinkReplacement) {
-	filepath.Walk(r.vaultPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil
+// relink rewrites the links broken by moves, given relative to the vault and
+// applied in order, each to the paths the moves before it left: Markdown
+// links and canvas file nodes pointing into a moved entry from elsewhere,
+// relative links out of the notes it holds, wiki links to notes in it written
+// with a folder prefix through it, and the wiki links whose note names
+// renames retargets. Links between files that moved together still work
+// and are left alone. No moves rewrites wiki links only, and nil renames path
+// links only. Each file is read and written once, with every rewrite the
+// moves need; only files the index knows to hold affected links are read, or
+// every note and canvas without an index.
+func (r *Repository) relink(moves []domain.NodeMove, renames LinkRenames, report *domain.OperationReport) {
+	sources := newLinkSources(r, report)
+	if len(moves) > 0 {
+		sources.addPathLinks(moves)
+	}
+	if len(renames) > 0 {
+		sources.addWikiLinks(renames)
+	}
+
+	for _, source := range sources.paths {
+		var rewriter domain.LinkRewriter
+		if len(moves) > 0 {
+			rewriter = pathRewriter(moves, source)
 		}
-
-		if info.IsDir() && strings.HasPrefix(info.Name(), ".") {
-			return filepath.SkipDir
+		if len(renames) > 0 {
+			rename, relocate := renames.rewriter().Wiki, rewriter.Wiki
+			rewriter.Wiki = func(link domain.WikiLink) (domain.WikiLink, bool) {
+				if next, ok := rename(link); ok || relocate == nil {
+					return next, ok
+				}
+				return relocate(link)
+			}
 		}
+		r.rewriteLinksInFile(filepath.Join(r.vaultPath, source), rewriter, report)
+	}
+}
 
-		if info.IsDir() || !strings.HasSuffix(strings.ToLower(info.Name()), ".md") {
-			return nil
-		}
+// nodeMove returns the move of the entry at oldPath to newPath relative to the vault
+func (r *Repository) nodeMove(oldPath, newPath string) domain.NodeMove {
+	return domain.NodeMove{OldPath: r.relPath(oldPath), NewPath: r.relPath(newPath)}
+}
 
-		content, err := os.ReadFile(path)
-		if err != nil {
-			return nil
-		}
+// rewriteLinksInFile rewrites the links in a single file and records the outcome
+func (r *Repository) rewriteLinksInFile(fullPath string, rewriter domain.LinkRewriter, report *domain.OperationReport) {
+	relPath := r.relPath(fullPath)
+
+	content, err := os.ReadFile(fullPath)
+	if err != nil {
+		report.Warnf("skipped %s: %v", relPath, err)
+		return
+	}
 
-		contentStr := string(content)
-		updated := applyLinkReplacements(contentStr, replacements)
+	updated, count := rewriter.Rewrite(relPath, content)
+	if count == 0 {
+		return
+	}
 
-		if updated != contentStr {
-			_ = os.WriteFile(path, []byte(updated), 0644) // Best-effort write
-		}
+	if err := writeFileAtomic(fullPath, updated, r.keepMtime); err != nil {
+		report.Errorf("failed to rewrite links in %s: %v", relPath, err)
+		return
+	}
+	report.AddRewrite(relPath, count)
+}
 
-		return nil
-	})
+// relPath returns a path relative to the vault root, falling back to the input on failure
+func (r *Repository) relPath(path string) string {
+	rel, err := filepath.Rel(r.vaultPath, path)
+	if err != nil {
+		return path
+	}
+	return rel
+}
+
+// idLinkRenames renames links to an entity by ID alone or by full JD name, in
+// every Obsidian form: [[ID]], [[ID Name]], ![[ID Name]], [[ID Name#Heading]],
+// [[ID^block]], [[Folder/ID Name]], [[ID Name|Alias]] and combinations of them
+func idLinkRenames(oldID, description, newName string) LinkRenames {
+	return LinkRenames{
+		strings.ToLower(oldID): newName,
+		strings.ToLower(domain.FormatFolderName(oldID, description)): newName,
+	}
+}
+
+// archiveLinkRenames renames links to an entity by ID or full JD name to its
+// [Archived] folder name
+func archiveLinkRenames(oldID, description string) LinkRenames {
+	return idLinkRenames(oldID, description, "[Archived] "+description)
+}
+
+// moveLinkRenames renames links to an entity by ID or full JD name to the JD
+// name it takes
+func moveLinkRenames(oldID, oldDescription, newID, newDescription string) LinkRenames {
+	return idLinkRenames(oldID, oldDescription, domain.FormatFolderName(newID, newDescription))
+}
+
+// rewriter retargets the wiki links whose note name is renamed, keeping their
+// embed markers, subpaths and aliases
+func (lr LinkRenames) rewriter() domain.LinkRewriter {
+	return domain.LinkRewriter{Wiki: func(link domain.WikiLink) (domain.WikiLink, bool) {
+		name, ok := lr[strings.ToLower(link.Name())]
+		if !ok {
+			return link, false
+		}
+		return link.Retarget(name), true
+	}}
 }
 
-// buildLinkReplacements creates the standard set of wiki link replacements for renaming/moving items.
-// It handles all Obsidian link formats: [[ID Name]], [[ID]], [[ID|Alias]], [[ID Name|Alias]]
-func buildLinkReplacements(oldID, description, newLinkText, newAliasPrefix string) []LinkReplacement {
-	return []LinkReplacement{
-		// [[S01.11.15 Theatre]] -> new link
-		{Old: fmt.Sprintf("[[%s %s]]", oldID, description), New: newLinkText},
-		// [[S01.11.15]] -> new link
-		{Old: fmt.Sprintf("[[%s]]", oldID), New: newLinkText},
-		// [[S01.11.15|Custom]] -> [[new|Custom]]
-		{Old: `\[\[` + regexp.QuoteMeta(oldID) + `\|`, New: newAliasPrefix, IsRegex: true},
-		// [[S01.11.15 Theatre|Custom]] -> [[new|Custom]]
-		{Old: `\[\[` + regexp.QuoteMeta(oldID+" "+description) + `\|`, New: newAliasPrefix, IsRegex: true},
-	}
-}
-
-// applyLinkReplacements applies a set of link replacements to content
-func applyLinkReplacements(content string, replacements []LinkReplacement) string {
-	for _, repl := range replacements {
-		if repl.IsRegex {
-			re := regexp.MustCompile(repl.Old)
-			content = re.ReplaceAllString(content, repl.New)
-		} else {
-			content = strings.ReplaceAll(content, repl.Old, repl.New)
+// chain adds the renames of a later move, following names lr already renames
+// into a name next renames again. Names both rename keep the earlier rename,
+// which links to them took first.
+func (lr LinkRenames) chain(next LinkRenames) {
+	for name, newName := range lr {
+		if renamed, ok := next[strings.ToLower(newName)]; ok {
+			lr[name] = renamed
+		}
+	}
+	for name, newName := range next {
+		if _, ok := lr[name]; !ok {
+			lr[name] = newName
 		}
 	}
-	return content
 }
 
-// updateObsidianLinksForArchive updates all wiki links when archiving (adds [Archived] prefix)
-// e.g., [[S01.11.15 Theatre]] -> [[[Archived] Theatre]], [[S01.11.15]] -> [[[Archived] Theatre]]
-func (r *Repository) updateObsidianLinksForArchive(oldID, description string) {
-	archivedName := "[Archived] " + description
-	newLink := fmt.Sprintf("[[%s]]", archivedName)
-	newAliasPrefix := fmt.Sprintf("[[%s|", archivedName)
+// pathRewriter repoints the path links in the file at source broken by
+// moves applied in order: links into a moved entry, and relative links out of
+// it if source moved with it. Wiki links whose folder prefix runs through a
+// moved entry count as path links.
+func pathRewriter(moves []domain.NodeMove, source string) domain.LinkRewriter {
+	oldSource := source
+	for _, move := range slices.Backward(moves) {
+		oldSource, _ = move.Reverse().Apply(oldSource)
+	}
+	apply := func(path string) (string, bool) {
+		moved := false
+		for _, move := range moves {
+			next, ok := move.Apply(path)
+			path, moved = next, moved || ok
+		}
+		return path, moved
+	}
+	return domain.LinkRewriter{
+		Wiki: func(link domain.WikiLink) (domain.WikiLink, bool) {
+			moved := false
+			for _, move := range moves {
+				next, ok := link.Relocate(move)
+				link, moved = next, moved || ok
+			}
+			return link, moved
+		},
+		Markdown: func(link domain.MarkdownLink) (domain.MarkdownLink, bool) {
+			linkPath, ok := link.Path()
+			if !ok {
+				return link, false
+			}
+			target, ok := domain.ResolveLinkPath(oldSource, linkPath)
+			if !ok {
+				return link, false
+			}
+			target, _ = apply(target)
+			newLinkPath := relinkPath(linkPath, source, target)
+			if newLinkPath == linkPath {
+				return link, false
+			}
+			return link.WithPath(newLinkPath), true
+		},
+		File: func(file string) (string, bool) {
+			target, ok := apply(filepath.FromSlash(file))
+			return filepath.ToSlash(target), ok
+		},
+	}
+}
 
-	r.updateVaultLinks(buildLinkReplacements(oldID, description, newLink, newAliasPrefix))
+// relinkPath returns the link path from the file at source to target, in the
+// style of linkPath: from the vault root if it starts with "/", and otherwise
+// relative, keeping a leading "./"
+func relinkPath(linkPath, source, target string) string {
+	newLinkPath := domain.RelativeLinkPath(source, target)
+	switch {
+	case strings.HasPrefix(linkPath, "/"):
+		newLinkPath = "/" + filepath.ToSlash(target)
+	case strings.HasPrefix(linkPath, "./") && !strings.HasPrefix(newLinkPath, "../"):
+		newLinkPath = "./" + newLinkPath
+	}
+	return newLinkPath
 }
 
-// updateObsidianLinksWithCache updates wiki links using the index if available
-func (r *Repository) updateObsidianLinksWithCache(oldID, newID, description string) {
-	newFullLink := fmt.Sprintf("[[%s %s]]", newID, description)
-	newAliasPrefix := fmt.Sprintf("[[%s %s|", newID, description)
-	replacements := buildLinkReplacements(oldID, description, newFullLink, newAliasPrefix)
+// linkSources collects the notes and canvases a relink reads, in the order
+// found and each once
+type linkSources struct {
+	r      *Repository
+	report *domain.OperationReport
+	paths  []string
+	seen   map[string]bool
+	walked bool // Every note and canvas in the vault was added
+}
 
-	if r.index != nil {
-		// Use indexed lookup for O(k) performance where k = files with links
-		edges, err := r.index.FindLinksToID(oldID)
-		if err == nil && len(edges) > 0 {
-			for _, edge := range edges {
-				fullPath := filepath.Join(r.vaultPath, edge.SourcePath)
-				content, err := os.ReadFile(fullPath)
-				if err != nil {
-					continue
-				}
+func newLinkSources(r *Repository, report *domain.OperationReport) *linkSources {
+	return &linkSources{r: r, report: report, seen: make(map[string]bool)}
+}
 
-				contentStr := string(content)
-				updated := applyLinkReplacements(contentStr, replacements)
+// add records a vault-relative file
+func (s *linkSources) add(relPath string) {
+	if !s.seen[relPath] {
+		s.seen[relPath] = true
+		s.paths = append(s.paths, relPath)
+	}
+}
 
-				if updated != contentStr {
-					_ = os.WriteFile(fullPath, []byte(updated), 0644) // Best-effort write
+// addPathLinks adds the files that may hold path links broken by moves:
+// those the index knows to link into an old path, or every file in the vault
+// without an index, and the files that moved. Note links written with a
+// folder prefix through a moved entry stop resolving once the index follows
+// the move, so files with unresolved prefixed note links are added too.
+func (s *linkSources) addPathLinks(moves []domain.NodeMove) {
+	if s.r.index != nil {
+		var edges []domain.Edge
+		var err error
+		for _, move := range moves {
+			var into []domain.Edge
+			if into, err = s.r.index.FindLinksToPath(move.OldPath); err != nil {
+				break
+			}
+			edges = append(edges, into...)
+		}
+		if err == nil {
+			var unresolved []domain.Edge
+			unresolved, err = s.r.index.FindUnresolvedNoteLinks()
+			for _, edge := range unresolved {
+				if strings.Contains(edge.TargetName, "/") {
+					edges = append(edges, edge)
 				}
 			}
-
-			// Update edge targets in the index
-			if tx, err := r.index.BeginTx(); err == nil {
-				tx.UpdateEdgeTarget(oldID, newID, newFullLink)
-				tx.Commit()
+		}
+		if err == nil {
+			for _, edge := range edges {
+				s.add(edge.SourcePath)
+			}
+			for _, move := range moves {
+				s.walk(filepath.Join(s.r.vaultPath, move.NewPath))
 			}
 			return
 		}
+		s.report.Warnf("index lookup for links to moved entries failed, scanning vault: %v", err)
 	}
+	s.walkVault()
+}
 
-	// Fallback: full vault walk
-	r.updateObsidianLinks(oldID, newID, description)
+// addWikiLinks adds the files holding wiki links to the names renames
+// retargets, found through the index, or every file in the vault without one
+func (s *linkSources) addWikiLinks(renames LinkRenames) {
+	if s.r.index != nil {
+		var sources []string
+		var err error
+		for name := range renames {
+			var edges []domain.Edge
+			if edges, err = s.r.index.FindLinksToName(name); err != nil {
+				break
+			}
+			for _, edge := range edges {
+				sources = append(sources, edge.SourcePath)
+			}
+		}
+		if err == nil {
+			slices.Sort(sources)
+			for _, source := range sources {
+				s.add(source)
+			}
+			return
+		}
+		s.report.Warnf("index lookup for wiki links failed, scanning vault: %v", err)
+	}
+	s.walkVault()
 }
 
-// updateObsidianLinks updates all wiki links in the vault from oldID to newID
-func (r *Repository) updateObsidianLinks(oldID, newID, description string) {
-	newFullLink := fmt.Sprintf("[[%s %s]]", newID, description)
-	newAliasPrefix := fmt.Sprintf("[[%s %s|", newID, description)
+// walkVault adds every note and canvas in the vault
+func (s *linkSources) walkVault() {
+	if !s.walked {
+		s.walked = true
+		s.walk(s.r.vaultPath)
+	}
+}
 
-	r.updateVaultLinks(buildLinkReplacements(oldID, description, newFullLink, newAliasPrefix))
+// walk adds every note and canvas at or beneath root, skipping hidden folders
+// below it
+func (s *linkSources) walk(root string) {
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			s.report.Warnf("skipped %s: %v", s.r.relPath(path), err)
+			return nil
+		}
+		if info.IsDir() && strings.HasPrefix(info.Name(), ".") && path != root {
+			return filepath.SkipDir
+		}
+		if !info.IsDir() && domain.IsLinkSource(info.Name()) {
+			s.add(s.r.relPath(path))
+		}
+		return nil
+	})
+	if err != nil {
+		s.report.Errorf("failed to walk %s: %v", s.r.relPath(root), err)
+	}
 }
 
 // UnarchiveItems restores archived items from an archive folder back to a category
-func (r *Repository) UnarchiveItems(archiveItemID, dstCategoryID string) ([]*domain.Item, error) {
+func (r *Repository) UnarchiveItems(archiveItemID, dstCategoryID string) ([]*domain.Item, *domain.OperationReport, error) {
+	unlock, err := r.lockVault()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer unlock()
+
 	// Get archive item path
 	archivePath, err := r.findItemPath(archiveItemID)
 	if err != nil {
-		return nil, fmt.Errorf("archive item not found: %w", err)
+		return nil, nil, fmt.Errorf("archive item not found: %w", err)
 	}
 
 	// Get destination category path
 	dstCategoryPath, err := r.findCategoryPath(dstCategoryID)
 	if err != nil {
-		return nil, fmt.Errorf("destination category not found: %w", err)
+		return nil, nil, fmt.Errorf("destination category not found: %w", err)
 	}
 
 	// Find all [Archived] folders in the archive item
 	entries, err := os.ReadDir(archivePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read archive: %w", err)
+		return nil, nil, fmt.Errorf("failed to read archive: %w", err)
 	}
 
 	var restoredItems []*domain.Item
+	var touched []string
+	var moves []domain.NodeMove
+	renames := LinkRenames{}
+	report := domain.NewOperationReport()
 	for _, entry := range entries {
 		if !entry.IsDir() {
 			continue
@@ -804,12 +1180,14 @@ func (r *Repository) UnarchiveItems(archiveItemID, dstCategoryID string) ([]*dom
 		// Extract description from "[Archived] Theatre" -> "Theatre"
 		description := domain.ExtractArchivedDescription(entry.Name())
 		if description == "" {
+			report.Warnf("skipped %q: empty description", entry.Name())
 			continue
 		}
 
 		// Get next available item ID
 		newID, err := r.nextAvailableItemID(dstCategoryID)
 		if err != nil {
+			report.Warnf("skipped %q: %v", entry.Name(), err)
 			continue
 		}
 
@@ -819,11 +1197,17 @@ func (r *Repository) UnarchiveItems(archiveItemID, dstCategoryID string) ([]*dom
 		dstPath := filepath.Join(dstCategoryPath, newFolderName)
 
 		if err := os.Rename(srcPath, dstPath); err != nil {
+			report.Warnf("skipped %q: %v", entry.Name(), err)
 			continue
 		}
+		r.indexMoveReport(srcPath, dstPath, report)
 
-		// Update Obsidian links: [[Archived] Theatre]] -> [[S01.11.15 Theatre]]
-		r.updateObsidianLinksForUnarchive(description, newID)
+		// Obsidian links are updated once every item is back:
+		// [[Archived] Theatre]] -> [[S01.11.15 Theatre]]
+		moves = append(moves, r.nodeMove(srcPath, dstPath))
+		renames.chain(LinkRenames{
+			strings.ToLower("[Archived] " + description): domain.FormatFolderName(newID, description),
+		})
 
 		restoredItems = append(restoredItems, &domain.Item{
 			ID:         newID,
@@ -831,33 +1215,32 @@ func (r *Repository) UnarchiveItems(archiveItemID, dstCategoryID string) ([]*dom
 			Path:       dstPath,
 			CategoryID: dstCategoryID,
 		})
+		touched = append(touched, srcPath, dstPath)
 	}
 
 	if len(restoredItems) == 0 {
-		return nil, fmt.Errorf("no archived items found in %s", archiveItemID)
+		return nil, nil, fmt.Errorf("no archived items found in %s", archiveItemID)
 	}
+	r.relink(moves, renames, report)
+	r.indexRewrites(report)
 
-	return restoredItems, nil
-}
-
-// updateObsidianLinksForUnarchive updates wiki links when unarchiving
-func (r *Repository) updateObsidianLinksForUnarchive(description, newID string) {
-	archivedName := "[Archived] " + description
-	newFullLink := fmt.Sprintf("[[%s %s]]", newID, description)
-
-	replacements := []LinkReplacement{
-		{Old: fmt.Sprintf("[[%s]]", archivedName), New: newFullLink},
-		{Old: `\[\[` + regexp.QuoteMeta(archivedName) + `\|`, New: fmt.Sprintf("[[%s %s|", newID, description), IsRegex: true},
-	}
+	message := fmt.Sprintf("unarchive %s -> %s (%s)", filepath.Base(archivePath), dstCategoryID, domain.Pluralize(len(restoredItems), "item"))
+	r.commitReport("unarchive", message, report, touched...)
 
-	r.updateVaultLinks(replacements)
+	return restoredItems, report, nil
 }
 
 // RenameItem renames an item's description (folder and JDex file)
-func (r *Repository) RenameItem(itemID, newDescription string) (*domain.Item, error) {
+func (r *Repository) RenameItem(itemID, newDescription string) (*domain.Item, *domain.OperationReport, error) {
+	unlock, err := r.lockVault()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer unlock()
+
 	srcPath, err := r.findItemPath(itemID)
 	if err != nil {
-		return nil, fmt.Errorf("item not found: %w", err)
+		return nil, nil, fmt.Errorf("item not found: %w", err)
 	}
 
 	oldFolderName := filepath.Base(srcPath)
@@ -865,12 +1248,16 @@ func (r *Repository) RenameItem(itemID, newDescription string) (*domain.Item, er
 	dstPath := filepath.Join(filepath.Dir(srcPath), newFolderName)
 
 	if err := os.Rename(srcPath, dstPath); err != nil {
-		return nil, fmt.Errorf("failed to rename item: %w", err)
+		return nil, nil, fmt.Errorf("failed to rename item: %w", err)
 	}
 
 	// Update Obsidian links
+	report := domain.NewOperationReport()
+	r.indexMoveReport(srcPath, dstPath, report)
 	oldDescription := domain.ExtractDescription(oldFolderName)
-	r.updateObsidianLinksForRename(itemID, oldDescription, newDescription)
+	r.relink([]domain.NodeMove{r.nodeMove(srcPath, dstPath)}, moveLinkRenames(itemID, oldDescription, itemID, newDescription), report)
+	r.indexRewrites(report)
+	r.commitReport("rename", fmt.Sprintf("rename %s -> %s", oldFolderName, newDescription), report, srcPath, dstPath)
 
 	categoryID, _ := domain.ParseCategory(itemID)
 	return &domain.Item{
@@ -878,14 +1265,20 @@ func (r *Repository) RenameItem(itemID, newDescription string) (*domain.Item, er
 		Name:       newDescription,
 		Path:       dstPath,
 		CategoryID: categoryID,
-	}, nil
+	}, report, nil
 }
 
 // RenameCategory renames a category's description (folder only, items keep their IDs)
-func (r *Repository) RenameCategory(categoryID, newDescription string) (*domain.Category, error) {
+func (r *Repository) RenameCategory(categoryID, newDescription string) (*domain.Category, *domain.OperationReport, error) {
+	unlock, err := r.lockVault()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer unlock()
+
 	srcPath, err := r.findCategoryPath(categoryID)
 	if err != nil {
-		return nil, fmt.Errorf("category not found: %w", err)
+		return nil, nil, fmt.Errorf("category not found: %w", err)
 	}
 
 	oldFolderName := filepath.Base(srcPath)
@@ -893,11 +1286,15 @@ func (r *Repository) RenameCategory(categoryID, newDescription string) (*domain.
 	dstPath := filepath.Join(filepath.Dir(srcPath), newFolderName)
 
 	if err := os.Rename(srcPath, dstPath); err != nil {
-		return nil, fmt.Errorf("failed to rename category: %w", err)
+		return nil, nil, fmt.Errorf("failed to rename category: %w", err)
 	}
 
+	report := domain.NewOperationReport()
+	r.indexMoveReport(srcPath, dstPath, report)
 	oldDescription := domain.ExtractDescription(oldFolderName)
-	r.updateObsidianLinksForRename(categoryID, oldDescription, newDescription)
+	r.relink([]domain.NodeMove{r.nodeMove(srcPath, dstPath)}, moveLinkRenames(categoryID, oldDescription, categoryID, newDescription), report)
+	r.indexRewrites(report)
+	r.commitReport("rename", fmt.Sprintf("rename %s -> %s", oldFolderName, newDescription), report, srcPath, dstPath)
 
 	areaID, _ := domain.ParseArea(categoryID)
 	return &domain.Category{
@@ -905,55 +1302,265 @@ func (r *Repository) RenameCategory(categoryID, newDescription string) (*domain.
 		Name:   newDescription,
 		Path:   dstPath,
 		AreaID: areaID,
-	}, nil
+	}, report, nil
 }
 
 // RenameArea renames an area's description (folder only)
-func (r *Repository) RenameArea(areaID, newDescription string) (*domain.Area, error) {
+func (r *Repository) RenameArea(areaID, newDescription string) (*domain.Area, *domain.OperationReport, error) {
+	unlock, err := r.lockVault()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer unlock()
+
 	srcPath, err := r.findAreaPath(areaID)
 	if err != nil {
-		return nil, fmt.Errorf("area not found: %w", err)
+		return nil, nil, fmt.Errorf("area not found: %w", err)
 	}
 
 	newFolderName := domain.FormatFolderName(areaID, newDescription)
 	dstPath := filepath.Join(filepath.Dir(srcPath), newFolderName)
 
 	if err := os.Rename(srcPath, dstPath); err != nil {
-		return nil, fmt.Errorf("failed to rename area: %w", err)
+		return nil, nil, fmt.Errorf("failed to rename area: %w", err)
 	}
 
+	report := domain.NewOperationReport()
+	r.indexMoveReport(srcPath, dstPath, report)
+	r.relink([]domain.NodeMove{r.nodeMove(srcPath, dstPath)}, nil, report)
+	r.indexRewrites(report)
+	r.commitReport("rename", fmt.Sprintf("rename %s -> %s", filepath.Base(srcPath), newDescription), report, srcPath, dstPath)
+
 	scopeID, _ := domain.ParseScope(areaID)
 	return &domain.Area{
 		ID:      areaID,
 		Name:    newDescription,
 		Path:    dstPath,
 		ScopeID: scopeID,
-	}, nil
+	}, report, nil
+}
+
+// RelinkMoves rewrites links that still name folders as they were before being
+// moved or renamed outside libraio, e.g. [[S01.11.15 Theatre]] after the folder
+// was renamed to S01.11.15 Drama. Moves are oldest first, each to where its
+// folder is now. Wiki links only change for moves that change a JD name;
+// relative Markdown links into and out of every moved entry are recomputed.
+func (r *Repository) RelinkMoves(moves []domain.NodeMove) (*domain.OperationReport, error) {
+	unlock, err := r.lockVault()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	report := domain.NewOperationReport()
+	var relinked []string
+	renames := LinkRenames{}
+	steps := make([]domain.NodeMove, len(moves))
+	for i, move := range moves {
+		if oldID, oldDescription, newID, newDescription, ok := move.LinkChange(); ok {
+			renames.chain(moveLinkRenames(oldID, oldDescription, newID, newDescription))
+		}
+		relinked = append(relinked, filepath.Base(move.OldPath)+" -> "+filepath.Base(move.NewPath))
+
+		// relink applies moves in turn, so take the folder back through the
+		// later moves to where this one left it
+		steps[i] = move
+		for _, later := range slices.Backward(moves[i+1:]) {
+			steps[i].NewPath, _ = later.Reverse().Apply(steps[i].NewPath)
+		}
+	}
+
+	r.relink(steps, renames, report)
+	r.indexRewrites(report)
+	if len(relinked) > 0 {
+		r.commitReport("relink", "relink "+strings.Join(relinked, ", "), report)
+	}
+	return report, nil
+}
+
+// BrokenLinks returns the links whose target is gone or has changed identity,
+// as found in the index
+func (r *Repository) BrokenLinks() ([]domain.BrokenLink, error) {
+	if r.index == nil {
+		return nil, fmt.Errorf("broken-link report needs the vault index")
+	}
+	return r.index.FindBrokenLinks()
 }
 
-// updateObsidianLinksForRename updates wiki links when an entity is renamed (same ID, new description)
-func (r *Repository) updateObsidianLinksForRename(id, oldDescription, newDescription string) {
-	newFullLink := fmt.Sprintf("[[%s %s]]", id, newDescription)
-	newAliasPrefix := fmt.Sprintf("[[%s %s|", id, newDescription)
+// FixLinks repoints each fixable broken link at its fix: wiki links take the
+// fix's folder name, keeping any heading, block or alias, and Markdown links
+// and canvas file nodes take its path, written as the link was. Links without
+// a fix are left alone.
+func (r *Repository) FixLinks(links []domain.BrokenLink) (*domain.OperationReport, error) {
+	unlock, err := r.lockVault()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	fixes := make(map[string]map[string]string) // Fix by link text, by source file
+	var sources []string
+	for _, link := range links {
+		if !link.Fixable() {
+			continue
+		}
+		if fixes[link.SourcePath] == nil {
+			fixes[link.SourcePath] = make(map[string]string)
+			sources = append(sources, link.SourcePath)
+		}
+		fixes[link.SourcePath][link.LinkText] = link.Fix
+	}
+
+	if len(sources) == 0 {
+		return domain.NewOperationReport(), nil
+	}
 
-	r.updateVaultLinks(buildLinkReplacements(id, oldDescription, newFullLink, newAliasPrefix))
+	paths := make([]string, len(sources))
+	for i, source := range sources {
+		paths[i] = filepath.Join(r.vaultPath, source)
+	}
+	snapshotID, err := r.snapshotFiles("fix", "links", paths)
+	if err != nil {
+		return nil, err
+	}
+
+	report := domain.NewOperationReport()
+	report.Snapshot = snapshotID
+	for i, source := range sources {
+		r.rewriteLinksInFile(paths[i], linkFixer(source, fixes[source]), report)
+	}
+
+	r.indexRewrites(report)
+	r.commitReport("relink", "fix broken links in "+domain.Pluralize(len(sources), "file"), report)
+	return report, nil
+}
+
+// linkFixer rewrites the links in the file at source whose text has a fix
+func linkFixer(source string, fixes map[string]string) domain.LinkRewriter {
+	return domain.LinkRewriter{
+		Wiki: func(link domain.WikiLink) (domain.WikiLink, bool) {
+			fix, ok := fixes[link.String()]
+			if !ok {
+				return link, false
+			}
+			return link.Retarget(domain.NoteName(fix)), true
+		},
+		Markdown: func(link domain.MarkdownLink) (domain.MarkdownLink, bool) {
+			fix, ok := fixes[link.String()]
+			linkPath, isPath := link.Path()
+			if !ok || !isPath {
+				return link, false
+			}
+			return link.WithPath(relinkPath(linkPath, source, fix)), true
+		},
+		File: func(file string) (string, bool) {
+			fix, ok := fixes[file]
+			return filepath.ToSlash(fix), ok
+		},
+	}
 }
 
-// Delete removes an item, category, area, or scope by ID
-func (r *Repository) Delete(id string) error {
-	path, err := r.GetPath(id)
+// NodeLinks returns the links into and out of a scope, area, category, item
+// or file, as found in the index, each with where it is written. Links within
+// the node are left out.
+func (r *Repository) NodeLinks(node *domain.TreeNode) (*domain.NodeLinks, error) {
+	if r.index == nil {
+		return nil, fmt.Errorf("links panel needs the vault index")
+	}
+	path := r.relPath(node.Path)
+	inside := func(p string) bool {
+		return p == path || strings.HasPrefix(p, path+string(filepath.Separator))
+	}
+
+	incoming, err := r.index.FindLinksToPath(path)
+	if err != nil {
+		return nil, err
+	}
+	if node.Type != domain.IDTypeFile && node.ID != "" {
+		byID, err := r.index.FindLinksToID(node.ID)
+		if err != nil {
+			return nil, err
+		}
+		incoming = append(incoming, byID...)
+	}
+	outgoing, err := r.index.FindLinksFromPath(path)
 	if err != nil {
-		return fmt.Errorf("not found: %w", err)
+		return nil, err
+	}
+
+	links := &domain.NodeLinks{}
+	seen := make(map[domain.Edge]bool)
+	for _, edge := range incoming {
+		if !inside(edge.SourcePath) && !seen[edge] {
+			seen[edge] = true
+			links.Incoming = append(links.Incoming, r.nodeLink(edge))
+		}
+	}
+	for _, edge := range outgoing {
+		if link := r.nodeLink(edge); link.TargetPath == "" || !inside(link.TargetPath) {
+			links.Outgoing = append(links.Outgoing, link)
+		}
 	}
-	return os.RemoveAll(path)
+	sortNodeLinks(links.Incoming)
+	sortNodeLinks(links.Outgoing)
+	return links, nil
 }
 
-// Search searches for files and folders matching the query
+// nodeLink turns an edge into a NodeLink, resolving the folder a wiki link
+// names
+func (r *Repository) nodeLink(edge domain.Edge) domain.NodeLink {
+	link := domain.NodeLink{
+		SourcePath: edge.SourcePath,
+		Line:       edge.Line,
+		Column:     edge.Column,
+		Context:    edge.Context,
+		LinkText:   edge.LinkText,
+		TargetID:   edge.TargetJDID,
+		TargetPath: edge.TargetPath,
+	}
+	if link.TargetPath == "" && link.TargetID != "" {
+		if target, err := r.index.GetNodeByJDID(link.TargetID); err == nil && target != nil {
+			link.TargetPath = target.Path
+		}
+	}
+	return link
+}
+
+// sortNodeLinks orders links by source file, then by position
+func sortNodeLinks(links []domain.NodeLink) {
+	slices.SortFunc(links, func(a, b domain.NodeLink) int {
+		return cmp.Or(strings.Compare(a.SourcePath, b.SourcePath), cmp.Compare(a.Line, b.Line), cmp.Compare(a.Column, b.Column))
+	})
+}
+
+// LinkGraph returns the links between the JD entities of the vault, counted
+// from the index
+func (r *Repository) LinkGraph(opts domain.GraphOptions) (*domain.LinkGraph, error) {
+	if r.index == nil {
+		return nil, fmt.Errorf("link graph needs the vault index")
+	}
+	return r.index.LinkGraph(opts)
+}
+
+// FindStale reviews the JD folders of the vault for a clean-up
+func (r *Repository) FindStale(opts domain.StaleOptions) ([]domain.StaleEntry, error) {
+	if r.index == nil {
+		return nil, fmt.Errorf("stale review needs the vault index")
+	}
+	return r.index.FindStale(opts)
+}
+
+// Search searches for files and folders matching the query. It uses the index
+// when available and falls back to walking the vault.
 func (r *Repository) Search(query string) ([]domain.SearchResult, error) {
 	query = strings.ToLower(query)
-	var results []domain.SearchResult
-	seenIDs := make(map[string]bool) // Deduplicate by ID
+	if r.index != nil {
+		if results, err := r.searchIndex(query); err == nil {
+			return results, nil
+		}
+	}
 
+	c := newSearchCollector()
 	err := filepath.Walk(r.vaultPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return nil // Skip errors
@@ -964,48 +1571,177 @@ func (r *Repository) Search(query string) ([]domain.SearchResult, error) {
 			return filepath.SkipDir
 		}
 
-		name := info.Name()
-		nameNoExt := strings.TrimSuffix(name, filepath.Ext(name))
-
 		// Check if name matches query
-		if !strings.Contains(strings.ToLower(name), query) &&
-			!strings.Contains(strings.ToLower(nameNoExt), query) {
+		if strings.Contains(strings.ToLower(info.Name()), query) {
+			c.add(r, path, info.Name(), info.IsDir())
+		}
+		return nil
+	})
+
+	return c.results, err
+}
+
+// searchCollector accumulates search results, keeping the first match per ID
+type searchCollector struct {
+	results []domain.SearchResult
+	seenIDs map[string]bool
+}
+
+func newSearchCollector() *searchCollector {
+	return &searchCollector{seenIDs: make(map[string]bool)}
+}
+
+// add records a file or folder whose name matched the query
+func (c *searchCollector) add(r *Repository, path, name string, isDir bool) {
+	if isDir {
+		// Match folder names (scopes, areas, categories, items)
+		id := domain.ExtractID(name)
+		idType := domain.ParseIDType(id)
+		if idType != domain.IDTypeUnknown && !c.seenIDs[id] {
+			c.seenIDs[id] = true
+			c.results = append(c.results, domain.SearchResult{
+				Type:        idType,
+				ID:          id,
+				Name:        domain.ExtractDescription(name),
+				Path:        path,
+				MatchedText: name,
+			})
+		}
+		return
+	}
+
+	// Match file names - deduplicate by parent ID
+	id, _ := r.findNearestID(path)
+	if id != "" && !c.seenIDs[id] {
+		c.seenIDs[id] = true
+		c.results = append(c.results, domain.SearchResult{
+			Type:        domain.IDTypeFile,
+			ID:          id,
+			Name:        name,
+			Path:        path,
+			MatchedText: strings.TrimSuffix(name, filepath.Ext(name)),
+		})
+	}
+}
+
+// SearchEntries returns the indexed files and folders a search query matches
+func (r *Repository) SearchEntries(query *domain.SearchQuery, now time.Time) ([]domain.IndexNode, error) {
+	if r.index == nil {
+		return nil, fmt.Errorf("search queries need the vault index")
+	}
+	return r.index.FindEntries(query, now)
+}
+
+// contentSnippetRadius is how many bytes of context surround a match in fallback snippets
+const contentSnippetRadius = 40
+
+// SearchContent searches markdown bodies, best match first. It uses the index's
+// full-text search when available and falls back to scanning every file.
+func (r *Repository) SearchContent(query string, limit int) ([]domain.SearchResult, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, nil
+	}
+
+	var matches []domain.ContentMatch
+	if r.index != nil {
+		var err error
+		if matches, err = r.index.SearchContent(query, limit); err != nil {
+			return nil, err
+		}
+	} else {
+		matches = r.scanContent(query, limit)
+	}
+
+	results := make([]domain.SearchResult, 0, len(matches))
+	for _, m := range matches {
+		path := filepath.Join(r.vaultPath, m.Path)
+		id, _ := r.findNearestID(path)
+		results = append(results, domain.SearchResult{
+			Type:        domain.IDTypeFile,
+			ID:          id,
+			Name:        filepath.Base(path),
+			Path:        path,
+			MatchedText: m.Snippet,
+		})
+	}
+	return results, nil
+}
+
+// scanContent is the unindexed fallback for SearchContent: a file matches if it
+// contains every word of the query, and files with more occurrences rank higher
+func (r *Repository) scanContent(query string, limit int) []domain.ContentMatch {
+	words := strings.Fields(strings.ToLower(query))
+	var matches []domain.ContentMatch
+
+	filepath.Walk(r.vaultPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil // Skip errors
+		}
+		if info.IsDir() && strings.HasPrefix(info.Name(), ".") {
+			return filepath.SkipDir
+		}
+		if info.IsDir() || !strings.HasSuffix(strings.ToLower(info.Name()), ".md") {
 			return nil
 		}
 
-		if info.IsDir() {
-			// Match folder names (scopes, areas, categories, items)
-			id := domain.ExtractID(name)
-			idType := domain.ParseIDType(id)
-			if idType != domain.IDTypeUnknown && !seenIDs[id] {
-				seenIDs[id] = true
-				results = append(results, domain.SearchResult{
-					Type:        idType,
-					ID:          id,
-					Name:        domain.ExtractDescription(name),
-					Path:        path,
-					MatchedText: name,
-				})
-			}
-		} else {
-			// Match file names - deduplicate by parent ID
-			id, _ := r.findNearestID(path)
-			if id != "" && !seenIDs[id] {
-				seenIDs[id] = true
-				results = append(results, domain.SearchResult{
-					Type:        domain.IDTypeFile,
-					ID:          id,
-					Name:        name,
-					Path:        path,
-					MatchedText: nameNoExt,
-				})
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		lower := strings.ToLower(string(content))
+
+		hits := 0
+		for _, w := range words {
+			n := strings.Count(lower, w)
+			if n == 0 {
+				return nil
 			}
+			hits += n
 		}
 
+		matches = append(matches, domain.ContentMatch{
+			Path:    r.relPath(path),
+			Snippet: contentSnippet(string(content), lower, words[0]),
+			Rank:    -float64(hits),
+		})
 		return nil
 	})
 
-	return results, err
+	slices.SortStableFunc(matches, func(a, b domain.ContentMatch) int {
+		return cmp.Compare(a.Rank, b.Rank)
+	})
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+// contentSnippet returns a single-line excerpt around the first occurrence of word,
+// with the occurrence wrapped in highlight markers
+func contentSnippet(content, lower, word string) string {
+	i := strings.Index(lower, word)
+	if i < 0 || len(lower) != len(content) {
+		return "" // Case folding changed byte offsets; skip the excerpt
+	}
+
+	start := max(0, i-contentSnippetRadius)
+	end := min(len(content), i+len(word)+contentSnippetRadius)
+	for start > 0 && !utf8.RuneStart(content[start]) {
+		start--
+	}
+	for end < len(content) && !utf8.RuneStart(content[end]) {
+		end++
+	}
+
+	snippet := content[start:i] + domain.HighlightStart + content[i:i+len(word)] + domain.HighlightEnd + content[i+len(word):end]
+	snippet = strings.Join(strings.Fields(snippet), " ")
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+	if end < len(content) {
+		snippet += "…"
+	}
+	return snippet
 }
 
 // findNearestID finds the nearest parent directory with a valid Johnny Decimal ID
@@ -1125,19 +1861,19 @@ func (r *Repository) LoadChildren(node *domain.TreeNode) error {
 
 	case domain.IDTypeItem:
 		// Load files inside item directory
-		entries, err := os.ReadDir(node.Path)
+		entries, err := r.readDir(node.Path)
 		if err != nil {
 			return err
 		}
 		for _, entry := range entries {
-			if entry.IsDir() {
+			if entry.isDir {
 				continue // Skip subdirectories
 			}
 			node.Children = append(node.Children, &domain.TreeNode{
 				Type:   domain.IDTypeFile,
 				ID:     "", // Files don't have Johnny Decimal IDs
-				Name:   entry.Name(),
-				Path:   filepath.Join(node.Path, entry.Name()),
+				Name:   entry.name,
+				Path:   filepath.Join(node.Path, entry.name),
 				Parent: node,
 			})
 		}
@@ -1167,16 +1903,16 @@ func (r *Repository) GetPath(id string) (string, error) {
 // Helper methods for finding paths
 
 // findPathInDir looks for a directory in parentPath that starts with "id "
-func findPathInDir(parentPath, id, entityType string) (string, error) {
-	entries, err := os.ReadDir(parentPath)
+func (r *Repository) findPathInDir(parentPath, id, entityType string) (string, error) {
+	entries, err := r.readDir(parentPath)
 	if err != nil {
 		return "", err
 	}
 
 	prefix := id + " "
 	for _, entry := range entries {
-		if entry.IsDir() && strings.HasPrefix(entry.Name(), prefix) {
-			return filepath.Join(parentPath, entry.Name()), nil
+		if entry.isDir && strings.HasPrefix(entry.name, prefix) {
+			return filepath.Join(parentPath, entry.name), nil
 		}
 	}
 
@@ -1184,10 +1920,16 @@ func findPathInDir(parentPath, id, entityType string) (string, error) {
 }
 
 func (r *Repository) findScopePath(scopeID string) (string, error) {
-	return findPathInDir(r.vaultPath, scopeID, "scope")
+	if path, ok := r.indexedPath(scopeID); ok {
+		return path, nil
+	}
+	return r.findPathInDir(r.vaultPath, scopeID, "scope")
 }
 
 func (r *Repository) findAreaPath(areaID string) (string, error) {
+	if path, ok := r.indexedPath(areaID); ok {
+		return path, nil
+	}
 	scopeID, err := domain.ParseScope(areaID)
 	if err != nil {
 		return "", err
@@ -1196,10 +1938,13 @@ func (r *Repository) findAreaPath(areaID string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return findPathInDir(scopePath, areaID, "area")
+	return r.findPathInDir(scopePath, areaID, "area")
 }
 
 func (r *Repository) findCategoryPath(categoryID string) (string, error) {
+	if path, ok := r.indexedPath(categoryID); ok {
+		return path, nil
+	}
 	areaID, err := domain.ParseArea(categoryID)
 	if err != nil {
 		return "", err
@@ -1208,10 +1953,13 @@ func (r *Repository) findCategoryPath(categoryID string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return findPathInDir(areaPath, categoryID, "category")
+	return r.findPathInDir(areaPath, categoryID, "category")
 }
 
 func (r *Repository) findItemPath(itemID string) (string, error) {
+	if path, ok := r.indexedPath(itemID); ok {
+		return path, nil
+	}
 	categoryID, err := domain.ParseCategory(itemID)
 	if err != nil {
 		return "", err
@@ -1220,5 +1968,5 @@ func (r *Repository) findItemPath(itemID string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return findPathInDir(categoryPath, itemID, "item")
+	return r.findPathInDir(categoryPath, itemID, "item")
 }
@@ -43,7 +43,7 @@ func TestCreateCategory_CreatesStandardZeros(t *testing.T) {
 
 	repo := NewRepository(vaultPath)
 
-	cat, err := repo.CreateCategory("S01.10-19", "Entertainment")
+	cat, _, err := repo.CreateCategory("S01.10-19", "Entertainment")
 	if err != nil {
 		t.Fatalf("CreateCategory failed: %v", err)
 	}
@@ -145,7 +145,7 @@ func TestCreateCategory_StandardZeroCount(t *testing.T) {
 
 	repo := NewRepository(vaultPath)
 
-	cat, err := repo.CreateCategory("S01.10-19", "TestCat")
+	cat, _, err := repo.CreateCategory("S01.10-19", "TestCat")
 	if err != nil {
 		t.Fatalf("CreateCategory failed: %v", err)
 	}
@@ -167,7 +167,7 @@ func TestCreateCategory_StandardZeroIDs(t *testing.T) {
 
 	repo := NewRepository(vaultPath)
 
-	cat, err := repo.CreateCategory("S01.10-19", "TestCat")
+	cat, _, err := repo.CreateCategory("S01.10-19", "TestCat")
 	if err != nil {
 		t.Fatalf("CreateCategory failed: %v", err)
 	}
@@ -462,6 +462,44 @@ func TestSearch_NoResultsForUnmatchedQuery(t *testing.T) {
 	}
 }
 
+func TestSearchContent_ScansWithoutIndex(t *testing.T) {
+	vaultPath, cleanup := setupSearchTestVault(t)
+	defer cleanup()
+
+	itemPath := filepath.Join(vaultPath, "S01 Personal", "S01.10-19 Lifestyle", "S01.11 Entertainment", "S01.11.15 Movies")
+	files := map[string]string{
+		"film-review.md": "# Movie Notes\n\nChristopher Nolan directed this masterpiece.\nNolan again.\n",
+		"watchlist.md":   "Next: the new Nolan film.\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(itemPath, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to create file: %v", err)
+		}
+	}
+
+	repo := NewRepository(vaultPath)
+
+	results, err := repo.SearchContent("nolan", 0)
+	if err != nil {
+		t.Fatalf("SearchContent failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+
+	top := results[0]
+	if top.Name != "film-review.md" || top.ID != "S01.11.15" || top.Type != domain.IDTypeFile {
+		t.Errorf("expected film-review.md in S01.11.15 ranked first, got %+v", top)
+	}
+	if !strings.Contains(top.MatchedText, domain.HighlightStart+"Nolan"+domain.HighlightEnd) {
+		t.Errorf("expected highlighted snippet, got %q", top.MatchedText)
+	}
+
+	if results, _ := repo.SearchContent("nolan masterpiece", 0); len(results) != 1 {
+		t.Errorf("expected every word to be required, got %d results", len(results))
+	}
+}
+
 // Archive test helpers
 
 func setupArchiveTestVault(t *testing.T) (string, func()) {
@@ -547,7 +585,7 @@ func TestArchiveItem_MovesToArchiveFolder(t *testing.T) {
 	repo := NewRepository(vaultPath)
 
 	// Archive the item
-	archivedItem, err := repo.ArchiveItem("S01.11.15")
+	archivedItem, _, err := repo.ArchiveItem("S01.11.15")
 	if err != nil {
 		t.Fatalf("ArchiveItem failed: %v", err)
 	}
@@ -602,12 +640,12 @@ func TestArchiveItem_MultipleItemsCanBeArchived(t *testing.T) {
 	repo := NewRepository(vaultPath)
 
 	// Archive both items
-	archivedItem1, err := repo.ArchiveItem("S01.11.15")
+	archivedItem1, _, err := repo.ArchiveItem("S01.11.15")
 	if err != nil {
 		t.Fatalf("ArchiveItem (first) failed: %v", err)
 	}
 
-	archivedItem2, err := repo.ArchiveItem("S01.11.16")
+	archivedItem2, _, err := repo.ArchiveItem("S01.11.16")
 	if err != nil {
 		t.Fatalf("ArchiveItem (second) failed: %v", err)
 	}
@@ -637,13 +675,13 @@ func TestArchiveItem_FailsForNonItem(t *testing.T) {
 	repo := NewRepository(vaultPath)
 
 	// Try to archive a category (should fail)
-	_, err := repo.ArchiveItem("S01.11")
+	_, _, err := repo.ArchiveItem("S01.11")
 	if err == nil {
 		t.Error("expected error when archiving a category, got nil")
 	}
 
 	// Try to archive an area (should fail)
-	_, err = repo.ArchiveItem("S01.10-19")
+	_, _, err = repo.ArchiveItem("S01.10-19")
 	if err == nil {
 		t.Error("expected error when archiving an area, got nil")
 	}
@@ -656,7 +694,7 @@ func TestArchiveItem_FailsIfAlreadyArchiveItem(t *testing.T) {
 	repo := NewRepository(vaultPath)
 
 	// Try to archive the .09 Archive item itself (should fail)
-	_, err := repo.ArchiveItem("S01.11.09")
+	_, _, err := repo.ArchiveItem("S01.11.09")
 	if err == nil {
 		t.Error("expected error when archiving an archive item (.09), got nil")
 	}
@@ -682,7 +720,7 @@ func TestArchiveItem_FailsIfArchiveItemMissing(t *testing.T) {
 
 	repo := NewRepository(tmpDir)
 
-	_, err = repo.ArchiveItem("S01.11.15")
+	_, _, err = repo.ArchiveItem("S01.11.15")
 	if err == nil {
 		t.Error("expected error when .09 Archive item is missing, got nil")
 	}
@@ -707,7 +745,7 @@ func TestArchiveCategory_MovesAllItemsToArchiveFolder(t *testing.T) {
 
 	repo := NewRepository(vaultPath)
 
-	archivedItems, err := repo.ArchiveCategory("S01.11")
+	archivedItems, _, err := repo.ArchiveCategory("S01.11")
 	if err != nil {
 		t.Fatalf("ArchiveCategory failed: %v", err)
 	}
@@ -758,7 +796,7 @@ func TestArchiveCategory_SkipsStandardZeros(t *testing.T) {
 
 	repo := NewRepository(vaultPath)
 
-	archivedItems, err := repo.ArchiveCategory("S01.11")
+	archivedItems, _, err := repo.ArchiveCategory("S01.11")
 	if err != nil {
 		t.Fatalf("ArchiveCategory failed: %v", err)
 	}
@@ -781,13 +819,13 @@ func TestArchiveCategory_FailsForNonCategory(t *testing.T) {
 	repo := NewRepository(vaultPath)
 
 	// Try to archive an item (should fail)
-	_, err := repo.ArchiveCategory("S01.11.15")
+	_, _, err := repo.ArchiveCategory("S01.11.15")
 	if err == nil {
 		t.Error("expected error when archiving an item, got nil")
 	}
 
 	// Try to archive an area (should fail)
-	_, err = repo.ArchiveCategory("S01.10-19")
+	_, _, err = repo.ArchiveCategory("S01.10-19")
 	if err == nil {
 		t.Error("expected error when archiving an area, got nil")
 	}
@@ -814,7 +852,7 @@ func TestArchiveCategory_FailsIfArchiveItemMissing(t *testing.T) {
 	repo := NewRepository(tmpDir)
 
 	// Try to archive category without .09 Archive item (should fail)
-	_, err = repo.ArchiveCategory("S01.11")
+	_, _, err = repo.ArchiveCategory("S01.11")
 	if err == nil {
 		t.Error("expected error when .09 Archive item is missing, got nil")
 	}
@@ -826,7 +864,7 @@ func TestArchiveCategory_PreservesItemDescriptions(t *testing.T) {
 
 	repo := NewRepository(vaultPath)
 
-	archivedItems, err := repo.ArchiveCategory("S01.11")
+	archivedItems, _, err := repo.ArchiveCategory("S01.11")
 	if err != nil {
 		t.Fatalf("ArchiveCategory failed: %v", err)
 	}
@@ -908,7 +946,7 @@ func TestArchiveCategoryToArea_MovesToAreaArchive(t *testing.T) {
 	repo := NewRepository(vaultPath)
 
 	// Archive category S01.11 to area archive S01.10.09
-	archivedCat, err := repo.ArchiveCategoryToArea("S01.11")
+	archivedCat, _, err := repo.ArchiveCategoryToArea("S01.11")
 	if err != nil {
 		t.Fatalf("ArchiveCategoryToArea failed: %v", err)
 	}
@@ -943,7 +981,7 @@ func TestArchiveCategoryToArea_PreservesContents(t *testing.T) {
 
 	repo := NewRepository(vaultPath)
 
-	_, err := repo.ArchiveCategoryToArea("S01.11")
+	_, _, err := repo.ArchiveCategoryToArea("S01.11")
 	if err != nil {
 		t.Fatalf("ArchiveCategoryToArea failed: %v", err)
 	}
@@ -971,13 +1009,13 @@ func TestArchiveCategoryToArea_FailsForNonCategory(t *testing.T) {
 	repo := NewRepository(vaultPath)
 
 	// Try to archive an item (should fail)
-	_, err := repo.ArchiveCategoryToArea("S01.11.15")
+	_, _, err := repo.ArchiveCategoryToArea("S01.11.15")
 	if err == nil {
 		t.Error("expected error when archiving an item, got nil")
 	}
 
 	// Try to archive an area (should fail)
-	_, err = repo.ArchiveCategoryToArea("S01.10-19")
+	_, _, err = repo.ArchiveCategoryToArea("S01.10-19")
 	if err == nil {
 		t.Error("expected error when archiving an area, got nil")
 	}
@@ -1000,7 +1038,7 @@ func TestArchiveCategoryToArea_FailsIfAreaArchiveMissing(t *testing.T) {
 
 	repo := NewRepository(tmpDir)
 
-	_, err = repo.ArchiveCategoryToArea("S01.11")
+	_, _, err = repo.ArchiveCategoryToArea("S01.11")
 	if err == nil {
 		t.Error("expected error when area archive is missing, got nil")
 	}
@@ -1013,7 +1051,7 @@ func TestArchiveCategoryToArea_FailsForManagementCategory(t *testing.T) {
 	repo := NewRepository(vaultPath)
 
 	// Try to archive the management category itself (should fail)
-	_, err := repo.ArchiveCategoryToArea("S01.10")
+	_, _, err := repo.ArchiveCategoryToArea("S01.10")
 	if err == nil {
 		t.Error("expected error when archiving management category, got nil")
 	}
@@ -1084,7 +1122,7 @@ func TestArchiveItem_UpdatesObsidianLinks(t *testing.T) {
 
 	repo := NewRepository(vaultPath)
 
-	_, err := repo.ArchiveItem("S01.11.15")
+	_, _, err := repo.ArchiveItem("S01.11.15")
 	if err != nil {
 		t.Fatalf("ArchiveItem failed: %v", err)
 	}
@@ -1144,7 +1182,7 @@ func TestArchiveItem_UpdatesLinksWithVariousFormats(t *testing.T) {
 
 	repo := NewRepository(vaultPath)
 
-	_, err := repo.ArchiveItem("S01.11.15")
+	_, _, err := repo.ArchiveItem("S01.11.15")
 	if err != nil {
 		t.Fatalf("ArchiveItem failed: %v", err)
 	}
@@ -1176,3 +1214,223 @@ func TestArchiveItem_UpdatesLinksWithVariousFormats(t *testing.T) {
 		t.Errorf("file should contain [[[Archived] Theatre|Another Title]] link after archiving")
 	}
 }
+
+func TestArchiveItem_ReportsRewrittenFiles(t *testing.T) {
+	vaultPath, cleanup := setupLinkTestVault(t)
+	defer cleanup()
+
+	repo := NewRepository(vaultPath)
+
+	_, report, err := repo.ArchiveItem("S01.11.15")
+	if err != nil {
+		t.Fatalf("ArchiveItem failed: %v", err)
+	}
+
+	// links.md has three links, notes.md has one
+	linksFile := filepath.Join("S01 Personal", "S01.10-19 Lifestyle", "S01.11 Entertainment", "S01.11.16 Links", "links.md")
+	got := make(map[string]int)
+	for _, fr := range report.FilesRewritten {
+		got[fr.Path] = fr.Links
+	}
+	if got[linksFile] != 3 {
+		t.Errorf("expected 3 links rewritten in %s, got %d", linksFile, got[linksFile])
+	}
+	if got["notes.md"] != 1 {
+		t.Errorf("expected 1 link rewritten in notes.md, got %d", got["notes.md"])
+	}
+	if report.LinksRewritten() != 4 {
+		t.Errorf("expected 4 links rewritten in total, got %d", report.LinksRewritten())
+	}
+	if report.HasErrors() {
+		t.Errorf("unexpected errors: %v", report.Errors)
+	}
+}
+
+func TestRenameItem_ReportsRewrittenLinks(t *testing.T) {
+	vaultPath, cleanup := setupLinkTestVault(t)
+	defer cleanup()
+
+	repo := NewRepository(vaultPath)
+
+	_, report, err := repo.RenameItem("S01.11.15", "Opera")
+	if err != nil {
+		t.Fatalf("RenameItem failed: %v", err)
+	}
+
+	notes, err := os.ReadFile(filepath.Join(vaultPath, "notes.md"))
+	if err != nil {
+		t.Fatalf("failed to read notes.md: %v", err)
+	}
+	if !strings.Contains(string(notes), "[[S01.11.15 Opera]]") {
+		t.Errorf("notes.md should link to the renamed item, got: %s", notes)
+	}
+	if report.LinksRewritten() != 4 {
+		t.Errorf("expected 4 links rewritten, got %d", report.LinksRewritten())
+	}
+}
+
+func TestRenameItem_RewritesEveryLinkForm(t *testing.T) {
+	vaultPath, cleanup := setupLinkTestVault(t)
+	defer cleanup()
+
+	content := "![[S01.11.15 Theatre]]\n" +
+		"[[S01.11.15 Theatre#Cast|Cast]]\n" +
+		"[[S01.11.15^block]]\n" +
+		"[[S01 Personal/S01.10-19 Lifestyle/S01.11 Entertainment/S01.11.15 Theatre]]\n" +
+		"| [[S01.11.15\\|Theatre]] |\n" +
+		"`[[S01.11.15]]`\n"
+	path := filepath.Join(vaultPath, "forms.md")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := NewRepository(vaultPath)
+	if _, _, err := repo.RenameItem("S01.11.15", "Opera"); err != nil {
+		t.Fatalf("RenameItem failed: %v", err)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "![[S01.11.15 Opera]]\n" +
+		"[[S01.11.15 Opera#Cast|Cast]]\n" +
+		"[[S01.11.15 Opera^block]]\n" +
+		"[[S01.11.15 Opera]]\n" +
+		"| [[S01.11.15 Opera\\|Theatre]] |\n" +
+		"`[[S01.11.15]]`\n"
+	if string(got) != want {
+		t.Errorf("forms.md = %q, want %q", got, want)
+	}
+}
+
+func TestRenameItem_RewritesCanvasAndFrontmatterLinks(t *testing.T) {
+	vaultPath, cleanup := setupLinkTestVault(t)
+	defer cleanup()
+
+	files := map[string]string{
+		"related.md": "---\nrelated: '[[S01.11.15 Theatre]]'\nup:\n  - \"[[S01.11.15|Theatre]]\"\n---\n",
+		"board.canvas": "{\n\t\"nodes\":[\n" +
+			"\t\t{\"id\":\"a\",\"type\":\"text\",\"text\":\"See [[S01.11.15]]\",\"x\":0,\"y\":0,\"width\":250,\"height\":60},\n" +
+			"\t\t{\"id\":\"b\",\"type\":\"file\",\"file\":\"S01 Personal/S01.10-19 Lifestyle/S01.11 Entertainment/S01.11.15 Theatre/README.md\",\"x\":300,\"y\":0,\"width\":400,\"height\":400}\n" +
+			"\t],\n\t\"edges\":[]\n}",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(vaultPath, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	repo := NewRepository(vaultPath)
+	if _, _, err := repo.RenameItem("S01.11.15", "Mum's Play"); err != nil {
+		t.Fatalf("RenameItem failed: %v", err)
+	}
+
+	want := map[string]string{
+		"related.md": "---\nrelated: '[[S01.11.15 Mum''s Play]]'\nup:\n  - \"[[S01.11.15 Mum's Play|Theatre]]\"\n---\n",
+		"board.canvas": "{\n\t\"nodes\":[\n" +
+			"\t\t{\"id\":\"a\",\"type\":\"text\",\"text\":\"See [[S01.11.15 Mum's Play]]\",\"x\":0,\"y\":0,\"width\":250,\"height\":60},\n" +
+			"\t\t{\"id\":\"b\",\"type\":\"file\",\"file\":\"S01 Personal/S01.10-19 Lifestyle/S01.11 Entertainment/S01.11.15 Mum's Play/README.md\",\"x\":300,\"y\":0,\"width\":400,\"height\":400}\n" +
+			"\t],\n\t\"edges\":[]\n}",
+	}
+	for name, content := range want {
+		got, err := os.ReadFile(filepath.Join(vaultPath, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != content {
+			t.Errorf("%s = %q, want %q", name, got, content)
+		}
+	}
+}
+
+func TestArchiveItem_RewritesMarkdownLinks(t *testing.T) {
+	vaultPath, cleanup := setupLinkTestVault(t)
+	defer cleanup()
+
+	category := filepath.Join(vaultPath, "S01 Personal", "S01.10-19 Lifestyle", "S01.11 Entertainment")
+	files := map[string]string{
+		// Incoming, relative and vault-rooted, with a fragment and an image
+		filepath.Join(category, "S01.11.16 Links", "md.md"): "[Cast](../S01.11.15%20Theatre/README.md#Cast) " +
+			"![p](</S01 Personal/S01.10-19 Lifestyle/S01.11 Entertainment/S01.11.15 Theatre/poster.png>) " +
+			"[web](https://example.com/README.md)",
+		// Outgoing from the moved item, and within it
+		filepath.Join(category, "S01.11.15 Theatre", "out.md"): "[Links](../S01.11.16%20Links/links.md) [Readme](README.md)",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	repo := NewRepository(vaultPath)
+	if _, _, err := repo.ArchiveItem("S01.11.15"); err != nil {
+		t.Fatalf("ArchiveItem failed: %v", err)
+	}
+
+	archived := filepath.Join(category, "S01.11.09 Archive for S01.11", "[Archived] Theatre")
+	tests := []struct {
+		path string
+		want string
+	}{
+		{
+			filepath.Join(category, "S01.11.16 Links", "md.md"),
+			"[Cast](../S01.11.09%20Archive%20for%20S01.11/[Archived]%20Theatre/README.md#Cast) " +
+				"![p](</S01 Personal/S01.10-19 Lifestyle/S01.11 Entertainment/S01.11.09 Archive for S01.11/[Archived] Theatre/poster.png>) " +
+				"[web](https://example.com/README.md)",
+		},
+		{
+			filepath.Join(archived, "out.md"),
+			"[Links](../../S01.11.16%20Links/links.md) [Readme](README.md)",
+		},
+	}
+	for _, tt := range tests {
+		got, err := os.ReadFile(tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("%s = %q, want %q", filepath.Base(tt.path), got, tt.want)
+		}
+	}
+}
+
+func TestArchiveCategory_ReportsSkippedItems(t *testing.T) {
+	vaultPath, cleanup := setupArchiveTestVault(t)
+	defer cleanup()
+
+	repo := NewRepository(vaultPath)
+
+	// Occupy the archived destination name so the rename of one item fails
+	items, err := repo.ListItems("S01.11")
+	if err != nil {
+		t.Fatalf("ListItems failed: %v", err)
+	}
+	archivePath, err := repo.GetPath("S01.11.09")
+	if err != nil {
+		t.Fatalf("GetPath failed: %v", err)
+	}
+	var blocked domain.Item
+	for _, item := range items {
+		if !domain.IsStandardZeroItem(item.ID) {
+			blocked = item
+			break
+		}
+	}
+	blocker := filepath.Join(archivePath, "[Archived] "+blocked.Name)
+	if err := os.MkdirAll(filepath.Join(blocker, "occupied"), 0755); err != nil {
+		t.Fatalf("failed to create blocker: %v", err)
+	}
+
+	_, report, err := repo.ArchiveCategory("S01.11")
+	if err != nil {
+		t.Fatalf("ArchiveCategory failed: %v", err)
+	}
+
+	if len(report.Warnings) != 1 {
+		t.Fatalf("expected 1 warning for the skipped item, got %v", report.Warnings)
+	}
+	if !strings.Contains(report.Warnings[0], blocked.ID) {
+		t.Errorf("warning should mention %s, got %q", blocked.ID, report.Warnings[0])
+	}
+}
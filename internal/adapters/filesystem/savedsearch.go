@@ -0,0 +1,94 @@
+package filesystem
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"libraio/internal/domain"
+)
+
+// savedSearchesFile is the vault-relative file holding the saved searches
+var savedSearchesFile = filepath.Join(appDir, "searches.json")
+
+// ListSavedSearches returns the saved searches of the vault in the order they
+// were first saved
+func (r *Repository) ListSavedSearches() ([]domain.SavedSearch, error) {
+	data, err := os.ReadFile(filepath.Join(r.vaultPath, savedSearchesFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read saved searches: %w", err)
+	}
+
+	var searches []domain.SavedSearch
+	if err := json.Unmarshal(data, &searches); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", savedSearchesFile, err)
+	}
+	return searches, nil
+}
+
+// SaveSearch saves a search, replacing the one with its name if any
+func (r *Repository) SaveSearch(search domain.SavedSearch) error {
+	unlock, err := r.lockVault()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	searches, err := r.ListSavedSearches()
+	if err != nil {
+		return err
+	}
+	return r.writeSavedSearches(domain.PutSavedSearch(searches, search))
+}
+
+// DeleteSavedSearch removes the saved search named name, ignoring case, and
+// returns it
+func (r *Repository) DeleteSavedSearch(name string) (*domain.SavedSearch, error) {
+	unlock, err := r.lockVault()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	searches, err := r.ListSavedSearches()
+	if err != nil {
+		return nil, err
+	}
+	i := domain.FindSavedSearch(searches, name)
+	if i < 0 {
+		return nil, fmt.Errorf("saved search not found: %s", name)
+	}
+	deleted := searches[i]
+	if err := r.writeSavedSearches(append(searches[:i], searches[i+1:]...)); err != nil {
+		return nil, err
+	}
+	return &deleted, nil
+}
+
+// writeSavedSearches replaces the saved searches file with searches
+func (r *Repository) writeSavedSearches(searches []domain.SavedSearch) error {
+	if searches == nil {
+		searches = []domain.SavedSearch{}
+	}
+	data, err := json.MarshalIndent(searches, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode saved searches: %w", err)
+	}
+
+	path := filepath.Join(r.vaultPath, savedSearchesFile)
+	if _, err = os.Stat(path); os.IsNotExist(err) {
+		if err = makeAppDir(filepath.Dir(path)); err == nil {
+			err = os.WriteFile(path, data, 0644)
+		}
+	} else {
+		err = writeFileAtomic(path, data, false)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write saved searches: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package filesystem
+
+import (
+	"slices"
+	"testing"
+
+	"libraio/internal/domain"
+)
+
+func TestSavedSearches_StoredWithTheVault(t *testing.T) {
+	vaultPath, cleanup := setupLinkTestVault(t)
+	defer cleanup()
+
+	repo := NewRepository(vaultPath)
+	if searches, err := repo.ListSavedSearches(); err != nil || searches != nil {
+		t.Fatalf("ListSavedSearches() = %v, %v, want none", searches, err)
+	}
+
+	for _, s := range []domain.SavedSearch{
+		{Name: "Inbox everywhere", Query: "type:item inbox"},
+		{Name: "Tagged #todo", Query: "tag:todo"},
+		{Name: "Modified this week", Query: "modified:>7d"},
+		{Name: "tagged #TODO", Query: "tag:todo -tag:done"},
+	} {
+		if err := repo.SaveSearch(s); err != nil {
+			t.Fatalf("SaveSearch(%q) failed: %v", s.Name, err)
+		}
+	}
+
+	deleted, err := repo.DeleteSavedSearch("INBOX everywhere")
+	if err != nil {
+		t.Fatalf("DeleteSavedSearch failed: %v", err)
+	}
+	if deleted.Query != "type:item inbox" {
+		t.Errorf("deleted %+v, want the inbox search", deleted)
+	}
+	if _, err := repo.DeleteSavedSearch("Inbox everywhere"); err == nil {
+		t.Error("deleting a missing search should fail")
+	}
+
+	// Another repository on the vault sees the same searches
+	searches, err := NewRepository(vaultPath).ListSavedSearches()
+	if err != nil {
+		t.Fatalf("ListSavedSearches failed: %v", err)
+	}
+	want := []domain.SavedSearch{
+		{Name: "tagged #TODO", Query: "tag:todo -tag:done"},
+		{Name: "Modified this week", Query: "modified:>7d"},
+	}
+	if !slices.Equal(searches, want) {
+		t.Errorf("ListSavedSearches() = %+v, want %+v", searches, want)
+	}
+}
@@ -0,0 +1,273 @@
+package filesystem
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"libraio/internal/domain"
+)
+
+// trashDir is the vault-relative folder holding deleted entities
+var trashDir = filepath.Join(appDir, "trash")
+
+// trashMetaFile is the metadata file stored next to each trashed folder
+const trashMetaFile = "trash.json"
+
+// trashPath returns the absolute path of the vault trash
+func (r *Repository) trashPath() string {
+	return filepath.Join(r.vaultPath, trashDir)
+}
+
+// Delete moves an item, category, area, or scope by ID into the vault trash.
+// Links pointing into the deleted subtree are recorded so they can be reviewed later.
+func (r *Repository) Delete(id string) (*domain.TrashEntry, *domain.OperationReport, error) {
+	unlock, err := r.lockVault()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer unlock()
+
+	path, err := r.GetPath(id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("not found: %w", err)
+	}
+
+	links := r.findIncomingLinks(id, path)
+	snapshotID, err := r.snapshotWithLinks("delete", id, path, links)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	entry := &domain.TrashEntry{
+		TrashID:       r.uniqueTrashID(id, now),
+		ID:            id,
+		Name:          filepath.Base(path),
+		OriginalPath:  r.relPath(path),
+		DeletedAt:     now,
+		IncomingLinks: links,
+		Snapshot:      snapshotID,
+	}
+
+	entryPath := filepath.Join(r.trashPath(), entry.TrashID)
+	if err := os.MkdirAll(entryPath, 0755); err != nil {
+		return nil, nil, fmt.Errorf("failed to create trash folder: %w", err)
+	}
+
+	if err := writeTrashMeta(entryPath, entry); err != nil {
+		os.RemoveAll(entryPath)
+		return nil, nil, err
+	}
+
+	trashedPath := filepath.Join(entryPath, entry.Name)
+	if err := os.Rename(path, trashedPath); err != nil {
+		os.RemoveAll(entryPath)
+		return nil, nil, fmt.Errorf("failed to move %s to trash: %w", id, err)
+	}
+	report := domain.NewOperationReport()
+	r.recordSnapshotMoves(snapshotID, []domain.NodeMove{r.nodeMove(path, trashedPath)}, report)
+	r.indexRemove(path, report)
+
+	if err := r.commitChange("delete", "delete "+entry.Name, path); err != nil {
+		return entry, report, fmt.Errorf("moved %s to trash but failed to commit: %w", id, err)
+	}
+	return entry, report, nil
+}
+
+// ListTrash returns all trashed entities, most recently deleted first
+func (r *Repository) ListTrash() ([]domain.TrashEntry, error) {
+	dirEntries, err := os.ReadDir(r.trashPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trash: %w", err)
+	}
+
+	var entries []domain.TrashEntry
+	for _, de := range dirEntries {
+		if !de.IsDir() {
+			continue
+		}
+		entry, err := readTrashMeta(filepath.Join(r.trashPath(), de.Name()))
+		if err != nil {
+			continue // Not a trash entry we created
+		}
+		entries = append(entries, *entry)
+	}
+
+	slices.SortFunc(entries, func(a, b domain.TrashEntry) int {
+		return b.DeletedAt.Compare(a.DeletedAt)
+	})
+	return entries, nil
+}
+
+// RestoreTrash moves a trashed entity back to its original location
+func (r *Repository) RestoreTrash(trashID string) (*domain.TrashEntry, *domain.OperationReport, error) {
+	unlock, err := r.lockVault()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer unlock()
+
+	entryPath, err := r.trashEntryPath(trashID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entry, err := readTrashMeta(entryPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dstPath := filepath.Join(r.vaultPath, entry.OriginalPath)
+	if _, err := os.Stat(dstPath); err == nil {
+		return nil, nil, fmt.Errorf("cannot restore %s: %s already exists", entry.ID, entry.OriginalPath)
+	}
+
+	parent := filepath.Dir(dstPath)
+	if info, err := os.Stat(parent); err != nil || !info.IsDir() {
+		return nil, nil, fmt.Errorf("cannot restore %s: parent folder %s no longer exists", entry.ID, r.relPath(parent))
+	}
+
+	if err := os.Rename(filepath.Join(entryPath, entry.Name), dstPath); err != nil {
+		return nil, nil, fmt.Errorf("failed to restore %s: %w", entry.ID, err)
+	}
+	report := domain.NewOperationReport()
+	r.indexAdd(dstPath, report)
+
+	if err := os.RemoveAll(entryPath); err != nil {
+		return entry, report, fmt.Errorf("restored %s but failed to clean up trash: %w", entry.ID, err)
+	}
+
+	if err := r.commitChange("restore", fmt.Sprintf("restore %s from trash", entry.Name), dstPath); err != nil {
+		return entry, report, fmt.Errorf("restored %s but failed to commit: %w", entry.ID, err)
+	}
+	return entry, report, nil
+}
+
+// PurgeTrash permanently removes a single entry from the trash
+func (r *Repository) PurgeTrash(trashID string) error {
+	unlock, err := r.lockVault()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	entryPath, err := r.trashEntryPath(trashID)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(entryPath)
+}
+
+// trashEntryPath resolves and validates the folder of a trash entry
+func (r *Repository) trashEntryPath(trashID string) (string, error) {
+	if trashID == "" || filepath.Base(trashID) != trashID || strings.HasPrefix(trashID, ".") {
+		return "", fmt.Errorf("invalid trash ID: %s", trashID)
+	}
+
+	entryPath := filepath.Join(r.trashPath(), trashID)
+	info, err := os.Stat(entryPath)
+	if err != nil || !info.IsDir() {
+		return "", fmt.Errorf("trash entry not found: %s", trashID)
+	}
+	return entryPath, nil
+}
+
+// uniqueTrashID returns a trash ID that does not collide with an existing entry
+func (r *Repository) uniqueTrashID(id string, now time.Time) string {
+	base := domain.NewTrashID(id, now)
+	trashID := base
+	for n := 2; ; n++ {
+		if _, err := os.Stat(filepath.Join(r.trashPath(), trashID)); os.IsNotExist(err) {
+			return trashID
+		}
+		trashID = fmt.Sprintf("%s-%d", base, n)
+	}
+}
+
+// findIncomingLinks scans notes and canvases outside excludePath for wiki
+// links that point at id or any entity beneath it, and path links into excludePath
+func (r *Repository) findIncomingLinks(id, excludePath string) []domain.TrashedLink {
+	links := []domain.TrashedLink{}
+	excluded := domain.NodeMove{OldPath: r.relPath(excludePath)}
+
+	filepath.Walk(r.vaultPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil // Skip errors
+		}
+
+		if info.IsDir() && (strings.HasPrefix(info.Name(), ".") || path == excludePath) {
+			return filepath.SkipDir
+		}
+
+		if info.IsDir() || !domain.IsLinkSource(info.Name()) {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+
+		source := r.relPath(path)
+		found := domain.ScanFileLinks(source, content)
+		for _, link := range found.Wiki {
+			linkedID := domain.ExtractID(link.Name())
+			if domain.ParseIDType(linkedID) == domain.IDTypeUnknown || !domain.IsWithin(linkedID, id) {
+				continue
+			}
+			links = append(links, domain.TrashedLink{SourcePath: source, LinkText: link.String()})
+		}
+		linksInto := func(linkPath, text string) {
+			if target, ok := domain.ResolveLinkPath(source, linkPath); ok {
+				if _, inside := excluded.Apply(target); inside {
+					links = append(links, domain.TrashedLink{SourcePath: source, LinkText: text})
+				}
+			}
+		}
+		for _, link := range found.Markdown {
+			if linkPath, ok := link.Path(); ok {
+				linksInto(linkPath, link.String())
+			}
+		}
+		for _, file := range found.Files {
+			linksInto("/"+file, file)
+		}
+		return nil
+	})
+
+	return links
+}
+
+// writeTrashMeta writes the metadata file for a trash entry
+func writeTrashMeta(entryPath string, entry *domain.TrashEntry) error {
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode trash metadata: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(entryPath, trashMetaFile), data, 0644); err != nil {
+		return fmt.Errorf("failed to write trash metadata: %w", err)
+	}
+	return nil
+}
+
+// readTrashMeta reads the metadata file for a trash entry
+func readTrashMeta(entryPath string) (*domain.TrashEntry, error) {
+	data, err := os.ReadFile(filepath.Join(entryPath, trashMetaFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trash metadata: %w", err)
+	}
+
+	var entry domain.TrashEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("failed to parse trash metadata: %w", err)
+	}
+	return &entry, nil
+}
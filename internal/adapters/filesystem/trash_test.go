@@ -0,0 +1,171 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDelete_MovesToTrash(t *testing.T) {
+	vaultPath, cleanup := setupLinkTestVault(t)
+	defer cleanup()
+
+	repo := NewRepository(vaultPath)
+
+	itemPath, err := repo.GetPath("S01.11.15")
+	if err != nil {
+		t.Fatalf("GetPath failed: %v", err)
+	}
+
+	entry, _, err := repo.Delete("S01.11.15")
+	if err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	if _, err := os.Stat(itemPath); !os.IsNotExist(err) {
+		t.Error("item should no longer exist at its original path")
+	}
+
+	trashed := filepath.Join(vaultPath, trashDir, entry.TrashID, "S01.11.15 Theatre", "README.md")
+	if _, err := os.Stat(trashed); err != nil {
+		t.Errorf("item contents should be in the trash: %v", err)
+	}
+
+	expectedOriginal := filepath.Join("S01 Personal", "S01.10-19 Lifestyle", "S01.11 Entertainment", "S01.11.15 Theatre")
+	if entry.OriginalPath != expectedOriginal {
+		t.Errorf("OriginalPath = %q, expected %q", entry.OriginalPath, expectedOriginal)
+	}
+
+	// links.md has three links and notes.md has one
+	if len(entry.IncomingLinks) != 4 {
+		t.Errorf("expected 4 incoming links, got %d: %+v", len(entry.IncomingLinks), entry.IncomingLinks)
+	}
+}
+
+func TestDelete_RecordsLinksIntoSubtree(t *testing.T) {
+	vaultPath, cleanup := setupLinkTestVault(t)
+	defer cleanup()
+
+	repo := NewRepository(vaultPath)
+
+	entry, _, err := repo.Delete("S01.11")
+	if err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	// Only notes.md lives outside the deleted category
+	if len(entry.IncomingLinks) != 1 {
+		t.Fatalf("expected 1 incoming link, got %+v", entry.IncomingLinks)
+	}
+	if entry.IncomingLinks[0].SourcePath != "notes.md" {
+		t.Errorf("expected link from notes.md, got %s", entry.IncomingLinks[0].SourcePath)
+	}
+}
+
+func TestRestoreTrash_RestoresOriginalLocation(t *testing.T) {
+	vaultPath, cleanup := setupLinkTestVault(t)
+	defer cleanup()
+
+	repo := NewRepository(vaultPath)
+
+	entry, _, err := repo.Delete("S01.11.15")
+	if err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	restored, _, err := repo.RestoreTrash(entry.TrashID)
+	if err != nil {
+		t.Fatalf("RestoreTrash failed: %v", err)
+	}
+	if restored.ID != "S01.11.15" {
+		t.Errorf("restored ID = %s, expected S01.11.15", restored.ID)
+	}
+
+	if _, err := repo.GetPath("S01.11.15"); err != nil {
+		t.Errorf("item should be back in the vault: %v", err)
+	}
+
+	entries, err := repo.ListTrash()
+	if err != nil {
+		t.Fatalf("ListTrash failed: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("trash should be empty after restore, got %d entries", len(entries))
+	}
+}
+
+func TestRestoreTrash_RefusesToOverwrite(t *testing.T) {
+	vaultPath, cleanup := setupLinkTestVault(t)
+	defer cleanup()
+
+	repo := NewRepository(vaultPath)
+
+	entry, _, err := repo.Delete("S01.11.15")
+	if err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(vaultPath, entry.OriginalPath), 0755); err != nil {
+		t.Fatalf("failed to recreate folder: %v", err)
+	}
+
+	if _, _, err := repo.RestoreTrash(entry.TrashID); err == nil {
+		t.Error("expected RestoreTrash to fail when the original path is occupied")
+	}
+
+	if _, err := os.Stat(filepath.Join(vaultPath, trashDir, entry.TrashID)); err != nil {
+		t.Error("trash entry should be kept when restore fails")
+	}
+}
+
+func TestRestoreTrash_RejectsInvalidID(t *testing.T) {
+	vaultPath, cleanup := setupLinkTestVault(t)
+	defer cleanup()
+
+	repo := NewRepository(vaultPath)
+
+	for _, id := range []string{"", "..", "../S01 Personal", "missing"} {
+		if _, _, err := repo.RestoreTrash(id); err == nil {
+			t.Errorf("expected error for trash ID %q", id)
+		}
+	}
+}
+
+func TestListTrash_NewestFirstAndPurge(t *testing.T) {
+	vaultPath, cleanup := setupLinkTestVault(t)
+	defer cleanup()
+
+	repo := NewRepository(vaultPath)
+
+	if entries, err := repo.ListTrash(); err != nil || len(entries) != 0 {
+		t.Fatalf("expected empty trash, got %v (err %v)", entries, err)
+	}
+
+	first, _, err := repo.Delete("S01.11.15")
+	if err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	second, _, err := repo.Delete("S01.11.16")
+	if err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	entries, err := repo.ListTrash()
+	if err != nil {
+		t.Fatalf("ListTrash failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].TrashID != second.TrashID {
+		t.Errorf("expected newest entry %s first, got %s", second.TrashID, entries[0].TrashID)
+	}
+
+	if err := repo.PurgeTrash(first.TrashID); err != nil {
+		t.Fatalf("PurgeTrash failed: %v", err)
+	}
+	entries, _ = repo.ListTrash()
+	if len(entries) != 1 || entries[0].TrashID != second.TrashID {
+		t.Errorf("expected only %s to remain, got %+v", second.TrashID, entries)
+	}
+}
@@ -0,0 +1,43 @@
+package filesystem
+
+import (
+	"path/filepath"
+
+	"libraio/internal/domain"
+	"libraio/internal/ports"
+)
+
+// WithVersionControl commits the paths touched by each mutation
+func WithVersionControl(vc ports.VersionControl) RepoOption {
+	return func(r *Repository) {
+		r.vcs = vc
+	}
+}
+
+// commitChange records the given absolute paths in version control, if enabled
+func (r *Repository) commitChange(operation, message string, paths ...string) error {
+	if r.vcs == nil {
+		return nil
+	}
+
+	relPaths := make([]string, 0, len(paths))
+	for _, p := range paths {
+		relPaths = append(relPaths, r.relPath(p))
+	}
+	return r.vcs.Commit(operation, message, relPaths)
+}
+
+// commitReport records the given absolute paths plus every file whose links were
+// rewritten. A failed commit does not undo the operation, so it is reported as a warning.
+func (r *Repository) commitReport(operation, message string, report *domain.OperationReport, paths ...string) {
+	if r.vcs == nil {
+		return
+	}
+
+	for _, fr := range report.FilesRewritten {
+		paths = append(paths, filepath.Join(r.vaultPath, fr.Path))
+	}
+	if err := r.commitChange(operation, message, paths...); err != nil {
+		report.Warnf("failed to commit %s: %v", operation, err)
+	}
+}
@@ -0,0 +1,166 @@
+package filesystem
+
+import (
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"libraio/internal/adapters/git"
+	"libraio/internal/domain"
+)
+
+// recordedCommit is a commit captured by fakeVersionControl
+type recordedCommit struct {
+	operation string
+	message   string
+	paths     []string
+}
+
+// fakeVersionControl records commits instead of running git
+type fakeVersionControl struct {
+	commits []recordedCommit
+	err     error
+}
+
+func (f *fakeVersionControl) Commit(operation, message string, paths []string) error {
+	f.commits = append(f.commits, recordedCommit{operation, message, paths})
+	return f.err
+}
+
+func (f *fakeVersionControl) History(limit int) ([]domain.HistoryEntry, error) {
+	return nil, nil
+}
+
+func TestArchiveItem_CommitsTouchedPaths(t *testing.T) {
+	vaultPath, cleanup := setupLinkTestVault(t)
+	defer cleanup()
+
+	vc := &fakeVersionControl{}
+	repo := NewRepository(vaultPath, WithVersionControl(vc))
+
+	if _, _, err := repo.ArchiveItem("S01.11.15"); err != nil {
+		t.Fatalf("ArchiveItem failed: %v", err)
+	}
+
+	if len(vc.commits) != 1 {
+		t.Fatalf("expected 1 commit, got %d", len(vc.commits))
+	}
+	commit := vc.commits[0]
+	if commit.operation != "archive" || commit.message != "archive S01.11.15 Theatre" {
+		t.Errorf("unexpected commit: %q %q", commit.operation, commit.message)
+	}
+
+	category := filepath.Join("S01 Personal", "S01.10-19 Lifestyle", "S01.11 Entertainment")
+	want := []string{
+		filepath.Join(category, "S01.11.15 Theatre"),
+		filepath.Join(category, "S01.11.09 Archive for S01.11", "[Archived] Theatre"),
+		filepath.Join(category, "S01.11.16 Links", "links.md"),
+	}
+	for _, p := range want {
+		if !slices.Contains(commit.paths, p) {
+			t.Errorf("commit should include %s, got %v", p, commit.paths)
+		}
+	}
+}
+
+func TestMoveItem_CommitMessage(t *testing.T) {
+	vaultPath, cleanup := setupLinkTestVault(t)
+	defer cleanup()
+
+	vc := &fakeVersionControl{}
+	repo := NewRepository(vaultPath, WithVersionControl(vc))
+
+	category, _, err := repo.CreateCategory("S01.10-19", "Hobbies")
+	if err != nil {
+		t.Fatalf("CreateCategory failed: %v", err)
+	}
+	item, _, err := repo.MoveItem("S01.11.15", category.ID)
+	if err != nil {
+		t.Fatalf("MoveItem failed: %v", err)
+	}
+
+	wantMessages := []string{
+		"create " + filepath.Base(category.Path),
+		"move S01.11.15 Theatre -> " + item.ID,
+	}
+	if len(vc.commits) != len(wantMessages) {
+		t.Fatalf("expected %d commits, got %d", len(wantMessages), len(vc.commits))
+	}
+	for i, want := range wantMessages {
+		if vc.commits[i].message != want {
+			t.Errorf("commit %d: expected message %q, got %q", i, want, vc.commits[i].message)
+		}
+	}
+}
+
+func TestCommitFailure_IsReportedNotFatal(t *testing.T) {
+	vaultPath, cleanup := setupLinkTestVault(t)
+	defer cleanup()
+
+	vc := &fakeVersionControl{err: errors.New("no identity configured")}
+	repo := NewRepository(vaultPath, WithVersionControl(vc))
+
+	_, report, err := repo.RenameItem("S01.11.15", "Drama")
+	if err != nil {
+		t.Fatalf("RenameItem should succeed despite the commit failure: %v", err)
+	}
+	if len(report.Warnings) != 1 || !strings.Contains(report.Warnings[0], "no identity configured") {
+		t.Errorf("expected a commit warning, got %v", report.Warnings)
+	}
+
+	entry, _, err := repo.Delete("S01.11.16")
+	if err == nil || !strings.Contains(err.Error(), "failed to commit") {
+		t.Errorf("expected commit error from Delete, got %v", err)
+	}
+	if entry == nil {
+		t.Error("Delete should still return the trash entry")
+	}
+}
+
+func TestCommit_NeverStagesAppDir(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	vaultPath, cleanup := setupLinkTestVault(t)
+	defer cleanup()
+
+	runGit := func(args ...string) string {
+		t.Helper()
+		out, err := exec.Command("git", append([]string{"-C", vaultPath}, args...)...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s failed: %v\n%s", strings.Join(args, " "), err, out)
+		}
+		return string(out)
+	}
+	runGit("init", "--quiet")
+	runGit("config", "user.name", "Test")
+	runGit("config", "user.email", "test@example.com")
+	runGit("config", "commit.gpgsign", "false")
+	runGit("add", "--all")
+	runGit("commit", "--quiet", "--message", "initial")
+
+	vc, err := git.Open(vaultPath)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	repo := NewRepository(vaultPath, WithVersionControl(vc), WithAutoBackup(domain.DefaultRetentionPolicy))
+
+	// Deleting fills the trash and takes a snapshot, then committing the whole
+	// vault must still leave libraio's own folder out
+	if _, _, err := repo.Delete("S01.11.15"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := repo.commitChange("test", "commit everything", vaultPath); err != nil {
+		t.Fatalf("commitChange failed: %v", err)
+	}
+
+	if tracked := runGit("ls-files", appDir); tracked != "" {
+		t.Errorf("%s should never be committed, got:\n%s", appDir, tracked)
+	}
+	if status := runGit("status", "--porcelain"); status != "" {
+		t.Errorf("%s should be ignored rather than left untracked, got:\n%s", appDir, status)
+	}
+}
@@ -0,0 +1,188 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"libraio/internal/domain"
+	"libraio/internal/ports"
+)
+
+// operationTrailer tags commits created by libraio so History can find them
+const operationTrailer = "Libraio-Operation"
+
+// ErrNotWorkTree is returned by Open when the vault is not inside a git work tree
+var ErrNotWorkTree = errors.New("vault is not inside a git work tree")
+
+// Repository implements ports.VersionControl using the local git binary
+type Repository struct {
+	root   string // Top level of the work tree
+	prefix string // Vault path relative to root ("" if the vault is the root)
+}
+
+// Ensure Repository implements ports.VersionControl
+var _ ports.VersionControl = (*Repository)(nil)
+
+// Open returns a Repository for the git work tree containing vaultPath.
+// It returns ErrNotWorkTree if git is not installed or the vault is not tracked.
+func Open(vaultPath string) (*Repository, error) {
+	// Expand ~ to home directory
+	if strings.HasPrefix(vaultPath, "~") {
+		home, _ := os.UserHomeDir()
+		vaultPath = filepath.Join(home, vaultPath[1:])
+	}
+
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, ErrNotWorkTree
+	}
+
+	out, err := run(vaultPath, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, ErrNotWorkTree
+	}
+	root := strings.TrimSpace(out)
+
+	// git reports the top level with symlinks resolved
+	resolved, err := filepath.EvalSymlinks(vaultPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve vault path: %w", err)
+	}
+	prefix, err := filepath.Rel(root, resolved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate vault in work tree: %w", err)
+	}
+	if prefix == "." {
+		prefix = ""
+	}
+
+	return &Repository{root: root, prefix: prefix}, nil
+}
+
+// Commit stages exactly the given vault-relative paths and commits them.
+// Paths may be files or folders, and may no longer exist (moves and deletions).
+func (g *Repository) Commit(operation, message string, paths []string) error {
+	changed, err := g.changedFiles(paths)
+	if err != nil {
+		return err
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	addArgs := append([]string{"add", "--all", "--"}, changed...)
+	if _, err := g.git(addArgs...); err != nil {
+		return fmt.Errorf("failed to stage changes: %w", err)
+	}
+
+	// Committing with pathspecs leaves anything else already staged alone
+	commitArgs := append([]string{
+		"commit", "--quiet",
+		"--message", message,
+		"--message", operationTrailer + ": " + operation,
+		"--",
+	}, changed...)
+	if _, err := g.git(commitArgs...); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+	return nil
+}
+
+// changedFiles returns the work-tree-relative files under paths that differ
+// from HEAD, including both sides of staged renames
+func (g *Repository) changedFiles(paths []string) ([]string, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	args := []string{"status", "--porcelain=v1", "-z", "--untracked-files=all", "--"}
+	for _, p := range paths {
+		args = append(args, filepath.ToSlash(filepath.Join(g.prefix, p)))
+	}
+	out, err := g.git(args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read git status: %w", err)
+	}
+
+	var files []string
+	records := strings.Split(out, "\x00")
+	for i := 0; i < len(records); i++ {
+		record := records[i]
+		if len(record) < 4 {
+			continue
+		}
+		files = append(files, record[3:])
+
+		// Renames and copies are followed by the original path
+		if (record[0] == 'R' || record[0] == 'C') && i+1 < len(records) {
+			i++
+			files = append(files, records[i])
+		}
+	}
+	return files, nil
+}
+
+// History returns commits created by libraio, most recent first
+func (g *Repository) History(limit int) ([]domain.HistoryEntry, error) {
+	args := []string{
+		"log",
+		"--grep", "^" + operationTrailer + ":",
+		"--format=%H%x1f%an%x1f%aI%x1f%s%x1f%(trailers:key=" + operationTrailer + ",valueonly,separator=%x2C)%x1e",
+	}
+	if limit > 0 {
+		args = append(args, "-n", strconv.Itoa(limit))
+	}
+
+	out, err := g.git(args...)
+	if err != nil {
+		// A repository without commits has no history yet
+		if _, headErr := g.git("rev-parse", "--verify", "--quiet", "HEAD"); headErr != nil {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read git log: %w", err)
+	}
+
+	var entries []domain.HistoryEntry
+	for _, record := range strings.Split(out, "\x1e") {
+		fields := strings.Split(strings.TrimSpace(record), "\x1f")
+		if len(fields) != 5 {
+			continue
+		}
+		date, _ := time.Parse(time.RFC3339, fields[2])
+		entries = append(entries, domain.HistoryEntry{
+			Hash:      fields[0],
+			Author:    fields[1],
+			Date:      date,
+			Subject:   fields[3],
+			Operation: strings.TrimSpace(fields[4]),
+		})
+	}
+	return entries, nil
+}
+
+// git runs a git command at the top level of the work tree
+func (g *Repository) git(args ...string) (string, error) {
+	return run(g.root, args...)
+}
+
+// run runs a git command in dir and returns its standard output.
+// Pathspecs are literal so folder names like "[Archived] Theatre" are not globs.
+func run(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir, "--literal-pathspecs"}, args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return stdout.String(), nil
+}
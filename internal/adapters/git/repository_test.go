@@ -0,0 +1,195 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setupGitRepo creates a work tree with an initial commit containing files,
+// and returns the path of the vault inside it
+func setupGitRepo(t *testing.T, vaultDir string, files map[string]string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	root := t.TempDir()
+	gitCmd(t, root, "init", "--quiet")
+	gitCmd(t, root, "config", "user.name", "Test")
+	gitCmd(t, root, "config", "user.email", "test@example.com")
+	gitCmd(t, root, "config", "commit.gpgsign", "false")
+
+	vaultPath := filepath.Join(root, vaultDir)
+	for name, content := range files {
+		writeFile(t, filepath.Join(vaultPath, name), content)
+	}
+	gitCmd(t, root, "add", "--all")
+	gitCmd(t, root, "commit", "--quiet", "--message", "initial")
+
+	return vaultPath
+}
+
+func gitCmd(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s failed: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return string(out)
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOpen_NotWorkTree(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	if _, err := Open(t.TempDir()); err != ErrNotWorkTree {
+		t.Errorf("expected ErrNotWorkTree, got %v", err)
+	}
+}
+
+func TestCommit_StagesOnlyTouchedPaths(t *testing.T) {
+	vaultPath := setupGitRepo(t, "", map[string]string{
+		"S01.11.15 Theatre/README.md": "theatre",
+		"notes.md":                    "[[S01.11.15 Theatre]]",
+		"other.md":                    "unrelated",
+	})
+
+	// Simulate a move with a link rewrite, plus an unrelated pending edit
+	if err := os.Rename(filepath.Join(vaultPath, "S01.11.15 Theatre"), filepath.Join(vaultPath, "S01.12.11 Theatre")); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(vaultPath, "notes.md"), "[[S01.12.11 Theatre]]")
+	writeFile(t, filepath.Join(vaultPath, "other.md"), "edited")
+
+	repo, err := Open(vaultPath)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	err = repo.Commit("move", "move S01.11.15 Theatre -> S01.12.11",
+		[]string{"S01.11.15 Theatre", "S01.12.11 Theatre", "notes.md"})
+	if err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+
+	status := gitCmd(t, vaultPath, "status", "--porcelain")
+	if strings.TrimSpace(status) != "M other.md" {
+		t.Errorf("only other.md should remain uncommitted, got:\n%s", status)
+	}
+
+	files := gitCmd(t, vaultPath, "show", "--name-status", "--format=", "HEAD")
+	for _, want := range []string{"S01.11.15 Theatre/README.md", "S01.12.11 Theatre/README.md", "notes.md"} {
+		if !strings.Contains(files, want) {
+			t.Errorf("commit should include %s, got:\n%s", want, files)
+		}
+	}
+}
+
+func TestCommit_LiteralPathsInSubdirectory(t *testing.T) {
+	vaultPath := setupGitRepo(t, "vault", map[string]string{
+		"S01.11.15 Theatre/README.md": "theatre",
+	})
+
+	archived := filepath.Join(vaultPath, "S01.11.09 Archive", "[Archived] Theatre")
+	if err := os.MkdirAll(filepath.Dir(archived), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(filepath.Join(vaultPath, "S01.11.15 Theatre"), archived); err != nil {
+		t.Fatal(err)
+	}
+
+	repo, err := Open(vaultPath)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	err = repo.Commit("archive", "archive S01.11.15 Theatre",
+		[]string{"S01.11.15 Theatre", filepath.Join("S01.11.09 Archive", "[Archived] Theatre")})
+	if err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+
+	if status := gitCmd(t, vaultPath, "status", "--porcelain"); status != "" {
+		t.Errorf("work tree should be clean, got:\n%s", status)
+	}
+}
+
+func TestCommit_NothingChanged(t *testing.T) {
+	vaultPath := setupGitRepo(t, "", map[string]string{"notes.md": "hello"})
+
+	repo, err := Open(vaultPath)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	// Empty folders are invisible to git, so creating one commits nothing
+	if err := os.Mkdir(filepath.Join(vaultPath, "S01.11.16 Opera"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Commit("create", "create S01.11.16 Opera", []string{"S01.11.16 Opera"}); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+
+	entries, err := repo.History(0)
+	if err != nil {
+		t.Fatalf("History failed: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected no commits, got %+v", entries)
+	}
+}
+
+func TestHistory_OnlyLibraioCommits(t *testing.T) {
+	vaultPath := setupGitRepo(t, "", map[string]string{"notes.md": "hello"})
+
+	repo, err := Open(vaultPath)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	writeFile(t, filepath.Join(vaultPath, "notes.md"), "first")
+	if err := repo.Commit("rename", "rename S01.11.15 Theatre -> Drama", []string{"notes.md"}); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+
+	writeFile(t, filepath.Join(vaultPath, "manual.md"), "by hand")
+	gitCmd(t, vaultPath, "add", "manual.md")
+	gitCmd(t, vaultPath, "commit", "--quiet", "--message", "manual edit")
+
+	writeFile(t, filepath.Join(vaultPath, "notes.md"), "second")
+	if err := repo.Commit("delete", "delete S01.11.16 Opera", []string{"notes.md"}); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+
+	entries, err := repo.History(0)
+	if err != nil {
+		t.Fatalf("History failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 libraio commits, got %+v", entries)
+	}
+	if entries[0].Operation != "delete" || entries[0].Subject != "delete S01.11.16 Opera" {
+		t.Errorf("unexpected latest entry: %+v", entries[0])
+	}
+	if entries[1].Operation != "rename" || entries[1].Author != "Test" || entries[1].Date.IsZero() {
+		t.Errorf("unexpected oldest entry: %+v", entries[1])
+	}
+
+	limited, err := repo.History(1)
+	if err != nil {
+		t.Fatalf("History failed: %v", err)
+	}
+	if len(limited) != 1 {
+		t.Errorf("expected 1 entry with limit, got %d", len(limited))
+	}
+}
@@ -0,0 +1,231 @@
+package sqlite
+
+import (
+	"cmp"
+	"database/sql"
+	"encoding/binary"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"libraio/internal/domain"
+)
+
+// ftsModule identifies the SQLite full-text engine backing content search.
+// FTS5 needs the sqlite_fts5 build tag; FTS4 is always compiled in.
+type ftsModule int
+
+const (
+	fts4 ftsModule = iota
+	fts5
+)
+
+// maxContentSize caps how much of a file body is indexed
+const maxContentSize = 1 << 20
+
+// defaultContentLimit is used when SearchContent is called without a limit
+const defaultContentLimit = 50
+
+// snippetTokens is the approximate number of tokens in a snippet
+const snippetTokens = 12
+
+// ensureContentTable creates the full-text table if needed, preferring FTS5,
+// and records which module backs it
+func (idx *Index) ensureContentTable() error {
+	var ddl string
+	err := idx.db.QueryRow(`SELECT sql FROM sqlite_master WHERE name = 'content_fts'`).Scan(&ddl)
+	if err == nil {
+		idx.fts = fts4
+		if strings.Contains(strings.ToLower(ddl), "fts5") {
+			idx.fts = fts5
+		}
+		// An FTS5 table cannot be read, or even dropped, by a build without FTS5
+		if _, err := idx.db.Exec(`SELECT 1 FROM content_fts WHERE 0`); err != nil {
+			return fmt.Errorf("index %s was built with a different full-text engine; delete it to rebuild: %w", idx.dbPath, err)
+		}
+		return nil
+	}
+	if err != sql.ErrNoRows {
+		return err
+	}
+
+	_, err = idx.db.Exec(`CREATE VIRTUAL TABLE content_fts USING fts5(path UNINDEXED, body, tokenize = 'unicode61 remove_diacritics 2')`)
+	if err == nil {
+		idx.fts = fts5
+		return nil
+	}
+	if !strings.Contains(err.Error(), "no such module") {
+		return err
+	}
+
+	if _, err := idx.db.Exec(`CREATE VIRTUAL TABLE content_fts USING fts4(path, body, notindexed=path, tokenize=unicode61)`); err != nil {
+		return err
+	}
+	idx.fts = fts4
+	return nil
+}
+
+// indexableBody returns the text to index for a file, or false for binary
+// content. A canvas is indexed by the text of its cards, not its JSON.
+func indexableBody(path string, content []byte) (string, bool) {
+	if strings.EqualFold(filepath.Ext(path), ".canvas") {
+		return domain.CanvasText(content), true
+	}
+	if len(content) > maxContentSize {
+		content = content[:maxContentSize]
+	}
+	if !utf8.Valid(content) {
+		return "", false
+	}
+	return string(content), true
+}
+
+// SearchContent returns files whose body matches query, best match first.
+// Every word must match; the last word also matches as a prefix so results
+// can be shown while typing.
+func (idx *Index) SearchContent(query string, limit int) ([]domain.ContentMatch, error) {
+	match := idx.ftsQuery(query)
+	if match == "" {
+		return nil, nil
+	}
+	if limit <= 0 {
+		limit = defaultContentLimit
+	}
+
+	if idx.fts == fts5 {
+		return idx.searchFTS5(match, limit)
+	}
+	return idx.searchFTS4(match, limit)
+}
+
+func (idx *Index) searchFTS5(match string, limit int) ([]domain.ContentMatch, error) {
+	rows, err := idx.db.Query(`
+		SELECT path, snippet(content_fts, 1, ?, ?, '…', ?), bm25(content_fts)
+		FROM content_fts WHERE content_fts MATCH ?
+		ORDER BY rank LIMIT ?
+	`, domain.HighlightStart, domain.HighlightEnd, snippetTokens, match, limit)
+	if err != nil {
+		return nil, fmt.Errorf("content search failed: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var matches []domain.ContentMatch
+	for rows.Next() {
+		var m domain.ContentMatch
+		if err := rows.Scan(&m.Path, &m.Snippet, &m.Rank); err != nil {
+			return nil, err
+		}
+		m.Snippet = flattenSnippet(m.Snippet)
+		matches = append(matches, m)
+	}
+	return matches, rows.Err()
+}
+
+// searchFTS4 ranks in Go because FTS4 has no built-in ranking function
+func (idx *Index) searchFTS4(match string, limit int) ([]domain.ContentMatch, error) {
+	rows, err := idx.db.Query(`
+		SELECT path, snippet(content_fts, ?, ?, '…', 1, ?), matchinfo(content_fts, 'pcx')
+		FROM content_fts WHERE content_fts MATCH ?
+	`, domain.HighlightStart, domain.HighlightEnd, snippetTokens, match)
+	if err != nil {
+		return nil, fmt.Errorf("content search failed: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var matches []domain.ContentMatch
+	for rows.Next() {
+		var m domain.ContentMatch
+		var info []byte
+		if err := rows.Scan(&m.Path, &m.Snippet, &info); err != nil {
+			return nil, err
+		}
+		m.Snippet = flattenSnippet(m.Snippet)
+		m.Rank = matchinfoRank(info)
+		matches = append(matches, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sortByRank(matches)
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
+
+// flattenSnippet collapses line breaks and runs of whitespace so a snippet fits on one line
+func flattenSnippet(snippet string) string {
+	return strings.Join(strings.Fields(snippet), " ")
+}
+
+// matchinfoRank scores an FTS4 'pcx' matchinfo blob: for each phrase, hits in
+// this row's body weighted by how rare the phrase is across all rows. The
+// result is negated so that, as with bm25, lower is better.
+func matchinfoRank(info []byte) float64 {
+	values := make([]uint32, len(info)/4)
+	for i := range values {
+		values[i] = binary.NativeEndian.Uint32(info[i*4:])
+	}
+	if len(values) < 2 {
+		return 0
+	}
+
+	phrases, columns := int(values[0]), int(values[1])
+	const bodyColumn = 1
+	score := 0.0
+	for p := 0; p < phrases; p++ {
+		base := 2 + 3*(p*columns+bodyColumn)
+		if base+1 >= len(values) {
+			break
+		}
+		hitsThisRow, hitsAllRows := values[base], values[base+1]
+		score += float64(hitsThisRow) / float64(1+hitsAllRows)
+	}
+	return -score
+}
+
+// sortByRank sorts matches best first
+func sortByRank(matches []domain.ContentMatch) {
+	slices.SortStableFunc(matches, func(a, b domain.ContentMatch) int {
+		return cmp.Compare(a.Rank, b.Rank)
+	})
+}
+
+// ftsQuery converts free text into a MATCH expression that cannot fail to parse:
+// each whitespace-separated word becomes a quoted phrase of its tokens, and the
+// last word is a prefix query
+func (idx *Index) ftsQuery(query string) string {
+	var phrases []string
+	for _, word := range strings.Fields(query) {
+		tokens := strings.FieldsFunc(strings.ToLower(word), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+		})
+		if len(tokens) > 0 {
+			phrases = append(phrases, strings.Join(tokens, " "))
+		}
+	}
+	if len(phrases) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	for i, phrase := range phrases {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		last := i == len(phrases)-1
+		switch {
+		case last && idx.fts == fts5:
+			fmt.Fprintf(&b, `"%s"*`, phrase)
+		case last:
+			fmt.Fprintf(&b, `"%s*"`, phrase)
+		default:
+			fmt.Fprintf(&b, `"%s"`, phrase)
+		}
+	}
+	return b.String()
+}
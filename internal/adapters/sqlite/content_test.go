@@ -0,0 +1,179 @@
+package sqlite
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"libraio/internal/domain"
+)
+
+// setupContentVault creates a small vault and an index stored in a temp data dir
+func setupContentVault(t *testing.T) (string, *Index) {
+	t.Helper()
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	vaultPath := t.TempDir()
+	itemPath := filepath.Join(vaultPath, "S01 Personal", "S01.10-19 Lifestyle", "S01.11 Entertainment", "S01.11.15 Theatre")
+	if err := os.MkdirAll(itemPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		filepath.Join(itemPath, "review.md"):   "# Review\n\nThe opening night was a triumph.\nThe opening act, the opening aria: all superb.",
+		filepath.Join(itemPath, "tickets.md"):  "Tickets for the opening night are in the drawer. [Review](review.md)",
+		filepath.Join(vaultPath, "journal.md"): "Went to the theatre with [[S01.11.15 Theatre]]. ![Poster](S01%20Personal/S01.10-19%20Lifestyle/S01.11%20Entertainment/S01.11.15%20Theatre/poster.png)",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	idx := NewIndex()
+	if err := idx.Open(vaultPath); err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	t.Cleanup(func() { _ = idx.Close() })
+
+	if _, err := idx.SyncFull(); err != nil {
+		t.Fatalf("SyncFull failed: %v", err)
+	}
+	return vaultPath, idx
+}
+
+func matchPaths(matches []domain.ContentMatch) []string {
+	paths := make([]string, len(matches))
+	for i, m := range matches {
+		paths[i] = filepath.Base(m.Path)
+	}
+	return paths
+}
+
+func TestSearchContent_RanksAndHighlights(t *testing.T) {
+	_, idx := setupContentVault(t)
+
+	matches, err := idx.SearchContent("opening", 0)
+	if err != nil {
+		t.Fatalf("SearchContent failed: %v", err)
+	}
+	if got := matchPaths(matches); len(got) != 2 || got[0] != "review.md" {
+		t.Fatalf("expected review.md ranked first of 2 matches, got %v", got)
+	}
+
+	snippet := matches[0].Snippet
+	if !strings.Contains(snippet, domain.HighlightStart+"opening"+domain.HighlightEnd) {
+		t.Errorf("snippet should highlight the match, got %q", snippet)
+	}
+	if strings.Contains(snippet, "\n") {
+		t.Errorf("snippet should fit on one line, got %q", snippet)
+	}
+}
+
+func TestSearchContent_Query(t *testing.T) {
+	_, idx := setupContentVault(t)
+
+	tests := []struct {
+		name  string
+		query string
+		want  int
+	}{
+		{"all words must match", "opening drawer", 1},
+		{"last word is a prefix", "open", 2},
+		{"case insensitive", "TRIUMPH", 1},
+		{"ID tokens", "S01.11.15", 1},
+		{"syntax characters are ignored", `"opening" (night*`, 2},
+		{"no match", "symphony", 0},
+		{"only punctuation", "***", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches, err := idx.SearchContent(tt.query, 0)
+			if err != nil {
+				t.Fatalf("SearchContent(%q) failed: %v", tt.query, err)
+			}
+			if len(matches) != tt.want {
+				t.Errorf("SearchContent(%q) = %v, want %d matches", tt.query, matchPaths(matches), tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchContent_Limit(t *testing.T) {
+	_, idx := setupContentVault(t)
+
+	matches, err := idx.SearchContent("opening", 1)
+	if err != nil {
+		t.Fatalf("SearchContent failed: %v", err)
+	}
+	if len(matches) != 1 {
+		t.Errorf("expected 1 match with limit, got %d", len(matches))
+	}
+}
+
+func TestSyncIncremental_UpdatesContent(t *testing.T) {
+	vaultPath, idx := setupContentVault(t)
+
+	itemPath := filepath.Join(vaultPath, "S01 Personal", "S01.10-19 Lifestyle", "S01.11 Entertainment", "S01.11.15 Theatre")
+	if err := os.Remove(filepath.Join(itemPath, "tickets.md")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Make sure the edit is newer than the last sync
+	future := time.Now().Add(2 * time.Second)
+	notes := filepath.Join(itemPath, "notes.md")
+	if err := os.WriteFile(notes, []byte("A symphony after the play."), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(notes, future, future); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := idx.SyncIncremental(); err != nil {
+		t.Fatalf("SyncIncremental failed: %v", err)
+	}
+
+	if matches, _ := idx.SearchContent("symphony", 0); len(matches) != 1 {
+		t.Errorf("new file should be searchable, got %v", matchPaths(matches))
+	}
+	if matches, _ := idx.SearchContent("drawer", 0); len(matches) != 0 {
+		t.Errorf("deleted file should not be searchable, got %v", matchPaths(matches))
+	}
+}
+
+func TestSyncIncremental_IndexesCanvases(t *testing.T) {
+	vaultPath, idx := setupContentVault(t)
+
+	canvas := `{"nodes":[` +
+		`{"id":"a","type":"text","text":"Seating plan for [[S01.11.15]]","x":0,"y":0,"width":250,"height":60},` +
+		`{"id":"b","type":"file","file":"S01 Personal/S01.10-19 Lifestyle/S01.11 Entertainment/S01.11.15 Theatre/review.md","x":300,"y":0,"width":400,"height":400}` +
+		`],"edges":[]}`
+	board := filepath.Join(vaultPath, "board.canvas")
+	if err := os.WriteFile(board, []byte(canvas), 0644); err != nil {
+		t.Fatal(err)
+	}
+	future := time.Now().Add(2 * time.Second)
+	if err := os.Chtimes(board, future, future); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := idx.SyncIncremental(); err != nil {
+		t.Fatalf("SyncIncremental failed: %v", err)
+	}
+
+	edges, err := idx.FindLinksFromFile("board.canvas")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(edges) != 2 || edges[0].TargetJDID != "S01.11.15" || filepath.Base(edges[1].TargetPath) != "review.md" {
+		t.Errorf("canvas edges = %+v, want the text node link and the file node", edges)
+	}
+	if matches, _ := idx.SearchContent("seating", 0); len(matches) != 1 {
+		t.Errorf("canvas cards should be searchable, got %v", matchPaths(matches))
+	}
+	if matches, _ := idx.SearchContent("width", 0); len(matches) != 0 {
+		t.Errorf("canvas JSON should not be searchable, got %v", matchPaths(matches))
+	}
+	assertSameAsFullSync(t, vaultPath, idx)
+}
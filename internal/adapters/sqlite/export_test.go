@@ -0,0 +1,54 @@
+package sqlite
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Dump returns every row of the index as sorted lines, so tests can compare
+// an incrementally maintained index with a freshly built one
+func (idx *Index) Dump() ([]string, error) {
+	queries := []string{
+		`SELECT 'node', path, parent, COALESCE(jd_id, ''), COALESCE(jd_type, ''), name, is_dir, mtime, COALESCE(hash, ''), COALESCE(file_id, 0) FROM nodes`,
+		`SELECT 'edge', source_path, target_jd_id, COALESCE(target_path, ''), COALESCE(target_name, ''), link_text FROM edges`,
+		`SELECT 'name', name, path, dir, jd_id, alias FROM note_names`,
+		`SELECT 'property', path, jd_id, key, value FROM properties`,
+		`SELECT 'tag', path, jd_id, tag FROM tags`,
+		`SELECT 'content', path, body FROM content_fts`,
+	}
+
+	var lines []string
+	for _, q := range queries {
+		rows, err := idx.db.Query(q)
+		if err != nil {
+			return nil, err
+		}
+		cols, _ := rows.Columns()
+		for rows.Next() {
+			values := make([]any, len(cols))
+			ptrs := make([]any, len(cols))
+			for i := range values {
+				ptrs[i] = &values[i]
+			}
+			if err := rows.Scan(ptrs...); err != nil {
+				_ = rows.Close()
+				return nil, err
+			}
+			fields := make([]string, len(values))
+			for i, v := range values {
+				if b, ok := v.([]byte); ok {
+					v = string(b)
+				}
+				fields[i] = fmt.Sprint(v)
+			}
+			lines = append(lines, strings.Join(fields, " | "))
+		}
+		if err := rows.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	slices.Sort(lines)
+	return lines, nil
+}
@@ -0,0 +1,11 @@
+//go:build !unix
+
+package sqlite
+
+import "os"
+
+// fileID has no stable identity to offer on this platform, so folders renamed
+// outside libraio are indexed as removed and added
+func fileID(info os.FileInfo) int64 {
+	return 0
+}
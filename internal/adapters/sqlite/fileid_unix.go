@@ -0,0 +1,17 @@
+//go:build unix
+
+package sqlite
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileID returns the inode of an entry, which stays the same when the entry
+// is renamed or moved within the same filesystem
+func fileID(info os.FileInfo) int64 {
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		return int64(st.Ino)
+	}
+	return 0
+}
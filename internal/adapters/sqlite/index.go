@@ -7,28 +7,44 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
 
 	"libraio/internal/domain"
 	"libraio/internal/ports"
-
-	_ "github.com/mattn/go-sqlite3"
 )
 
-const schemaVersion = "1"
-
 // Index implements ports.VaultIndex using SQLite
 type Index struct {
-	db        *sql.DB
-	vaultPath string
-	dbPath    string
+	db              *sql.DB
+	vaultPath       string
+	dbPath          string
+	fts             ftsModule // Full-text engine backing content search
+	manualMigration bool      // Open leaves the schema as is; see WithManualMigrations
 }
 
 // Ensure Index implements VaultIndex
 var _ ports.VaultIndex = (*Index)(nil)
 
+// IndexOption configures an Index
+type IndexOption func(*Index)
+
+// WithManualMigrations makes Open leave the schema as it is, for inspecting it
+// with Status before calling Migrate. Queries fail until the schema is current.
+func WithManualMigrations() IndexOption {
+	return func(idx *Index) {
+		idx.manualMigration = true
+	}
+}
+
 // NewIndex creates a new SQLite index
-func NewIndex() *Index {
-	return &Index{}
+func NewIndex(opts ...IndexOption) *Index {
+	idx := &Index{}
+	for _, opt := range opts {
+		opt(idx)
+	}
+	return idx
 }
 
 // Open initializes the index for the given vault path
@@ -51,7 +67,7 @@ func (idx *Index) Open(vaultPath string) error {
 	}
 
 	// Open database with WAL mode for better concurrency
-	db, err := sql.Open("sqlite3", idx.dbPath+"?_journal_mode=WAL")
+	db, err := sql.Open(driverName, idx.dbPath+"?_journal_mode=WAL")
 	if err != nil {
 		return fmt.Errorf("failed to open database: %w", err)
 	}
@@ -62,33 +78,15 @@ func (idx *Index) Open(vaultPath string) error {
 		return fmt.Errorf("failed to set pragmas: %w", err)
 	}
 
-	// Check if schema exists (fast path for warm startup)
-	var exists int
-	if err := db.QueryRow(`SELECT 1 FROM sqlite_master WHERE type='table' AND name='meta' LIMIT 1`).Scan(&exists); err != nil {
-		// Schema doesn't exist, create it
-		_, err = db.Exec(`
-			CREATE TABLE nodes (path TEXT PRIMARY KEY, jd_id TEXT, jd_type TEXT, name TEXT, mtime INTEGER NOT NULL);
-			CREATE TABLE edges (source_path TEXT NOT NULL, target_jd_id TEXT NOT NULL, link_text TEXT NOT NULL, PRIMARY KEY (source_path, link_text));
-			CREATE TABLE meta (key TEXT PRIMARY KEY, value TEXT NOT NULL);
-			CREATE INDEX idx_nodes_jd_id ON nodes(jd_id);
-			CREATE INDEX idx_edges_target ON edges(target_jd_id);
-			CREATE INDEX idx_edges_source ON edges(source_path);
-		`)
-		if err != nil {
-			if cerr := db.Close(); cerr != nil {
-				return fmt.Errorf("failed to create schema: %w (also failed to close db: %v)", err, cerr)
-			}
-			return fmt.Errorf("failed to create schema: %w", err)
-		}
-		// Set initial metadata for new DB
-		if err := idx.updateMeta(); err != nil {
-			if cerr := db.Close(); cerr != nil {
-				return fmt.Errorf("failed to set metadata: %w (also failed to close db: %v)", err, cerr)
-			}
-			return fmt.Errorf("failed to set metadata: %w", err)
+	if idx.manualMigration {
+		return nil
+	}
+	if _, err := idx.Migrate(); err != nil {
+		if cerr := db.Close(); cerr != nil {
+			return fmt.Errorf("failed to migrate index: %w (also failed to close db: %v)", err, cerr)
 		}
+		return fmt.Errorf("failed to migrate index: %w", err)
 	}
-
 	return nil
 }
 
@@ -102,16 +100,15 @@ func (idx *Index) Close() error {
 
 // NeedsFullRebuild returns true if the index should be fully rebuilt
 func (idx *Index) NeedsFullRebuild() bool {
-	var version, vaultHash string
+	var vaultHash string
 	var nodeCount int
 
-	_ = idx.db.QueryRow("SELECT value FROM meta WHERE key = 'schema_version'").Scan(&version)
 	_ = idx.db.QueryRow("SELECT value FROM meta WHERE key = 'vault_path_hash'").Scan(&vaultHash)
 	_ = idx.db.QueryRow("SELECT COUNT(*) FROM nodes").Scan(&nodeCount)
 
 	expectedHash := hashVaultPath(idx.vaultPath)
 
-	return version != schemaVersion || vaultHash != expectedHash || nodeCount == 0
+	return idx.storedDerivedVersion() != derivedVersion || vaultHash != expectedHash || nodeCount == 0
 }
 
 // databasePath returns the path for the SQLite database
@@ -135,63 +132,106 @@ func hashVaultPath(vaultPath string) string {
 	return hex.EncodeToString(h[:8]) // First 8 bytes = 16 hex chars
 }
 
-// updateMeta updates the schema version and vault path hash
-func (idx *Index) updateMeta() error {
-	_, err := idx.db.Exec(`
-		INSERT OR REPLACE INTO meta (key, value) VALUES ('schema_version', ?);
-		INSERT OR REPLACE INTO meta (key, value) VALUES ('vault_path_hash', ?);
-	`, schemaVersion, hashVaultPath(idx.vaultPath))
-	return err
-}
+// nodeColumns lists the nodes columns read by scanNode
+const nodeColumns = `path, jd_id, jd_type, name, is_dir, mtime, hash`
 
-// GetNode retrieves a node by path
-func (idx *Index) GetNode(path string) (*domain.IndexNode, error) {
+// scanNode reads a node selected with nodeColumns
+func scanNode(row interface{ Scan(...any) error }) (*domain.IndexNode, error) {
 	var node domain.IndexNode
-	var jdType sql.NullString
-
-	err := idx.db.QueryRow(`
-		SELECT path, jd_id, jd_type, name, mtime
-		FROM nodes WHERE path = ?
-	`, path).Scan(&node.Path, &node.JDID, &jdType, &node.Name, &node.Mtime)
+	var jdID, jdType, name, hash sql.NullString
+	if err := row.Scan(&node.Path, &jdID, &jdType, &name, &node.IsDir, &node.Mtime, &hash); err != nil {
+		return nil, err
+	}
+	node.Hash = hash.String
+	node.JDID = jdID.String
+	node.JDType = parseJDType(jdType.String)
+	node.Name = name.String
+	return &node, nil
+}
 
-	if err == sql.ErrNoRows {
-		return nil, nil
+// parseJDType converts a stored jd_type (IDType.String) back to an IDType
+func parseJDType(s string) domain.IDType {
+	for _, t := range []domain.IDType{domain.IDTypeScope, domain.IDTypeArea, domain.IDTypeCategory, domain.IDTypeItem} {
+		if s == t.String() {
+			return t
+		}
 	}
+	return domain.IDTypeUnknown
+}
+
+// queryNodes runs a query selecting nodeColumns and collects the results
+func (idx *Index) queryNodes(query string, args ...any) ([]domain.IndexNode, error) {
+	rows, err := idx.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
+	defer func() { _ = rows.Close() }()
 
-	if jdType.Valid {
-		node.JDType = domain.ParseIDType(jdType.String)
+	var nodes []domain.IndexNode
+	for rows.Next() {
+		node, err := scanNode(rows)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, *node)
 	}
+	return nodes, rows.Err()
+}
 
-	return &node, nil
+// GetNode retrieves a node by path
+func (idx *Index) GetNode(path string) (*domain.IndexNode, error) {
+	node, err := scanNode(idx.db.QueryRow(`SELECT `+nodeColumns+` FROM nodes WHERE path = ?`, path))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return node, err
 }
 
-// GetNodeByJDID retrieves a node by Johnny Decimal ID
+// GetNodeByJDID retrieves a node by Johnny Decimal ID. An ID may be indexed
+// more than once, as when a category archived with its ID is reused; the
+// shallowest node wins, which is the live one.
 func (idx *Index) GetNodeByJDID(jdID string) (*domain.IndexNode, error) {
-	var node domain.IndexNode
-	var jdType sql.NullString
-
-	err := idx.db.QueryRow(`
-		SELECT path, jd_id, jd_type, name, mtime
-		FROM nodes WHERE jd_id = ?
-	`, jdID).Scan(&node.Path, &node.JDID, &jdType, &node.Name, &node.Mtime)
-
+	node, err := scanNode(idx.db.QueryRow(`SELECT `+nodeColumns+` FROM nodes WHERE jd_id = ?
+		ORDER BY length(path) - length(replace(path, '/', '')), path LIMIT 1`, jdID))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
+	return node, err
+}
+
+// ListChildren returns the entries directly inside parentPath ("." for the
+// vault root), sorted by path
+func (idx *Index) ListChildren(parentPath string) ([]domain.IndexNode, error) {
+	return idx.queryNodes(`SELECT `+nodeColumns+` FROM nodes WHERE parent = ? ORDER BY path`, parentPath)
+}
+
+// SearchNames returns the entries whose file or folder name contains query,
+// ignoring case, sorted by path
+func (idx *Index) SearchNames(query string) ([]domain.IndexNode, error) {
+	// LIKE narrows the scan to matching paths; names are then checked exactly.
+	// LIKE only folds ASCII case, so other queries check every path.
+	pattern := "%"
+	if strings.IndexFunc(query, func(r rune) bool { return r > unicode.MaxASCII }) < 0 {
+		pattern = "%" + likeEscaper.Replace(query) + "%"
+	}
+	nodes, err := idx.queryNodes(`SELECT `+nodeColumns+` FROM nodes WHERE path LIKE ? ESCAPE '\' AND path != '.' ORDER BY path`, pattern)
 	if err != nil {
 		return nil, err
 	}
 
-	if jdType.Valid {
-		node.JDType = domain.ParseIDType(jdType.String)
+	query = strings.ToLower(query)
+	matches := nodes[:0]
+	for _, node := range nodes {
+		if strings.Contains(strings.ToLower(filepath.Base(node.Path)), query) {
+			matches = append(matches, node)
+		}
 	}
-
-	return &node, nil
+	return matches, nil
 }
 
+// likeEscaper escapes LIKE wildcards so a query matches literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
 // GetNextAvailableItemID returns the next available item number for a category
 func (idx *Index) GetNextAvailableItemID(categoryID string) (int, error) {
 	var maxID sql.NullInt64
@@ -252,12 +292,12 @@ func (idx *Index) GetNextAvailableCategoryID(areaID string) (int, error) {
 	return int(maxID.Int64) + 1, nil
 }
 
-// FindLinksToID returns all edges pointing to a JD ID
-func (idx *Index) FindLinksToID(targetJDID string) ([]domain.Edge, error) {
-	rows, err := idx.db.Query(`
-		SELECT source_path, target_jd_id, link_text
-		FROM edges WHERE target_jd_id = ?
-	`, targetJDID)
+// edgeColumns lists the edges columns read by queryEdges
+const edgeColumns = `source_path, target_jd_id, target_path, target_name, link_text, kind, line, col, context, occurrence, occurrences`
+
+// queryEdges runs a query selecting edgeColumns and collects the results
+func (idx *Index) queryEdges(query string, args ...any) ([]domain.Edge, error) {
+	rows, err := idx.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -266,36 +306,83 @@ func (idx *Index) FindLinksToID(targetJDID string) ([]domain.Edge, error) {
 	var edges []domain.Edge
 	for rows.Next() {
 		var e domain.Edge
-		if err := rows.Scan(&e.SourcePath, &e.TargetJDID, &e.LinkText); err != nil {
+		var targetPath, targetName sql.NullString
+		if err := rows.Scan(&e.SourcePath, &e.TargetJDID, &targetPath, &targetName, &e.LinkText,
+			&e.Kind, &e.Line, &e.Column, &e.Context, &e.Occurrence, &e.Occurrences); err != nil {
 			return nil, err
 		}
+		e.TargetPath, e.TargetName = targetPath.String, targetName.String
 		edges = append(edges, e)
 	}
 
 	return edges, rows.Err()
 }
 
-// FindLinksFromFile returns all edges from a source file
-func (idx *Index) FindLinksFromFile(sourcePath string) ([]domain.Edge, error) {
-	rows, err := idx.db.Query(`
-		SELECT source_path, target_jd_id, link_text
-		FROM edges WHERE source_path = ?
-	`, sourcePath)
+// FindLinksToID returns all edges pointing to a JD ID
+func (idx *Index) FindLinksToID(targetJDID string) ([]domain.Edge, error) {
+	return idx.queryEdges(`
+		SELECT `+edgeColumns+`
+		FROM edges WHERE target_jd_id = ?
+	`, targetJDID)
+}
+
+// FindLinksToName returns the wiki links naming a note, ignoring case and any
+// folder prefix: [[S01.11.15 Theatre]], [[Folder/s01.11.15 theatre#Cast]]
+func (idx *Index) FindLinksToName(name string) ([]domain.Edge, error) {
+	// LIKE narrows the scan to links containing the name; LIKE only folds ASCII
+	// case, so other names check every wiki link
+	pattern := "%"
+	if strings.IndexFunc(name, func(r rune) bool { return r > unicode.MaxASCII }) < 0 {
+		pattern = "%" + likeEscaper.Replace(name) + "%"
+	}
+	edges, err := idx.queryEdges(`
+		SELECT `+edgeColumns+`
+		FROM edges WHERE target_path IS NULL AND link_text LIKE ? ESCAPE '\'
+	`, pattern)
 	if err != nil {
 		return nil, err
 	}
-	defer func() { _ = rows.Close() }()
+	return slices.DeleteFunc(edges, func(edge domain.Edge) bool {
+		links := domain.ScanWikiLinks([]byte(edge.LinkText))
+		return len(links) != 1 || !strings.EqualFold(links[0].Name(), name)
+	}), nil
+}
 
-	var edges []domain.Edge
-	for rows.Next() {
-		var e domain.Edge
-		if err := rows.Scan(&e.SourcePath, &e.TargetJDID, &e.LinkText); err != nil {
-			return nil, err
-		}
-		edges = append(edges, e)
-	}
+// FindUnresolvedNoteLinks returns the wiki links to notes by name that lead
+// to no note or alias in the vault
+func (idx *Index) FindUnresolvedNoteLinks() ([]domain.Edge, error) {
+	return idx.queryEdges(`
+		SELECT ` + edgeColumns + `
+		FROM edges WHERE target_name IS NOT NULL AND target_path IS NULL
+	`)
+}
 
-	return edges, rows.Err()
+// FindLinksToPath returns the Markdown link edges pointing to a path or
+// anything beneath it
+func (idx *Index) FindLinksToPath(targetPath string) ([]domain.Edge, error) {
+	lo, hi := subtreeRange(targetPath)
+	return idx.queryEdges(`
+		SELECT `+edgeColumns+`
+		FROM edges WHERE target_path = ? OR (target_path >= ? AND target_path < ?)
+	`, targetPath, lo, hi)
+}
+
+// FindLinksFromFile returns all edges from a source file
+func (idx *Index) FindLinksFromFile(sourcePath string) ([]domain.Edge, error) {
+	return idx.queryEdges(`
+		SELECT `+edgeColumns+`
+		FROM edges WHERE source_path = ?
+	`, sourcePath)
+}
+
+// FindLinksFromPath returns the edges from a file or from any file beneath a
+// folder
+func (idx *Index) FindLinksFromPath(sourcePath string) ([]domain.Edge, error) {
+	lo, hi := subtreeRange(sourcePath)
+	return idx.queryEdges(`
+		SELECT `+edgeColumns+`
+		FROM edges WHERE source_path = ? OR (source_path >= ? AND source_path < ?)
+	`, sourcePath, lo, hi)
 }
 
 // BeginTx starts a new transaction
@@ -304,5 +391,5 @@ func (idx *Index) BeginTx() (ports.IndexTx, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &indexTx{tx: tx}, nil
+	return &indexTx{tx: tx, vaultPath: idx.vaultPath}, nil
 }
@@ -0,0 +1,407 @@
+package sqlite
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"libraio/internal/domain"
+)
+
+func TestListChildren(t *testing.T) {
+	vaultPath, idx := setupContentVault(t)
+	itemPath := filepath.Join("S01 Personal", "S01.10-19 Lifestyle", "S01.11 Entertainment", "S01.11.15 Theatre")
+	if err := os.WriteFile(filepath.Join(vaultPath, itemPath, "poster.png"), []byte{0x89, 'P', 'N', 'G'}, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(vaultPath, itemPath, "attachments"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := idx.SyncFull(); err != nil {
+		t.Fatalf("SyncFull failed: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		parent string
+		want   []string
+	}{
+		{"vault root", ".", []string{"S01 Personal", "journal.md"}},
+		{"scope", "S01 Personal", []string{"S01.10-19 Lifestyle"}},
+		{"item with files and folders", itemPath, []string{"attachments", "poster.png", "review.md", "tickets.md"}},
+		{"unknown folder", "Nowhere", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, err := idx.ListChildren(tt.parent)
+			if err != nil {
+				t.Fatalf("ListChildren failed: %v", err)
+			}
+			var names []string
+			for _, n := range nodes {
+				names = append(names, filepath.Base(n.Path))
+			}
+			if len(names) != len(tt.want) {
+				t.Fatalf("got %v, want %v", names, tt.want)
+			}
+			for i := range names {
+				if names[i] != tt.want[i] {
+					t.Errorf("got %v, want %v", names, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestGetNode_FolderAndFile(t *testing.T) {
+	vaultPath, idx := setupContentVault(t)
+	scopePath := filepath.Join(vaultPath, "S01 Personal")
+
+	node, err := idx.GetNodeByJDID("S01")
+	if err != nil || node == nil {
+		t.Fatalf("GetNodeByJDID failed: %v, %v", node, err)
+	}
+	info, err := os.Stat(scopePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if node.Path != "S01 Personal" || node.Name != "Personal" || node.JDType != domain.IDTypeScope || !node.IsDir {
+		t.Errorf("unexpected scope node: %+v", node)
+	}
+	if node.Mtime != info.ModTime().UnixNano() {
+		t.Errorf("mtime should match the folder, got %d want %d", node.Mtime, info.ModTime().UnixNano())
+	}
+
+	file, err := idx.GetNode("journal.md")
+	if err != nil || file == nil {
+		t.Fatalf("GetNode failed: %v, %v", file, err)
+	}
+	if file.IsDir || file.JDID != "" || file.Name != "journal.md" {
+		t.Errorf("unexpected file node: %+v", file)
+	}
+
+	if root, err := idx.GetNode("."); err != nil || root == nil || !root.IsDir {
+		t.Errorf("vault root should be indexed, got %+v, %v", root, err)
+	}
+}
+
+func TestSearchNames(t *testing.T) {
+	_, idx := setupContentVault(t)
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"theatre", []string{"S01.11.15 Theatre"}},
+		{"S01.11", []string{"S01.11 Entertainment", "S01.11.15 Theatre"}},
+		{".md", []string{"journal.md", "review.md", "tickets.md"}},
+		{"TICKETS", []string{"tickets.md"}},
+		{"%", nil},
+		{"personal/s01", nil}, // Only names match, not paths
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			nodes, err := idx.SearchNames(tt.query)
+			if err != nil {
+				t.Fatalf("SearchNames failed: %v", err)
+			}
+			got := map[string]bool{}
+			for _, n := range nodes {
+				got[filepath.Base(n.Path)] = true
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for _, w := range tt.want {
+				if !got[w] {
+					t.Errorf("missing %s in %v", w, got)
+				}
+			}
+		})
+	}
+}
+
+func TestSyncIncremental_TracksFoldersAndFiles(t *testing.T) {
+	vaultPath, idx := setupContentVault(t)
+	categoryPath := filepath.Join("S01 Personal", "S01.10-19 Lifestyle", "S01.11 Entertainment")
+
+	newItem := filepath.Join(categoryPath, "S01.11.16 Opera")
+	if err := os.Mkdir(filepath.Join(vaultPath, newItem), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(vaultPath, newItem, "program.pdf"), []byte("%PDF"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := idx.SyncIncremental(); err != nil {
+		t.Fatalf("SyncIncremental failed: %v", err)
+	}
+
+	node, err := idx.GetNodeByJDID("S01.11.16")
+	if err != nil || node == nil || node.Path != newItem {
+		t.Fatalf("new item should be indexed, got %+v, %v", node, err)
+	}
+	files, err := idx.ListChildren(newItem)
+	if err != nil || len(files) != 1 || filepath.Base(files[0].Path) != "program.pdf" {
+		t.Errorf("new file should be indexed, got %+v, %v", files, err)
+	}
+
+	// The category's mtime is refreshed so callers can trust its listing again
+	category, err := idx.GetNode(categoryPath)
+	if err != nil || category == nil {
+		t.Fatalf("GetNode failed: %v, %v", category, err)
+	}
+	info, err := os.Stat(filepath.Join(vaultPath, categoryPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if category.Mtime != info.ModTime().UnixNano() {
+		t.Errorf("category mtime not refreshed: got %d want %d", category.Mtime, info.ModTime().UnixNano())
+	}
+}
+
+// assertSameAsFullSync compares an incrementally synced index with a fresh one
+func assertSameAsFullSync(t *testing.T, vaultPath string, idx *Index) {
+	t.Helper()
+	got, err := idx.Dump()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	fresh := NewIndex()
+	if err := fresh.Open(vaultPath); err != nil {
+		t.Fatal(err)
+	}
+	defer fresh.Close()
+	if _, err := fresh.SyncFull(); err != nil {
+		t.Fatal(err)
+	}
+	want, err := fresh.Dump()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("incremental sync differs from a full sync:\ngot  %q\nwant %q", got, want)
+	}
+}
+
+func TestSyncIncremental_FollowsExternalMoves(t *testing.T) {
+	lifestyle := filepath.Join("S01 Personal", "S01.10-19 Lifestyle")
+	category := filepath.Join(lifestyle, "S01.11 Entertainment")
+	item := filepath.Join(category, "S01.11.15 Theatre")
+
+	tests := []struct {
+		name      string
+		move      func(t *testing.T, vaultPath string)
+		wantMoves []domain.NodeMove
+	}{
+		{
+			name: "renamed folder",
+			move: func(t *testing.T, vaultPath string) {
+				rename(t, vaultPath, item, filepath.Join(category, "S01.11.15 Drama"))
+			},
+			wantMoves: []domain.NodeMove{{OldPath: item, NewPath: filepath.Join(category, "S01.11.15 Drama"), IsDir: true}},
+		},
+		{
+			name: "renamed folder inside a moved one",
+			move: func(t *testing.T, vaultPath string) {
+				rename(t, vaultPath, category, filepath.Join(lifestyle, "S01.12 Entertainment"))
+				rename(t, vaultPath, filepath.Join(lifestyle, "S01.12 Entertainment", "S01.11.15 Theatre"), filepath.Join(lifestyle, "S01.12 Entertainment", "S01.12.11 Theatre"))
+			},
+			wantMoves: []domain.NodeMove{
+				{OldPath: category, NewPath: filepath.Join(lifestyle, "S01.12 Entertainment"), IsDir: true},
+				{OldPath: filepath.Join(lifestyle, "S01.12 Entertainment", "S01.11.15 Theatre"), NewPath: filepath.Join(lifestyle, "S01.12 Entertainment", "S01.12.11 Theatre"), IsDir: true},
+			},
+		},
+		{
+			name: "moved and renamed file",
+			move: func(t *testing.T, vaultPath string) {
+				rename(t, vaultPath, "journal.md", filepath.Join(item, "diary.md"))
+			},
+			wantMoves: []domain.NodeMove{{OldPath: "journal.md", NewPath: filepath.Join(item, "diary.md")}},
+		},
+		{
+			name: "unrelated folder reusing nothing",
+			move: func(t *testing.T, vaultPath string) {
+				if err := os.RemoveAll(filepath.Join(vaultPath, item)); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.Mkdir(filepath.Join(vaultPath, category, "S01.11.16 Cinema"), 0755); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vaultPath, idx := setupContentVault(t)
+			tt.move(t, vaultPath)
+
+			stats, err := idx.SyncIncremental()
+			if err != nil {
+				t.Fatalf("SyncIncremental failed: %v", err)
+			}
+			if !slices.Equal(stats.Moves, tt.wantMoves) || stats.NodesMoved != len(tt.wantMoves) {
+				t.Errorf("moves = %+v, want %+v", stats.Moves, tt.wantMoves)
+			}
+			if len(tt.wantMoves) > 0 && (stats.NodesAdded != 0 || stats.NodesDeleted != 0) {
+				t.Errorf("moved entries should not be added or deleted: %+v", stats)
+			}
+			assertSameAsFullSync(t, vaultPath, idx)
+		})
+	}
+}
+
+func rename(t *testing.T, vaultPath, oldPath, newPath string) {
+	t.Helper()
+	if err := os.Rename(filepath.Join(vaultPath, oldPath), filepath.Join(vaultPath, newPath)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSyncIncremental_SkipsTouchedUnchangedFiles(t *testing.T) {
+	vaultPath, idx := setupContentVault(t)
+
+	// Rewriting identical content changes the mtime but not the hash
+	journal := filepath.Join(vaultPath, "journal.md")
+	content, err := os.ReadFile(journal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(journal, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	future := time.Now().Add(time.Hour)
+	if err := os.Chtimes(journal, future, future); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := idx.SyncIncremental()
+	if err != nil {
+		t.Fatalf("SyncIncremental failed: %v", err)
+	}
+	if stats.NodesUpdated != 1 || stats.EdgesAdded != 0 {
+		t.Errorf("touched file should only have its mtime updated, got %+v", stats)
+	}
+	assertSameAsFullSync(t, vaultPath, idx)
+}
+
+func TestSyncIncremental_StatsOnlyWhatCanHaveChanged(t *testing.T) {
+	vaultPath, idx := setupContentVault(t)
+	category := filepath.Join(vaultPath, "S01 Personal", "S01.10-19 Lifestyle", "S01.11 Entertainment")
+	for _, dir := range []string{category, filepath.Join(category, "S01.11.15 Theatre")} {
+		for i := range 10 {
+			if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("photo%d.jpg", i)), nil, 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if _, err := idx.SyncIncremental(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Adding a note and removing a photo changes the category's mtime only
+	if err := os.WriteFile(filepath.Join(category, "notes.md"), []byte("See [[S01.11.15 Theatre]]"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(category, "photo0.jpg")); err != nil {
+		t.Fatal(err)
+	}
+
+	var stats atomic.Int64
+	t.Cleanup(func() { lstat = os.Lstat })
+	lstat = func(path string) (os.FileInfo, error) {
+		stats.Add(1)
+		return os.Lstat(path)
+	}
+
+	result, err := idx.SyncIncremental()
+	if err != nil {
+		t.Fatalf("SyncIncremental failed: %v", err)
+	}
+	// The vault root, the four subfolders, the three notes already indexed and
+	// the new one; none of the photos
+	if got := stats.Load(); got != 9 {
+		t.Errorf("stat'ed %d entries, want 9", got)
+	}
+	if result.NodesAdded != 1 || result.NodesDeleted != 1 || result.NodesUpdated != 1 {
+		t.Errorf("expected the note added, the photo deleted and the category updated, got %+v", result)
+	}
+	assertSameAsFullSync(t, vaultPath, idx)
+}
+
+func TestSameFolder(t *testing.T) {
+	tests := []struct {
+		old, new string
+		want     bool
+	}{
+		{"S01.11.15 Theatre", "S01.11.15 Drama", true},
+		{"S01.11.15 Theatre", "S01.12.11 Theatre", true},
+		{"S01.11.15 Theatre", "S01.11.16 Cinema", false},
+		{"attachments", filepath.Join("elsewhere", "attachments"), true},
+		{"attachments", "files", false},
+	}
+	for _, tt := range tests {
+		if got := sameFolder(tt.old, tt.new); got != tt.want {
+			t.Errorf("sameFolder(%q, %q) = %v, want %v", tt.old, tt.new, got, tt.want)
+		}
+	}
+}
+
+func TestSyncIncremental_ListsUnchangedFoldersFromIndex(t *testing.T) {
+	vaultPath, idx := setupContentVault(t)
+	category := filepath.Join(vaultPath, "S01 Personal", "S01.10-19 Lifestyle", "S01.11 Entertainment")
+
+	// Editing in place leaves the item folder's mtime alone; adding a note
+	// changes the category's
+	review := filepath.Join(category, "S01.11.15 Theatre", "review.md")
+	if err := os.WriteFile(review, []byte("# Review\n\nA standing ovation."), 0644); err != nil {
+		t.Fatal(err)
+	}
+	future := time.Now().Add(time.Hour)
+	if err := os.Chtimes(review, future, future); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(category, "notes.md"), []byte("See [[S01.11.15 Theatre]]"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var updates []domain.SyncProgress
+	stats, err := idx.Sync(func(p domain.SyncProgress) { updates = append(updates, p) })
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if stats.Rebuilt || stats.DirsUnchanged != 4 || stats.NodesAdded != 1 || stats.NodesUpdated != 2 {
+		t.Errorf("expected all folders but the category listed from the index, got %+v", stats)
+	}
+	rel := func(path string) string { r, _ := filepath.Rel(vaultPath, path); return r }
+	want := []string{rel(category), rel(filepath.Join(category, "S01.11.15 Theatre", "review.md")), rel(filepath.Join(category, "notes.md"))}
+	if got := slices.Sorted(slices.Values(stats.Changed)); !slices.Equal(got, slices.Sorted(slices.Values(want))) {
+		t.Errorf("Changed = %v, want %v", got, want)
+	}
+	if matches, _ := idx.SearchContent("ovation", 10); len(matches) != 1 {
+		t.Errorf("edit in an unchanged folder not indexed, got %+v", matches)
+	}
+	assertSameAsFullSync(t, vaultPath, idx)
+
+	if len(updates) == 0 || updates[0].Phase != domain.SyncScanning {
+		t.Fatalf("expected progress to start with scanning, got %+v", updates)
+	}
+	for i := 1; i < len(updates); i++ {
+		prev, cur := updates[i-1], updates[i]
+		if cur.Phase < prev.Phase || (cur.Phase == prev.Phase && cur.Done < prev.Done) {
+			t.Errorf("progress went backwards: %+v", updates)
+		}
+	}
+	if last := updates[len(updates)-1]; last != (domain.SyncProgress{Phase: domain.SyncIndexing, Done: 2, Total: 2}) {
+		t.Errorf("expected progress to end with both changed notes indexed, got %+v", last)
+	}
+}
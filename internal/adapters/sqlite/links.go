@@ -0,0 +1,281 @@
+package sqlite
+
+import (
+	"database/sql"
+	"path/filepath"
+	"strings"
+
+	"libraio/internal/domain"
+)
+
+// FindBrokenLinks checks every indexed link against the indexed vault: wiki
+// links to JD IDs nothing has, or that now belong to a folder with another
+// description; links to [Archived] names whose folder is gone; links to notes
+// no note or alias is named after; and Markdown links and canvas file nodes
+// to missing paths. A broken link gets a fix when the rename history, or else
+// the only folder or note with the name linked to, tells where its target
+// went. A link is reported each time it is written;
+// links are sorted by source file, then by line.
+func (idx *Index) FindBrokenLinks() ([]domain.BrokenLink, error) {
+	edges, err := idx.queryEdges(`
+		SELECT ` + edgeColumns + `
+		FROM edges ORDER BY source_path, line, col
+	`)
+	if err != nil {
+		return nil, err
+	}
+	renames, err := idx.renameHistory()
+	if err != nil {
+		return nil, err
+	}
+	dirs, err := idx.queryNodes(`SELECT ` + nodeColumns + ` FROM nodes WHERE is_dir AND path != '.'`)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &brokenLinkFinder{
+		idx:     idx,
+		renames: renames,
+		byID:    make(map[string][]domain.IndexNode),
+		byName:  make(map[string][]domain.IndexNode),
+	}
+	for _, dir := range dirs {
+		if dir.JDID != "" {
+			f.byID[dir.JDID] = append(f.byID[dir.JDID], dir)
+		}
+		f.byName[strings.ToLower(dir.Name)] = append(f.byName[strings.ToLower(dir.Name)], dir)
+	}
+
+	var broken []domain.BrokenLink
+	for _, edge := range edges {
+		link, ok, err := f.check(edge)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			broken = append(broken, link)
+		}
+	}
+	return broken, nil
+}
+
+// renameHistory returns every recorded rename, oldest first
+func (idx *Index) renameHistory() ([]domain.NodeMove, error) {
+	rows, err := idx.db.Query(`SELECT old_path, new_path FROM renames ORDER BY renamed_at, rowid`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var renames []domain.NodeMove
+	for rows.Next() {
+		var move domain.NodeMove
+		if err := rows.Scan(&move.OldPath, &move.NewPath); err != nil {
+			return nil, err
+		}
+		renames = append(renames, move)
+	}
+	return renames, rows.Err()
+}
+
+// brokenLinkFinder checks edges against the folders of the vault
+type brokenLinkFinder struct {
+	idx     *Index
+	renames []domain.NodeMove
+	byID    map[string][]domain.IndexNode // Folders by JD ID
+	byName  map[string][]domain.IndexNode // Folders by lower-cased description, or name without an ID
+}
+
+// check returns the edge as a broken link, or false if it still leads where
+// it was written to
+func (f *brokenLinkFinder) check(edge domain.Edge) (domain.BrokenLink, bool, error) {
+	link := domain.BrokenLink{SourcePath: edge.SourcePath, Line: edge.Line, LinkText: edge.LinkText}
+
+	if edge.TargetPath != "" {
+		if exists, err := f.exists(edge.TargetPath, false); err != nil || exists {
+			return link, false, err
+		}
+		link.Kind, link.Target = domain.BrokenLinkMissing, edge.TargetPath
+		fix, err := f.followRenames(func(move domain.NodeMove) (string, bool) { return move.Apply(edge.TargetPath) }, false)
+		link.Fix = fix
+		return link, true, err
+	}
+
+	if edge.TargetName != "" {
+		return f.checkNote(link, edge.TargetName)
+	}
+
+	wiki := domain.ScanWikiLinks([]byte(edge.LinkText))
+	if len(wiki) != 1 {
+		return link, false, nil
+	}
+	name := wiki[0].Name()
+	link.Target = name
+
+	if edge.TargetJDID == "" {
+		if len(f.byName[strings.ToLower(name)]) > 0 {
+			return link, false, nil
+		}
+		link.Kind = domain.BrokenLinkArchived
+		fix, err := f.followRenames(renamedFrom(func(oldName string) bool { return strings.EqualFold(oldName, name) }), true)
+		if fix == "" && err == nil {
+			fix = f.redirect(domain.ExtractArchivedDescription(name), "")
+		}
+		link.Fix = fix
+		return link, true, err
+	}
+
+	id, description := edge.TargetJDID, domain.ExtractDescription(name)
+	folders := f.byID[id]
+	switch {
+	case len(folders) == 0:
+		link.Kind = domain.BrokenLinkMissing
+	case description != "" && !hasDescription(folders, description):
+		link.Kind = domain.BrokenLinkReused
+		link.Current = domain.FormatFolderName(id, folders[0].Name)
+	default:
+		return link, false, nil
+	}
+
+	fix, err := f.followRenames(renamedFrom(func(oldName string) bool {
+		return domain.ExtractID(oldName) == id && (description == "" || strings.EqualFold(domain.ExtractDescription(oldName), description))
+	}), true)
+	if fix == "" && err == nil && description != "" {
+		fix = f.redirect(description, id)
+	}
+	link.Fix = fix
+	return link, true, err
+}
+
+// checkNote reports a note link the index could not resolve. Its fix is where
+// the rename history took a note of that name, or else the only note with the
+// name, such as when the folder prefix the link was written with is stale.
+func (f *brokenLinkFinder) checkNote(link domain.BrokenLink, targetName string) (domain.BrokenLink, bool, error) {
+	link.Kind, link.Target = domain.BrokenLinkMissing, targetName
+	name, _ := nameKey(targetName)
+	fix, err := f.followRenames(renamedFrom(func(oldName string) bool {
+		return strings.EqualFold(domain.NoteName(oldName), name)
+	}), false)
+	if err != nil {
+		return link, true, err
+	}
+	if fix == "" {
+		var paths []string
+		rows, err := f.idx.db.Query(`SELECT path FROM note_names WHERE name = ? AND NOT alias`, name)
+		if err != nil {
+			return link, true, err
+		}
+		defer func() { _ = rows.Close() }()
+		for rows.Next() {
+			var path string
+			if err := rows.Scan(&path); err != nil {
+				return link, true, err
+			}
+			paths = append(paths, path)
+		}
+		if err := rows.Err(); err != nil {
+			return link, true, err
+		}
+		if len(paths) == 1 {
+			fix = paths[0]
+		}
+	}
+	link.Fix = fix
+	return link, true, nil
+}
+
+// renamedFrom matches renames of folders whose old name satisfies match
+func renamedFrom(match func(oldName string) bool) func(domain.NodeMove) (string, bool) {
+	return func(move domain.NodeMove) (string, bool) {
+		return move.NewPath, match(filepath.Base(move.OldPath))
+	}
+}
+
+// followRenames finds the latest rename that moved the target, as told by
+// match, and follows it through the later renames. It returns where the
+// target is now, or "" if no rename matched or the target is gone.
+func (f *brokenLinkFinder) followRenames(match func(domain.NodeMove) (string, bool), dir bool) (string, error) {
+	for i := len(f.renames) - 1; i >= 0; i-- {
+		path, ok := match(f.renames[i])
+		if !ok {
+			continue
+		}
+		for _, later := range f.renames[i+1:] {
+			path, _ = later.Apply(path)
+		}
+		exists, err := f.exists(path, dir)
+		if err != nil || !exists {
+			return "", err
+		}
+		return path, nil
+	}
+	return "", nil
+}
+
+// redirect returns the only JD folder, other than one with the ID linked to,
+// or [Archived] folder with a description, or "" if there is none or several
+func (f *brokenLinkFinder) redirect(description, id string) string {
+	var found []string
+	for _, dir := range f.byName[strings.ToLower(description)] {
+		if dir.JDID != "" && dir.JDID != id {
+			found = append(found, dir.Path)
+		}
+	}
+	for _, dir := range f.byName[strings.ToLower("[Archived] "+description)] {
+		found = append(found, dir.Path)
+	}
+	if len(found) != 1 {
+		return ""
+	}
+	return found[0]
+}
+
+// exists reports whether a path is indexed, as a folder if dir is set
+func (f *brokenLinkFinder) exists(path string, dir bool) (bool, error) {
+	var isDir bool
+	err := f.idx.db.QueryRow(`SELECT is_dir FROM nodes WHERE path = ?`, path).Scan(&isDir)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil && (isDir || !dir), err
+}
+
+// hasDescription reports whether one of the folders has the description
+func hasDescription(folders []domain.IndexNode, description string) bool {
+	for _, dir := range folders {
+		if strings.EqualFold(dir.Name, description) {
+			return true
+		}
+	}
+	return false
+}
+
+// FindStale reviews the indexed JD folders for a vault clean-up, as shaped by opts
+func (idx *Index) FindStale(opts domain.StaleOptions) ([]domain.StaleEntry, error) {
+	nodes, err := idx.queryNodes(`SELECT ` + nodeColumns + ` FROM nodes WHERE path != '.'`)
+	if err != nil {
+		return nil, err
+	}
+	edges, err := idx.queryEdges(`SELECT ` + edgeColumns + ` FROM edges`)
+	if err != nil {
+		return nil, err
+	}
+	return domain.FindStale(nodes, edges, opts), nil
+}
+
+// LinkGraph counts the indexed links between JD entities, as shaped by opts
+func (idx *Index) LinkGraph(opts domain.GraphOptions) (*domain.LinkGraph, error) {
+	edges, err := idx.queryEdges(`SELECT ` + edgeColumns + ` FROM edges`)
+	if err != nil {
+		return nil, err
+	}
+	dirs, err := idx.queryNodes(`SELECT ` + nodeColumns + ` FROM nodes WHERE is_dir AND jd_id != ''`)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string, len(dirs))
+	for _, dir := range dirs {
+		names[dir.JDID] = dir.Name
+	}
+	return domain.BuildLinkGraph(edges, names, opts), nil
+}
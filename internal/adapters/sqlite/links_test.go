@@ -0,0 +1,326 @@
+package sqlite_test
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"libraio/internal/adapters/filesystem"
+	"libraio/internal/domain"
+)
+
+func TestFindBrokenLinks_FixesFromRenameHistory(t *testing.T) {
+	vaultPath := setupMutationVault(t)
+	category := filepath.Join("S01 Personal", "S01.10-19 Lifestyle", "S01.11 Entertainment")
+	if err := os.WriteFile(filepath.Join(vaultPath, "films.md"), []byte("[[S01.11.16 Cinema]] and [[S01.11.99 Gone]]"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(vaultPath, category, "S01.11.18 Cinema"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	idx := openSyncedIndex(t, vaultPath)
+	repo := filesystem.NewRepository(vaultPath,
+		filesystem.WithIndex(idx),
+		filesystem.WithAutoBackup(domain.RetentionPolicy{}))
+
+	// Renumbered outside libraio; the archived opera is deleted
+	theatre := filepath.Join(category, "S01.11.17 Theatre")
+	if err := os.Rename(filepath.Join(vaultPath, category, "S01.11.15 Theatre"), filepath.Join(vaultPath, theatre)); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.RemoveAll(filepath.Join(vaultPath, category, "S01.11.09 Archive", "[Archived] Opera")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := idx.SyncIncremental(); err != nil {
+		t.Fatalf("SyncIncremental failed: %v", err)
+	}
+
+	links, err := repo.BrokenLinks()
+	if err != nil {
+		t.Fatalf("BrokenLinks failed: %v", err)
+	}
+	type found struct {
+		kind domain.BrokenLinkKind
+		fix  string
+	}
+	got := make(map[string]found)
+	for _, link := range links {
+		got[filepath.Base(link.SourcePath)+" "+link.LinkText] = found{link.Kind, link.Fix}
+	}
+	want := map[string]found{
+		"films.md [[S01.11.16 Cinema]]":     {domain.BrokenLinkReused, filepath.Join(category, "S01.11.18 Cinema")},
+		"films.md [[S01.11.99 Gone]]":       {domain.BrokenLinkMissing, ""},
+		"links.md [[S01.11.15 Theatre]]":    {domain.BrokenLinkMissing, theatre},
+		"links.md [[S01.11.15]]":            {domain.BrokenLinkMissing, theatre},
+		"links.md [[S01.11.15|My theatre]]": {domain.BrokenLinkMissing, theatre},
+		"notes.md [[S01.11.15 Theatre]]":    {domain.BrokenLinkMissing, theatre},
+		"notes.md [[[Archived] Opera]]":     {domain.BrokenLinkArchived, ""},
+		"notes.md [Theatre](S01%20Personal/S01.10-19%20Lifestyle/S01.11%20Entertainment/S01.11.15%20Theatre/README.md)": {domain.BrokenLinkMissing, filepath.Join(theatre, "README.md")},
+		"plan.md [[S01.11.15 Theatre]]": {domain.BrokenLinkMissing, theatre},
+		"stub.md [[S01.11.15]]":         {domain.BrokenLinkMissing, theatre},
+	}
+	if len(got) != len(want) {
+		t.Errorf("BrokenLinks = %+v, want %d links", links, len(want))
+	}
+	for text, w := range want {
+		if got[text] != w {
+			t.Errorf("%s: got %+v, want %+v", text, got[text], w)
+		}
+	}
+	for _, link := range links {
+		if link.Kind == domain.BrokenLinkReused && link.Current != "S01.11.16 Links" {
+			t.Errorf("reused link current = %q, want S01.11.16 Links", link.Current)
+		}
+	}
+
+	report, err := repo.FixLinks(links)
+	if err != nil {
+		t.Fatalf("FixLinks failed: %v", err)
+	}
+	if report.LinksRewritten() != 8 {
+		t.Errorf("expected 8 links rewritten, got %s", report.Summary())
+	}
+
+	// The files to fix are backed up first
+	backups, err := repo.ListBackups()
+	if err != nil || len(backups) != 1 || backups[0].ID != report.Snapshot {
+		t.Fatalf("ListBackups() = %+v, %v, want the snapshot %q", backups, err, report.Snapshot)
+	}
+	var backedUp []string
+	for _, file := range backups[0].Files {
+		backedUp = append(backedUp, filepath.Base(file))
+	}
+	slices.Sort(backedUp)
+	if want := []string{"films.md", "links.md", "notes.md", "plan.md", "stub.md"}; !slices.Equal(backedUp, want) {
+		t.Errorf("snapshot holds %q, want %q", backedUp, want)
+	}
+	notes, err := os.ReadFile(filepath.Join(vaultPath, "notes.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantNotes := "[[S01.11.17 Theatre]], [[S01.12 Travel]] and [[[Archived] Opera]]\n\n[Theatre](S01%20Personal/S01.10-19%20Lifestyle/S01.11%20Entertainment/S01.11.17%20Theatre/README.md)"
+	if string(notes) != wantNotes {
+		t.Errorf("notes.md = %q, want %q", notes, wantNotes)
+	}
+	films, err := os.ReadFile(filepath.Join(vaultPath, "films.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(films) != "[[S01.11.18 Cinema]] and [[S01.11.99 Gone]]" {
+		t.Errorf("films.md = %q", films)
+	}
+
+	// Only the links without a fix are left
+	links, err = repo.BrokenLinks()
+	if err != nil {
+		t.Fatalf("BrokenLinks failed: %v", err)
+	}
+	var left []string
+	for _, link := range links {
+		left = append(left, link.LinkText)
+	}
+	if want := []string{"[[S01.11.99 Gone]]", "[[[Archived] Opera]]"}; !slices.Equal(left, want) {
+		t.Errorf("after fixing, broken links = %q, want %q", left, want)
+	}
+	assertMatchesFreshSync(t, vaultPath, idx)
+}
+
+func TestNodeLinks_IncomingAndOutgoing(t *testing.T) {
+	vaultPath := setupMutationVault(t)
+	idx := openSyncedIndex(t, vaultPath)
+	repo := filesystem.NewRepository(vaultPath, filesystem.WithIndex(idx))
+
+	category := filepath.Join("S01 Personal", "S01.10-19 Lifestyle", "S01.11 Entertainment")
+	theatre := filepath.Join(category, "S01.11.15 Theatre")
+	links, err := repo.NodeLinks(&domain.TreeNode{Type: domain.IDTypeItem, ID: "S01.11.15", Path: filepath.Join(vaultPath, theatre)})
+	if err != nil {
+		t.Fatalf("NodeLinks failed: %v", err)
+	}
+
+	linksMD := filepath.Join(category, "S01.11.16 Links", "links.md")
+	wantIncoming := []domain.NodeLink{
+		{SourcePath: linksMD, Line: 1, Column: 3, Context: "- [[S01.11.15 Theatre]]", LinkText: "[[S01.11.15 Theatre]]", TargetID: "S01.11.15", TargetPath: theatre},
+		{SourcePath: linksMD, Line: 2, Column: 3, Context: "- [[S01.11.15]]", LinkText: "[[S01.11.15]]", TargetID: "S01.11.15", TargetPath: theatre},
+		{SourcePath: linksMD, Line: 3, Column: 3, Context: "- [[S01.11.15|My theatre]]", LinkText: "[[S01.11.15|My theatre]]", TargetID: "S01.11.15", TargetPath: theatre},
+		{SourcePath: filepath.Join("S01 Personal", "S01.10-19 Lifestyle", "S01.12 Travel", "S01.12.11 Japan", "plan.md"), Line: 1, Column: 14, Context: "Kabuki, like [[S01.11.15 Theatre]]", LinkText: "[[S01.11.15 Theatre]]", TargetID: "S01.11.15", TargetPath: theatre},
+		{SourcePath: "notes.md", Line: 1, Column: 1, Context: "[[S01.11.15 Theatre]], [[S01.12 Travel]] and [[[Archived] Opera]]", LinkText: "[[S01.11.15 Theatre]]", TargetID: "S01.11.15", TargetPath: theatre},
+		{SourcePath: "notes.md", Line: 3, Column: 1, Context: "[Theatre](S01%20Personal/S01.10-19%20Lifestyle/S01.11%20Entertainment/S01.11.15%20Theatre/README.md)", LinkText: "[Theatre](S01%20Personal/S01.10-19%20Lifestyle/S01.11%20Entertainment/S01.11.15%20Theatre/README.md)", TargetID: "S01.11.15", TargetPath: filepath.Join(theatre, "README.md")},
+	}
+	if !slices.Equal(links.Incoming, wantIncoming) {
+		t.Errorf("Incoming = %+v\nwant %+v", links.Incoming, wantIncoming)
+	}
+
+	// Links within the item, from the stub to the item and its poster, are left out
+	readme := filepath.Join(theatre, "README.md")
+	wantOutgoing := []domain.NodeLink{
+		{SourcePath: readme, Line: 3, Column: 5, Context: "See [[S01.11.16 Links]] and [links](../S01.11.16%20Links/links.md).", LinkText: "[[S01.11.16 Links]]", TargetID: "S01.11.16", TargetPath: filepath.Join(category, "S01.11.16 Links")},
+		{SourcePath: readme, Line: 3, Column: 29, Context: "See [[S01.11.16 Links]] and [links](../S01.11.16%20Links/links.md).", LinkText: "[links](../S01.11.16%20Links/links.md)", TargetID: "S01.11.16", TargetPath: linksMD},
+	}
+	if !slices.Equal(links.Outgoing, wantOutgoing) {
+		t.Errorf("Outgoing = %+v\nwant %+v", links.Outgoing, wantOutgoing)
+	}
+}
+
+func TestFindStale_ReviewsIndexedVault(t *testing.T) {
+	vaultPath := setupMutationVault(t)
+	idx := openSyncedIndex(t, vaultPath)
+	repo := filesystem.NewRepository(vaultPath, filesystem.WithIndex(idx))
+
+	// Japan is the only item nothing else links to; the Links item is linked
+	// from the Theatre README, and the Theatre item from everywhere
+	entries, err := repo.FindStale(domain.StaleOptions{})
+	if err != nil {
+		t.Fatalf("FindStale failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].ID != "S01.12.11" || !slices.Equal(entries[0].Reasons, []domain.StaleReason{domain.StaleOrphan}) {
+		t.Errorf("FindStale() = %+v, want S01.12.11 as orphan", entries)
+	}
+
+	// Everything was written just now, so a cut-off in the future makes every item untouched
+	entries, err = repo.FindStale(domain.StaleOptions{Before: time.Now().Add(time.Hour), Reasons: []domain.StaleReason{domain.StaleUntouched}})
+	if err != nil {
+		t.Fatalf("FindStale failed: %v", err)
+	}
+	var ids []string
+	for _, entry := range entries {
+		ids = append(ids, entry.ID)
+	}
+	if want := []string{"S01.11.15", "S01.11.16", "S01.12.11"}; !slices.Equal(ids, want) {
+		t.Errorf("untouched = %v, want %v", ids, want)
+	}
+
+	// Trashing the Japan item leaves its category empty
+	if _, _, err := repo.Delete("S01.12.11"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	entries, err = repo.FindStale(domain.StaleOptions{})
+	if err != nil {
+		t.Fatalf("FindStale failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].ID != "S01.12" || !entries[0].Has(domain.StaleEmpty) {
+		t.Errorf("FindStale() after delete = %+v, want S01.12 as empty", entries)
+	}
+}
+
+func TestNoteLinks_ResolveByNameAndAlias(t *testing.T) {
+	vaultPath := setupMutationVault(t)
+	theatre := filepath.Join("S01 Personal", "S01.10-19 Lifestyle", "S01.11 Entertainment", "S01.11.15 Theatre")
+	files := map[string]string{
+		filepath.Join(theatre, "Meeting notes.md"): "---\naliases: [Minutes]\n---\n# Meeting",
+		filepath.Join("Inbox", "Meeting notes.md"): "# Draft",
+		"reading.md": "[[Meeting notes]], [[minutes#Agenda]], [[S01.11.15 Theatre/Meeting notes]] and [[Nowhere]]",
+	}
+	for name, content := range files {
+		if err := os.MkdirAll(filepath.Join(vaultPath, filepath.Dir(name)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(vaultPath, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	idx := openSyncedIndex(t, vaultPath)
+	repo := filesystem.NewRepository(vaultPath, filesystem.WithIndex(idx))
+
+	// incoming returns the links from reading.md into the theatre item
+	incoming := func(id string) []string {
+		t.Helper()
+		path, err := repo.GetPath(id)
+		if err != nil {
+			t.Fatalf("GetPath failed: %v", err)
+		}
+		links, err := repo.NodeLinks(&domain.TreeNode{Type: domain.IDTypeItem, ID: id, Path: path})
+		if err != nil {
+			t.Fatalf("NodeLinks failed: %v", err)
+		}
+		var texts []string
+		for _, link := range links.Incoming {
+			if link.SourcePath == "reading.md" {
+				texts = append(texts, link.LinkText)
+			}
+		}
+		return texts
+	}
+	// broken returns the broken links in reading.md
+	broken := func() []domain.BrokenLink {
+		t.Helper()
+		links, err := repo.BrokenLinks()
+		if err != nil {
+			t.Fatalf("BrokenLinks failed: %v", err)
+		}
+		return slices.DeleteFunc(links, func(link domain.BrokenLink) bool { return link.SourcePath != "reading.md" })
+	}
+
+	// The shorter path wins the plain name; the alias and the prefix lead into the item
+	if got, want := incoming("S01.11.15"), []string{"[[minutes#Agenda]]", "[[S01.11.15 Theatre/Meeting notes]]"}; !slices.Equal(got, want) {
+		t.Errorf("incoming = %q, want %q", got, want)
+	}
+	if links := broken(); len(links) != 1 || links[0].LinkText != "[[Nowhere]]" || links[0].Kind != domain.BrokenLinkMissing || links[0].Fixable() {
+		t.Errorf("broken links = %+v, want only [[Nowhere]], without a fix", links)
+	}
+
+	// With the draft gone, the plain name resolves into the item
+	if err := os.Remove(filepath.Join(vaultPath, "Inbox", "Meeting notes.md")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := idx.SyncIncremental(); err != nil {
+		t.Fatalf("SyncIncremental failed: %v", err)
+	}
+	if got := incoming("S01.11.15"); len(got) != 3 {
+		t.Errorf("incoming = %q, want all three note links", got)
+	}
+
+	// Moving the item follows it with the prefixed link
+	item, _, err := repo.MoveItem("S01.11.15", "S01.12")
+	if err != nil {
+		t.Fatalf("MoveItem failed: %v", err)
+	}
+	reading, err := os.ReadFile(filepath.Join(vaultPath, "reading.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	moved := filepath.ToSlash(repoRel(t, vaultPath, item.Path)) + "/Meeting notes"
+	if want := "[[Meeting notes]], [[minutes#Agenda]], [[" + moved + "]] and [[Nowhere]]"; string(reading) != want {
+		t.Errorf("reading.md = %q, want %q", reading, want)
+	}
+	if got := incoming(item.ID); len(got) != 3 {
+		t.Errorf("after the move, incoming = %q, want all three note links", got)
+	}
+	assertMatchesFreshSync(t, vaultPath, idx)
+
+	// Renamed outside libraio, the note is found again through the rename history
+	if err := os.Rename(filepath.Join(item.Path, "Meeting notes.md"), filepath.Join(item.Path, "Agenda.md")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := idx.SyncIncremental(); err != nil {
+		t.Fatalf("SyncIncremental failed: %v", err)
+	}
+	links := broken()
+	if len(links) != 3 {
+		t.Fatalf("broken links = %+v, want the plain name, the prefixed link and [[Nowhere]]", links)
+	}
+	if _, err := repo.FixLinks(links); err != nil {
+		t.Fatalf("FixLinks failed: %v", err)
+	}
+	reading, err = os.ReadFile(filepath.Join(vaultPath, "reading.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The prefixed link keeps its folder, where the note was renamed
+	if want := "[[Agenda]], [[minutes#Agenda]], [[" + path.Dir(moved) + "/Agenda]] and [[Nowhere]]"; string(reading) != want {
+		t.Errorf("after fixing, reading.md = %q, want %q", reading, want)
+	}
+	assertMatchesFreshSync(t, vaultPath, idx)
+}
+
+// repoRel returns a path relative to the vault
+func repoRel(t *testing.T, vaultPath, path string) string {
+	t.Helper()
+	rel, err := filepath.Rel(vaultPath, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rel
+}
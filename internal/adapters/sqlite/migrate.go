@@ -0,0 +1,290 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// The index holds two kinds of tables. Derived tables (nodes, edges,
+// note_names, properties, tags and content_fts) mirror the vault and can
+// always be rebuilt by a full sync, so changing their layout only needs
+// derivedVersion bumped. Durable tables hold
+// data that exists nowhere else; they change only through migrations, which are
+// applied in order and never rerun.
+
+// derivedVersion is the layout of the derived tables. Bump it whenever
+// derivedSchema or what a sync stores changes.
+const derivedVersion = 11
+
+// derivedSchema creates the derived tables, except content_fts whose engine
+// depends on the build (see ensureContentTable)
+const derivedSchema = `
+	CREATE TABLE nodes (path TEXT PRIMARY KEY, parent TEXT NOT NULL, jd_id TEXT, jd_type TEXT, name TEXT, is_dir INTEGER NOT NULL, mtime INTEGER NOT NULL, hash TEXT, file_id INTEGER);
+	CREATE TABLE edges (source_path TEXT NOT NULL, target_jd_id TEXT NOT NULL, target_path TEXT, target_name TEXT, name_key TEXT, name_dir TEXT, link_text TEXT NOT NULL, kind TEXT NOT NULL, line INTEGER NOT NULL, col INTEGER NOT NULL, context TEXT NOT NULL, occurrence INTEGER NOT NULL, occurrences INTEGER NOT NULL, PRIMARY KEY (source_path, link_text, occurrence));
+	CREATE TABLE note_names (name TEXT NOT NULL, path TEXT NOT NULL, dir TEXT NOT NULL, jd_id TEXT NOT NULL, alias INTEGER NOT NULL);
+	CREATE TABLE properties (path TEXT NOT NULL, jd_id TEXT NOT NULL, key TEXT NOT NULL COLLATE NOCASE, value TEXT NOT NULL COLLATE NOCASE);
+	CREATE TABLE tags (path TEXT NOT NULL, jd_id TEXT NOT NULL, tag TEXT NOT NULL COLLATE NOCASE);
+	CREATE INDEX idx_nodes_parent ON nodes(parent);
+	CREATE INDEX idx_nodes_jd_id ON nodes(jd_id);
+	CREATE INDEX idx_edges_target ON edges(target_jd_id);
+	CREATE INDEX idx_edges_source ON edges(source_path);
+	CREATE INDEX idx_edges_target_path ON edges(target_path);
+	CREATE INDEX idx_edges_name_key ON edges(name_key) WHERE target_name IS NOT NULL;
+	CREATE INDEX idx_edges_unresolved ON edges(source_path) WHERE target_name IS NOT NULL AND target_path IS NULL;
+	CREATE INDEX idx_note_names_name ON note_names(name);
+	CREATE INDEX idx_note_names_path ON note_names(path);
+	CREATE INDEX idx_properties_path ON properties(path);
+	CREATE INDEX idx_properties_key ON properties(key, value);
+	CREATE INDEX idx_tags_path ON tags(path);
+	CREATE INDEX idx_tags_tag ON tags(tag);
+`
+
+// derivedTables lists the tables dropped when derivedVersion changes
+var derivedTables = []string{"nodes", "edges", "note_names", "properties", "tags", "content_fts"}
+
+// migration upgrades the durable tables by one version
+type migration struct {
+	version int
+	name    string
+	up      func(tx *sql.Tx) error
+}
+
+// migrations lists every durable schema change, oldest first. Never edit or
+// remove a migration once released; append a new one instead.
+var migrations = []migration{
+	{1, "create meta", execSQL(`CREATE TABLE IF NOT EXISTS meta (key TEXT PRIMARY KEY, value TEXT NOT NULL)`)},
+	{2, "create pending moves", execSQL(`CREATE TABLE pending_moves (old_path TEXT PRIMARY KEY, new_path TEXT NOT NULL, detected_at INTEGER NOT NULL)`)},
+	{3, "create rename history", execSQL(`CREATE TABLE renames (old_path TEXT NOT NULL, new_path TEXT NOT NULL, renamed_at INTEGER NOT NULL)`)},
+}
+
+// execSQL returns a migration step that runs fixed statements
+func execSQL(statements string) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		_, err := tx.Exec(statements)
+		return err
+	}
+}
+
+// Migration is a durable schema change, applied or pending
+type Migration struct {
+	Version   int       `json:"version"`
+	Name      string    `json:"name"`
+	AppliedAt time.Time `json:"applied_at,omitzero"`
+}
+
+// MigrationResult describes what Migrate changed
+type MigrationResult struct {
+	Applied        []Migration `json:"applied"`
+	DerivedRebuilt bool        `json:"derived_rebuilt"` // Derived tables were recreated empty and need a full sync
+}
+
+// Status describes the schema of an index database
+type Status struct {
+	DBPath         string      `json:"db_path"`
+	Version        int         `json:"version"`        // Latest applied migration
+	LatestVersion  int         `json:"latest_version"` // Latest migration known to this build
+	Applied        []Migration `json:"applied"`
+	Pending        []Migration `json:"pending"`
+	DerivedVersion int         `json:"derived_version"` // Layout of the derived tables, 0 if never created
+	DerivedLatest  int         `json:"derived_latest"`
+	Nodes          int         `json:"nodes"`
+	Edges          int         `json:"edges"`
+	LastSync       time.Time   `json:"last_sync,omitzero"`
+}
+
+// UpToDate reports whether no migration or derived rebuild is pending
+func (s *Status) UpToDate() bool {
+	return len(s.Pending) == 0 && s.Version <= s.LatestVersion && s.DerivedVersion == s.DerivedLatest
+}
+
+// Migrate applies pending migrations, each in its own transaction, then
+// recreates the derived tables if their layout changed
+func (idx *Index) Migrate() (*MigrationResult, error) {
+	return idx.migrate(migrations)
+}
+
+func (idx *Index) migrate(list []migration) (*MigrationResult, error) {
+	if _, err := idx.db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER PRIMARY KEY, name TEXT NOT NULL, applied_at INTEGER NOT NULL)`); err != nil {
+		return nil, err
+	}
+	applied, err := idx.appliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+	if err := checkNotNewer(applied, list); err != nil {
+		return nil, err
+	}
+
+	result := &MigrationResult{}
+	done := make(map[int]bool, len(applied))
+	for _, m := range applied {
+		done[m.Version] = true
+	}
+	for _, m := range list {
+		if done[m.version] {
+			continue
+		}
+		appliedAt := time.Now()
+		if err := idx.applyMigration(m, appliedAt); err != nil {
+			return result, fmt.Errorf("migration %d (%s) failed: %w", m.version, m.name, err)
+		}
+		result.Applied = append(result.Applied, Migration{Version: m.version, Name: m.name, AppliedAt: appliedAt})
+	}
+
+	if idx.storedDerivedVersion() != derivedVersion {
+		if err := idx.rebuildDerived(); err != nil {
+			return result, fmt.Errorf("failed to recreate derived tables: %w", err)
+		}
+		result.DerivedRebuilt = true
+	}
+
+	// The full-text table is created separately: its engine depends on the build
+	if err := idx.ensureContentTable(); err != nil {
+		return result, fmt.Errorf("failed to create content table: %w", err)
+	}
+	return result, nil
+}
+
+// applyMigration runs one migration and records it in the same transaction
+func (idx *Index) applyMigration(m migration, appliedAt time.Time) error {
+	tx, err := idx.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := m.up(tx); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)`,
+		m.version, m.name, appliedAt.UnixNano()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// checkNotNewer refuses databases migrated by a newer build, whose durable
+// tables this build does not understand
+func checkNotNewer(applied []Migration, list []migration) error {
+	latest := 0
+	if len(list) > 0 {
+		latest = list[len(list)-1].version
+	}
+	for _, m := range applied {
+		if m.Version > latest {
+			return fmt.Errorf("index schema version %d is newer than this build supports (%d); upgrade libraio", m.Version, latest)
+		}
+	}
+	return nil
+}
+
+// rebuildDerived drops and recreates the derived tables, leaving them empty
+func (idx *Index) rebuildDerived() error {
+	tx, err := idx.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, table := range derivedTables {
+		if _, err := tx.Exec(`DROP TABLE IF EXISTS ` + table); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.Exec(derivedSchema); err != nil {
+		return err
+	}
+	// schema_version is the key used before migrations existed
+	if _, err := tx.Exec(`DELETE FROM meta WHERE key IN ('schema_version', 'last_sync_time')`); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`
+		INSERT OR REPLACE INTO meta (key, value) VALUES ('derived_version', ?);
+		INSERT OR REPLACE INTO meta (key, value) VALUES ('vault_path_hash', ?);
+	`, strconv.Itoa(derivedVersion), hashVaultPath(idx.vaultPath)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Status reports the schema state without changing anything, so it also works
+// on an index opened WithManualMigrations that still needs migrating
+func (idx *Index) Status() (*Status, error) {
+	status := &Status{
+		DBPath:         idx.dbPath,
+		DerivedVersion: idx.storedDerivedVersion(),
+		DerivedLatest:  derivedVersion,
+		Applied:        []Migration{},
+		Pending:        []Migration{},
+	}
+	if len(migrations) > 0 {
+		status.LatestVersion = migrations[len(migrations)-1].version
+	}
+
+	if idx.hasTable("schema_migrations") {
+		applied, err := idx.appliedMigrations()
+		if err != nil {
+			return nil, err
+		}
+		status.Applied = applied
+	}
+	done := make(map[int]bool, len(status.Applied))
+	for _, m := range status.Applied {
+		done[m.Version] = true
+		status.Version = max(status.Version, m.Version)
+	}
+	for _, m := range migrations {
+		if !done[m.version] {
+			status.Pending = append(status.Pending, Migration{Version: m.version, Name: m.name})
+		}
+	}
+
+	if status.DerivedVersion == derivedVersion {
+		_ = idx.db.QueryRow(`SELECT COUNT(*) FROM nodes`).Scan(&status.Nodes)
+		_ = idx.db.QueryRow(`SELECT COUNT(*) FROM edges`).Scan(&status.Edges)
+		var lastSync string
+		_ = idx.db.QueryRow(`SELECT value FROM meta WHERE key = 'last_sync_time'`).Scan(&lastSync)
+		if ns, err := strconv.ParseInt(lastSync, 10, 64); err == nil {
+			status.LastSync = time.Unix(0, ns)
+		}
+	}
+	return status, nil
+}
+
+// appliedMigrations returns the recorded migrations, oldest first
+func (idx *Index) appliedMigrations() ([]Migration, error) {
+	rows, err := idx.db.Query(`SELECT version, name, applied_at FROM schema_migrations ORDER BY version`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var applied []Migration
+	for rows.Next() {
+		var m Migration
+		var appliedAt int64
+		if err := rows.Scan(&m.Version, &m.Name, &appliedAt); err != nil {
+			return nil, err
+		}
+		m.AppliedAt = time.Unix(0, appliedAt)
+		applied = append(applied, m)
+	}
+	return applied, rows.Err()
+}
+
+// storedDerivedVersion returns the recorded layout of the derived tables, or 0
+func (idx *Index) storedDerivedVersion() int {
+	var value string
+	_ = idx.db.QueryRow(`SELECT value FROM meta WHERE key = 'derived_version'`).Scan(&value)
+	version, _ := strconv.Atoi(value)
+	return version
+}
+
+// hasTable reports whether a table exists in the database
+func (idx *Index) hasTable(name string) bool {
+	var n int
+	err := idx.db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?`, name).Scan(&n)
+	return err == nil && n > 0
+}
@@ -0,0 +1,219 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// openManual opens an index for an empty vault without migrating it
+func openManual(t *testing.T) *Index {
+	t.Helper()
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	idx := NewIndex(WithManualMigrations())
+	if err := idx.Open(t.TempDir()); err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	t.Cleanup(func() { _ = idx.Close() })
+	return idx
+}
+
+// testMigrations extends the real migrations with a durable table holding data
+var testMigrations = append(migrations[:len(migrations):len(migrations)],
+	migration{100, "create retired", execSQL(`CREATE TABLE retired (jd_id TEXT PRIMARY KEY)`)},
+	migration{101, "seed retired", execSQL(`INSERT INTO retired (jd_id) VALUES ('S01.11.15')`)},
+)
+
+func appliedVersions(t *testing.T, idx *Index) []int {
+	t.Helper()
+	applied, err := idx.appliedMigrations()
+	if err != nil {
+		t.Fatalf("appliedMigrations failed: %v", err)
+	}
+	versions := make([]int, len(applied))
+	for i, m := range applied {
+		versions[i] = m.Version
+	}
+	return versions
+}
+
+func TestMigrate_AppliesInOrderOnce(t *testing.T) {
+	idx := openManual(t)
+
+	result, err := idx.migrate(testMigrations)
+	if err != nil {
+		t.Fatalf("migrate failed: %v", err)
+	}
+	if len(result.Applied) != len(testMigrations) || !result.DerivedRebuilt {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	for i, m := range result.Applied {
+		if m.Version != testMigrations[i].version {
+			t.Errorf("applied out of order: %+v", result.Applied)
+		}
+	}
+
+	result, err = idx.migrate(testMigrations)
+	if err != nil {
+		t.Fatalf("second migrate failed: %v", err)
+	}
+	if len(result.Applied) != 0 || result.DerivedRebuilt {
+		t.Errorf("second migrate should do nothing, got %+v", result)
+	}
+
+	var n int
+	if err := idx.db.QueryRow(`SELECT COUNT(*) FROM retired`).Scan(&n); err != nil || n != 1 {
+		t.Errorf("seed migration should run exactly once, got %d rows (%v)", n, err)
+	}
+}
+
+func TestMigrate_FailureRollsBackThatMigration(t *testing.T) {
+	idx := openManual(t)
+
+	failing := append(migrations[:len(migrations):len(migrations)],
+		migration{100, "create retired", execSQL(`CREATE TABLE retired (jd_id TEXT PRIMARY KEY)`)},
+		migration{101, "broken", func(tx *sql.Tx) error {
+			if _, err := tx.Exec(`CREATE TABLE half_done (x)`); err != nil {
+				return err
+			}
+			return errors.New("boom")
+		}},
+	)
+	result, err := idx.migrate(failing)
+	if err == nil {
+		t.Fatal("expected migration error")
+	}
+	if len(result.Applied) != len(migrations)+1 {
+		t.Errorf("migrations before the failure should stay applied, got %+v", result.Applied)
+	}
+	if idx.hasTable("half_done") {
+		t.Error("failed migration should be rolled back")
+	}
+
+	// Fixing the migration lets it apply on the next run
+	if _, err := idx.migrate(testMigrations); err != nil {
+		t.Fatalf("migrate after fix failed: %v", err)
+	}
+	if got := appliedVersions(t, idx); len(got) != len(testMigrations) || got[len(got)-1] != 101 {
+		t.Errorf("expected every migration up to 101 applied, got %v", got)
+	}
+}
+
+func TestMigrate_RefusesNewerSchema(t *testing.T) {
+	idx := openManual(t)
+	if _, err := idx.migrate(testMigrations); err != nil {
+		t.Fatalf("migrate failed: %v", err)
+	}
+
+	if _, err := idx.Migrate(); err == nil {
+		t.Error("an older build should refuse an index migrated by a newer one")
+	}
+}
+
+func TestMigrate_RebuildsDerivedKeepsDurable(t *testing.T) {
+	_, idx := setupContentVault(t)
+	if _, err := idx.migrate(testMigrations); err != nil {
+		t.Fatalf("migrate failed: %v", err)
+	}
+	if idx.NeedsFullRebuild() {
+		t.Fatal("synced index should not need a rebuild")
+	}
+
+	// Simulate an index whose derived tables were built by an older layout
+	if _, err := idx.db.Exec(`UPDATE meta SET value = '1' WHERE key = 'derived_version'`); err != nil {
+		t.Fatal(err)
+	}
+	result, err := idx.migrate(testMigrations)
+	if err != nil {
+		t.Fatalf("migrate failed: %v", err)
+	}
+	if !result.DerivedRebuilt || len(result.Applied) != 0 {
+		t.Errorf("expected only a derived rebuild, got %+v", result)
+	}
+
+	var nodes, retired int
+	_ = idx.db.QueryRow(`SELECT COUNT(*) FROM nodes`).Scan(&nodes)
+	_ = idx.db.QueryRow(`SELECT COUNT(*) FROM retired`).Scan(&retired)
+	if nodes != 0 || retired != 1 {
+		t.Errorf("derived tables should be emptied and durable ones kept, got %d nodes, %d retired", nodes, retired)
+	}
+	if !idx.NeedsFullRebuild() {
+		t.Error("rebuilt derived tables should need a full sync")
+	}
+}
+
+func TestOpen_UpgradesIndexWithoutMigrations(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	vaultPath := t.TempDir()
+
+	// The layout used before migrations: derived tables and meta, no schema_migrations
+	dbPath := databasePath(vaultPath)
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Exec(`
+		CREATE TABLE nodes (path TEXT PRIMARY KEY, parent TEXT NOT NULL, jd_id TEXT, jd_type TEXT, name TEXT, is_dir INTEGER NOT NULL, mtime INTEGER NOT NULL);
+		CREATE TABLE edges (source_path TEXT NOT NULL, target_jd_id TEXT NOT NULL, link_text TEXT NOT NULL, PRIMARY KEY (source_path, link_text));
+		CREATE TABLE meta (key TEXT PRIMARY KEY, value TEXT NOT NULL);
+		INSERT INTO nodes VALUES ('.', '', NULL, NULL, '.', 1, 1);
+		INSERT INTO meta VALUES ('schema_version', '3'), ('vault_path_hash', 'stale');
+	`)
+	if cerr := db.Close(); err != nil || cerr != nil {
+		t.Fatalf("failed to create legacy index: %v, %v", err, cerr)
+	}
+
+	idx := NewIndex()
+	if err := idx.Open(vaultPath); err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer idx.Close()
+
+	status, err := idx.Status()
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if !status.UpToDate() || status.Nodes != 0 {
+		t.Errorf("legacy index should be migrated with empty derived tables, got %+v", status)
+	}
+	var legacy int
+	_ = idx.db.QueryRow(`SELECT COUNT(*) FROM meta WHERE key = 'schema_version'`).Scan(&legacy)
+	if legacy != 0 {
+		t.Error("legacy schema_version key should be removed")
+	}
+	if !idx.NeedsFullRebuild() {
+		t.Error("migrated legacy index should need a full sync")
+	}
+}
+
+func TestStatus_ReportsPendingWork(t *testing.T) {
+	idx := openManual(t)
+
+	status, err := idx.Status()
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if status.Version != 0 || len(status.Pending) != len(migrations) || status.DerivedVersion != 0 || status.UpToDate() {
+		t.Errorf("fresh database should have everything pending, got %+v", status)
+	}
+	if filepath.Base(status.DBPath) != hashVaultPath(idx.vaultPath)+".db" {
+		t.Errorf("unexpected database path %s", status.DBPath)
+	}
+
+	if _, err := idx.Migrate(); err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+	status, err = idx.Status()
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if !status.UpToDate() || status.Version != status.LatestVersion || len(status.Applied) != len(migrations) {
+		t.Errorf("migrated database should be up to date, got %+v", status)
+	}
+}
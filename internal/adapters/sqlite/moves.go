@@ -0,0 +1,84 @@
+package sqlite
+
+import (
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"libraio/internal/domain"
+)
+
+// recordMove keeps pending moves pointing at where their folders are now, and
+// adds a folder move that changed the folder's JD name so links using the old
+// name can be rewritten later, possibly by another process
+func recordMove(tx *sql.Tx, move domain.NodeMove, at time.Time) error {
+	rows, err := tx.Query(`SELECT old_path, new_path FROM pending_moves`)
+	if err != nil {
+		return err
+	}
+	updates := make(map[string]string)
+	chained := false
+	for rows.Next() {
+		var oldPath, newPath string
+		if err := rows.Scan(&oldPath, &newPath); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		if rest, ok := strings.CutPrefix(newPath, move.OldPath); ok && (rest == "" || rest[0] == filepath.Separator) {
+			updates[oldPath] = move.NewPath + rest
+			chained = chained || rest == ""
+		}
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+
+	for oldPath, newPath := range updates {
+		if _, err := tx.Exec(`UPDATE pending_moves SET new_path = ? WHERE old_path = ?`, newPath, oldPath); err != nil {
+			return err
+		}
+	}
+	if _, _, _, _, ok := move.LinkChange(); ok && !chained {
+		if _, err := tx.Exec(`INSERT OR REPLACE INTO pending_moves (old_path, new_path, detected_at) VALUES (?, ?, ?)`,
+			move.OldPath, move.NewPath, at.UnixNano()); err != nil {
+			return err
+		}
+	}
+
+	// A folder moved back to where it was needs no relinking
+	_, err = tx.Exec(`DELETE FROM pending_moves WHERE old_path = new_path`)
+	return err
+}
+
+// PendingMoves returns folder moves found by syncs whose old JD names may still
+// be used by links, oldest first. Moves whose folder is gone again are skipped.
+func (idx *Index) PendingMoves() ([]domain.NodeMove, error) {
+	rows, err := idx.db.Query(`
+		SELECT m.old_path, m.new_path FROM pending_moves m
+		JOIN nodes n ON n.path = m.new_path AND n.is_dir
+		ORDER BY m.detected_at, m.old_path
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var moves []domain.NodeMove
+	for rows.Next() {
+		move := domain.NodeMove{IsDir: true}
+		if err := rows.Scan(&move.OldPath, &move.NewPath); err != nil {
+			return nil, err
+		}
+		if _, _, _, _, ok := move.LinkChange(); ok {
+			moves = append(moves, move)
+		}
+	}
+	return moves, rows.Err()
+}
+
+// ClearPendingMoves forgets all pending moves, once their links were rewritten
+func (idx *Index) ClearPendingMoves() error {
+	_, err := idx.db.Exec(`DELETE FROM pending_moves`)
+	return err
+}
@@ -0,0 +1,83 @@
+package sqlite
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"libraio/internal/domain"
+)
+
+func TestPendingMoves_FollowFoldersAcrossSyncs(t *testing.T) {
+	vaultPath, idx := setupContentVault(t)
+	category := filepath.Join("S01 Personal", "S01.10-19 Lifestyle", "S01.11 Entertainment")
+	sync := func() {
+		t.Helper()
+		if _, err := idx.SyncIncremental(); err != nil {
+			t.Fatalf("SyncIncremental failed: %v", err)
+		}
+	}
+
+	// Renumbered, then renamed: both steps together are one pending move
+	rename(t, vaultPath, filepath.Join(category, "S01.11.15 Theatre"), filepath.Join(category, "S01.11.16 Theatre"))
+	sync()
+	rename(t, vaultPath, filepath.Join(category, "S01.11.16 Theatre"), filepath.Join(category, "S01.11.16 Drama"))
+	sync()
+	// Renaming the area carries the pending move's folder along, and is a move of its own
+	rename(t, vaultPath, filepath.Join("S01 Personal", "S01.10-19 Lifestyle"), filepath.Join("S01 Personal", "S01.10-19 Life"))
+	sync()
+
+	moves, err := idx.PendingMoves()
+	if err != nil {
+		t.Fatalf("PendingMoves failed: %v", err)
+	}
+	want := []domain.NodeMove{{
+		OldPath: filepath.Join(category, "S01.11.15 Theatre"),
+		NewPath: filepath.Join("S01 Personal", "S01.10-19 Life", "S01.11 Entertainment", "S01.11.16 Drama"),
+		IsDir:   true,
+	}, {
+		OldPath: filepath.Join("S01 Personal", "S01.10-19 Lifestyle"),
+		NewPath: filepath.Join("S01 Personal", "S01.10-19 Life"),
+		IsDir:   true,
+	}}
+	if !slices.Equal(moves, want) {
+		t.Errorf("PendingMoves = %+v, want %+v", moves, want)
+	}
+
+	// Pending moves are durable: they survive a rebuild of the derived tables
+	if _, err := idx.db.Exec(`UPDATE meta SET value = '1' WHERE key = 'derived_version'`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := idx.Migrate(); err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+	if _, err := idx.SyncFull(); err != nil {
+		t.Fatalf("SyncFull failed: %v", err)
+	}
+	if moves, _ := idx.PendingMoves(); !slices.Equal(moves, want) {
+		t.Errorf("after rebuild PendingMoves = %+v, want %+v", moves, want)
+	}
+
+	if err := idx.ClearPendingMoves(); err != nil {
+		t.Fatalf("ClearPendingMoves failed: %v", err)
+	}
+	if moves, _ := idx.PendingMoves(); len(moves) != 0 {
+		t.Errorf("expected no pending moves after clearing, got %+v", moves)
+	}
+}
+
+func TestPendingMoves_MovedBackIsDropped(t *testing.T) {
+	vaultPath, idx := setupContentVault(t)
+	category := filepath.Join("S01 Personal", "S01.10-19 Lifestyle", "S01.11 Entertainment")
+
+	for _, step := range [][2]string{{"S01.11.15 Theatre", "S01.11.15 Drama"}, {"S01.11.15 Drama", "S01.11.15 Theatre"}} {
+		rename(t, vaultPath, filepath.Join(category, step[0]), filepath.Join(category, step[1]))
+		if _, err := idx.SyncIncremental(); err != nil {
+			t.Fatalf("SyncIncremental failed: %v", err)
+		}
+	}
+
+	if moves, err := idx.PendingMoves(); err != nil || len(moves) != 0 {
+		t.Errorf("expected no pending moves, got %+v, %v", moves, err)
+	}
+}
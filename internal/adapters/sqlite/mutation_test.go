@@ -0,0 +1,433 @@
+package sqlite_test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"libraio/internal/adapters/filesystem"
+	"libraio/internal/adapters/sqlite"
+	"libraio/internal/domain"
+)
+
+// setupMutationVault creates a vault with links in several forms, Markdown links
+// into, out of and within an item, archives at
+// category and area level, and files that are not markdown
+func setupMutationVault(t *testing.T) string {
+	t.Helper()
+	vaultPath := t.TempDir()
+	area := filepath.Join("S01 Personal", "S01.10-19 Lifestyle")
+	files := map[string]string{
+		filepath.Join(area, "S01.10 Lifestyle management", "S01.10.09 Archive", "README.md"):             "# Area archive",
+		filepath.Join(area, "S01.11 Entertainment", "S01.11.09 Archive", "[Archived] Opera", "notes.md"): "# Opera",
+		filepath.Join(area, "S01.11 Entertainment", "S01.11.15 Theatre", "README.md"):                    "# Theatre\n\nSee [[S01.11.16 Links]] and [links](../S01.11.16%20Links/links.md).",
+		filepath.Join(area, "S01.11 Entertainment", "S01.11.15 Theatre", "poster.png"):                   "\x89PNG\xff",
+		filepath.Join(area, "S01.11 Entertainment", "S01.11.15 Theatre", "tickets", "stub.md"):           "Stub for [[S01.11.15]], ![poster](../poster.png)",
+		filepath.Join(area, "S01.11 Entertainment", "S01.11.16 Links", "links.md"):                       "- [[S01.11.15 Theatre]]\n- [[S01.11.15]]\n- [[S01.11.15|My theatre]]\n- [[S01.11 Entertainment]]",
+		filepath.Join(area, "S01.12 Travel", "S01.12.09 Archive", "README.md"):                           "# Travel archive",
+		filepath.Join(area, "S01.12 Travel", "S01.12.11 Japan", "plan.md"):                               "Kabuki, like [[S01.11.15 Theatre]]",
+		"notes.md": "[[S01.11.15 Theatre]], [[S01.12 Travel]] and [[[Archived] Opera]]\n\n[Theatre](S01%20Personal/S01.10-19%20Lifestyle/S01.11%20Entertainment/S01.11.15%20Theatre/README.md)",
+	}
+	for name, content := range files {
+		path := filepath.Join(vaultPath, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The lock folder is created on first use; create it up front so it does
+	// not change the vault root's mtime during the operation under test
+	if err := os.MkdirAll(filepath.Join(vaultPath, ".libraio"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return vaultPath
+}
+
+// openSyncedIndex opens a fully synced index stored in its own data dir
+func openSyncedIndex(t *testing.T, vaultPath string) *sqlite.Index {
+	t.Helper()
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	idx := sqlite.NewIndex()
+	if err := idx.Open(vaultPath); err != nil {
+		t.Fatalf("failed to open index: %v", err)
+	}
+	t.Cleanup(func() { _ = idx.Close() })
+	if _, err := idx.SyncFull(); err != nil {
+		t.Fatalf("SyncFull failed: %v", err)
+	}
+	return idx
+}
+
+// assertMatchesFreshSync compares an index with one built from scratch
+func assertMatchesFreshSync(t *testing.T, vaultPath string, idx *sqlite.Index) {
+	t.Helper()
+	got, err := idx.Dump()
+	if err != nil {
+		t.Fatalf("Dump failed: %v", err)
+	}
+	want, err := openSyncedIndex(t, vaultPath).Dump()
+	if err != nil {
+		t.Fatalf("Dump failed: %v", err)
+	}
+
+	gotSet := make(map[string]bool, len(got))
+	for _, line := range got {
+		gotSet[line] = true
+	}
+	wantSet := make(map[string]bool, len(want))
+	for _, line := range want {
+		wantSet[line] = true
+	}
+
+	var diff []string
+	for _, line := range want {
+		if !gotSet[line] {
+			diff = append(diff, "- "+line)
+		}
+	}
+	for _, line := range got {
+		if !wantSet[line] {
+			diff = append(diff, "+ "+line)
+		}
+	}
+	if len(diff) > 0 {
+		t.Errorf("index differs from a fresh sync (- missing, + stale):\n%s", strings.Join(diff, "\n"))
+	}
+}
+
+func TestMutations_KeepIndexConsistent(t *testing.T) {
+	tests := []struct {
+		name string
+		op   func(repo *filesystem.Repository) error
+	}{
+		{"CreateScope", func(repo *filesystem.Repository) error {
+			_, _, err := repo.CreateScope("Work")
+			return err
+		}},
+		{"CreateCategory", func(repo *filesystem.Repository) error {
+			_, _, err := repo.CreateCategory("S01.10-19", "Hobbies")
+			return err
+		}},
+		{"CreateItem", func(repo *filesystem.Repository) error {
+			_, _, err := repo.CreateItem("S01.11", "Cinema")
+			return err
+		}},
+		{"MoveItem", func(repo *filesystem.Repository) error {
+			_, _, err := repo.MoveItem("S01.11.15", "S01.12")
+			return err
+		}},
+		{"MoveCategory", func(repo *filesystem.Repository) error {
+			if _, _, err := repo.CreateArea("S01", "Work"); err != nil {
+				return err
+			}
+			_, _, err := repo.MoveCategory("S01.11", "S01.20-29")
+			return err
+		}},
+		{"RenameItem", func(repo *filesystem.Repository) error {
+			_, _, err := repo.RenameItem("S01.11.15", "Drama")
+			return err
+		}},
+		{"RenameCategory", func(repo *filesystem.Repository) error {
+			_, _, err := repo.RenameCategory("S01.11", "Culture")
+			return err
+		}},
+		{"RenameArea", func(repo *filesystem.Repository) error {
+			_, _, err := repo.RenameArea("S01.10-19", "Life")
+			return err
+		}},
+		{"ArchiveItem", func(repo *filesystem.Repository) error {
+			_, _, err := repo.ArchiveItem("S01.11.15")
+			return err
+		}},
+		{"ArchiveCategory", func(repo *filesystem.Repository) error {
+			_, _, err := repo.ArchiveCategory("S01.11")
+			return err
+		}},
+		{"ArchiveCategoryToArea", func(repo *filesystem.Repository) error {
+			_, _, err := repo.ArchiveCategoryToArea("S01.12")
+			return err
+		}},
+		{"UnarchiveItems", func(repo *filesystem.Repository) error {
+			_, _, err := repo.UnarchiveItems("S01.11.09", "S01.12")
+			return err
+		}},
+		{"Delete", func(repo *filesystem.Repository) error {
+			_, _, err := repo.Delete("S01.11.15")
+			return err
+		}},
+		{"RestoreTrash", func(repo *filesystem.Repository) error {
+			entry, _, err := repo.Delete("S01.11.15")
+			if err != nil {
+				return err
+			}
+			_, _, err = repo.RestoreTrash(entry.TrashID)
+			return err
+		}},
+		{"RestoreBackup", func(repo *filesystem.Repository) error {
+			entry, _, err := repo.Delete("S01.11.15")
+			if err != nil {
+				return err
+			}
+			_, _, err = repo.RestoreBackup(entry.Snapshot)
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vaultPath := setupMutationVault(t)
+			idx := openSyncedIndex(t, vaultPath)
+			repo := filesystem.NewRepository(vaultPath,
+				filesystem.WithIndex(idx),
+				filesystem.WithAutoBackup(domain.RetentionPolicy{}))
+
+			if err := tt.op(repo); err != nil {
+				t.Fatalf("%s failed: %v", tt.name, err)
+			}
+			assertMatchesFreshSync(t, vaultPath, idx)
+		})
+	}
+}
+
+func TestMutations_ReportIndexFailures(t *testing.T) {
+	vaultPath := setupMutationVault(t)
+	idx := openSyncedIndex(t, vaultPath)
+	repo := filesystem.NewRepository(vaultPath, filesystem.WithIndex(idx))
+
+	// A closed index fails every update, but the operations still succeed on disk
+	if err := idx.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	item, report, err := repo.CreateItem("S01.11", "Cinema")
+	if err != nil {
+		t.Fatalf("CreateItem failed: %v", err)
+	}
+	if len(report.Warnings) != 1 || !strings.Contains(report.Warnings[0], "failed to update index") {
+		t.Errorf("expected an index warning from CreateItem, got %v", report.Warnings)
+	}
+
+	_, report, err = repo.Delete(item.ID)
+	if err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if len(report.Warnings) != 1 || !strings.Contains(report.Warnings[0], "failed to update index") {
+		t.Errorf("expected an index warning from Delete, got %v", report.Warnings)
+	}
+}
+
+func TestMoveItem_RewritesLinksFoundThroughIndex(t *testing.T) {
+	vaultPath := setupMutationVault(t)
+	idx := openSyncedIndex(t, vaultPath)
+	repo := filesystem.NewRepository(vaultPath, filesystem.WithIndex(idx))
+
+	item, report, err := repo.MoveItem("S01.11.15", "S01.12")
+	if err != nil {
+		t.Fatalf("MoveItem failed: %v", err)
+	}
+	if len(report.Warnings) > 0 || len(report.Errors) > 0 {
+		t.Errorf("unexpected problems: %+v", report)
+	}
+
+	// The stub inside the moved item links to it, and must be found at its new path
+	stub, err := os.ReadFile(filepath.Join(item.Path, "tickets", "stub.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(stub), "[["+item.ID+" Theatre]]") {
+		t.Errorf("link inside moved item not rewritten: %s", stub)
+	}
+
+	edges, err := idx.FindLinksToID(item.ID)
+	if err != nil {
+		t.Fatalf("FindLinksToID failed: %v", err)
+	}
+	// Both bare and titled links in links.md become [[S01.12.12 Theatre]], an
+	// edge each; the Markdown link in notes.md and the image in stub.md add two
+	if len(edges) != 8 {
+		t.Errorf("expected 8 edges to %s, got %+v", item.ID, edges)
+	}
+	if old, _ := idx.FindLinksToID("S01.11.15"); len(old) != 0 {
+		t.Errorf("expected no edges to the old ID, got %+v", old)
+	}
+
+	// Relative Markdown links into and out of the moved item follow it
+	notes, err := os.ReadFile(filepath.Join(vaultPath, "notes.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(notes), "[Theatre](S01%20Personal/S01.10-19%20Lifestyle/S01.12%20Travel/S01.12.12%20Theatre/README.md)") {
+		t.Errorf("Markdown link into moved item not rewritten: %s", notes)
+	}
+	readme, err := os.ReadFile(filepath.Join(item.Path, "README.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(readme), "[links](../../S01.11%20Entertainment/S01.11.16%20Links/links.md)") {
+		t.Errorf("Markdown link out of moved item not rewritten: %s", readme)
+	}
+	if !strings.Contains(string(stub), "![poster](../poster.png)") {
+		t.Errorf("Markdown link within moved item should be kept: %s", stub)
+	}
+}
+
+func TestRelinkMoves_RewritesLinksAfterExternalRename(t *testing.T) {
+	vaultPath := setupMutationVault(t)
+	idx := openSyncedIndex(t, vaultPath)
+	repo := filesystem.NewRepository(vaultPath, filesystem.WithIndex(idx))
+
+	category := filepath.Join(vaultPath, "S01 Personal", "S01.10-19 Lifestyle", "S01.11 Entertainment")
+	if err := os.Rename(filepath.Join(category, "S01.11.15 Theatre"), filepath.Join(category, "S01.11.15 Drama")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := idx.SyncIncremental(); err != nil {
+		t.Fatalf("SyncIncremental failed: %v", err)
+	}
+	moves, err := idx.PendingMoves()
+	if err != nil || len(moves) != 1 {
+		t.Fatalf("expected one pending move, got %+v, %v", moves, err)
+	}
+
+	report, err := repo.RelinkMoves(moves)
+	if err != nil {
+		t.Fatalf("RelinkMoves failed: %v", err)
+	}
+	// Six wiki links, plus the Markdown link in notes.md
+	if report.LinksRewritten() != 7 {
+		t.Errorf("expected 7 links rewritten, got %s", report.Summary())
+	}
+
+	links, err := os.ReadFile(filepath.Join(category, "S01.11.16 Links", "links.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "- [[S01.11.15 Drama]]\n- [[S01.11.15 Drama]]\n- [[S01.11.15 Drama|My theatre]]\n- [[S01.11 Entertainment]]"
+	if string(links) != want {
+		t.Errorf("links.md = %q, want %q", links, want)
+	}
+	assertMatchesFreshSync(t, vaultPath, idx)
+}
+
+func TestRelinkMoves_RelinksSeveralMovesInOnePass(t *testing.T) {
+	vaultPath := setupMutationVault(t)
+	idx := openSyncedIndex(t, vaultPath)
+	repo := filesystem.NewRepository(vaultPath, filesystem.WithIndex(idx))
+
+	area := filepath.Join(vaultPath, "S01 Personal", "S01.10-19 Lifestyle")
+	for _, rename := range [][2]string{
+		{filepath.Join("S01.11 Entertainment", "S01.11.15 Theatre"), filepath.Join("S01.11 Entertainment", "S01.11.15 Drama")},
+		{"S01.11 Entertainment", "S01.11 Shows"},
+	} {
+		if err := os.Rename(filepath.Join(area, rename[0]), filepath.Join(area, rename[1])); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := idx.SyncIncremental(); err != nil {
+			t.Fatalf("SyncIncremental failed: %v", err)
+		}
+	}
+	moves, err := idx.PendingMoves()
+	if err != nil || len(moves) != 2 {
+		t.Fatalf("expected two pending moves, got %+v, %v", moves, err)
+	}
+
+	report, err := repo.RelinkMoves(moves)
+	if err != nil {
+		t.Fatalf("RelinkMoves failed: %v", err)
+	}
+	// Seven wiki links, plus the Markdown link in notes.md, each rewritten once
+	if report.LinksRewritten() != 8 {
+		t.Errorf("expected 8 links rewritten, got %s", report.Summary())
+	}
+
+	links, err := os.ReadFile(filepath.Join(area, "S01.11 Shows", "S01.11.16 Links", "links.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "- [[S01.11.15 Drama]]\n- [[S01.11.15 Drama]]\n- [[S01.11.15 Drama|My theatre]]\n- [[S01.11 Shows]]"
+	if string(links) != want {
+		t.Errorf("links.md = %q, want %q", links, want)
+	}
+	notes, err := os.ReadFile(filepath.Join(vaultPath, "notes.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(notes), "(S01%20Personal/S01.10-19%20Lifestyle/S01.11%20Shows/S01.11.15%20Drama/README.md)") {
+		t.Errorf("Markdown link into the moved item not rewritten: %s", notes)
+	}
+	readme, err := os.ReadFile(filepath.Join(area, "S01.11 Shows", "S01.11.15 Drama", "README.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(readme), "[links](../S01.11.16%20Links/links.md)") {
+		t.Errorf("Markdown link between entries that moved together should be kept: %s", readme)
+	}
+	assertMatchesFreshSync(t, vaultPath, idx)
+}
+
+func TestFindLinksToName(t *testing.T) {
+	vaultPath := setupMutationVault(t)
+	idx := openSyncedIndex(t, vaultPath)
+
+	tests := []struct {
+		name    string
+		sources []string
+	}{
+		{"s01.11.15 theatre", []string{"links.md", "notes.md", "plan.md"}},
+		{"S01.11.15", []string{"links.md", "links.md", "stub.md"}},
+		{"[Archived] Opera", []string{"notes.md"}},
+		{"S01.11.15 Theatre Extra", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			edges, err := idx.FindLinksToName(tt.name)
+			if err != nil {
+				t.Fatalf("FindLinksToName failed: %v", err)
+			}
+			var sources []string
+			for _, edge := range edges {
+				sources = append(sources, filepath.Base(edge.SourcePath))
+			}
+			slices.Sort(sources)
+			if !slices.Equal(sources, tt.sources) {
+				t.Errorf("sources = %v, want %v", sources, tt.sources)
+			}
+		})
+	}
+}
+
+func TestRenameItem_RewritesOnlyIndexedLinks(t *testing.T) {
+	vaultPath := setupMutationVault(t)
+	idx := openSyncedIndex(t, vaultPath)
+	repo := filesystem.NewRepository(vaultPath, filesystem.WithIndex(idx))
+
+	// A note the index has not seen yet is left alone: the rename trusts the
+	// index instead of walking the vault
+	unseen := filepath.Join(vaultPath, "unseen.md")
+	if err := os.WriteFile(unseen, []byte("[[S01.11.15 Theatre]]"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, report, err := repo.RenameItem("S01.11.15", "Drama")
+	if err != nil {
+		t.Fatalf("RenameItem failed: %v", err)
+	}
+	if len(report.FilesRewritten) != 4 {
+		t.Errorf("expected 4 files rewritten, got %+v", report.FilesRewritten)
+	}
+	if content, _ := os.ReadFile(unseen); string(content) != "[[S01.11.15 Theatre]]" {
+		t.Errorf("unindexed note was rewritten: %s", content)
+	}
+	plan, err := os.ReadFile(filepath.Join(vaultPath, "S01 Personal", "S01.10-19 Lifestyle", "S01.12 Travel", "S01.12.11 Japan", "plan.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(plan) != "Kabuki, like [[S01.11.15 Drama]]" {
+		t.Errorf("plan.md = %q", plan)
+	}
+}
@@ -0,0 +1,133 @@
+package sqlite
+
+import (
+	"database/sql"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"libraio/internal/domain"
+)
+
+// Wiki links to notes by name, [[Meeting notes]] or [[Projects/Meeting
+// notes]], are resolved against note_names: every file under its name, a note
+// without its .md extension, and every note under the aliases its frontmatter
+// gives it. Names and folders are stored lower-cased, since Obsidian matches
+// them ignoring case. A resolved link keeps its note in target_path; when
+// names change, the links they may affect are unresolved again, and resolved
+// anew before a sync or a change through the index commits.
+
+// insertNoteNameSQL inserts a name from noteNameArgs
+const insertNoteNameSQL = `INSERT INTO note_names (name, path, dir, jd_id, alias) VALUES (?, ?, ?, ?, ?)`
+
+// execer runs a statement, as sql.Tx does
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// insertNoteNames stores the name of the file at relPath and the aliases of
+// the note, through a prepared insertNoteNameSQL statement. Links are left as
+// they are, for syncs that resolve every link afterwards.
+func insertNoteNames(stmt *sql.Stmt, relPath string, aliases []string) error {
+	if _, err := stmt.Exec(noteNameArgs(relPath, domain.NoteName(relPath), false)...); err != nil {
+		return err
+	}
+	for _, alias := range aliases {
+		if _, err := stmt.Exec(noteNameArgs(relPath, alias, true)...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// replaceNoteNames replaces the names of the file at relPath with its own
+// name and the aliases given
+func replaceNoteNames(tx execer, relPath string, aliases []string) error {
+	if err := dropNoteNames(tx, relPath, false); err != nil {
+		return err
+	}
+	if err := addNoteName(tx, relPath, domain.NoteName(relPath), false); err != nil {
+		return err
+	}
+	return addAliases(tx, relPath, aliases)
+}
+
+// addAliases stores the aliases of the note at relPath
+func addAliases(tx execer, relPath string, aliases []string) error {
+	for _, alias := range aliases {
+		if err := addNoteName(tx, relPath, alias, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addNoteName stores a name of the file at relPath. Links by that name may
+// now lead to it, so they are unresolved.
+func addNoteName(tx execer, relPath, name string, alias bool) error {
+	args := noteNameArgs(relPath, name, alias)
+	if _, err := tx.Exec(insertNoteNameSQL, args...); err != nil {
+		return err
+	}
+	_, err := tx.Exec(`UPDATE edges SET target_path = NULL, target_jd_id = ''
+		WHERE target_name IS NOT NULL AND name_key = ? AND target_path IS NOT NULL`, args[0])
+	return err
+}
+
+// dropNoteNames removes the names of the file at relPath, or of everything
+// at or beneath it if subtree is set, and unresolves the links leading there
+func dropNoteNames(tx execer, relPath string, subtree bool) error {
+	lo, hi := relPath, relPath
+	if subtree {
+		lo, hi = subtreeRange(relPath)
+	}
+	if _, err := tx.Exec(`UPDATE edges SET target_path = NULL, target_jd_id = ''
+		WHERE target_name IS NOT NULL AND (target_path = ? OR (target_path >= ? AND target_path < ?))`, relPath, lo, hi); err != nil {
+		return err
+	}
+	_, err := tx.Exec(`DELETE FROM note_names WHERE path = ? OR (path >= ? AND path < ?)`, relPath, lo, hi)
+	return err
+}
+
+// noteNameArgs returns the statement arguments for a name of the file at
+// relPath, in column order
+func noteNameArgs(relPath, name string, alias bool) []any {
+	dir := filepath.ToSlash(filepath.Dir(relPath))
+	if dir == "." {
+		dir = ""
+	}
+	return []any{strings.ToLower(name), relPath, strings.ToLower(dir), domain.PathJDID(relPath), alias}
+}
+
+// nameKey returns the lower-cased name and folder prefix a note link is
+// resolved by, for the name_key and name_dir columns
+func nameKey(targetName string) (string, string) {
+	target := strings.ToLower(strings.Trim(targetName, "/"))
+	dir := path.Dir(target)
+	if dir == "." {
+		dir = ""
+	}
+	return path.Base(target), dir
+}
+
+// resolveNoteLinksSQL points each unresolved note link at the note it names,
+// the way Obsidian resolves it: a folder prefix must match the end of the
+// note's folder; notes by name come before notes by alias, then the note with
+// the shortest path wins. The link takes the JD ID its note lies in. Links
+// naming no note stay unresolved.
+const resolveNoteLinksSQL = `
+	UPDATE edges SET (target_path, target_jd_id) = (
+		SELECT max(path), coalesce(max(jd_id), '') FROM (
+			SELECT n.path, n.jd_id FROM note_names n
+			WHERE n.name = edges.name_key
+				AND (edges.name_dir = '' OR n.dir = edges.name_dir OR substr(n.dir, -length(edges.name_dir) - 1) = '/' || edges.name_dir)
+			ORDER BY n.alias, length(n.path), n.path LIMIT 1
+		)
+	) WHERE target_name IS NOT NULL AND target_path IS NULL
+`
+
+// resolveNoteLinks resolves the note links left unresolved
+func resolveNoteLinks(tx execer) error {
+	_, err := tx.Exec(resolveNoteLinksSQL)
+	return err
+}
@@ -0,0 +1,133 @@
+package sqlite
+
+import (
+	"database/sql"
+	"path/filepath"
+	"strings"
+
+	"libraio/internal/domain"
+)
+
+// The frontmatter properties and the tags of every note are kept in the
+// properties and tags tables, under the JD ID the note lies in, so items can
+// be found by status, date or tag without reading their files. Keys, values
+// and tags compare ignoring case.
+
+// insertPropertySQL and insertTagSQL store a property or tag of a note
+const (
+	insertPropertySQL = `INSERT INTO properties (path, jd_id, key, value) VALUES (?, ?, ?, ?)`
+	insertTagSQL      = `INSERT INTO tags (path, jd_id, tag) VALUES (?, ?, ?)`
+)
+
+// noteMetadata is what a note's frontmatter and body say about it
+type noteMetadata struct {
+	aliases    []string
+	properties []domain.Property
+	tags       []string
+}
+
+// readMetadata returns the metadata of a note, or none for other files
+func readMetadata(relPath string, content []byte) noteMetadata {
+	if !strings.EqualFold(filepath.Ext(relPath), ".md") {
+		return noteMetadata{}
+	}
+	return noteMetadata{
+		aliases:    domain.FrontmatterAliases(content),
+		properties: domain.FrontmatterProperties(content),
+		tags:       domain.NoteTags(content),
+	}
+}
+
+// metadataWriter stores note metadata through prepared statements
+type metadataWriter struct {
+	insertProperty *sql.Stmt
+	insertTag      *sql.Stmt
+}
+
+// prepareMetadataWriter prepares the statements of a metadataWriter in tx
+func prepareMetadataWriter(tx *sql.Tx) (*metadataWriter, error) {
+	insertProperty, err := tx.Prepare(insertPropertySQL)
+	if err != nil {
+		return nil, err
+	}
+	insertTag, err := tx.Prepare(insertTagSQL)
+	if err != nil {
+		_ = insertProperty.Close()
+		return nil, err
+	}
+	return &metadataWriter{insertProperty: insertProperty, insertTag: insertTag}, nil
+}
+
+// insert stores the properties and tags of the note at relPath
+func (w *metadataWriter) insert(relPath string, meta noteMetadata) error {
+	jdID := domain.PathJDID(relPath)
+	for _, p := range meta.properties {
+		if _, err := w.insertProperty.Exec(relPath, jdID, p.Key, p.Value); err != nil {
+			return err
+		}
+	}
+	for _, tag := range meta.tags {
+		if _, err := w.insertTag.Exec(relPath, jdID, tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close releases the prepared statements
+func (w *metadataWriter) Close() error {
+	_ = w.insertTag.Close()
+	return w.insertProperty.Close()
+}
+
+// replaceMetadata replaces the properties and tags of the note at relPath
+func replaceMetadata(tx execer, relPath string, meta noteMetadata) error {
+	if err := deleteMetadata(tx, relPath, false); err != nil {
+		return err
+	}
+	jdID := domain.PathJDID(relPath)
+	for _, p := range meta.properties {
+		if _, err := tx.Exec(insertPropertySQL, relPath, jdID, p.Key, p.Value); err != nil {
+			return err
+		}
+	}
+	for _, tag := range meta.tags {
+		if _, err := tx.Exec(insertTagSQL, relPath, jdID, tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteMetadata removes the properties and tags of the note at relPath, or
+// of every note at or beneath it if subtree is set
+func deleteMetadata(tx execer, relPath string, subtree bool) error {
+	lo, hi := relPath, relPath
+	if subtree {
+		lo, hi = subtreeRange(relPath)
+	}
+	for _, q := range []string{
+		`DELETE FROM properties WHERE path = ? OR (path >= ? AND path < ?)`,
+		`DELETE FROM tags WHERE path = ? OR (path >= ? AND path < ?)`,
+	} {
+		if _, err := tx.Exec(q, relPath, lo, hi); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// moveMetadata carries the properties and tags of the note at oldPath over
+// to newPath, and to the JD ID it now lies in
+func moveMetadata(tx execer, oldPath, newPath string) error {
+	jdID := domain.PathJDID(newPath)
+	for _, q := range []string{
+		`UPDATE properties SET path = ?, jd_id = ? WHERE path = ?`,
+		`UPDATE tags SET path = ?, jd_id = ? WHERE path = ?`,
+	} {
+		if _, err := tx.Exec(q, newPath, jdID, oldPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
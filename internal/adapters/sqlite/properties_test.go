@@ -0,0 +1,103 @@
+package sqlite_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"libraio/internal/adapters/filesystem"
+	"libraio/internal/application/commands"
+	"libraio/internal/domain"
+)
+
+func TestItemSearch_QueriesPropertiesAndTags(t *testing.T) {
+	vaultPath := setupMutationVault(t)
+	area := filepath.Join("S01 Personal", "S01.10-19 Lifestyle")
+	theatre := filepath.Join(area, "S01.11 Entertainment", "S01.11.15 Theatre")
+	japan := filepath.Join(area, "S01.12 Travel", "S01.12.11 Japan")
+	files := map[string]string{
+		filepath.Join(theatre, "season.md"): "---\nstatus: active\ntags: [project/stage]\n---\n# Season #Ideas",
+		filepath.Join(japan, "plan.md"):     "---\nstatus: Active\n---\nKabuki, like [[S01.11.15 Theatre]] #project\n\n`#code`",
+		filepath.Join(japan, "budget.md"):   "---\nstatus: done\ntype: trip\n---\n# Budget",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(vaultPath, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	idx := openSyncedIndex(t, vaultPath)
+	repo := filesystem.NewRepository(vaultPath, filesystem.WithIndex(idx))
+
+	// find returns the IDs of the items matching query and name
+	find := func(query domain.ItemQuery, name string) []string {
+		t.Helper()
+		results, err := commands.NewItemSearchCommand(repo, query, name).Execute(context.Background())
+		if err != nil {
+			t.Fatalf("item search %+v failed: %v", query, err)
+		}
+		var ids []string
+		for _, r := range results {
+			ids = append(ids, r.ID)
+		}
+		return ids
+	}
+	active := domain.Property{Key: "Status", Value: "active"}
+
+	tests := []struct {
+		name  string
+		query domain.ItemQuery
+		words string
+		want  []string
+	}{
+		{"nested tags match their parent", domain.ItemQuery{Tags: []string{"project"}}, "", []string{"S01.11.15", "S01.12.11"}},
+		{"nested tag", domain.ItemQuery{Tags: []string{"#project/stage"}}, "", []string{"S01.11.15"}},
+		{"tags ignore case", domain.ItemQuery{Tags: []string{"ideas"}}, "", []string{"S01.11.15"}},
+		{"tags in code do not count", domain.ItemQuery{Tags: []string{"code"}}, "", nil},
+		{"property ignores case", domain.ItemQuery{Properties: []domain.Property{active}}, "", []string{"S01.11.15", "S01.12.11"}},
+		{"any note may match", domain.ItemQuery{Tags: []string{"project"}, Properties: []domain.Property{{Key: "status", Value: "done"}}}, "", []string{"S01.12.11"}},
+		{"every condition must match", domain.ItemQuery{Tags: []string{"ideas"}, Properties: []domain.Property{{Key: "status", Value: "done"}}}, "", nil},
+		{"scope", domain.ItemQuery{Scope: "S01.12", Tags: []string{"project"}}, "", []string{"S01.12.11"}},
+		{"property keys are never filters", domain.ItemQuery{Properties: []domain.Property{{Key: "type", Value: "trip"}}}, "", []string{"S01.12.11"}},
+		{"name only", domain.ItemQuery{Scope: "S01.11"}, "thtr", []string{"S01.11.15"}},
+		{"name narrows", domain.ItemQuery{Tags: []string{"project"}}, "japan", []string{"S01.12.11"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := find(tt.query, tt.words); !slices.Equal(got, tt.want) {
+				t.Errorf("item search %+v %q = %v, want %v", tt.query, tt.words, got, tt.want)
+			}
+		})
+	}
+
+	// An edit outside libraio is picked up by the next sync
+	if err := os.WriteFile(filepath.Join(vaultPath, japan, "plan.md"), []byte("---\nstatus: paused\n---\nKabuki"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := idx.SyncIncremental(); err != nil {
+		t.Fatalf("SyncIncremental failed: %v", err)
+	}
+	if got := find(domain.ItemQuery{Properties: []domain.Property{active}}, ""); !slices.Equal(got, []string{"S01.11.15"}) {
+		t.Errorf("after the edit, active items = %v, want [S01.11.15]", got)
+	}
+	assertMatchesFreshSync(t, vaultPath, idx)
+
+	// Moving an item carries its metadata to its new ID
+	item, _, err := repo.MoveItem("S01.11.15", "S01.12")
+	if err != nil {
+		t.Fatalf("MoveItem failed: %v", err)
+	}
+	if got := find(domain.ItemQuery{Tags: []string{"project"}}, ""); !slices.Equal(got, []string{item.ID}) {
+		t.Errorf("after the move, items tagged project = %v, want [%s]", got, item.ID)
+	}
+	assertMatchesFreshSync(t, vaultPath, idx)
+
+	results, err := commands.NewItemSearchCommand(repo, domain.ItemQuery{Tags: []string{"ideas"}}, "").Execute(context.Background())
+	if err != nil {
+		t.Fatalf("item search failed: %v", err)
+	}
+	if len(results) != 1 || results[0].ID != item.ID || results[0].Path != item.Path {
+		t.Errorf("item search = %+v, want the moved item at %s", results, item.Path)
+	}
+}
@@ -0,0 +1,184 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"libraio/internal/domain"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// Search queries are compiled into a condition on the nodes table, so only
+// the entries they match are read. Filters on tags, properties and backlinks
+// become subqueries; matching names and JD IDs, which SQLite cannot do the
+// way the domain does, calls back into Go through the functions below.
+
+// driverName is the SQLite driver the index is opened with: go-sqlite3 with
+// the functions search queries are compiled to
+const driverName = "sqlite3_libraio"
+
+func init() {
+	sql.Register(driverName, &sqlite3.SQLiteDriver{ConnectHook: registerSearchFunctions})
+}
+
+// registerSearchFunctions adds the functions search queries call to a
+// connection: search_text(name, jd_id, text, phrase) matches a word or
+// phrase, and search_within(path, id) whether a path lies within a JD ID
+func registerSearchFunctions(conn *sqlite3.SQLiteConn) error {
+	if err := conn.RegisterFunc("search_text", func(name, jdID, text string, phrase bool) bool {
+		return (&domain.TextNode{Text: text, Phrase: phrase}).MatchName(name, jdID)
+	}, true); err != nil {
+		return err
+	}
+	return conn.RegisterFunc("search_within", func(path, id string) bool {
+		return domain.IsWithin(domain.PathEntity(path), id)
+	}, true)
+}
+
+// FindEntries returns the files and folders but the vault root a search query
+// matches, by path, with relative dates taken from now
+func (idx *Index) FindEntries(query *domain.SearchQuery, now time.Time) ([]domain.IndexNode, error) {
+	if query.Root == nil {
+		return nil, nil
+	}
+	c := &queryCompiler{now: now}
+	where := c.compile(query.Root)
+	return idx.queryNodes(`SELECT `+nodeColumns+` FROM nodes n WHERE n.path != '.' AND `+where+` ORDER BY n.path`, c.args...)
+}
+
+// queryCompiler compiles search query nodes into SQL conditions on the nodes
+// table, aliased n, collecting their arguments in order
+type queryCompiler struct {
+	now  time.Time
+	args []any
+}
+
+// compile returns the condition a node matches entries by. Conditions never
+// evaluate to NULL, so negating them matches exactly the other entries.
+func (c *queryCompiler) compile(node domain.QueryNode) string {
+	switch n := node.(type) {
+	case *domain.AndNode:
+		return c.join(n.Terms, " AND ")
+	case *domain.OrNode:
+		return c.join(n.Terms, " OR ")
+	case *domain.NotNode:
+		return `NOT (` + c.compile(n.Term) + `)`
+	case *domain.TextNode:
+		return c.text(n)
+	case *domain.TypeFilter:
+		if n.Type == domain.IDTypeFile {
+			return `NOT n.is_dir`
+		}
+		return c.bind(`(n.is_dir AND n.jd_type IS ?)`, n.Type.String())
+	case *domain.InFilter:
+		return c.within(n.ID)
+	case *domain.ScopeFilter:
+		return c.within(n.ID)
+	case *domain.ExtFilter:
+		// An extension never holds a dot or a separator
+		if strings.ContainsAny(n.Ext, `./\`) {
+			return `0`
+		}
+		return c.bind(`(NOT n.is_dir AND n.path LIKE ? ESCAPE '\')`, "%."+likeEscaper.Replace(n.Ext))
+	case *domain.TagFilter:
+		return c.owned(`tags`, `(m.tag = ? OR m.tag LIKE ? ESCAPE '\')`, n.Tag, likeEscaper.Replace(n.Tag)+"/%")
+	case *domain.PropertyFilter:
+		return c.owned(`properties`, `m.key = ? AND m.value = ?`, n.Property.Key, n.Property.Value)
+	case *domain.HasFilter:
+		switch n.What {
+		case "backlinks":
+			return c.backlinked()
+		case "tags":
+			return c.owned(`tags`, `1`)
+		default:
+			return c.owned(`properties`, `1`)
+		}
+	case *domain.ModifiedFilter:
+		start, end := n.Bounds(c.now)
+		switch n.Op {
+		case ">":
+			return c.bind(`n.mtime >= ?`, end.UnixNano())
+		case ">=":
+			return c.bind(`n.mtime >= ?`, start.UnixNano())
+		case "<":
+			return c.bind(`n.mtime < ?`, start.UnixNano())
+		case "<=":
+			return c.bind(`n.mtime < ?`, end.UnixNano())
+		default:
+			return c.bind(`(n.mtime >= ? AND n.mtime < ?)`, start.UnixNano(), end.UnixNano())
+		}
+	}
+	panic(fmt.Sprintf("sqlite: unknown query node %T", node))
+}
+
+// join compiles terms joined by AND or OR
+func (c *queryCompiler) join(terms []domain.QueryNode, op string) string {
+	conditions := make([]string, len(terms))
+	for i, term := range terms {
+		conditions[i] = c.compile(term)
+	}
+	return "(" + strings.Join(conditions, op) + ")"
+}
+
+// bind returns a condition, adding its arguments
+func (c *queryCompiler) bind(condition string, args ...any) string {
+	c.args = append(c.args, args...)
+	return condition
+}
+
+// text returns the condition of a word or phrase. Words and phrases written
+// in ASCII are first looked for with LIKE, so the function matching them is
+// called for few entries. LIKE ignores ASCII case only, which misses just the
+// rare letters lower-casing to ASCII, such as the Kelvin sign.
+func (c *queryCompiler) text(n *domain.TextNode) string {
+	const match = `search_text(COALESCE(n.name, ''), COALESCE(n.jd_id, ''), ?, ?)`
+	for i := range len(n.Text) {
+		if n.Text[i] >= utf8.RuneSelf {
+			return c.bind(match, n.Text, n.Phrase)
+		}
+	}
+
+	pattern := "%" + likeEscaper.Replace(n.Text) + "%"
+	if !n.Phrase {
+		letters := make([]string, len(n.Text))
+		for i := range len(n.Text) {
+			letters[i] = likeEscaper.Replace(n.Text[i : i+1])
+		}
+		pattern = "%" + strings.Join(letters, "%") + "%"
+	}
+	return c.bind(`((COALESCE(n.name, '') LIKE ? ESCAPE '\' OR COALESCE(n.jd_id, '') LIKE ? ESCAPE '\') AND `+match+`)`, pattern, pattern, n.Text, n.Phrase)
+}
+
+// within returns the condition of in: and scope:. The innermost JD folder
+// holding an entry names its JD ID, which starts like every ID it lies
+// within, so LIKE rules most entries out before the function is called.
+func (c *queryCompiler) within(id string) string {
+	prefix := id
+	if domain.ParseIDType(id) == domain.IDTypeArea {
+		// S01.10-19 holds the IDs starting with S01.1
+		start, _, _ := strings.Cut(id, "-")
+		prefix = start[:len(start)-1]
+	}
+	return c.bind(`(n.path LIKE ? ESCAPE '\' AND search_within(n.path, ?))`, "%"+likeEscaper.Replace(prefix)+"%", id)
+}
+
+// owned returns a condition on the tags or properties table, aliased m, met
+// by a row of the note itself or, for an item or category folder, of a note
+// filed in it: one beneath its path, outside any item it holds
+func (c *queryCompiler) owned(table, condition string, args ...any) string {
+	lo, hi := subtreeRange("")
+	c.args = append(c.args, domain.IDTypeCategory.String(), domain.IDTypeItem.String(), lo, hi)
+	return c.bind(`EXISTS (SELECT 1 FROM `+table+` m WHERE (m.path = n.path OR (n.is_dir AND n.jd_type IN (?, ?) AND m.path >= n.path || ? AND m.path < n.path || ? AND m.jd_id = n.jd_id)) AND `+condition+`)`, args...)
+}
+
+// backlinked returns the condition of has:backlinks: a file linked to from
+// another file, or a JD folder linked to from a file outside it
+func (c *queryCompiler) backlinked() string {
+	lo, hi := subtreeRange("")
+	return c.bind(`(EXISTS (SELECT 1 FROM edges e WHERE e.target_path = n.path AND e.source_path != e.target_path)
+		OR (n.is_dir AND COALESCE(n.jd_id, '') != '' AND EXISTS (SELECT 1 FROM edges e WHERE e.target_jd_id = n.jd_id AND NOT (e.source_path >= n.path || ? AND e.source_path < n.path || ?))))`, lo, hi)
+}
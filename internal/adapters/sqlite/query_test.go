@@ -0,0 +1,79 @@
+package sqlite_test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"libraio/internal/application"
+)
+
+func TestFindEntries_CompilesQueries(t *testing.T) {
+	vaultPath := setupMutationVault(t)
+	area := filepath.Join("S01 Personal", "S01.10-19 Lifestyle")
+	theatre := filepath.Join(area, "S01.11 Entertainment", "S01.11.15 Theatre")
+	drama := filepath.Join(area, "S01.11 Entertainment", "S01.11.15 Drama")
+	files := map[string]string{
+		filepath.Join(theatre, "season.md"): "---\nstatus: active\n---\n# Season #stage",
+		// A folder reusing the item's JD ID keeps its own tags
+		filepath.Join(drama, "cast.md"): "#cast",
+	}
+	for name, content := range files {
+		path := filepath.Join(vaultPath, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := time.Date(1999, 12, 31, 12, 0, 0, 0, time.Local)
+	if err := os.Chtimes(filepath.Join(vaultPath, theatre, "poster.png"), old, old); err != nil {
+		t.Fatal(err)
+	}
+	idx := openSyncedIndex(t, vaultPath)
+
+	tests := []struct {
+		query string
+		want  []string // Paths within the area, in vault order
+	}{
+		{"tag:stage", []string{"S01.11 Entertainment/S01.11.15 Theatre", "S01.11 Entertainment/S01.11.15 Theatre/season.md"}},
+		{"status:ACTIVE", []string{"S01.11 Entertainment/S01.11.15 Theatre", "S01.11 Entertainment/S01.11.15 Theatre/season.md"}},
+		{"tag:cast", []string{"S01.11 Entertainment/S01.11.15 Drama", "S01.11 Entertainment/S01.11.15 Drama/cast.md"}},
+		{"has:tags -type:file", []string{"S01.11 Entertainment/S01.11.15 Drama", "S01.11 Entertainment/S01.11.15 Theatre"}},
+		{"tag:cast OR tag:stage type:file", []string{"S01.11 Entertainment/S01.11.15 Drama", "S01.11 Entertainment/S01.11.15 Drama/cast.md", "S01.11 Entertainment/S01.11.15 Theatre/season.md"}},
+		{"type:item in:S01.11", []string{"S01.11 Entertainment/S01.11.09 Archive", "S01.11 Entertainment/S01.11.15 Drama", "S01.11 Entertainment/S01.11.15 Theatre", "S01.11 Entertainment/S01.11.16 Links"}},
+		{"scope:S01 -in:S01.11 -in:S01.12 type:file", []string{"S01.10 Lifestyle management/S01.10.09 Archive/README.md"}},
+		{"thtr", []string{"S01.11 Entertainment/S01.11.15 Theatre"}},
+		{"ENTERTAIN", []string{"S01.11 Entertainment"}},
+		{`"100%"`, nil},
+		{`"lifestyle m"`, []string{"S01.10 Lifestyle management"}},
+		{"ext:PNG", []string{"S01.11 Entertainment/S01.11.15 Theatre/poster.png"}},
+		{"modified:<2000-01-01", []string{"S01.11 Entertainment/S01.11.15 Theatre/poster.png"}},
+		{"has:backlinks type:file", []string{"S01.11 Entertainment/S01.11.15 Theatre/README.md", "S01.11 Entertainment/S01.11.15 Theatre/poster.png", "S01.11 Entertainment/S01.11.16 Links/links.md"}},
+		{"-has:backlinks type:file in:S01.11.15", []string{"S01.11 Entertainment/S01.11.15 Drama/cast.md", "S01.11 Entertainment/S01.11.15 Theatre/season.md", "S01.11 Entertainment/S01.11.15 Theatre/tickets/stub.md"}},
+		{"ext:tar.gz", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			query, err := application.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatalf("ParseQuery(%q) error = %v", tt.query, err)
+			}
+			nodes, err := idx.FindEntries(query, time.Now())
+			if err != nil {
+				t.Fatalf("FindEntries failed: %v", err)
+			}
+			var got []string
+			for _, node := range nodes {
+				got = append(got, filepath.ToSlash(strings.TrimPrefix(node.Path, area+string(filepath.Separator))))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("FindEntries(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
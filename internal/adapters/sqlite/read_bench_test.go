@@ -0,0 +1,164 @@
+package sqlite_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"libraio/internal/adapters/filesystem"
+	"libraio/internal/adapters/sqlite"
+	"libraio/internal/application/commands"
+	"libraio/internal/domain"
+)
+
+// benchVault returns VAULT_PATH, or a generated vault when it is not set
+func benchVault(b *testing.B) string {
+	b.Helper()
+	if vaultPath := os.Getenv("VAULT_PATH"); vaultPath != "" {
+		return vaultPath
+	}
+	return generateBenchVault(b)
+}
+
+// generateBenchVault writes a vault of 50k files (10 areas x 10 categories x
+// 50 items x 10 files) whose notes link to other items of their category
+func generateBenchVault(b *testing.B) string {
+	b.Helper()
+	vaultPath := b.TempDir()
+	scopePath := filepath.Join(vaultPath, "S01 Bench")
+	for a := 1; a <= 9; a++ {
+		areaPath := filepath.Join(scopePath, fmt.Sprintf("S01.%d0-%d9 Area %d", a, a, a))
+		for c := 0; c <= 9; c++ {
+			categoryID := fmt.Sprintf("S01.%d%d", a, c)
+			categoryPath := filepath.Join(areaPath, categoryID+" Category")
+			for i := 11; i <= 60; i++ {
+				itemPath := filepath.Join(categoryPath, fmt.Sprintf("%s.%02d Item %d", categoryID, i, i))
+				if err := os.MkdirAll(itemPath, 0755); err != nil {
+					b.Fatal(err)
+				}
+				for f := 0; f < 10; f++ {
+					content := fmt.Sprintf("# Note %d\n\nSee [[%s.%02d]].\n", f, categoryID, 11+(i+f)%50)
+					if err := os.WriteFile(filepath.Join(itemPath, fmt.Sprintf("note %d.md", f)), []byte(content), 0644); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		}
+	}
+	return vaultPath
+}
+
+// benchRepos returns repositories reading from disk and from a synced index
+func benchRepos(b *testing.B) map[string]*filesystem.Repository {
+	b.Helper()
+	vaultPath := benchVault(b)
+	b.Setenv("XDG_DATA_HOME", b.TempDir())
+
+	idx := sqlite.NewIndex()
+	if err := idx.Open(vaultPath); err != nil {
+		b.Fatalf("failed to open index: %v", err)
+	}
+	b.Cleanup(func() { _ = idx.Close() })
+	if _, err := idx.SyncFull(); err != nil {
+		b.Fatalf("sync failed: %v", err)
+	}
+
+	return map[string]*filesystem.Repository{
+		"disk":  filesystem.NewRepository(vaultPath),
+		"index": filesystem.NewRepository(vaultPath, filesystem.WithIndex(idx)),
+	}
+}
+
+// lastItem returns the last item of the last category of the vault, the
+// most expensive one to find by reading folders
+func lastItem(b *testing.B, repo *filesystem.Repository) domain.Item {
+	b.Helper()
+	var found *domain.Item
+	scopes, _ := repo.ListScopes()
+	for _, scope := range scopes {
+		areas, _ := repo.ListAreas(scope.ID)
+		for _, area := range areas {
+			categories, _ := repo.ListCategories(area.ID)
+			for _, category := range categories {
+				items, _ := repo.ListItems(category.ID)
+				if len(items) > 0 {
+					found = &items[len(items)-1]
+				}
+			}
+		}
+	}
+	if found == nil {
+		b.Skip("vault has no items")
+	}
+	return *found
+}
+
+// BenchmarkGetPath benchmarks resolving an item ID to its folder
+func BenchmarkGetPath(b *testing.B) {
+	for name, repo := range benchRepos(b) {
+		item := lastItem(b, repo)
+		b.Run(name, func(b *testing.B) {
+			for b.Loop() {
+				if _, err := repo.GetPath(item.ID); err != nil {
+					b.Fatalf("GetPath failed: %v", err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkListItems benchmarks listing the items of a category
+func BenchmarkListItems(b *testing.B) {
+	for name, repo := range benchRepos(b) {
+		item := lastItem(b, repo)
+		b.Run(name, func(b *testing.B) {
+			for b.Loop() {
+				if _, err := repo.ListItems(item.CategoryID); err != nil {
+					b.Fatalf("ListItems failed: %v", err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkLoadChildren benchmarks expanding an item in the tree
+func BenchmarkLoadChildren(b *testing.B) {
+	for name, repo := range benchRepos(b) {
+		item := lastItem(b, repo)
+		b.Run(name, func(b *testing.B) {
+			for b.Loop() {
+				node := &domain.TreeNode{Type: domain.IDTypeItem, ID: item.ID, Path: item.Path}
+				if err := repo.LoadChildren(node); err != nil {
+					b.Fatalf("LoadChildren failed: %v", err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkSearch benchmarks a name search across the whole vault
+func BenchmarkSearch(b *testing.B) {
+	for name, repo := range benchRepos(b) {
+		item := lastItem(b, repo)
+		b.Run(name, func(b *testing.B) {
+			for b.Loop() {
+				if _, err := repo.Search(item.Name); err != nil {
+					b.Fatalf("Search failed: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkQuerySearch(b *testing.B) {
+	repo := benchRepos(b)["index"]
+	item := lastItem(b, repo)
+	query := "type:item in:" + item.ID[:len(item.ID)-3] + " " + item.Name
+	for b.Loop() {
+		if _, err := commands.NewQuerySearchCommand(repo, query, 0).Execute(context.Background()); err != nil {
+			b.Fatalf("query search failed: %v", err)
+		}
+	}
+}
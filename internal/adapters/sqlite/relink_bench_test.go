@@ -0,0 +1,47 @@
+package sqlite_test
+
+import (
+	"testing"
+
+	"libraio/internal/adapters/filesystem"
+	"libraio/internal/adapters/sqlite"
+)
+
+// BenchmarkRenameItem benchmarks renaming an item and rewriting the links to
+// it, finding them by walking the vault or through the index. It always uses
+// a generated vault, since it changes the vault.
+func BenchmarkRenameItem(b *testing.B) {
+	for _, name := range []string{"disk", "index"} {
+		b.Run(name, func(b *testing.B) {
+			vaultPath := generateBenchVault(b)
+			var opts []filesystem.RepoOption
+			if name == "index" {
+				b.Setenv("XDG_DATA_HOME", b.TempDir())
+				idx := sqlite.NewIndex()
+				if err := idx.Open(vaultPath); err != nil {
+					b.Fatalf("failed to open index: %v", err)
+				}
+				b.Cleanup(func() { _ = idx.Close() })
+				if _, err := idx.SyncFull(); err != nil {
+					b.Fatalf("sync failed: %v", err)
+				}
+				opts = append(opts, filesystem.WithIndex(idx))
+			}
+			repo := filesystem.NewRepository(vaultPath, opts...)
+			item := lastItem(b, repo)
+
+			descriptions := []string{"Renamed", item.Name}
+			i := 0
+			for b.Loop() {
+				_, report, err := repo.RenameItem(item.ID, descriptions[i%2])
+				if err != nil {
+					b.Fatalf("RenameItem failed: %v", err)
+				}
+				if len(report.FilesRewritten) == 0 {
+					b.Fatalf("no links rewritten: %+v", report)
+				}
+				i++
+			}
+		})
+	}
+}
@@ -1,11 +1,12 @@
 package sqlite
 
 import (
-	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"os"
 	"path/filepath"
-	"regexp"
 	"runtime"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -13,23 +14,35 @@ import (
 	"libraio/internal/domain"
 )
 
-// mdFile holds info about a markdown file to process
+// mdFile holds info about a note or canvas to process
 type mdFile struct {
 	fullPath string
 	relPath  string
-	mtime    int64
+	info     os.FileInfo
 }
 
-// Link pattern for Obsidian wiki links: [[S01.XX.YY...]]
-var linkPattern = regexp.MustCompile(`\[\[([^\]|]+)(?:\|[^\]]+)?\]\]`)
-
-// JD ID pattern: S0X.XX.XX or S0X.XX
-var jdIDPattern = regexp.MustCompile(`^(S0[0-9]\.[0-9][0-9](?:\.[0-9][0-9])?)`)
+// Sync rebuilds the index if it needs a full rebuild and updates it
+// incrementally otherwise, reporting progress to fn if it is not nil
+func (idx *Index) Sync(progress domain.SyncProgressFunc) (*domain.SyncStats, error) {
+	if idx.NeedsFullRebuild() {
+		return idx.syncFull(progress)
+	}
+	return idx.syncIncremental(progress)
+}
 
 // SyncFull performs a complete rebuild of the index
 func (idx *Index) SyncFull() (*domain.SyncStats, error) {
+	return idx.syncFull(nil)
+}
+
+func (idx *Index) syncFull(progress domain.SyncProgressFunc) (*domain.SyncStats, error) {
 	start := time.Now()
-	stats := &domain.SyncStats{}
+	stats := &domain.SyncStats{Rebuilt: true}
+	reporter := &progressReporter{fn: progress}
+
+	// The previous node count estimates how much there is to scan
+	var estimate int
+	_ = idx.db.QueryRow(`SELECT COUNT(*) FROM nodes`).Scan(&estimate)
 
 	// Temporarily disable sync for faster bulk writes (safe - we can rebuild)
 	if _, err := idx.db.Exec(`PRAGMA synchronous = OFF`); err != nil {
@@ -51,27 +64,51 @@ func (idx *Index) SyncFull() (*domain.SyncStats, error) {
 	if _, err := tx.Exec(`DELETE FROM edges`); err != nil {
 		return nil, err
 	}
+	if _, err := tx.Exec(`DELETE FROM note_names`); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(`DELETE FROM properties`); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(`DELETE FROM tags`); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(`DELETE FROM content_fts`); err != nil {
+		return nil, err
+	}
 
 	// Prepare statements once
-	insertNodeStmt, err := tx.Prepare(`
-		INSERT INTO nodes (path, jd_id, jd_type, name, mtime)
-		VALUES (?, ?, ?, ?, ?)
-	`)
+	insertNodeStmt, err := tx.Prepare(insertNodeSQL)
 	if err != nil {
 		return nil, err
 	}
 	defer func() { _ = insertNodeStmt.Close() }()
 
-	insertEdgeStmt, err := tx.Prepare(`
-		INSERT OR REPLACE INTO edges (source_path, target_jd_id, link_text)
-		VALUES (?, ?, ?)
-	`)
+	insertEdgeStmt, err := tx.Prepare(insertEdgeSQL)
 	if err != nil {
 		return nil, err
 	}
 	defer func() { _ = insertEdgeStmt.Close() }()
 
-	// Collect markdown files for parallel processing
+	insertNameStmt, err := tx.Prepare(insertNoteNameSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = insertNameStmt.Close() }()
+
+	metadata, err := prepareMetadataWriter(tx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = metadata.Close() }()
+
+	insertContentStmt, err := tx.Prepare(`INSERT INTO content_fts (path, body) VALUES (?, ?)`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = insertContentStmt.Close() }()
+
+	// Collect notes and canvases for parallel processing
 	// Pre-allocate with estimated capacity
 	mdFiles := make([]mdFile, 0, 1024)
 
@@ -84,44 +121,35 @@ func (idx *Index) SyncFull() (*domain.SyncStats, error) {
 		name := d.Name()
 
 		// Skip hidden directories
-		if d.IsDir() && len(name) > 0 && name[0] == '.' {
+		if d.IsDir() && len(name) > 0 && name[0] == '.' && path != idx.vaultPath {
 			return filepath.SkipDir
 		}
 
 		relPath, _ := filepath.Rel(idx.vaultPath, path)
 		stats.FilesScanned++
+		reporter.report(domain.SyncProgress{Phase: domain.SyncScanning, Done: stats.FilesScanned, Total: max(estimate, stats.FilesScanned)}, false)
 
-		if d.IsDir() {
-			jdID, jdType := extractJDInfo(name)
-			if jdType != domain.IDTypeUnknown {
-				info, err := d.Info()
-				if err != nil {
-					return nil
-				}
-				_, err = insertNodeStmt.Exec(
-					relPath,
-					nullString(jdID),
-					nullString(jdType.String()),
-					extractDescription(name),
-					info.ModTime().Unix(),
-				)
-				if err == nil {
-					stats.NodesAdded++
-				}
-			}
-		} else if len(name) > 3 && strings.EqualFold(name[len(name)-3:], ".md") {
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+
+		if !d.IsDir() && domain.IsLinkSource(name) {
 			// Collect for parallel processing
-			info, err := d.Info()
-			if err != nil {
-				return nil
-			}
 			mdFiles = append(mdFiles, mdFile{
 				fullPath: path,
 				relPath:  relPath,
-				mtime:    info.ModTime().Unix(),
+				info:     info,
 			})
+			return nil
 		}
 
+		if _, err := insertNodeStmt.Exec(nodeArgs(relPath, name, info, "")...); err == nil {
+			stats.NodesAdded++
+		}
+		if !d.IsDir() {
+			return insertNoteNames(insertNameStmt, relPath, nil)
+		}
 		return nil
 	})
 
@@ -129,70 +157,42 @@ func (idx *Index) SyncFull() (*domain.SyncStats, error) {
 		return stats, err
 	}
 
-	// Process markdown files in parallel
-	// Use more workers for I/O bound tasks
-	numWorkers := runtime.NumCPU() * 2
-	if numWorkers > 32 {
-		numWorkers = 32
-	}
-
-	// Channel for files to process
-	fileCh := make(chan mdFile, len(mdFiles))
-	for _, f := range mdFiles {
-		fileCh <- f
-	}
-	close(fileCh)
-
-	// Channel for results
-	type fileResult struct {
-		relPath string
-		name    string
-		mtime   int64
-		edges   []domain.Edge
-	}
-	resultCh := make(chan fileResult, len(mdFiles))
-
-	// Spawn workers
-	var wg sync.WaitGroup
-	for i := 0; i < numWorkers; i++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for f := range fileCh {
-				edges, _ := parseLinksInFile(f.fullPath, f.relPath)
-				resultCh <- fileResult{
-					relPath: f.relPath,
-					name:    filepath.Base(f.fullPath),
-					mtime:   f.mtime,
-					edges:   edges,
-				}
-			}
-		}()
-	}
-
-	// Close results channel when workers done
-	go func() {
-		wg.Wait()
-		close(resultCh)
-	}()
-
-	// Insert nodes and edges from results
-	for r := range resultCh {
-		_, err := insertNodeStmt.Exec(r.relPath, nil, nil, r.name, r.mtime)
+	// Process notes and canvases in parallel
+	reporter.report(domain.SyncProgress{Phase: domain.SyncIndexing, Total: len(mdFiles)}, true)
+	parsed := 0
+	for r := range parseFiles(mdFiles, nil) {
+		_, err := insertNodeStmt.Exec(nodeArgs(r.relPath, r.info.Name(), r.info, r.hash)...)
 		if err == nil {
 			stats.NodesAdded++
 		}
+		if err := insertNoteNames(insertNameStmt, r.relPath, r.meta.aliases); err != nil {
+			return stats, err
+		}
+		if err := metadata.insert(r.relPath, r.meta); err != nil {
+			return stats, err
+		}
 		for _, edge := range r.edges {
-			_, err := insertEdgeStmt.Exec(edge.SourcePath, edge.TargetJDID, edge.LinkText)
+			_, err := insertEdgeStmt.Exec(edgeArgs(&edge)...)
 			if err == nil {
 				stats.EdgesAdded++
 			}
 		}
+		if r.hasBody {
+			if _, err := insertContentStmt.Exec(r.relPath, r.body); err != nil {
+				return stats, err
+			}
+		}
+		parsed++
+		reporter.report(domain.SyncProgress{Phase: domain.SyncIndexing, Done: parsed, Total: len(mdFiles)}, parsed == len(mdFiles))
+	}
+
+	if err := resolveNoteLinks(tx); err != nil {
+		return stats, err
 	}
 
 	// Update last sync time
 	if _, err := tx.Exec(`INSERT OR REPLACE INTO meta (key, value) VALUES ('last_sync_time', ?)`,
-		time.Now().Unix()); err != nil {
+		time.Now().UnixNano()); err != nil {
 		return stats, err
 	}
 
@@ -204,34 +204,50 @@ func (idx *Index) SyncFull() (*domain.SyncStats, error) {
 	return stats, nil
 }
 
-// SyncIncremental updates only files that changed since last sync
+// indexedEntry is what the index holds about an entry, for change detection
+type indexedEntry struct {
+	mtime  int64
+	hash   string
+	fileID int64
+	isDir  bool
+}
+
+// walkedEntry is an entry found on disk by an incremental sync
+type walkedEntry struct {
+	path    string
+	relPath string
+	info    os.FileInfo
+}
+
+// SyncIncremental updates only entries whose mtime changed since they were
+// indexed. Folders that kept their identity on disk and markdown files that
+// kept their content at a new path are recorded as moves, so their edges and
+// content are carried over instead of being dropped and reparsed.
 func (idx *Index) SyncIncremental() (*domain.SyncStats, error) {
+	return idx.syncIncremental(nil)
+}
+
+func (idx *Index) syncIncremental(progress domain.SyncProgressFunc) (*domain.SyncStats, error) {
 	start := time.Now()
 	stats := &domain.SyncStats{}
+	reporter := &progressReporter{fn: progress}
 
-	// Get last sync time
-	var lastSyncUnix int64
-	_ = idx.db.QueryRow(`SELECT value FROM meta WHERE key = 'last_sync_time'`).Scan(&lastSyncUnix)
-
-	// Track existing paths to detect deletions
-	existingPaths := make(map[string]bool)
-	rows, err := idx.db.Query(`SELECT path FROM nodes`)
+	// Track indexed mtimes to detect changes and deletions. Comparing against
+	// the last sync time instead would miss changes made just after a sync,
+	// since file timestamps are coarser than the wall clock.
+	indexed, err := idx.indexedEntries()
 	if err != nil {
 		return nil, err
 	}
-	for rows.Next() {
-		var path string
-		if err := rows.Scan(&path); err != nil {
-			continue
-		}
-		existingPaths[path] = true
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
 
-	// Track paths we've seen during this walk
-	seenPaths := make(map[string]bool)
+	// Scan the whole vault first: a move can only be told apart from a
+	// deletion once both of its ends have been seen
+	scan, err := idx.scanVault(indexed, reporter)
+	if err != nil {
+		return stats, err
+	}
+	stats.FilesScanned = len(scan.seen)
+	stats.DirsUnchanged = scan.unchanged
 
 	// Begin transaction - CRITICAL for performance
 	tx, err := idx.db.Begin()
@@ -241,28 +257,13 @@ func (idx *Index) SyncIncremental() (*domain.SyncStats, error) {
 	defer func() { _ = tx.Rollback() }()
 
 	// Prepare statements once
-	insertNodeStmt, err := tx.Prepare(`
-		INSERT INTO nodes (path, jd_id, jd_type, name, mtime)
-		VALUES (?, ?, ?, ?, ?)
-	`)
+	upsertNodeStmt, err := tx.Prepare(upsertNodeSQL)
 	if err != nil {
 		return nil, err
 	}
-	defer func() { _ = insertNodeStmt.Close() }()
-
-	updateNodeStmt, err := tx.Prepare(`
-		UPDATE nodes SET jd_id = ?, jd_type = ?, name = ?, mtime = ?
-		WHERE path = ?
-	`)
-	if err != nil {
-		return nil, err
-	}
-	defer func() { _ = updateNodeStmt.Close() }()
+	defer func() { _ = upsertNodeStmt.Close() }()
 
-	insertEdgeStmt, err := tx.Prepare(`
-		INSERT OR REPLACE INTO edges (source_path, target_jd_id, link_text)
-		VALUES (?, ?, ?)
-	`)
+	insertEdgeStmt, err := tx.Prepare(insertEdgeSQL)
 	if err != nil {
 		return nil, err
 	}
@@ -280,98 +281,123 @@ func (idx *Index) SyncIncremental() (*domain.SyncStats, error) {
 	}
 	defer func() { _ = deleteNodeStmt.Close() }()
 
-	// Walk the vault
-	err = filepath.Walk(idx.vaultPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil
+	insertContentStmt, err := tx.Prepare(`INSERT INTO content_fts (path, body) VALUES (?, ?)`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = insertContentStmt.Close() }()
+
+	deleteContentStmt, err := tx.Prepare(`DELETE FROM content_fts WHERE path = ?`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = deleteContentStmt.Close() }()
+
+	// Carry moved entries over to their new paths; contents read while
+	// matching files are kept for indexing below
+	contents := make(map[string][]byte)
+	moves, err := detectMoves(&indexTx{tx: tx, vaultPath: idx.vaultPath}, indexed, scan.walked, scan.seen, contents)
+	if err != nil {
+		return stats, err
+	}
+	stats.Moves = moves
+	stats.NodesMoved = len(moves)
+	for _, move := range moves {
+		if err := recordMove(tx, move, start); err != nil {
+			return stats, err
 		}
+		stats.Changed = append(stats.Changed, move.OldPath)
+	}
 
-		// Skip hidden directories
-		if info.IsDir() && strings.HasPrefix(info.Name(), ".") {
-			return filepath.SkipDir
+	// Update new and modified entries; notes and canvases are parsed in parallel below
+	var changed []mdFile
+	for _, w := range scan.walked {
+		mtime := w.info.ModTime().UnixNano()
+		old, existed := indexed[w.relPath]
+		if existed && mtime == old.mtime {
+			continue
+		}
+		if !w.info.IsDir() && domain.IsLinkSource(w.info.Name()) {
+			changed = append(changed, mdFile{fullPath: w.path, relPath: w.relPath, info: w.info})
+			continue
+		}
+		if _, err := upsertNodeStmt.Exec(nodeArgs(w.relPath, w.info.Name(), w.info, "")...); err == nil {
+			countUpsert(stats, w.relPath, existed)
+		}
+		if !w.info.IsDir() {
+			if err := replaceNoteNames(tx, w.relPath, nil); err != nil {
+				return stats, err
+			}
 		}
+	}
 
-		relPath, _ := filepath.Rel(idx.vaultPath, path)
-		seenPaths[relPath] = true
-		stats.FilesScanned++
+	reporter.report(domain.SyncProgress{Phase: domain.SyncIndexing, Total: len(changed)}, true)
+	parsed := 0
+	for r := range parseFiles(changed, contents) {
+		parsed++
+		reporter.report(domain.SyncProgress{Phase: domain.SyncIndexing, Done: parsed, Total: len(changed)}, parsed == len(changed))
 
-		// Check if file is new or modified
-		mtime := info.ModTime().Unix()
-		needsUpdate := mtime > lastSyncUnix || !existingPaths[relPath]
+		old, existed := indexed[r.relPath]
+		if _, err := upsertNodeStmt.Exec(nodeArgs(r.relPath, r.info.Name(), r.info, r.hash)...); err == nil {
+			countUpsert(stats, r.relPath, existed)
+		}
 
-		if !needsUpdate {
-			return nil
+		// A touched file whose content is unchanged keeps its links and content
+		if existed && r.hash != "" && r.hash == old.hash {
+			continue
 		}
 
-		if info.IsDir() {
-			jdID, jdType := extractJDInfo(info.Name())
-			if jdType != domain.IDTypeUnknown {
-				if existingPaths[relPath] {
-					if _, err := updateNodeStmt.Exec(
-						nullString(jdID),
-						nullString(jdType.String()),
-						extractDescription(info.Name()),
-						mtime,
-						relPath,
-					); err == nil {
-						stats.NodesUpdated++
-					}
-				} else {
-					if _, err := insertNodeStmt.Exec(
-						relPath,
-						nullString(jdID),
-						nullString(jdType.String()),
-						extractDescription(info.Name()),
-						mtime,
-					); err == nil {
-						stats.NodesAdded++
-					}
-				}
-			}
-		} else if strings.HasSuffix(strings.ToLower(info.Name()), ".md") {
-			if existingPaths[relPath] {
-				if _, err := updateNodeStmt.Exec(nil, nil, info.Name(), mtime, relPath); err == nil {
-					stats.NodesUpdated++
-				}
-				// Delete old edges
-				_, _ = deleteEdgesStmt.Exec(relPath)
-			} else {
-				if _, err := insertNodeStmt.Exec(relPath, nil, nil, info.Name(), mtime); err == nil {
-					stats.NodesAdded++
-				}
-			}
+		// Replace old edges, names, metadata and content
+		if existed {
+			_, _ = deleteEdgesStmt.Exec(r.relPath)
+			_, _ = deleteContentStmt.Exec(r.relPath)
+		}
+		if err := replaceNoteNames(tx, r.relPath, r.meta.aliases); err != nil {
+			return stats, err
+		}
+		if err := replaceMetadata(tx, r.relPath, r.meta); err != nil {
+			return stats, err
+		}
 
-			// Parse and index links
-			edges, err := parseLinksInFile(filepath.Join(idx.vaultPath, relPath), relPath)
-			if err == nil {
-				for _, edge := range edges {
-					_, err := insertEdgeStmt.Exec(edge.SourcePath, edge.TargetJDID, edge.LinkText)
-					if err == nil {
-						stats.EdgesAdded++
-					}
+		// Index links and content
+		if r.err == nil {
+			for _, edge := range r.edges {
+				_, err := insertEdgeStmt.Exec(edgeArgs(&edge)...)
+				if err == nil {
+					stats.EdgesAdded++
 				}
 			}
+			if r.hasBody {
+				_, _ = insertContentStmt.Exec(r.relPath, r.body)
+			}
 		}
-
-		return nil
-	})
-
-	if err != nil {
-		return stats, err
 	}
 
 	// Delete nodes that no longer exist
-	for path := range existingPaths {
-		if !seenPaths[path] {
+	for path := range indexed {
+		if !scan.seen[path] {
 			_, _ = deleteNodeStmt.Exec(path)
 			_, _ = deleteEdgesStmt.Exec(path)
+			if err := dropNoteNames(tx, path, false); err != nil {
+				return stats, err
+			}
+			if err := deleteMetadata(tx, path, false); err != nil {
+				return stats, err
+			}
+			_, _ = deleteContentStmt.Exec(path)
 			stats.NodesDeleted++
+			stats.Changed = append(stats.Changed, path)
 		}
 	}
 
+	// Resolve links by name that new or changed files hold, or whose note changed
+	if err := resolveNoteLinks(tx); err != nil {
+		return stats, err
+	}
+
 	// Update last sync time
 	if _, err := tx.Exec(`INSERT OR REPLACE INTO meta (key, value) VALUES ('last_sync_time', ?)`,
-		time.Now().Unix()); err != nil {
+		time.Now().UnixNano()); err != nil {
 		return stats, err
 	}
 
@@ -384,44 +410,442 @@ func (idx *Index) SyncIncremental() (*domain.SyncStats, error) {
 	return stats, nil
 }
 
-// parseLinksInFile extracts all JD links from a markdown file
-func parseLinksInFile(fullPath, relPath string) ([]domain.Edge, error) {
-	content, err := os.ReadFile(fullPath)
+// countUpsert counts an upserted node as added or updated, and lists it as changed
+func countUpsert(stats *domain.SyncStats, path string, existed bool) {
+	stats.Changed = append(stats.Changed, path)
+	if existed {
+		stats.NodesUpdated++
+	} else {
+		stats.NodesAdded++
+	}
+}
+
+// vaultScan is what an incremental sync found on disk
+type vaultScan struct {
+	walked    []walkedEntry   // Entries stat'ed on disk, the vault root first
+	seen      map[string]bool // Every entry known to exist, stat'ed or not
+	unchanged int             // Folders listed from the index
+}
+
+// dirListing is a folder listed by a scanVault worker
+type dirListing struct {
+	entries   []walkedEntry // Children stat'ed on disk
+	trusted   []string      // Indexed children known to exist without a stat
+	unchanged bool
+}
+
+// scanVault walks the vault with a pool of workers, one folder per job.
+// Folders whose mtime matches the index are listed from the index, since a
+// folder's mtime changes whenever an entry is added, removed or renamed in it.
+// Their subfolders are still visited, as changes deeper down leave it alone.
+func (idx *Index) scanVault(indexed map[string]indexedEntry, reporter *progressReporter) (*vaultScan, error) {
+	info, err := lstat(idx.vaultPath)
 	if err != nil {
 		return nil, err
 	}
+	root := walkedEntry{path: idx.vaultPath, relPath: ".", info: info}
+	scan := &vaultScan{walked: []walkedEntry{root}, seen: map[string]bool{".": true}}
 
-	// Quick check for [[ before expensive regex
-	if !bytes.Contains(content, []byte("[[")) {
-		return nil, nil
+	children := make(map[string][]string)
+	for path := range indexed {
+		if path != "." {
+			parent := parentPath(path)
+			children[parent] = append(children[parent], path)
+		}
 	}
 
-	var edges []domain.Edge
-	matches := linkPattern.FindAllStringSubmatch(string(content), -1)
+	jobs := make(chan walkedEntry)
+	results := make(chan dirListing)
+	var wg sync.WaitGroup
+	for range syncWorkers() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for dir := range jobs {
+				results <- scanDir(dir, indexed, children)
+			}
+		}()
+	}
 
-	for _, match := range matches {
-		linkContent := match[1]
-		jdMatch := jdIDPattern.FindString(linkContent)
-		if jdMatch != "" {
-			edges = append(edges, domain.Edge{
-				SourcePath: relPath,
-				TargetJDID: jdMatch,
-				LinkText:   match[0],
-			})
+	// Hand out folders as they are found until every listing is back
+	queue := []walkedEntry{root}
+	pending := 0
+	for len(queue) > 0 || pending > 0 {
+		var send chan<- walkedEntry
+		var next walkedEntry
+		if len(queue) > 0 {
+			send, next = jobs, queue[len(queue)-1]
+		}
+		select {
+		case send <- next:
+			queue = queue[:len(queue)-1]
+			pending++
+		case listing := <-results:
+			pending--
+			if listing.unchanged {
+				scan.unchanged++
+			}
+			for _, e := range listing.entries {
+				scan.walked = append(scan.walked, e)
+				scan.seen[e.relPath] = true
+				if e.info.IsDir() {
+					queue = append(queue, e)
+				}
+			}
+			for _, path := range listing.trusted {
+				scan.seen[path] = true
+			}
+			reporter.report(domain.SyncProgress{Phase: domain.SyncScanning, Done: len(scan.seen), Total: max(len(indexed), len(scan.seen))}, false)
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	return scan, nil
+}
+
+// scanDir lists a folder, skipping hidden subfolders. A folder whose mtime
+// matches the index is listed from the index, without reading it. Either way
+// indexed files other than notes and canvases are trusted to be there
+// unchanged, and only subfolders, notes, canvases and new entries are stat'ed:
+// subfolders to tell whether they changed in turn, and notes and canvases
+// since editing one in place leaves the folder's mtime alone.
+func scanDir(dir walkedEntry, indexed map[string]indexedEntry, children map[string][]string) dirListing {
+	var listing dirListing
+	var names []string
+	isDir := make(map[string]bool)
+	if old, ok := indexed[dir.relPath]; ok && old.isDir && old.mtime == dir.info.ModTime().UnixNano() {
+		listing.unchanged = true
+		for _, relPath := range children[dir.relPath] {
+			names = append(names, filepath.Base(relPath))
+			isDir[filepath.Base(relPath)] = indexed[relPath].isDir
+		}
+	} else {
+		entries, err := os.ReadDir(dir.path)
+		if err != nil {
+			return listing
+		}
+		for _, e := range entries {
+			if e.IsDir() && strings.HasPrefix(e.Name(), ".") {
+				continue
+			}
+			names = append(names, e.Name())
+			isDir[e.Name()] = e.IsDir()
+		}
+	}
+
+	for _, name := range names {
+		relPath := filepath.Join(dir.relPath, name)
+		if old, ok := indexed[relPath]; ok && !old.isDir && !isDir[name] && !domain.IsLinkSource(name) {
+			listing.trusted = append(listing.trusted, relPath)
+			continue
+		}
+		path := filepath.Join(dir.path, name)
+		info, err := lstat(path)
+		if err != nil {
+			continue
+		}
+		listing.entries = append(listing.entries, walkedEntry{path: path, relPath: relPath, info: info})
+	}
+	return listing
+}
+
+// lstat stats an entry found by an incremental sync. Tests count its calls.
+var lstat = os.Lstat
+
+// parsedFile is a note or canvas read and parsed by a parseFiles worker
+type parsedFile struct {
+	mdFile
+	hash    string
+	edges   []domain.Edge
+	meta    noteMetadata
+	body    string
+	hasBody bool
+	err     error
+}
+
+// parseFiles reads and parses notes and canvases with a pool of workers, taking
+// contents already read from the cache. The cache must not change meanwhile.
+func parseFiles(files []mdFile, contents map[string][]byte) <-chan parsedFile {
+	fileCh := make(chan mdFile, len(files))
+	for _, f := range files {
+		fileCh <- f
+	}
+	close(fileCh)
+
+	resultCh := make(chan parsedFile, len(files))
+	var wg sync.WaitGroup
+	for range syncWorkers() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for f := range fileCh {
+				result := parsedFile{mdFile: f}
+				content, ok := contents[f.relPath]
+				if !ok {
+					content, result.err = os.ReadFile(f.fullPath)
+				}
+				if result.err == nil {
+					result.hash = contentHash(content)
+					result.edges = domain.ParseLinks(content, f.relPath)
+					result.meta = readMetadata(f.relPath, content)
+					result.body, result.hasBody = indexableBody(f.relPath, content)
+				}
+				resultCh <- result
+			}
+		}()
+	}
+
+	// Close results channel when workers done
+	go func() {
+		wg.Wait()
+		close(resultCh)
+	}()
+	return resultCh
+}
+
+// syncWorkers returns how many workers a sync runs in parallel. Syncing is
+// I/O bound, so there are more workers than CPUs.
+func syncWorkers() int {
+	return min(runtime.NumCPU()*2, 32)
+}
+
+// progressInterval is the least time between two throttled progress updates
+const progressInterval = 50 * time.Millisecond
+
+// progressReporter passes sync progress on, throttled. It is used from the
+// syncing goroutine only.
+type progressReporter struct {
+	fn   domain.SyncProgressFunc
+	last time.Time
+}
+
+// report passes progress on unless an update was passed on very recently.
+// Forced updates, such as the start and end of a phase, always go through.
+func (r *progressReporter) report(progress domain.SyncProgress, force bool) {
+	if r.fn == nil || (!force && time.Since(r.last) < progressInterval) {
+		return
+	}
+	r.last = time.Now()
+	r.fn(progress)
+}
+
+// indexedEntries returns what the index holds about every entry, by path
+func (idx *Index) indexedEntries() (map[string]indexedEntry, error) {
+	rows, err := idx.db.Query(`SELECT path, mtime, COALESCE(hash, ''), COALESCE(file_id, 0), is_dir FROM nodes`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	indexed := make(map[string]indexedEntry)
+	for rows.Next() {
+		var path string
+		var e indexedEntry
+		if err := rows.Scan(&path, &e.mtime, &e.hash, &e.fileID, &e.isDir); err != nil {
+			continue
+		}
+		indexed[path] = e
+	}
+	return indexed, rows.Err()
+}
+
+// detectMoves finds indexed entries that are gone from their path but present
+// at another, renames them in the index and carries their indexed state along.
+// Folders are matched by identity, shallowest first so a moved folder takes its
+// contents with it; notes and canvases are matched by content when the match is unique.
+func detectMoves(tx *indexTx, indexed map[string]indexedEntry, walked []walkedEntry, seen map[string]bool, contents map[string][]byte) ([]domain.NodeMove, error) {
+	var moves []domain.NodeMove
+
+	addedDirs := make(map[int64]string)
+	for _, w := range walked {
+		if _, ok := indexed[w.relPath]; !ok && w.info.IsDir() {
+			if id := fileID(w.info); id != 0 {
+				addedDirs[id] = w.relPath
+			}
+		}
+	}
+	var goneDirs []string
+	for path, e := range indexed {
+		if !seen[path] && e.isDir && e.fileID != 0 {
+			goneDirs = append(goneDirs, path)
+		}
+	}
+	slices.Sort(goneDirs)
+
+	for i, oldPath := range goneDirs {
+		newPath, ok := addedDirs[indexed[oldPath].fileID]
+		if !ok || !sameFolder(oldPath, newPath) {
+			continue
+		}
+		if err := tx.RenameNode(oldPath, newPath); err != nil {
+			return nil, err
+		}
+		carryIndexed(indexed, oldPath, newPath)
+		delete(addedDirs, indexed[newPath].fileID)
+		moves = append(moves, domain.NodeMove{OldPath: oldPath, NewPath: newPath, IsDir: true})
+
+		// Folders still to match beneath this one have moved along with it
+		for j := i + 1; j < len(goneDirs); j++ {
+			if rest, ok := strings.CutPrefix(goneDirs[j], oldPath+string(filepath.Separator)); ok {
+				goneDirs[j] = filepath.Join(newPath, rest)
+			}
+		}
+	}
+
+	goneFiles := make(map[string][]string)
+	for path, e := range indexed {
+		if !seen[path] && !e.isDir && e.hash != "" {
+			goneFiles[e.hash] = append(goneFiles[e.hash], path)
+		}
+	}
+	if len(goneFiles) == 0 {
+		return moves, nil
+	}
+	addedFiles := make(map[string][]string)
+	for _, w := range walked {
+		if _, ok := indexed[w.relPath]; ok || w.info.IsDir() || !domain.IsLinkSource(w.info.Name()) {
+			continue
+		}
+		content, err := readContent(w, contents)
+		if err != nil || len(content) == 0 {
+			continue
 		}
+		hash := contentHash(content)
+		addedFiles[hash] = append(addedFiles[hash], w.relPath)
 	}
 
-	return edges, nil
+	var fileMoves []domain.NodeMove
+	for hash, gone := range goneFiles {
+		if added := addedFiles[hash]; len(gone) == 1 && len(added) == 1 {
+			fileMoves = append(fileMoves, domain.NodeMove{OldPath: gone[0], NewPath: added[0]})
+		}
+	}
+	slices.SortFunc(fileMoves, func(a, b domain.NodeMove) int { return strings.Compare(a.NewPath, b.NewPath) })
+	for _, move := range fileMoves {
+		if err := tx.RenameNode(move.OldPath, move.NewPath); err != nil {
+			return nil, err
+		}
+		carryIndexed(indexed, move.OldPath, move.NewPath)
+	}
+	return append(moves, fileMoves...), nil
+}
+
+// sameFolder guards against inode reuse: a folder found with the identity of
+// a removed one only counts as moved if it kept its name, JD ID or description
+func sameFolder(oldPath, newPath string) bool {
+	oldName, newName := filepath.Base(oldPath), filepath.Base(newPath)
+	if oldName == newName {
+		return true
+	}
+	oldID := domain.ExtractID(oldName)
+	if domain.ParseIDType(oldID) == domain.IDTypeUnknown {
+		return false
+	}
+	return oldID == domain.ExtractID(newName) || domain.ExtractDescription(oldName) == domain.ExtractDescription(newName)
+}
+
+// carryIndexed moves the indexed state of an entry and everything beneath it
+// to a new path. The entry itself is marked stale, since its name changed.
+func carryIndexed(indexed map[string]indexedEntry, oldPath, newPath string) {
+	prefix := oldPath + string(filepath.Separator)
+	var moved []string
+	for path := range indexed {
+		if path == oldPath || strings.HasPrefix(path, prefix) {
+			moved = append(moved, path)
+		}
+	}
+	for _, path := range moved {
+		e := indexed[path]
+		delete(indexed, path)
+		indexed[newPath+path[len(oldPath):]] = e
+	}
+	root := indexed[newPath]
+	root.mtime = -1
+	indexed[newPath] = root
+}
+
+// readContent reads a walked file once, caching it in contents
+func readContent(w walkedEntry, contents map[string][]byte) ([]byte, error) {
+	if content, ok := contents[w.relPath]; ok {
+		return content, nil
+	}
+	content, err := os.ReadFile(w.path)
+	if err != nil {
+		return nil, err
+	}
+	contents[w.relPath] = content
+	return content, nil
+}
+
+// insertNodeSQL inserts a node from nodeArgs
+const insertNodeSQL = `
+	INSERT INTO nodes (path, parent, jd_id, jd_type, name, is_dir, mtime, hash, file_id)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+`
+
+// upsertNodeSQL inserts or updates a node from nodeArgs
+const upsertNodeSQL = insertNodeSQL + `
+	ON CONFLICT (path) DO UPDATE SET
+		parent = excluded.parent, jd_id = excluded.jd_id, jd_type = excluded.jd_type,
+		name = excluded.name, is_dir = excluded.is_dir, mtime = excluded.mtime,
+		hash = excluded.hash, file_id = excluded.file_id
+`
+
+// insertEdgeSQL inserts an edge from edgeArgs
+const insertEdgeSQL = `
+	INSERT OR REPLACE INTO edges (source_path, target_jd_id, target_path, target_name, name_key, name_dir, link_text, kind, line, col, context, occurrence, occurrences)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+`
+
+// edgeArgs returns the statement arguments for an edge, in column order. A
+// note link is stored with the name and folder it is resolved by.
+func edgeArgs(edge *domain.Edge) []any {
+	var targetName, key, dir any
+	if edge.TargetName != "" {
+		targetName = edge.TargetName
+		key, dir = nameKey(edge.TargetName)
+	}
+	return []any{edge.SourcePath, edge.TargetJDID, nullString(edge.TargetPath), targetName, key, dir,
+		edge.LinkText, edge.Kind, edge.Line, edge.Column, edge.Context, edge.Occurrence, edge.Occurrences}
+}
+
+// nodeArgs returns the statement arguments for an entry, in column order.
+// Folders with a JD ID are stored by ID and description, and every folder with
+// its identity on disk; hash is the content hash of a note or canvas.
+func nodeArgs(relPath, name string, info os.FileInfo, hash string) []any {
+	isDir := info.IsDir()
+	var id int64
+	if isDir {
+		id = fileID(info)
+	}
+	jdID, jdType := "", domain.IDTypeUnknown
+	if isDir && relPath != "." {
+		jdID, jdType = extractJDInfo(name)
+	}
+	if jdType != domain.IDTypeUnknown {
+		name = extractDescription(name)
+	}
+	return []any{relPath, parentPath(relPath), nullString(jdID), nullString(jdType.String()), name, isDir, info.ModTime().UnixNano(), nullString(hash), nullID(id)}
+}
+
+// parentPath returns the relative path of the folder containing relPath;
+// the vault root has no parent
+func parentPath(relPath string) string {
+	if relPath == "." {
+		return ""
+	}
+	return filepath.Dir(relPath)
 }
 
 // extractJDInfo extracts the JD ID and type from a folder name
 func extractJDInfo(name string) (string, domain.IDType) {
 	// Try to parse as JD ID
 	jdID := domain.ExtractID(name)
-	if jdID == "" {
+	jdType := domain.ParseIDType(jdID)
+	if jdType == domain.IDTypeUnknown {
 		return "", domain.IDTypeUnknown
 	}
-	return jdID, domain.ParseIDType(jdID)
+	return jdID, jdType
 }
 
 // extractDescription extracts the description from a JD folder name
@@ -434,9 +858,23 @@ func extractDescription(name string) string {
 	return name
 }
 
+// contentHash returns the hash stored for a file's content
+func contentHash(content []byte) string {
+	h := sha256.Sum256(content)
+	return hex.EncodeToString(h[:16])
+}
+
+// nullID returns nil for a missing file identity (for nullable columns)
+func nullID(id int64) any {
+	if id == 0 {
+		return nil
+	}
+	return id
+}
+
 // nullString returns nil for empty strings (for nullable columns)
 func nullString(s string) interface{} {
-	if s == "" || s == "unknown" {
+	if s == "" || strings.EqualFold(s, "unknown") {
 		return nil
 	}
 	return s
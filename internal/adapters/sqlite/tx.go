@@ -2,6 +2,9 @@ package sqlite
 
 import (
 	"database/sql"
+	"os"
+	"path/filepath"
+	"time"
 
 	"libraio/internal/domain"
 	"libraio/internal/ports"
@@ -9,33 +12,206 @@ import (
 
 // indexTx implements ports.IndexTx
 type indexTx struct {
-	tx *sql.Tx
+	tx        *sql.Tx
+	vaultPath string
 }
 
 // Ensure indexTx implements IndexTx
 var _ ports.IndexTx = (*indexTx)(nil)
 
-// UpsertNode inserts or updates a node
+// UpsertNode inserts or updates a node. Folders are stamped with their
+// identity on disk so a later sync can follow them when renamed elsewhere;
+// files are stored under their name for note links, without aliases until
+// UpdateContent reads them.
 func (t *indexTx) UpsertNode(node *domain.IndexNode) error {
-	_, err := t.tx.Exec(`
-		INSERT OR REPLACE INTO nodes (path, jd_id, jd_type, name, mtime)
-		VALUES (?, ?, ?, ?, ?)
-	`, node.Path, node.JDID, node.JDType.String(), node.Name, node.Mtime)
-	return err
+	var id int64
+	if node.IsDir {
+		if info, err := os.Lstat(filepath.Join(t.vaultPath, node.Path)); err == nil {
+			id = fileID(info)
+		}
+	}
+	if _, err := t.tx.Exec(upsertNodeSQL,
+		node.Path, parentPath(node.Path), nullString(node.JDID), nullString(node.JDType.String()),
+		node.Name, node.IsDir, node.Mtime, nullString(node.Hash), nullID(id)); err != nil {
+		return err
+	}
+	if node.IsDir {
+		return nil
+	}
+	return replaceNoteNames(t.tx, node.Path, nil)
 }
 
-// DeleteNode removes a node by path
+// DeleteNode removes a node and everything beneath it, along with their
+// outgoing edges, names, metadata and indexed content
 func (t *indexTx) DeleteNode(path string) error {
-	_, err := t.tx.Exec(`DELETE FROM nodes WHERE path = ?`, path)
-	return err
+	if err := dropNoteNames(t.tx, path, true); err != nil {
+		return err
+	}
+	if err := deleteMetadata(t.tx, path, true); err != nil {
+		return err
+	}
+	lo, hi := subtreeRange(path)
+	for _, q := range []string{
+		`DELETE FROM nodes WHERE path = ? OR (path >= ? AND path < ?)`,
+		`DELETE FROM edges WHERE source_path = ? OR (source_path >= ? AND source_path < ?)`,
+		`DELETE FROM content_fts WHERE path = ? OR (path >= ? AND path < ?)`,
+	} {
+		if _, err := t.tx.Exec(q, path, lo, hi); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-// RenameNode updates a node's path
+// RenameNode moves a node and everything beneath it to a new path, carrying
+// their outgoing edges, names, metadata and indexed content along. Markdown
+// links are resolved relative to the file holding them, so those are parsed
+// again from disk. The rename is kept in the rename history for fixing broken
+// links later.
 func (t *indexTx) RenameNode(oldPath, newPath string) error {
-	_, err := t.tx.Exec(`UPDATE nodes SET path = ? WHERE path = ?`, newPath, oldPath)
+	if _, err := t.tx.Exec(`INSERT INTO renames (old_path, new_path, renamed_at) VALUES (?, ?, ?)`,
+		oldPath, newPath, time.Now().UnixNano()); err != nil {
+		return err
+	}
+	aliases, err := t.subtreeAliases(oldPath)
+	if err != nil {
+		return err
+	}
+	if err := dropNoteNames(t.tx, oldPath, true); err != nil {
+		return err
+	}
+
+	lo, hi := subtreeRange(oldPath)
+	rows, err := t.tx.Query(`SELECT path, is_dir FROM nodes WHERE path = ? OR (path >= ? AND path < ?)`, oldPath, lo, hi)
+	if err != nil {
+		return err
+	}
+	var paths []string
+	files := make(map[string]bool)
+	for rows.Next() {
+		var p string
+		var isDir bool
+		if err := rows.Scan(&p, &isDir); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		paths = append(paths, p)
+		files[p] = !isDir
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+
+	for _, p := range paths {
+		renamed := newPath + p[len(oldPath):]
+		if _, err := t.tx.Exec(`UPDATE OR REPLACE nodes SET path = ?, parent = ? WHERE path = ?`, renamed, parentPath(renamed), p); err != nil {
+			return err
+		}
+		if _, err := t.tx.Exec(`UPDATE OR REPLACE edges SET source_path = ? WHERE source_path = ?`, renamed, p); err != nil {
+			return err
+		}
+		if _, err := t.tx.Exec(`UPDATE content_fts SET path = ? WHERE path = ?`, renamed, p); err != nil {
+			return err
+		}
+		if files[p] {
+			if err := replaceNoteNames(t.tx, renamed, aliases[p]); err != nil {
+				return err
+			}
+			if err := moveMetadata(t.tx, p, renamed); err != nil {
+				return err
+			}
+		}
+	}
+	return t.resolvePathLinks(newPath)
+}
+
+// subtreeAliases returns the aliases of the notes at or beneath path, by note
+func (t *indexTx) subtreeAliases(path string) (map[string][]string, error) {
+	lo, hi := subtreeRange(path)
+	rows, err := t.tx.Query(`SELECT path, name FROM note_names WHERE alias AND (path = ? OR (path >= ? AND path < ?)) ORDER BY rowid`, path, lo, hi)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	aliases := make(map[string][]string)
+	for rows.Next() {
+		var p, name string
+		if err := rows.Scan(&p, &name); err != nil {
+			return nil, err
+		}
+		aliases[p] = append(aliases[p], name)
+	}
+	return aliases, rows.Err()
+}
+
+// resolvePathLinks parses again the files at or beneath path that hold
+// Markdown links, replacing their edges. Files that cannot be read keep theirs.
+func (t *indexTx) resolvePathLinks(path string) error {
+	lo, hi := subtreeRange(path)
+	rows, err := t.tx.Query(`SELECT DISTINCT source_path FROM edges WHERE target_path IS NOT NULL AND (source_path = ? OR (source_path >= ? AND source_path < ?))`, path, lo, hi)
+	if err != nil {
+		return err
+	}
+	var sources []string
+	for rows.Next() {
+		var p string
+		if err := rows.Scan(&p); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		sources = append(sources, p)
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+
+	for _, source := range sources {
+		content, err := os.ReadFile(filepath.Join(t.vaultPath, source))
+		if err != nil {
+			continue
+		}
+		if err := t.DeleteEdgesFromFile(source); err != nil {
+			return err
+		}
+		for _, edge := range domain.ParseLinks(content, source) {
+			if err := t.InsertEdge(&edge); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// UpdateContent replaces the indexed body, hash, aliases, properties and tags
+// of a file; binary content is hashed but not indexed
+func (t *indexTx) UpdateContent(path string, content []byte) error {
+	if _, err := t.tx.Exec(`UPDATE nodes SET hash = ? WHERE path = ?`, contentHash(content), path); err != nil {
+		return err
+	}
+	meta := readMetadata(path, content)
+	if err := replaceNoteNames(t.tx, path, meta.aliases); err != nil {
+		return err
+	}
+	if err := replaceMetadata(t.tx, path, meta); err != nil {
+		return err
+	}
+	if _, err := t.tx.Exec(`DELETE FROM content_fts WHERE path = ?`, path); err != nil {
+		return err
+	}
+	body, ok := indexableBody(path, content)
+	if !ok {
+		return nil
+	}
+	_, err := t.tx.Exec(`INSERT INTO content_fts (path, body) VALUES (?, ?)`, path, body)
 	return err
 }
 
+// subtreeRange returns bounds selecting every path strictly beneath path:
+// those starting with path plus a separator
+func subtreeRange(path string) (string, string) {
+	return path + string(filepath.Separator), path + string(filepath.Separator+1)
+}
+
 // DeleteEdgesFromFile removes all edges from a source file
 func (t *indexTx) DeleteEdgesFromFile(sourcePath string) error {
 	_, err := t.tx.Exec(`DELETE FROM edges WHERE source_path = ?`, sourcePath)
@@ -44,10 +220,7 @@ func (t *indexTx) DeleteEdgesFromFile(sourcePath string) error {
 
 // InsertEdge adds a new edge
 func (t *indexTx) InsertEdge(edge *domain.Edge) error {
-	_, err := t.tx.Exec(`
-		INSERT OR REPLACE INTO edges (source_path, target_jd_id, link_text)
-		VALUES (?, ?, ?)
-	`, edge.SourcePath, edge.TargetJDID, edge.LinkText)
+	_, err := t.tx.Exec(insertEdgeSQL, edgeArgs(edge)...)
 	return err
 }
 
@@ -61,8 +234,12 @@ func (t *indexTx) UpdateEdgeTarget(oldTargetJDID, newTargetJDID, newLinkText str
 	return err
 }
 
-// Commit commits the transaction
+// Commit resolves note links against the names as changed, then commits the
+// transaction
 func (t *indexTx) Commit() error {
+	if err := resolveNoteLinks(t.tx); err != nil {
+		return err
+	}
 	return t.tx.Commit()
 }
 
@@ -1,11 +1,13 @@
 package tui
 
 import (
+	"context"
 	"fmt"
 
 	tea "github.com/charmbracelet/bubbletea"
 
 	"libraio/internal/adapters/tui/views"
+	"libraio/internal/domain"
 	"libraio/internal/ports"
 )
 
@@ -31,7 +33,11 @@ const (
 	ViewSmartCatalog
 	ViewUnarchive
 	ViewSmartSearch
+	ViewTrash
+	ViewBrokenLinks
+	ViewStale
 	ViewHelp
+	ViewSync
 )
 
 // App is the main TUI application model
@@ -50,8 +56,17 @@ type App struct {
 	delete       *views.DeleteModel
 	smartCatalog *views.SmartCatalogModel
 	smartSearch  *views.SmartSearchModel
+	trash        *views.TrashModel
+	brokenLinks  *views.BrokenLinksModel
+	stale        *views.StaleModel
 	help         *views.HelpModel
+	sync         *views.SyncModel
 
+	syncer             ports.IndexSyncer
+	watcher            ports.VaultWatcher
+	watchEvents        chan domain.WatchEvent
+	stopWatching       context.CancelFunc // Stops the watcher, nil until it starts
+	watchDone          chan struct{}      // Closed once the watcher has stopped
 	smartSearchEnabled bool
 
 	width  int
@@ -79,14 +94,75 @@ func NewApp(repo ports.VaultRepository, ed ports.EditorOpener, obs ports.Obsidia
 		unarchive:          views.NewUnarchiveModel(repo),
 		delete:             views.NewDeleteModel(repo),
 		smartCatalog:       views.NewSmartCatalogModel(repo, assistant),
+		trash:              views.NewTrashModel(repo),
+		brokenLinks:        views.NewBrokenLinksModel(repo),
+		stale:              views.NewStaleModel(repo),
 		help:               views.NewHelpModel(),
+		sync:               views.NewSyncModel(),
 		smartSearchEnabled: smartSearchEnabled,
 	}
 }
 
+// SetStartupSync makes the app bring the index up to date before showing the
+// vault, with a progress bar for large vaults
+func (a *App) SetStartupSync(syncer ports.IndexSyncer) {
+	a.syncer = syncer
+	a.state = ViewSync
+}
+
+// SetWatcher makes the app follow changes made to the vault outside libraio
+func (a *App) SetWatcher(watcher ports.VaultWatcher) {
+	a.watcher = watcher
+}
+
 // Init initializes the application
 func (a *App) Init() tea.Cmd {
-	return a.browser.Init()
+	var watch tea.Cmd
+	if a.watcher != nil {
+		watch = a.startWatching()
+	}
+	if a.syncer != nil {
+		return tea.Batch(a.sync.Start(a.syncer), watch)
+	}
+	return tea.Batch(a.browser.Init(), watch)
+}
+
+// startWatching runs the watcher in the background until Close. Its events
+// arrive one at a time as views.VaultChangedMsg.
+func (a *App) startWatching() tea.Cmd {
+	ctx, cancel := context.WithCancel(context.Background())
+	events := make(chan domain.WatchEvent)
+	done := make(chan struct{})
+	a.watchEvents, a.stopWatching, a.watchDone = events, cancel, done
+	go func() {
+		defer close(done)
+		_ = a.watcher.Watch(ctx, func(e domain.WatchEvent) {
+			select {
+			case events <- e:
+			case <-ctx.Done():
+			}
+		})
+	}()
+	return a.nextWatchEvent()
+}
+
+// Close waits for the startup sync and stops the watcher, letting a check in
+// progress finish, so the index can be closed after them. Call it once the
+// program has quit.
+func (a *App) Close() {
+	a.sync.Stop()
+	if a.stopWatching != nil {
+		a.stopWatching()
+		<-a.watchDone
+	}
+}
+
+// nextWatchEvent returns a command that delivers the watcher's next event
+func (a *App) nextWatchEvent() tea.Cmd {
+	events := a.watchEvents
+	return func() tea.Msg {
+		return views.VaultChangedMsg{Event: <-events}
+	}
 }
 
 // Update handles messages for the application
@@ -105,9 +181,32 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if a.smartSearch != nil {
 			a.smartSearch.SetSize(msg.Width, msg.Height)
 		}
+		a.trash.SetSize(msg.Width, msg.Height)
+		a.brokenLinks.SetSize(msg.Width, msg.Height)
+		a.stale.SetSize(msg.Width, msg.Height)
 		a.help.SetSize(msg.Width, msg.Height)
+		a.sync.SetSize(msg.Width, msg.Height)
 		return a, nil
 
+	// Startup sync messages
+	case views.SyncDoneMsg:
+		a.state = ViewBrowser
+		if msg.Err != nil {
+			a.browser.SetMessage(views.ErrorStatus(fmt.Errorf("index sync failed: %w", msg.Err)), true)
+		} else if msg.Stats.NodesMoved > 0 {
+			a.browser.SetMessage(fmt.Sprintf("Index followed %s made outside libraio", domain.Pluralize(msg.Stats.NodesMoved, "move")), false)
+		}
+		return a, a.browser.Init()
+
+	case views.VaultChangedMsg:
+		var cmd tea.Cmd
+		if msg.Event.Err != nil {
+			a.browser.SetMessage(views.ErrorStatus(fmt.Errorf("failed to check vault for changes: %w", msg.Event.Err)), true)
+		} else {
+			cmd = a.browser.RefreshChanged(msg.Event.Stats)
+		}
+		return a, tea.Batch(cmd, a.nextWatchEvent())
+
 	// View switching messages
 	case views.SwitchToCreateMsg:
 		a.state = ViewCreate
@@ -156,6 +255,18 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Search is now inline in browser, no need to switch
 		return a, nil
 
+	case views.SwitchToTrashMsg:
+		a.state = ViewTrash
+		return a, a.trash.Init()
+
+	case views.SwitchToBrokenLinksMsg:
+		a.state = ViewBrokenLinks
+		return a, a.brokenLinks.Init()
+
+	case views.SwitchToStaleMsg:
+		a.state = ViewStale
+		return a, a.stale.Init()
+
 	case views.SwitchToHelpMsg:
 		a.state = ViewHelp
 		return a, nil
@@ -167,48 +278,56 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	// Create view messages
 	case views.CreateSuccessMsg:
 		a.state = ViewBrowser
+		a.browser.SetMessage(views.OperationStatus(msg.Message, msg.Report))
 		return a, a.browser.Reload()
 
 	case views.CreateErrMsg:
-		a.create.SetMessage(msg.Err.Error(), true)
+		a.create.SetMessage(views.ErrorStatus(msg.Err), true)
 		return a, nil
 
 	// Move view messages
 	case views.MoveSuccessMsg:
 		a.state = ViewBrowser
+		a.browser.SetMessage(views.OperationStatus(msg.Message, msg.Report))
 		return a, a.browser.Reload()
 
 	case views.MoveErrMsg:
-		a.move.SetMessage(msg.Err.Error(), true)
+		a.move.SetMessage(views.ErrorStatus(msg.Err), true)
 		return a, nil
 
 	// Archive view messages
 	case views.ArchiveSuccessMsg:
 		a.state = ViewBrowser
+		a.browser.SetMessage(views.OperationStatus(msg.Message, msg.Report))
 		return a, a.browser.Reload()
 
 	case views.ArchiveErrMsg:
 		// Return to browser on error
 		a.state = ViewBrowser
+		a.browser.SetMessage(views.ErrorStatus(msg.Err), true)
 		return a, nil
 
 	// Unarchive view messages
 	case views.UnarchiveSuccessMsg:
 		a.state = ViewBrowser
+		a.browser.SetMessage(views.OperationStatus(msg.Message, msg.Report))
 		return a, a.browser.Reload()
 
 	case views.UnarchiveErrMsg:
 		a.state = ViewBrowser
+		a.browser.SetMessage(views.ErrorStatus(msg.Err), true)
 		return a, nil
 
 	// Delete view messages
 	case views.DeleteSuccessMsg:
 		a.state = ViewBrowser
+		a.browser.SetMessage(views.OperationStatus(msg.Message, msg.Report))
 		return a, a.browser.Reload()
 
 	case views.DeleteErrMsg:
 		// Return to browser on error (delete view has no SetMessage)
 		a.state = ViewBrowser
+		a.browser.SetMessage(views.ErrorStatus(msg.Err), true)
 		return a, nil
 
 	// Smart catalog view messages
@@ -237,6 +356,9 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case views.OpenEditorMsg:
 		// Return to browser, then open editor
 		a.state = ViewBrowser
+		if msg.Message != "" {
+			a.browser.SetMessage(views.OperationStatus(msg.Message, msg.Report))
+		}
 		return a, a.openEditor(msg.Path)
 
 	case views.OpenObsidianMsg:
@@ -270,8 +392,16 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if a.smartSearch != nil {
 			_, cmd = a.smartSearch.Update(msg)
 		}
+	case ViewTrash:
+		_, cmd = a.trash.Update(msg)
+	case ViewBrokenLinks:
+		_, cmd = a.brokenLinks.Update(msg)
+	case ViewStale:
+		_, cmd = a.stale.Update(msg)
 	case ViewHelp:
 		_, cmd = a.help.Update(msg)
+	case ViewSync:
+		_, cmd = a.sync.Update(msg)
 	}
 
 	return a, cmd
@@ -330,8 +460,16 @@ func (a *App) View() string {
 			return a.smartSearch.View()
 		}
 		return a.browser.View()
+	case ViewTrash:
+		return a.trash.View()
+	case ViewBrokenLinks:
+		return a.brokenLinks.View()
+	case ViewStale:
+		return a.stale.View()
 	case ViewHelp:
 		return a.help.View()
+	case ViewSync:
+		return a.sync.View()
 	default:
 		return a.browser.View()
 	}
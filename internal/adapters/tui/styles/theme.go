@@ -52,6 +52,10 @@ var (
 			Foreground(Muted).
 			Italic(true)
 
+	NodeSavedSearch = lipgloss.NewStyle().
+			Foreground(Primary).
+			Italic(true)
+
 	NodeVisualSelected = lipgloss.NewStyle().
 				Background(lipgloss.Color("#1E3A5F")).
 				Foreground(White)
@@ -97,6 +101,14 @@ var (
 			BorderForeground(Secondary).
 			Padding(0, 1)
 
+	// Side panel beside the tree
+	Panel = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(Muted).
+		Padding(0, 1)
+
+	PanelFocused = Panel.BorderForeground(Primary)
+
 	// Help styles
 	HelpKey = lipgloss.NewStyle().
 		Foreground(Primary).
@@ -130,6 +142,13 @@ var (
 	// Spinner style
 	Spinner = lipgloss.NewStyle().
 		Foreground(Primary)
+
+	// Progress bar
+	ProgressFilled = lipgloss.NewStyle().
+			Foreground(Primary)
+
+	ProgressEmpty = lipgloss.NewStyle().
+			Foreground(Muted)
 )
 
 // ScopeColor returns the color for a scope ID
@@ -68,7 +68,8 @@ func (m *ArchiveModel) doArchive() tea.Msg {
 			return ArchiveErrMsg{Err: err}
 		}
 		return ArchiveSuccessMsg{
-			Message: fmt.Sprintf("Archived %s %s -> %s", m.TargetNode.ID, m.TargetNode.Name, result.ArchivedItem.ID),
+			Message: fmt.Sprintf("Archived %s %s", m.TargetNode.ID, m.TargetNode.Name),
+			Report:  result.Report,
 		}
 
 	case application.IDTypeCategory:
@@ -79,6 +80,7 @@ func (m *ArchiveModel) doArchive() tea.Msg {
 		}
 		return ArchiveSuccessMsg{
 			Message: fmt.Sprintf("Archived %d items from %s %s", len(result.ArchivedItems), m.TargetNode.ID, m.TargetNode.Name),
+			Report:  result.Report,
 		}
 
 	default:
@@ -89,6 +91,7 @@ func (m *ArchiveModel) doArchive() tea.Msg {
 // ArchiveSuccessMsg indicates successful archiving
 type ArchiveSuccessMsg struct {
 	Message string
+	Report  *application.OperationReport
 }
 
 // ArchiveErrMsg indicates an error during archiving
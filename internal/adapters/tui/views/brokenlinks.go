@@ -0,0 +1,276 @@
+package views
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"libraio/internal/adapters/tui/styles"
+	"libraio/internal/application/commands"
+	"libraio/internal/domain"
+	"libraio/internal/ports"
+)
+
+// BrokenLinksKeyMap defines key bindings for the broken links view
+type BrokenLinksKeyMap struct {
+	Up       key.Binding
+	Down     key.Binding
+	NextPage key.Binding
+	PrevPage key.Binding
+	Fix      key.Binding
+	FixAll   key.Binding
+	Cancel   key.Binding
+}
+
+var BrokenLinksKeys = BrokenLinksKeyMap{
+	Up: key.NewBinding(
+		key.WithKeys("k", "up"),
+		key.WithHelp("k", "up"),
+	),
+	Down: key.NewBinding(
+		key.WithKeys("j", "down"),
+		key.WithHelp("j", "down"),
+	),
+	NextPage: key.NewBinding(
+		key.WithKeys("ctrl+f", "pgdown"),
+		key.WithHelp("ctrl+f", "next page"),
+	),
+	PrevPage: key.NewBinding(
+		key.WithKeys("ctrl+b", "pgup"),
+		key.WithHelp("ctrl+b", "prev page"),
+	),
+	Fix: key.NewBinding(
+		key.WithKeys("f"),
+		key.WithHelp("f", "fix"),
+	),
+	FixAll: key.NewBinding(
+		key.WithKeys("F"),
+		key.WithHelp("F", "fix all"),
+	),
+	Cancel: key.NewBinding(
+		key.WithKeys("esc", "q"),
+		key.WithHelp("esc", "back"),
+	),
+}
+
+// BrokenLinksModel is the model for reviewing and fixing broken links
+type BrokenLinksModel struct {
+	ViewState
+	repo      ports.VaultRepository
+	links     []domain.BrokenLink
+	loaded    bool
+	paginator *Paginator
+}
+
+// NewBrokenLinksModel creates a new broken links view model
+func NewBrokenLinksModel(repo ports.VaultRepository) *BrokenLinksModel {
+	return &BrokenLinksModel{
+		repo:      repo,
+		paginator: NewPaginator(10),
+	}
+}
+
+type brokenLinksLoadedMsg struct {
+	links []domain.BrokenLink
+	err   error
+}
+
+// brokenLinksFixedMsg reports the outcome of fixing links
+type brokenLinksFixedMsg struct {
+	result *commands.FixLinksResult
+	err    error
+}
+
+// Init loads the broken link report
+func (m *BrokenLinksModel) Init() tea.Cmd {
+	m.links = nil
+	m.loaded = false
+	m.paginator.Reset()
+	m.ClearMessage()
+	return m.loadLinks
+}
+
+func (m *BrokenLinksModel) loadLinks() tea.Msg {
+	links, err := commands.NewBrokenLinksCommand(m.repo).Execute(context.Background())
+	return brokenLinksLoadedMsg{links: links, err: err}
+}
+
+// Update handles messages for the broken links view
+func (m *BrokenLinksModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.Width = msg.Width
+		m.Height = msg.Height
+		return m, nil
+
+	case brokenLinksLoadedMsg:
+		m.loaded = true
+		if msg.err != nil {
+			m.SetMessage(msg.err.Error(), true)
+			return m, nil
+		}
+		m.links = msg.links
+		m.paginator.SetTotal(len(m.links))
+		return m, nil
+
+	case brokenLinksFixedMsg:
+		if msg.err != nil {
+			m.SetMessage(ErrorStatus(msg.err), true)
+		} else {
+			m.SetMessage(OperationStatus(msg.result.Message, msg.result.Report))
+		}
+		return m, m.loadLinks
+
+	case tea.KeyMsg:
+		m.ClearMessage()
+		switch {
+		case key.Matches(msg, BrokenLinksKeys.Cancel):
+			return m, func() tea.Msg { return SwitchToBrowserMsg{} }
+		case key.Matches(msg, BrokenLinksKeys.Up):
+			m.paginator.CursorUp()
+		case key.Matches(msg, BrokenLinksKeys.Down):
+			m.paginator.CursorDown()
+		case key.Matches(msg, BrokenLinksKeys.NextPage):
+			m.paginator.NextPage()
+		case key.Matches(msg, BrokenLinksKeys.PrevPage):
+			m.paginator.PrevPage()
+		case key.Matches(msg, BrokenLinksKeys.Fix):
+			if link := m.selectedLink(); link != nil {
+				if !link.Fixable() {
+					m.SetMessage("No fix known for this link", true)
+					return m, nil
+				}
+				return m, m.fix([]domain.BrokenLink{*link})
+			}
+		case key.Matches(msg, BrokenLinksKeys.FixAll):
+			if m.fixable() == 0 {
+				m.SetMessage("No fix known for any link", true)
+				return m, nil
+			}
+			return m, m.fix(m.links)
+		}
+	}
+
+	return m, nil
+}
+
+func (m *BrokenLinksModel) selectedLink() *domain.BrokenLink {
+	cursor := m.paginator.Cursor()
+	if cursor < 0 || cursor >= len(m.links) {
+		return nil
+	}
+	return &m.links[cursor]
+}
+
+// fixable counts the links with a known fix
+func (m *BrokenLinksModel) fixable() int {
+	n := 0
+	for _, link := range m.links {
+		if link.Fixable() {
+			n++
+		}
+	}
+	return n
+}
+
+func (m *BrokenLinksModel) fix(links []domain.BrokenLink) tea.Cmd {
+	return func() tea.Msg {
+		result, err := commands.NewFixLinksCommand(m.repo, links).Execute(context.Background())
+		return brokenLinksFixedMsg{result: result, err: err}
+	}
+}
+
+// View renders the broken links view
+func (m *BrokenLinksModel) View() string {
+	var b strings.Builder
+
+	b.WriteString(styles.Title.Render("Broken Links"))
+	b.WriteString("\n\n")
+
+	switch {
+	case !m.loaded:
+		b.WriteString(styles.MutedText.Render("Checking links..."))
+		b.WriteString("\n")
+	case len(m.links) == 0 && m.Message == "":
+		b.WriteString(styles.MutedText.Render("No broken links"))
+		b.WriteString("\n")
+	case len(m.links) > 0:
+		start, end := m.paginator.VisibleRange()
+		cursor := m.paginator.Cursor()
+		for i := start; i < end; i++ {
+			link := m.links[i]
+			line := fmt.Sprintf("%s:%d  %s", link.SourcePath, link.Line, link.LinkText)
+			if i == cursor {
+				b.WriteString(styles.NodeSelected.Render(" > " + line + " "))
+			} else {
+				b.WriteString("   " + line)
+			}
+			if link.Fixable() {
+				b.WriteString(styles.MutedText.Render("  (fix)"))
+			}
+			b.WriteString("\n")
+		}
+
+		if m.paginator.TotalPages() > 1 {
+			b.WriteString("\n")
+			b.WriteString(styles.MutedText.Render(fmt.Sprintf("Page %d/%d", m.paginator.CurrentPage(), m.paginator.TotalPages())))
+			b.WriteString("\n")
+		}
+
+		if link := m.selectedLink(); link != nil {
+			b.WriteString("\n")
+			b.WriteString(renderBrokenLink(link))
+		}
+	}
+
+	if m.Message != "" {
+		b.WriteString("\n")
+		b.WriteString(RenderMessage(m.Message, m.MessageErr))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	bindings := []key.Binding{BrokenLinksKeys.Up, BrokenLinksKeys.Down}
+	if m.fixable() > 0 {
+		bindings = append(bindings, BrokenLinksKeys.Fix, BrokenLinksKeys.FixAll)
+	}
+	bindings = append(bindings, BrokenLinksKeys.Cancel)
+	b.WriteString(RenderHelpLine(bindings...))
+
+	return styles.App.Render(b.String())
+}
+
+// renderBrokenLink explains why the selected link is broken and its fix
+func renderBrokenLink(link *domain.BrokenLink) string {
+	var b strings.Builder
+
+	var why string
+	switch link.Kind {
+	case domain.BrokenLinkReused:
+		why = "ID now belongs to " + link.Current
+	case domain.BrokenLinkArchived:
+		why = "Archived folder is gone"
+	default:
+		why = "Nothing has this ID or path"
+	}
+	b.WriteString(styles.InputLabel.Render("Target: "))
+	b.WriteString(link.Target)
+	b.WriteString("\n")
+	b.WriteString(styles.InputLabel.Render("Why:    "))
+	b.WriteString(why)
+	b.WriteString("\n")
+	b.WriteString(styles.InputLabel.Render("Fix:    "))
+	if link.Fixable() {
+		b.WriteString(link.Fix)
+	} else {
+		b.WriteString(styles.MutedText.Render("none known"))
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// SwitchToBrokenLinksMsg requests switching to the broken links view
+type SwitchToBrokenLinksMsg struct{}
@@ -11,6 +11,7 @@ import (
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 
 	"libraio/internal/adapters/tui/styles"
 	"libraio/internal/application"
@@ -34,6 +35,10 @@ type BrowserKeyMap struct {
 	Archive      key.Binding
 	Unarchive    key.Binding
 	Delete       key.Binding
+	Trash        key.Binding
+	BrokenLinks  key.Binding
+	Stale        key.Binding
+	Links        key.Binding
 	Visual       key.Binding
 	Cut          key.Binding
 	Paste        key.Binding
@@ -97,6 +102,22 @@ var BrowserKeys = BrowserKeyMap{
 		key.WithKeys("d"),
 		key.WithHelp("d", "delete"),
 	),
+	Trash: key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "trash"),
+	),
+	BrokenLinks: key.NewBinding(
+		key.WithKeys("L"),
+		key.WithHelp("L", "broken links"),
+	),
+	Stale: key.NewBinding(
+		key.WithKeys("R"),
+		key.WithHelp("R", "clean-up review"),
+	),
+	Links: key.NewBinding(
+		key.WithKeys("b"),
+		key.WithHelp("b", "links"),
+	),
 	Visual: key.NewBinding(
 		key.WithKeys("v"),
 		key.WithHelp("v", "visual select"),
@@ -146,6 +167,15 @@ type BrowserModel struct {
 	searchMatches []application.SearchResult // matched results from repo
 	searchIndex   int                        // current match index
 	searchScorer  *SearchScorer              // fuzzy search scorer
+	searchContent bool                       // match file contents instead of names
+	searchErr     string                     // why the query could not run, shown instead of "no match"
+
+	// Saved searches shown above the tree, and naming a search to save
+	saved     savedSearchTree
+	savedRows int // Rows of the saved searches at the top of flatNodes
+	saveMode  bool
+	saveInput textinput.Model
+	saveQuery string
 
 	// Rename mode
 	renameMode  bool
@@ -162,6 +192,9 @@ type BrowserModel struct {
 	// Smart search (Claude-powered)
 	smartSearchEnabled bool
 
+	// Incoming and outgoing links of the selected node
+	links linksPanel
+
 	// For restoring state after reload
 	restoreCursor int
 	expandedIDs   map[string]bool
@@ -177,10 +210,16 @@ func NewBrowserModel(repo ports.VaultRepository) *BrowserModel {
 	renameInput.Placeholder = ""
 	renameInput.Prompt = "Rename: "
 
+	saveInput := textinput.New()
+	saveInput.Placeholder = ""
+	saveInput.Prompt = "Save search as: "
+
 	return &BrowserModel{
 		repo:          repo,
 		searchInput:   input,
 		searchScorer:  NewSearchScorer(),
+		saved:         newSavedSearchTree(),
+		saveInput:     saveInput,
 		renameInput:   renameInput,
 		selectedNodes: make(map[int]bool),
 	}
@@ -188,7 +227,7 @@ func NewBrowserModel(repo ports.VaultRepository) *BrowserModel {
 
 // Init initializes the browser
 func (m *BrowserModel) Init() tea.Cmd {
-	return m.loadTree
+	return tea.Batch(m.loadTree, loadSavedSearches(m.repo))
 }
 
 func (m *BrowserModel) loadTree() tea.Msg {
@@ -213,10 +252,27 @@ type childrenLoadedMsg struct {
 
 type successMsg struct {
 	message string
+	report  *application.OperationReport
 }
 
-// Update handles messages for the browser
+// Update handles messages for the browser. While the links panel is open, it
+// follows the selection.
 func (m *BrowserModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	model, cmd := m.update(msg)
+	return model, tea.Batch(cmd, m.links.load(m.repo, m.linkedNode()))
+}
+
+// linkedNode returns the selected node, unless it is a saved search, which
+// has no links
+func (m *BrowserModel) linkedNode() *application.TreeNode {
+	node := m.selectedNode()
+	if m.saved.heading(node) != nil {
+		return nil
+	}
+	return node
+}
+
+func (m *BrowserModel) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.Width = msg.Width
@@ -225,6 +281,7 @@ func (m *BrowserModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case treeLoadedMsg:
 		m.root = msg.root
+		m.links.nodePath = "" // Reload the links shown, which may have changed
 		m.refreshFlatNodes()
 
 		// Restore expansion state and load children for expanded nodes
@@ -250,14 +307,21 @@ func (m *BrowserModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.refreshFlatNodes()
 		return m, nil
 
+	case savedSearchesLoadedMsg:
+		m.savedSearchesLoaded(msg)
+		return m, nil
+
+	case nodeLinksLoadedMsg:
+		m.links.setLinks(msg)
+		return m, nil
+
 	case errMsg:
-		m.Message = msg.err.Error()
+		m.Message = ErrorStatus(msg.err)
 		m.MessageErr = true
 		return m, nil
 
 	case successMsg:
-		m.Message = msg.message
-		m.MessageErr = false
+		m.Message, m.MessageErr = OperationStatus(msg.message, msg.report)
 		return m, m.Reload()
 
 	case tea.KeyMsg:
@@ -268,11 +332,25 @@ func (m *BrowserModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.updateSearchMode(msg)
 		}
 
+		if m.saveMode {
+			return m.updateSaveMode(msg)
+		}
+
 		// Rename mode handling
 		if m.renameMode {
 			return m.updateRenameMode(msg)
 		}
 
+		if m.links.focused {
+			return m.updateLinksPanel(msg)
+		}
+
+		if m.cursor < m.savedRows {
+			if cmd, ok := m.updateSavedRow(msg); ok {
+				return m, cmd
+			}
+		}
+
 		switch {
 		case key.Matches(msg, BrowserKeys.Quit):
 			// Layered escape: clear cut first, then visual, then quit
@@ -408,6 +486,29 @@ func (m *BrowserModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
+		case key.Matches(msg, BrowserKeys.Trash):
+			return m, func() tea.Msg {
+				return SwitchToTrashMsg{}
+			}
+
+		case key.Matches(msg, BrowserKeys.Links):
+			m.links.toggle()
+			return m, nil
+
+		case key.Matches(msg, LinksPanelKeys.Focus):
+			m.links.focused = m.links.visible
+			return m, nil
+
+		case key.Matches(msg, BrowserKeys.BrokenLinks):
+			return m, func() tea.Msg {
+				return SwitchToBrokenLinksMsg{}
+			}
+
+		case key.Matches(msg, BrowserKeys.Stale):
+			return m, func() tea.Msg {
+				return SwitchToStaleMsg{}
+			}
+
 		case key.Matches(msg, BrowserKeys.SmartCatalog):
 			return m.handleSmartCatalog()
 
@@ -422,9 +523,11 @@ func (m *BrowserModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case key.Matches(msg, BrowserKeys.Search):
 			m.searchMode = true
+			m.setSearchContent(false)
 			m.searchInput.SetValue("")
 			m.searchInput.Focus()
 			m.searchMatches = nil
+			m.searchErr = ""
 			m.searchIndex = 0
 			return m, textinput.Blink
 
@@ -578,6 +681,15 @@ func (m *BrowserModel) updateSearchMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case tea.KeyCtrlS:
+		return m, m.startSaveSearch()
+
+	case tea.KeyTab:
+		// Toggle between name and content search
+		m.setSearchContent(!m.searchContent)
+		m.runSearch()
+		return m, nil
+
 	case tea.KeyCtrlP:
 		// Previous match
 		if len(m.searchMatches) > 0 {
@@ -610,22 +722,82 @@ func (m *BrowserModel) updateSearchMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	m.searchInput, cmd = m.searchInput.Update(msg)
 
-	// Search using repository (searches filesystem)
+	m.runSearch()
+	return m, cmd
+}
+
+// contentSearchLimit caps content matches in the browser search
+const contentSearchLimit = 50
+
+// setSearchContent switches the search prompt between name and content matching
+func (m *BrowserModel) setSearchContent(content bool) {
+	m.searchContent = content
+	if content {
+		m.searchInput.Prompt = "content/"
+	} else {
+		m.searchInput.Prompt = "/"
+	}
+}
+
+// runSearch searches the repository for the current query and jumps to the best match.
+// Name queries using filters, phrases, negation or OR run against the index.
+func (m *BrowserModel) runSearch() {
 	query := m.searchInput.Value()
-	if len(query) >= 2 {
-		results, err := m.repo.Search(query)
-		if err == nil {
-			m.searchMatches = m.searchScorer.SortResults(results, query)
-			if len(m.searchMatches) > 0 {
-				m.searchIndex = 0
-				m.navigateToResult(m.searchMatches[0])
-			}
+	m.searchErr = ""
+	if len(query) < 2 {
+		m.searchMatches = nil
+		return
+	}
+
+	if m.searchContent {
+		// Content results are already ranked by the repository
+		results, err := m.repo.SearchContent(query, contentSearchLimit)
+		if err != nil {
+			return
 		}
-	} else {
+		m.searchMatches = results
+	} else if parsed, err := application.ParseQuery(query); err == nil && !parsed.Plain() {
 		m.searchMatches = nil
+		results, err := commands.NewQuerySearchCommand(m.repo, query, 0).Execute(context.Background())
+		if err != nil {
+			m.searchErr = err.Error()
+			return
+		}
+		for _, r := range results {
+			m.searchMatches = append(m.searchMatches, r.SearchResult)
+		}
+	} else {
+		// A query that does not parse is searched as plain words; its error
+		// shows if nothing matches
+		if err != nil {
+			m.searchErr = err.Error()
+		}
+		results, err := m.repo.Search(query)
+		if err != nil {
+			return
+		}
+		m.searchMatches = m.searchScorer.SortResults(results, query)
 	}
 
-	return m, cmd
+	if len(m.searchMatches) > 0 {
+		m.searchIndex = 0
+		m.navigateToResult(m.searchMatches[0])
+	}
+}
+
+// renderSnippet renders a content search snippet with matched terms highlighted
+func renderSnippet(snippet string) string {
+	var b strings.Builder
+	for i, part := range strings.Split(snippet, domain.HighlightStart) {
+		if i == 0 {
+			b.WriteString(styles.MutedText.Render(part))
+			continue
+		}
+		match, rest, _ := strings.Cut(part, domain.HighlightEnd)
+		b.WriteString(styles.SearchMatch.Render(match))
+		b.WriteString(styles.MutedText.Render(rest))
+	}
+	return b.String()
 }
 
 // navigateToResult expands the tree path and navigates to a search result (file)
@@ -662,10 +834,10 @@ func (m *BrowserModel) navigateToResult(result application.SearchResult) {
 	}
 	current.Expand()
 
-	// Refresh and find cursor position for the file
+	// Refresh and find cursor position for the file, below the saved searches
 	m.refreshFlatNodes()
 	for i, node := range m.flatNodes {
-		if node.Path == result.Path {
+		if i >= m.savedRows && node.Path == result.Path {
 			m.cursor = i
 			m.ensureCursorVisible()
 			return
@@ -674,7 +846,7 @@ func (m *BrowserModel) navigateToResult(result application.SearchResult) {
 
 	// Fallback: select the item if file not found
 	for i, node := range m.flatNodes {
-		if node.ID == result.ID {
+		if i >= m.savedRows && node.ID == result.ID {
 			m.cursor = i
 			m.ensureCursorVisible()
 			break
@@ -717,8 +889,13 @@ func (m *BrowserModel) selectedNode() *application.TreeNode {
 
 // getJDexPath returns the JDex file path for a node, with fallback to legacy README.md
 func (m *BrowserModel) getJDexPath(node *application.TreeNode) string {
-	folderName := filepath.Base(node.Path)
-	jdexPath := filepath.Join(node.Path, domain.JDexFileName(folderName))
+	return jdexPath(node.Path)
+}
+
+// jdexPath returns the JDex file path for a folder, with fallback to legacy README.md
+func jdexPath(folder string) string {
+	folderName := filepath.Base(folder)
+	jdexPath := filepath.Join(folder, domain.JDexFileName(folderName))
 
 	// Check if new-style JDex file exists
 	if _, err := os.Stat(jdexPath); err == nil {
@@ -726,7 +903,7 @@ func (m *BrowserModel) getJDexPath(node *application.TreeNode) string {
 	}
 
 	// Fallback to legacy README.md for backwards compatibility
-	legacyPath := filepath.Join(node.Path, "README.md")
+	legacyPath := filepath.Join(folder, "README.md")
 	if _, err := os.Stat(legacyPath); err == nil {
 		return legacyPath
 	}
@@ -771,6 +948,10 @@ func (m *BrowserModel) refreshFlatNodes() {
 	if len(m.flatNodes) > 0 {
 		m.flatNodes = m.flatNodes[1:]
 	}
+	// Saved searches come first
+	saved := m.saved.flatten()
+	m.savedRows = len(saved)
+	m.flatNodes = append(saved, m.flatNodes...)
 	// Clamp cursor
 	if m.cursor >= len(m.flatNodes) {
 		m.cursor = len(m.flatNodes) - 1
@@ -799,11 +980,27 @@ func (m *BrowserModel) View() string {
 	viewHeight := m.treeViewHeight()
 	endIdx := min(m.viewport+viewHeight, len(m.flatNodes))
 
+	var tree strings.Builder
 	for i := m.viewport; i < endIdx; i++ {
 		node := m.flatNodes[i]
-		line := m.renderNode(node, i == m.cursor)
-		b.WriteString(line)
+		var line string
+		if i < m.savedRows {
+			line = m.renderSavedRow(node, i == m.cursor)
+		} else {
+			line = m.renderNode(node, i == m.cursor)
+		}
+		tree.WriteString(line)
+		tree.WriteString("\n")
+	}
+	if m.links.visible {
+		// The tree is cut to fit, then padded so the panel stays in place
+		width := max(m.Width-4-m.panelWidth()-1, 10)
+		treeBlock := lipgloss.NewStyle().MaxWidth(width).Render(strings.TrimSuffix(tree.String(), "\n"))
+		treeBlock = lipgloss.NewStyle().Width(width).Height(viewHeight).Render(treeBlock)
+		b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, treeBlock, " ", m.renderLinksPanel(viewHeight)))
 		b.WriteString("\n")
+	} else {
+		b.WriteString(tree.String())
 	}
 
 	// Message
@@ -822,9 +1019,21 @@ func (m *BrowserModel) View() string {
 		b.WriteString(m.searchInput.View())
 		if len(m.searchMatches) > 0 {
 			b.WriteString(styles.MutedText.Render(fmt.Sprintf(" [%d/%d]", m.searchIndex+1, len(m.searchMatches))))
+		} else if m.searchErr != "" {
+			b.WriteString(styles.ErrorMsg.Render(" [" + m.searchErr + "]"))
 		} else if len(m.searchInput.Value()) >= 2 {
 			b.WriteString(styles.ErrorMsg.Render(" [no match]"))
 		}
+		if m.searchContent && len(m.searchMatches) > 0 {
+			b.WriteString("\n")
+			b.WriteString(renderSnippet(m.searchMatches[m.searchIndex].MatchedText))
+		}
+
+	} else if m.saveMode {
+		b.WriteString("\n")
+		b.WriteString(m.saveInput.View())
+		b.WriteString("\n")
+		b.WriteString(styles.HelpDesc.Render("Enter to save " + m.saveQuery + ", Esc to cancel"))
 	} else if m.renameMode {
 		// Rename input shown inline in the tree
 		b.WriteString("\n")
@@ -969,7 +1178,7 @@ func (m *BrowserModel) updateRenameMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			if err != nil {
 				return errMsg{err}
 			}
-			return successMsg{result.Message}
+			return successMsg{message: result.Message, report: result.Report}
 		}
 	}
 
@@ -1000,7 +1209,7 @@ func (m *BrowserModel) updateVisualSelection() {
 	if start > end {
 		start, end = end, start
 	}
-	for i := start; i <= end; i++ {
+	for i := max(start, m.savedRows); i <= end; i++ {
 		m.selectedNodes[i] = true
 	}
 }
@@ -1119,18 +1328,26 @@ func (m *BrowserModel) handlePaste() (tea.Model, tea.Cmd) {
 	return m, func() tea.Msg {
 		var lastErr error
 		moved := 0
+		report := domain.NewOperationReport()
 		for _, node := range cutNodes {
 			var err error
 			switch node.Type {
 			case application.IDTypeItem:
 				cmd := commands.NewMoveItemCommand(m.repo, node.ID, destID)
-				_, err = cmd.Execute(context.Background())
+				var result *commands.MoveItemResult
+				if result, err = cmd.Execute(context.Background()); err == nil {
+					report.Merge(result.Report)
+				}
 			case application.IDTypeCategory:
 				cmd := commands.NewMoveCategoryCommand(m.repo, node.ID, destID)
-				_, err = cmd.Execute(context.Background())
+				var result *commands.MoveCategoryResult
+				if result, err = cmd.Execute(context.Background()); err == nil {
+					report.Merge(result.Report)
+				}
 			}
 			if err != nil {
 				lastErr = err
+				report.Errorf("%s: %v", node.ID, err)
 			} else {
 				moved++
 			}
@@ -1138,7 +1355,10 @@ func (m *BrowserModel) handlePaste() (tea.Model, tea.Cmd) {
 		if lastErr != nil && moved == 0 {
 			return errMsg{lastErr}
 		}
-		return successMsg{fmt.Sprintf("Moved %d/%d to %s", moved, len(cutNodes), destID)}
+		return successMsg{
+			message: fmt.Sprintf("Moved %d/%d to %s", moved, len(cutNodes), destID),
+			report:  report,
+		}
 	}
 }
 
@@ -1183,6 +1403,13 @@ func (m *BrowserModel) isNodeCut(node *application.TreeNode) bool {
 }
 
 func (m *BrowserModel) renderHelpLine() string {
+	if m.links.focused {
+		return RenderHelpLine(LinksPanelKeys.Up, LinksPanelKeys.Down, LinksPanelKeys.Navigate,
+			LinksPanelKeys.Editor, LinksPanelKeys.Obsidian, LinksPanelKeys.Back)
+	}
+	if m.cursor < m.savedRows {
+		return m.renderSavedHelpLine()
+	}
 	node := m.selectedNode()
 
 	// Context-specific bindings based on node type
@@ -1228,7 +1455,11 @@ func (m *BrowserModel) renderHelpLine() string {
 		bindings = append(bindings, BrowserKeys.Visual)
 	}
 
-	// Always show search and help
+	// Always show links, search and help
+	bindings = append(bindings, BrowserKeys.Links)
+	if m.links.visible {
+		bindings = append(bindings, LinksPanelKeys.Focus)
+	}
 	bindings = append(bindings, BrowserKeys.Search)
 	if m.smartSearchEnabled {
 		bindings = append(bindings, BrowserKeys.SmartSearch)
@@ -1249,6 +1480,9 @@ func (m *BrowserModel) SetSize(width, height int) {
 func (m *BrowserModel) treeViewHeight() int {
 	// Subtract: title (1) + subtitle (1) + blank line (1) + footer area (3)
 	available := m.Height - 6
+	if m.searchMode && m.searchContent {
+		available-- // Snippet line below the search input
+	}
 	if available < 1 {
 		return 1
 	}
@@ -1285,15 +1519,86 @@ func (m *BrowserModel) Reload() tea.Cmd {
 	m.restoreCursor = m.cursor
 	// Save expanded nodes
 	m.expandedIDs = make(map[string]bool)
-	for _, node := range m.flatNodes {
+	for _, node := range m.flatNodes[m.savedRows:] {
 		if node.IsExpanded {
 			m.expandedIDs[node.ID] = true
 		}
 	}
 	m.root = nil
 	m.flatNodes = nil
+	m.savedRows = 0
 	m.cursor = 0
-	return m.loadTree
+	return tea.Batch(m.loadTree, loadSavedSearches(m.repo))
+}
+
+// RefreshChanged updates the tree after entries changed outside libraio.
+// Only loaded folders containing a changed path are reloaded; the cursor stays
+// on the same entry and expanded folders stay expanded. Saved searches run
+// again.
+func (m *BrowserModel) RefreshChanged(stats *domain.SyncStats) tea.Cmd {
+	if m.root == nil {
+		return nil // A reload is under way and will pick the changes up
+	}
+	if stats.Rebuilt {
+		return m.Reload()
+	}
+
+	dirs := make(map[string]bool)
+	for _, path := range stats.Changed {
+		dirs[filepath.Dir(filepath.Join(m.repo.VaultPath(), path))] = true
+	}
+
+	selected, anchor := m.rowKey(m.cursor), m.rowKey(m.visualAnchor)
+	if err := m.refreshLoaded(m.root, dirs); err != nil {
+		m.SetMessage(ErrorStatus(err), true)
+	}
+	m.refreshFlatNodes()
+	m.cursor = m.indexOfRow(selected, m.cursor)
+	if m.visualMode {
+		m.visualAnchor = m.indexOfRow(anchor, m.visualAnchor)
+		m.updateVisualSelection()
+	}
+	m.ensureCursorVisible()
+
+	m.links.nodePath = "" // The links shown may have changed with the notes
+	return tea.Batch(m.links.load(m.repo, m.linkedNode()), loadSavedSearches(m.repo))
+}
+
+// refreshLoaded reloads the children of loaded nodes whose folder is in dirs.
+// Children still there keep their node, so their own children and expansion
+// carry over.
+func (m *BrowserModel) refreshLoaded(node *application.TreeNode, dirs map[string]bool) error {
+	if len(node.Children) == 0 && !node.IsExpanded {
+		return nil // Not loaded yet; loads fresh when expanded
+	}
+
+	if dirs[node.Path] {
+		old := make(map[string]*application.TreeNode, len(node.Children))
+		for _, child := range node.Children {
+			old[child.Path] = child
+		}
+		node.Children = nil
+		if err := m.repo.LoadChildren(node); err != nil {
+			return err
+		}
+		for i, child := range node.Children {
+			if prev, ok := old[child.Path]; ok && prev.Type == child.Type {
+				node.Children[i] = prev
+			}
+		}
+	}
+
+	for _, child := range node.Children {
+		if err := m.refreshLoaded(child, dirs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// VaultChangedMsg carries a check of the watched vault
+type VaultChangedMsg struct {
+	Event domain.WatchEvent
 }
 
 // Messages for view switching
@@ -1383,7 +1688,7 @@ func (m *BrowserModel) navigateToID(jdid string) {
 	// Refresh and find cursor position
 	m.refreshFlatNodes()
 	for i, node := range m.flatNodes {
-		if node.ID == jdid {
+		if i >= m.savedRows && node.ID == jdid {
 			m.cursor = i
 			m.ensureCursorVisible()
 			return
@@ -3,7 +3,11 @@ package views
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
 
 	"libraio/internal/domain"
 )
@@ -239,4 +243,263 @@ func TestFormatTreeForSearch_WithJDexDescription(t *testing.T) {
 	}
 }
 
+// treeRepository serves LoadChildren from a map of folder paths to child names
+type treeRepository struct {
+	*mockVaultRepository
+	children map[string][]string
+}
+
+func (r *treeRepository) LoadChildren(node *domain.TreeNode) error {
+	if len(node.Children) > 0 {
+		return nil
+	}
+	for _, name := range r.children[node.Path] {
+		childType := domain.IDTypeItem
+		if filepath.Ext(name) != "" {
+			childType = domain.IDTypeFile
+		}
+		node.Children = append(node.Children, &domain.TreeNode{
+			Type:   childType,
+			Name:   name,
+			Path:   filepath.Join(node.Path, name),
+			Parent: node,
+		})
+	}
+	return nil
+}
+
+func TestRefreshChanged_KeepsCursorAndExpansion(t *testing.T) {
+	scopePath := filepath.Join("/mock/vault", "S01 Me")
+	repo := &treeRepository{
+		mockVaultRepository: newMockVaultRepository(),
+		children: map[string][]string{
+			scopePath:                     {"A", "B", "C"},
+			filepath.Join(scopePath, "B"): {"b.md"},
+		},
+	}
+	m := NewBrowserModel(repo)
+
+	root := &domain.TreeNode{ID: "root", Path: "/mock/vault", IsExpanded: true}
+	scope := &domain.TreeNode{Type: domain.IDTypeScope, ID: "S01", Name: "Me", Path: scopePath, Parent: root, IsExpanded: true}
+	root.Children = []*domain.TreeNode{scope}
+	_ = repo.LoadChildren(scope)
+	expanded := scope.Children[1]
+	expanded.IsExpanded = true
+	_ = repo.LoadChildren(expanded)
+	m.root = root
+	m.refreshFlatNodes()
+	m.cursor = 4 // C, below B's file
+
+	// An item appears above the cursor, and B's file is replaced
+	repo.children[scopePath] = []string{"A", "A2", "B", "C"}
+	repo.children[expanded.Path] = []string{"b2.md"}
+	m.RefreshChanged(&domain.SyncStats{Changed: []string{
+		filepath.Join("S01 Me", "A2"),
+		filepath.Join("S01 Me", "B", "b.md"),
+		filepath.Join("S01 Me", "B", "b2.md"),
+	}})
+
+	var names []string
+	for _, node := range m.flatNodes {
+		names = append(names, node.Name)
+	}
+	if got := strings.Join(names, " "); got != "Me A A2 B b2.md C" {
+		t.Fatalf("unexpected tree after refresh: %s", got)
+	}
+	if m.selectedNode().Name != "C" {
+		t.Errorf("cursor should stay on C, got %s", m.selectedNode().Name)
+	}
+	if scope.Children[2] != expanded || !expanded.IsExpanded {
+		t.Error("B should keep its node and stay expanded")
+	}
+}
+
 // contains is defined in smartcatalog_test.go
+
+// linksRepository serves NodeLinks from a map of node paths to their links
+type linksRepository struct {
+	*mockVaultRepository
+	links map[string]*domain.NodeLinks
+}
+
+func (r *linksRepository) NodeLinks(node *domain.TreeNode) (*domain.NodeLinks, error) {
+	return r.links[node.Path], nil
+}
+
+func TestLinksPanel_FollowsSelectionAndOpensEntries(t *testing.T) {
+	scopePath := filepath.Join("/mock/vault", "S01 Me")
+	itemPath := filepath.Join(scopePath, "S01.11.15 Theatre")
+	repo := &linksRepository{
+		mockVaultRepository: newMockVaultRepository(),
+		links: map[string]*domain.NodeLinks{
+			scopePath: {},
+			itemPath: {
+				Incoming: []domain.NodeLink{{SourcePath: "journal.md", Line: 3, Context: "Went to [[S01.11.15 Theatre]]", LinkText: "[[S01.11.15 Theatre]]", TargetID: "S01.11.15"}},
+				Outgoing: []domain.NodeLink{{SourcePath: filepath.Join("S01 Me", "S01.11.15 Theatre", "cast.md"), Line: 1, LinkText: "[[S01.11.16]]", TargetID: "S01.11.16"}},
+			},
+		},
+	}
+	m := NewBrowserModel(repo)
+	root := &domain.TreeNode{ID: "root", Path: "/mock/vault", IsExpanded: true}
+	scope := &domain.TreeNode{Type: domain.IDTypeScope, ID: "S01", Name: "Me", Path: scopePath, Parent: root, IsExpanded: true}
+	item := &domain.TreeNode{Type: domain.IDTypeItem, ID: "S01.11.15", Name: "Theatre", Path: itemPath, Parent: scope}
+	root.Children = []*domain.TreeNode{scope}
+	scope.Children = []*domain.TreeNode{item}
+	m.root = root
+	m.refreshFlatNodes()
+	m.SetSize(100, 30)
+
+	// run feeds the message to the model, then the messages of the commands it returns
+	run := func(msg tea.Msg) tea.Msg {
+		t.Helper()
+		_, cmd := m.Update(msg)
+		if cmd == nil {
+			return nil
+		}
+		out := cmd()
+		if loaded, ok := out.(nodeLinksLoadedMsg); ok {
+			m.Update(loaded)
+			return nil
+		}
+		return out
+	}
+	keyMsg := func(s string) tea.KeyMsg {
+		if s == "tab" {
+			return tea.KeyMsg{Type: tea.KeyTab}
+		}
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+	}
+
+	run(keyMsg("b"))
+	if !strings.Contains(m.View(), "Incoming (0)") {
+		t.Fatalf("panel should show the scope's links:\n%s", m.View())
+	}
+	run(keyMsg("j"))
+	if view := m.View(); !strings.Contains(view, "Incoming (1)") || !strings.Contains(view, "journal.md:3") {
+		t.Fatalf("panel should follow the selection to the item:\n%s", view)
+	}
+
+	// Focus the panel: the outgoing link leads nowhere, the incoming one to its note
+	run(keyMsg("tab"))
+	run(keyMsg("j"))
+	if got := run(keyMsg("e")); got != nil || !m.MessageErr {
+		t.Errorf("a link to nothing should not open, got %#v", got)
+	}
+	run(keyMsg("k"))
+	got, ok := run(keyMsg("e")).(OpenEditorMsg)
+	if !ok || got.Path != filepath.Join("/mock/vault", "journal.md") {
+		t.Errorf("expected journal.md to open in the editor, got %#v", got)
+	}
+
+	run(keyMsg("b"))
+	if strings.Contains(m.View(), "Incoming") || m.links.focused {
+		t.Error("b should hide the panel and return focus to the tree")
+	}
+}
+
+// savedRepository serves saved searches and the index entries they run against
+type savedRepository struct {
+	*treeRepository
+	searches []domain.SavedSearch
+	entries  []domain.SearchEntry
+}
+
+func (r *savedRepository) ListSavedSearches() ([]domain.SavedSearch, error) { return r.searches, nil }
+func (r *savedRepository) SearchEntries(query *domain.SearchQuery, now time.Time) ([]domain.IndexNode, error) {
+	var nodes []domain.IndexNode
+	for i := range r.entries {
+		if query.Match(&r.entries[i], now) {
+			nodes = append(nodes, r.entries[i].IndexNode)
+		}
+	}
+	return nodes, nil
+}
+func (r *savedRepository) DeleteSavedSearch(name string) (*domain.SavedSearch, error) {
+	i := domain.FindSavedSearch(r.searches, name)
+	deleted := r.searches[i]
+	r.searches = append(r.searches[:i], r.searches[i+1:]...)
+	return &deleted, nil
+}
+
+func TestSavedSearches_ShownAboveTheTree(t *testing.T) {
+	scopePath := filepath.Join("/mock/vault", "S01 Me")
+	item := filepath.Join("S01 Me", "S01.11.15 Theatre")
+	cast := domain.SearchEntry{IndexNode: domain.IndexNode{Path: filepath.Join(item, "cast.md")}, Tags: []string{"todo"}}
+	repo := &savedRepository{
+		treeRepository: &treeRepository{
+			mockVaultRepository: newMockVaultRepository(),
+			children:            map[string][]string{filepath.Join("/mock/vault", item): {"cast.md"}},
+		},
+		searches: []domain.SavedSearch{{Name: "Tagged #todo", Query: "tag:todo"}, {Name: "Items", Query: "type:item"}},
+		entries: []domain.SearchEntry{
+			{IndexNode: domain.IndexNode{Path: item, JDID: "S01.11.15", JDType: domain.IDTypeItem, Name: "Theatre", IsDir: true}},
+			cast,
+		},
+	}
+	m := NewBrowserModel(repo)
+	root := &domain.TreeNode{ID: "root", Path: "/mock/vault", IsExpanded: true}
+	scope := &domain.TreeNode{Type: domain.IDTypeScope, ID: "S01", Name: "Me", Path: scopePath, Parent: root, IsExpanded: true}
+	scope.Children = []*domain.TreeNode{{Type: domain.IDTypeItem, ID: "S01.11.15", Name: "Theatre", Path: filepath.Join("/mock/vault", item), Parent: scope}}
+	root.Children = []*domain.TreeNode{scope}
+	m.root = root
+	m.refreshFlatNodes()
+	m.SetSize(100, 30)
+
+	rows := func() string {
+		var names []string
+		for _, node := range m.flatNodes {
+			names = append(names, node.Name)
+		}
+		return strings.Join(names, " | ")
+	}
+	press := func(msg tea.KeyMsg) tea.Cmd {
+		_, cmd := m.Update(msg)
+		return cmd
+	}
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+	keyMsg := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+
+	m.Update(loadSavedSearches(repo)())
+	if got := rows(); got != "Tagged #todo | Items | Me | Theatre" {
+		t.Fatalf("rows = %s", got)
+	}
+	if view := m.View(); !strings.Contains(view, "Tagged #todo (1)") || !strings.Contains(view, "Items (1)") {
+		t.Fatalf("headings should show their result counts:\n%s", view)
+	}
+
+	if got := m.selectedNode(); got != scope {
+		t.Fatalf("the cursor should stay on the scope, got %s", got.Name)
+	}
+
+	// A heading expands to its results, which stand in for the entries
+	m.cursor = 0
+	press(enter)
+	if got := rows(); got != "Tagged #todo | cast.md | Items | Me | Theatre" {
+		t.Fatalf("rows after expanding = %s", got)
+	}
+	press(keyMsg("j"))
+	if cmd := press(keyMsg("m")); cmd != nil || !m.MessageErr {
+		t.Error("a result should not be moved from the saved search")
+	}
+	press(enter)
+	if got := m.selectedNode(); m.cursor < m.savedRows || got.Path != filepath.Join("/mock/vault", item, "cast.md") {
+		t.Fatalf("enter should jump to the file in the tree, cursor %d on %+v", m.cursor, got)
+	}
+
+	// Results follow the index, and the cursor stays on the entry
+	repo.entries[1].Tags = nil
+	m.Update(loadSavedSearches(repo)())
+	if !strings.Contains(m.View(), "Tagged #todo (0)") {
+		t.Errorf("the search should no longer match:\n%s", m.View())
+	}
+	if got := m.selectedNode(); got.Name != "cast.md" || m.cursor < m.savedRows {
+		t.Errorf("cursor should stay on cast.md in the tree, got %s", got.Name)
+	}
+
+	// Deleting a heading deletes its search
+	m.cursor = 0
+	m.Update(press(keyMsg("d"))())
+	if got := rows(); got != "Items | Me | Theatre | cast.md" {
+		t.Errorf("rows after deleting = %s", got)
+	}
+}
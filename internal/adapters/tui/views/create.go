@@ -172,7 +172,7 @@ func (m *CreateModel) create() tea.Cmd {
 			if err != nil {
 				return CreateErrMsg{Err: err}
 			}
-			return CreateSuccessMsg{Message: result.Message}
+			return CreateSuccessMsg{Message: result.Message, Report: result.Report}
 		}
 
 		// All other modes require a parent
@@ -189,7 +189,7 @@ func (m *CreateModel) create() tea.Cmd {
 			if err != nil {
 				return CreateErrMsg{Err: err}
 			}
-			return CreateSuccessMsg{Message: result.Message}
+			return CreateSuccessMsg{Message: result.Message, Report: result.Report}
 
 		case application.IDTypeArea:
 			cmd := commands.NewCreateCategoryCommand(m.repo, parentID, description)
@@ -197,7 +197,7 @@ func (m *CreateModel) create() tea.Cmd {
 			if err != nil {
 				return CreateErrMsg{Err: err}
 			}
-			return CreateSuccessMsg{Message: result.Message}
+			return CreateSuccessMsg{Message: result.Message, Report: result.Report}
 
 		case application.IDTypeCategory:
 			cmd := commands.NewCreateItemCommand(m.repo, parentID, description)
@@ -209,9 +209,10 @@ func (m *CreateModel) create() tea.Cmd {
 				return OpenEditorMsg{
 					Path:    result.Item.Path,
 					Message: result.Message,
+					Report:  result.Report,
 				}
 			}
-			return CreateSuccessMsg{Message: result.Message}
+			return CreateSuccessMsg{Message: result.Message, Report: result.Report}
 
 		default:
 			return CreateErrMsg{Err: fmt.Errorf("invalid parent type: %s", parentType)}
@@ -222,6 +223,7 @@ func (m *CreateModel) create() tea.Cmd {
 // CreateSuccessMsg indicates successful creation
 type CreateSuccessMsg struct {
 	Message string
+	Report  *application.OperationReport
 }
 
 // CreateErrMsg indicates an error during creation
@@ -233,6 +235,7 @@ type CreateErrMsg struct {
 type OpenEditorMsg struct {
 	Path    string
 	Message string
+	Report  *application.OperationReport
 }
 
 // View renders the create view
@@ -1,6 +1,7 @@
 package views
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -8,6 +9,7 @@ import (
 
 	"libraio/internal/adapters/tui/styles"
 	"libraio/internal/application"
+	"libraio/internal/application/commands"
 	"libraio/internal/ports"
 )
 
@@ -56,18 +58,22 @@ func (m *DeleteModel) doDelete() tea.Msg {
 		return DeleteErrMsg{Err: fmt.Errorf("no target selected")}
 	}
 
-	if err := m.repo.Delete(m.TargetNode.ID); err != nil {
+	cmd := commands.NewDeleteCommand(m.repo, m.TargetNode.ID)
+	result, err := cmd.Execute(context.Background())
+	if err != nil {
 		return DeleteErrMsg{Err: err}
 	}
 
 	return DeleteSuccessMsg{
-		Message: fmt.Sprintf("Deleted %s %s", m.TargetNode.ID, m.TargetNode.Name),
+		Message: result.Message,
+		Report:  result.Report,
 	}
 }
 
 // DeleteSuccessMsg indicates successful deletion
 type DeleteSuccessMsg struct {
 	Message string
+	Report  *application.OperationReport
 }
 
 // DeleteErrMsg indicates an error during deletion
@@ -84,7 +90,7 @@ func (m *DeleteModel) View() string {
 	b.WriteString("\n\n")
 
 	// Warning
-	b.WriteString(styles.ErrorMsg.Render("This action cannot be undone!"))
+	b.WriteString(styles.MutedText.Render("The entity will be moved to the trash and can be restored from there."))
 	b.WriteString("\n\n")
 
 	// Target info
@@ -93,7 +99,7 @@ func (m *DeleteModel) View() string {
 
 	// Additional warning for containers
 	if m.TargetNode != nil && m.TargetNode.Type != application.IDTypeItem {
-		b.WriteString(styles.MutedText.Render("  All contents will be permanently deleted."))
+		b.WriteString(styles.MutedText.Render("  All contents will be moved to the trash."))
 		b.WriteString("\n\n")
 	}
 
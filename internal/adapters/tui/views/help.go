@@ -80,10 +80,15 @@ func (m *HelpModel) View() string {
 	b.WriteString(helpLine("m", "Move item/category"))
 	b.WriteString(helpLine("a", "Archive"))
 	b.WriteString(helpLine("c", "Smart catalog (inbox items)"))
-	b.WriteString(helpLine("d", "Delete"))
+	b.WriteString(helpLine("d", "Delete (move to trash)"))
+	b.WriteString(helpLine("t", "Browse trash / restore"))
+	b.WriteString(helpLine("b", "Toggle links panel (Tab to focus)"))
+	b.WriteString(helpLine("L", "Broken links / fix"))
+	b.WriteString(helpLine("R", "Clean-up review (orphan / stale)"))
 	b.WriteString(helpLine("o", "Open in Obsidian"))
 	b.WriteString(helpLine("y", "Copy ID to clipboard"))
-	b.WriteString(helpLine("/", "Search"))
+	b.WriteString(helpLine("/", "Search (Tab: names / contents; type:item tag:x -in:S01 ...)"))
+	b.WriteString(helpLine("Ctrl+S in search", "Save the search, shown at the top of the tree (d deletes)"))
 	b.WriteString(helpLine("Ctrl+S", "Smart search (AI)"))
 	b.WriteString("\n")
 
@@ -0,0 +1,302 @@
+package views
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"libraio/internal/adapters/tui/styles"
+	"libraio/internal/application"
+	"libraio/internal/application/commands"
+	"libraio/internal/domain"
+	"libraio/internal/ports"
+)
+
+// LinksPanelKeyMap defines key bindings for the links panel beside the tree
+type LinksPanelKeyMap struct {
+	Focus    key.Binding
+	Up       key.Binding
+	Down     key.Binding
+	Navigate key.Binding
+	Editor   key.Binding
+	Obsidian key.Binding
+	Back     key.Binding
+}
+
+var LinksPanelKeys = LinksPanelKeyMap{
+	Focus: key.NewBinding(
+		key.WithKeys("tab"),
+		key.WithHelp("tab", "focus links"),
+	),
+	Up: key.NewBinding(
+		key.WithKeys("k", "up"),
+		key.WithHelp("k", "up"),
+	),
+	Down: key.NewBinding(
+		key.WithKeys("j", "down"),
+		key.WithHelp("j", "down"),
+	),
+	Navigate: key.NewBinding(
+		key.WithKeys("enter", " "),
+		key.WithHelp("enter", "go to"),
+	),
+	Editor: key.NewBinding(
+		key.WithKeys("e"),
+		key.WithHelp("e", "editor"),
+	),
+	Obsidian: key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o", "obsidian"),
+	),
+	Back: key.NewBinding(
+		key.WithKeys("tab", "esc"),
+		key.WithHelp("tab/esc", "tree"),
+	),
+}
+
+// linksPanel shows the incoming and outgoing links of the node selected in
+// the browser tree
+type linksPanel struct {
+	visible  bool
+	focused  bool
+	nodePath string            // Path of the node the links are for
+	links    []domain.NodeLink // Incoming links, then outgoing ones
+	incoming int               // How many of links are incoming
+	cursor   int
+	err      error
+}
+
+type nodeLinksLoadedMsg struct {
+	path  string
+	links *domain.NodeLinks
+	err   error
+}
+
+// load fetches the links of node, if they are not shown already
+func (p *linksPanel) load(repo ports.VaultRepository, node *application.TreeNode) tea.Cmd {
+	if !p.visible || node == nil || node.Path == p.nodePath {
+		return nil
+	}
+	p.nodePath = node.Path
+	p.links, p.incoming, p.cursor, p.err = nil, 0, 0, nil
+	return func() tea.Msg {
+		links, err := commands.NewNodeLinksCommand(repo, node).Execute(context.Background())
+		return nodeLinksLoadedMsg{path: node.Path, links: links, err: err}
+	}
+}
+
+// setLinks shows loaded links, unless the selection moved on meanwhile
+func (p *linksPanel) setLinks(msg nodeLinksLoadedMsg) {
+	if msg.path != p.nodePath {
+		return
+	}
+	p.err = msg.err
+	if msg.err != nil {
+		return
+	}
+	p.links = append(append([]domain.NodeLink(nil), msg.links.Incoming...), msg.links.Outgoing...)
+	p.incoming = len(msg.links.Incoming)
+	p.cursor = min(p.cursor, max(len(p.links)-1, 0))
+}
+
+// toggle shows or hides the panel; hiding it returns focus to the tree
+func (p *linksPanel) toggle() {
+	p.visible = !p.visible
+	p.focused = false
+	p.nodePath = ""
+}
+
+// selected returns the highlighted link and whether it is incoming
+func (p *linksPanel) selected() (domain.NodeLink, bool, bool) {
+	if p.cursor < 0 || p.cursor >= len(p.links) {
+		return domain.NodeLink{}, false, false
+	}
+	return p.links[p.cursor], p.cursor < p.incoming, true
+}
+
+// destination returns the vault path an entry leads to: the note holding an
+// incoming link, or the folder or file an outgoing link points to
+func (p *linksPanel) destination() string {
+	link, incoming, ok := p.selected()
+	switch {
+	case !ok:
+		return ""
+	case incoming:
+		return link.SourcePath
+	default:
+		return link.TargetPath
+	}
+}
+
+// updateLinksPanel handles keys while the links panel has focus
+func (m *BrowserModel) updateLinksPanel(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p := &m.links
+	switch {
+	case key.Matches(msg, BrowserKeys.Links):
+		p.toggle()
+	case key.Matches(msg, LinksPanelKeys.Back):
+		p.focused = false
+	case key.Matches(msg, LinksPanelKeys.Up):
+		if p.cursor > 0 {
+			p.cursor--
+		}
+	case key.Matches(msg, LinksPanelKeys.Down):
+		if p.cursor < len(p.links)-1 {
+			p.cursor++
+		}
+	case key.Matches(msg, LinksPanelKeys.Navigate):
+		dest := p.destination()
+		if dest == "" {
+			m.SetMessage("Link target not found", true)
+			return m, nil
+		}
+		if !m.navigateToPath(dest) {
+			m.SetMessage("Not in the tree: "+dest, true)
+			return m, nil
+		}
+		p.focused = false
+	case key.Matches(msg, LinksPanelKeys.Editor), key.Matches(msg, LinksPanelKeys.Obsidian):
+		dest := p.destination()
+		if dest == "" {
+			m.SetMessage("Link target not found", true)
+			return m, nil
+		}
+		path := filepath.Join(m.repo.VaultPath(), dest)
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			path = jdexPath(path)
+		}
+		if key.Matches(msg, LinksPanelKeys.Editor) {
+			return m, func() tea.Msg { return OpenEditorMsg{Path: path} }
+		}
+		if !canObsidianOpen(path) {
+			m.SetMessage("Obsidian cannot open "+filepath.Base(path), true)
+			return m, nil
+		}
+		return m, func() tea.Msg { return OpenObsidianMsg{Path: path} }
+	}
+	return m, nil
+}
+
+// navigateToPath selects the folder or file at a vault path in the tree,
+// expanding its parents. It reports false if the path lies outside the JD tree.
+func (m *BrowserModel) navigateToPath(relPath string) bool {
+	path := filepath.Join(m.repo.VaultPath(), relPath)
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		if id := domain.ExtractID(filepath.Base(relPath)); domain.ParseIDType(id) != domain.IDTypeUnknown {
+			m.navigateToID(id)
+			return m.selectedNode() != nil && m.selectedNode().Path == path
+		}
+		return false
+	}
+	id := domain.PathJDID(relPath)
+	if id == "" {
+		return false
+	}
+	m.navigateToResult(application.SearchResult{ID: id, Path: path})
+	return true
+}
+
+// panelWidth returns the width of the links panel, borders included
+func (m *BrowserModel) panelWidth() int {
+	if m.Width == 0 {
+		return 40
+	}
+	return max(m.Width*2/5, 30)
+}
+
+// renderLinksPanel renders the links panel with the given height in lines,
+// borders included
+func (m *BrowserModel) renderLinksPanel(height int) string {
+	p := &m.links
+	style := styles.Panel
+	if p.focused {
+		style = styles.PanelFocused
+	}
+	// Border and padding take 4 columns and 2 lines
+	width := m.panelWidth() - 4
+	lines := max(height-2, 1)
+
+	var rows []string
+	switch {
+	case p.err != nil:
+		rows = append(rows, styles.ErrorMsg.Render(p.err.Error()))
+	case p.nodePath == "":
+		rows = append(rows, styles.MutedText.Render("Select a node"))
+	default:
+		rows = append(rows, styles.InputLabel.Render(fmt.Sprintf("Incoming (%d)", p.incoming)))
+		for i := 0; i < p.incoming; i++ {
+			rows = append(rows, m.renderPanelLink(i, p.links[i].SourcePath, width)...)
+		}
+		rows = append(rows, "", styles.InputLabel.Render(fmt.Sprintf("Outgoing (%d)", len(p.links)-p.incoming)))
+		for i := p.incoming; i < len(p.links); i++ {
+			target := p.links[i].TargetPath
+			if target == "" {
+				target = p.links[i].LinkText
+			}
+			rows = append(rows, m.renderPanelLink(i, target, width)...)
+		}
+	}
+
+	// Keep the selected entry in view
+	if len(rows) > lines {
+		first := 0
+		if p.cursor >= 0 && p.cursor < len(p.links) {
+			// Entry rows follow one header, plus a blank line and a header for outgoing links
+			row := p.cursor*2 + 1
+			if p.cursor >= p.incoming {
+				row += 2
+			}
+			first = min(max(row+2-lines, 0), len(rows)-lines)
+		}
+		rows = rows[first : first+lines]
+	}
+
+	return style.Width(width + 2).Height(lines).Render(strings.Join(rows, "\n"))
+}
+
+// renderPanelLink renders an entry as two lines: the path it leads to, and
+// the line the link is written on, after the note holding it if that is not
+// the path
+func (m *BrowserModel) renderPanelLink(i int, path string, width int) []string {
+	link := m.links.links[i]
+	incoming := i < m.links.incoming
+	title := filepath.Base(path)
+	if link.Line > 0 && incoming {
+		title = fmt.Sprintf("%s:%d", title, link.Line)
+	}
+	title = truncate(title, width-2)
+	if i == m.links.cursor && m.links.focused {
+		title = styles.NodeSelected.Render("> " + title)
+	} else {
+		title = "  " + title
+	}
+	written := link.Context
+	if written == "" {
+		written = link.LinkText
+	}
+	if !incoming {
+		written = fmt.Sprintf("%s:%d %s", filepath.Base(link.SourcePath), link.Line, written)
+	}
+	return []string{title, styles.MutedText.Render("  " + truncate(written, width-2))}
+}
+
+// truncate shortens s to at most width cells, ending it with "…" if cut
+func truncate(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if lipgloss.Width(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && lipgloss.Width(string(runes))+1 > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
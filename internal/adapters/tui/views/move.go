@@ -112,7 +112,7 @@ func (m *MoveModel) move() tea.Cmd {
 			if err != nil {
 				return MoveErrMsg{Err: err}
 			}
-			return MoveSuccessMsg{Message: result.Message}
+			return MoveSuccessMsg{Message: result.Message, Report: result.Report}
 
 		case application.IDTypeCategory:
 			cmd := commands.NewMoveCategoryCommand(m.repo, m.sourceNode.ID, destID)
@@ -120,7 +120,7 @@ func (m *MoveModel) move() tea.Cmd {
 			if err != nil {
 				return MoveErrMsg{Err: err}
 			}
-			return MoveSuccessMsg{Message: result.Message}
+			return MoveSuccessMsg{Message: result.Message, Report: result.Report}
 
 		default:
 			return MoveErrMsg{Err: fmt.Errorf("can only move items or categories")}
@@ -131,6 +131,7 @@ func (m *MoveModel) move() tea.Cmd {
 // MoveSuccessMsg indicates successful move
 type MoveSuccessMsg struct {
 	Message string
+	Report  *application.OperationReport
 }
 
 // MoveErrMsg indicates an error during move
@@ -1,12 +1,15 @@
 package views
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
 
 	"libraio/internal/adapters/tui/styles"
+	"libraio/internal/application"
+	"libraio/internal/domain"
 )
 
 // RenderKeyHelp formats a key binding as help text (key + description)
@@ -38,6 +41,24 @@ func RenderMessage(message string, isError bool) string {
 	return styles.Success.Render(message)
 }
 
+// OperationStatus formats a status line for a completed operation, appending
+// the report summary. The returned flag is true when the report recorded errors.
+func OperationStatus(message string, report *application.OperationReport) (string, bool) {
+	if report == nil {
+		return message, false
+	}
+	return fmt.Sprintf("%s (%s)", message, report.Summary()), report.HasErrors()
+}
+
+// ErrorStatus formats an error for a status line. A vault locked by another
+// process is called out explicitly, since retrying later will succeed.
+func ErrorStatus(err error) string {
+	if errors.Is(err, domain.ErrVaultLocked) {
+		return "Another process is modifying the vault: " + err.Error()
+	}
+	return err.Error()
+}
+
 // RenderTitle renders a title with the standard title style
 func RenderTitle(title string) string {
 	return styles.Title.Render(title)
@@ -61,6 +82,14 @@ func RenderLabelValue(label, value string) string {
 	)
 }
 
+// RenderProgressBar renders a bar of the given width, filled to fraction
+func RenderProgressBar(fraction float64, width int) string {
+	filled := int(fraction * float64(width))
+	filled = min(max(filled, 0), width)
+	return styles.ProgressFilled.Render(strings.Repeat("█", filled)) +
+		styles.ProgressEmpty.Render(strings.Repeat("░", width-filled))
+}
+
 // ViewBuilder helps construct view output with consistent formatting
 type ViewBuilder struct {
 	b strings.Builder
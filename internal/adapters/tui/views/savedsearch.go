@@ -0,0 +1,314 @@
+package views
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"libraio/internal/adapters/tui/styles"
+	"libraio/internal/application"
+	"libraio/internal/application/commands"
+	"libraio/internal/domain"
+	"libraio/internal/ports"
+)
+
+// savedSearchLimit caps the results listed under a saved search
+const savedSearchLimit = 100
+
+// savedSearchTree holds the saved searches shown as virtual folders at the top
+// of the tree. Each heading lists the results of its search as children, which
+// stand in for the entries they match: Enter jumps to the entry in the tree.
+type savedSearchTree struct {
+	root     *application.TreeNode // Parent of the headings, never shown
+	headings []savedHeading
+	expanded map[string]bool // Lowercased names of the searches showing their results
+}
+
+// savedHeading is the node of a saved search with its run
+type savedHeading struct {
+	search domain.SavedSearch
+	node   *application.TreeNode
+	total  int    // Matches, including those past savedSearchLimit
+	err    string // Why the search could not run
+}
+
+// savedSearchesLoadedMsg carries the saved searches and their results
+type savedSearchesLoadedMsg struct {
+	searches []domain.SavedSearch
+	results  [][]commands.SearchResult // Results of each search, nil if it failed
+	errs     []error                   // Failure of each search
+	err      error                     // Failure to list the searches
+}
+
+// loadSavedSearches runs every saved search against the index
+func loadSavedSearches(repo ports.VaultRepository) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		searches, err := commands.NewListSavedSearchesCommand(repo).Execute(ctx)
+		if err != nil || len(searches) == 0 {
+			return savedSearchesLoadedMsg{err: err}
+		}
+
+		msg := savedSearchesLoadedMsg{
+			searches: searches,
+			results:  make([][]commands.SearchResult, len(searches)),
+			errs:     make([]error, len(searches)),
+		}
+		for i, search := range searches {
+			msg.results[i], msg.errs[i] = commands.NewQuerySearchCommand(repo, search.Query, 0).Execute(ctx)
+		}
+		return msg
+	}
+}
+
+// newSavedSearchTree creates an empty savedSearchTree
+func newSavedSearchTree() savedSearchTree {
+	return savedSearchTree{
+		root:     &application.TreeNode{IsExpanded: true},
+		expanded: make(map[string]bool),
+	}
+}
+
+// set replaces the headings with the loaded searches. Searches keep their
+// expansion by name.
+func (t *savedSearchTree) set(msg savedSearchesLoadedMsg) {
+	t.root.Children = nil
+	t.headings = nil
+
+	for i, search := range msg.searches {
+		node := &application.TreeNode{
+			Type:       application.IDTypeUnknown,
+			Name:       search.Name,
+			Parent:     t.root,
+			IsExpanded: t.expanded[strings.ToLower(search.Name)],
+		}
+		heading := savedHeading{search: search, node: node, total: len(msg.results[i])}
+		if msg.errs[i] != nil {
+			heading.err = msg.errs[i].Error()
+		}
+		for _, r := range msg.results[i][:min(len(msg.results[i]), savedSearchLimit)] {
+			node.Children = append(node.Children, &application.TreeNode{
+				Type:   r.Type,
+				ID:     r.ID,
+				Name:   r.Name,
+				Path:   r.Path,
+				Parent: node,
+			})
+		}
+		t.root.Children = append(t.root.Children, node)
+		t.headings = append(t.headings, heading)
+	}
+}
+
+// flatten returns the visible rows of the saved searches
+func (t *savedSearchTree) flatten() []*application.TreeNode {
+	return t.root.Flatten()[1:]
+}
+
+// heading returns the saved search headed by node, or nil
+func (t *savedSearchTree) heading(node *application.TreeNode) *savedHeading {
+	for i := range t.headings {
+		if t.headings[i].node == node {
+			return &t.headings[i]
+		}
+	}
+	return nil
+}
+
+// setExpanded shows or hides the results of a heading
+func (t *savedSearchTree) setExpanded(h *savedHeading, expanded bool) {
+	h.node.IsExpanded = expanded
+	t.expanded[strings.ToLower(h.search.Name)] = expanded
+}
+
+// savedSearchesLoaded shows the loaded saved searches, keeping the cursor on
+// the same row
+func (m *BrowserModel) savedSearchesLoaded(msg savedSearchesLoadedMsg) {
+	if msg.err != nil {
+		m.SetMessage(ErrorStatus(fmt.Errorf("failed to load saved searches: %w", msg.err)), true)
+		return
+	}
+	selected := m.rowKey(m.cursor)
+	m.saved.set(msg)
+	m.refreshFlatNodes()
+	m.cursor = m.indexOfRow(selected, m.cursor)
+	m.ensureCursorVisible()
+}
+
+// updateSavedRow handles keys on a saved search or one of its results. It
+// reports whether it handled the key; other keys work as on any node.
+func (m *BrowserModel) updateSavedRow(msg tea.KeyMsg) (tea.Cmd, bool) {
+	node := m.selectedNode()
+	h := m.saved.heading(node)
+
+	switch {
+	case key.Matches(msg, BrowserKeys.Enter), key.Matches(msg, BrowserKeys.Right):
+		if h != nil {
+			m.saved.setExpanded(h, !h.node.IsExpanded || key.Matches(msg, BrowserKeys.Right))
+			m.refreshFlatNodes()
+		} else {
+			m.navigateToResult(application.SearchResult{ID: node.ID, Path: node.Path})
+		}
+		return nil, true
+
+	case key.Matches(msg, BrowserKeys.Left):
+		if h != nil {
+			m.saved.setExpanded(h, false)
+			m.refreshFlatNodes()
+		} else {
+			m.cursor = m.indexOfRow(m.rowKeyOf(node.Parent), m.cursor)
+			m.ensureCursorVisible()
+		}
+		return nil, true
+
+	case key.Matches(msg, BrowserKeys.Delete) && h != nil:
+		result, err := commands.NewDeleteSavedSearchCommand(m.repo, h.search.Name).Execute(context.Background())
+		if err != nil {
+			m.SetMessage(ErrorStatus(err), true)
+			return nil, true
+		}
+		m.SetMessage(result.Message, false)
+		return loadSavedSearches(m.repo), true
+
+	case key.Matches(msg, BrowserKeys.New), key.Matches(msg, BrowserKeys.Move),
+		key.Matches(msg, BrowserKeys.Archive), key.Matches(msg, BrowserKeys.Unarchive),
+		key.Matches(msg, BrowserKeys.Rename), key.Matches(msg, BrowserKeys.Delete),
+		key.Matches(msg, BrowserKeys.Visual), key.Matches(msg, BrowserKeys.Cut),
+		key.Matches(msg, BrowserKeys.Paste), key.Matches(msg, BrowserKeys.SmartCatalog):
+		m.SetMessage("Saved search results are read-only; Enter jumps to the entry", true)
+		return nil, true
+	}
+	return nil, false
+}
+
+// startSaveSearch asks for a name to save the current search under
+func (m *BrowserModel) startSaveSearch() tea.Cmd {
+	query := strings.TrimSpace(m.searchInput.Value())
+	if m.searchContent {
+		m.SetMessage("Only name searches can be saved", true)
+		return nil
+	}
+	if query == "" {
+		return nil
+	}
+
+	m.searchMode = false
+	m.saveMode = true
+	m.saveQuery = query
+	m.saveInput.SetValue("")
+	m.saveInput.Focus()
+	return textinput.Blink
+}
+
+// updateSaveMode handles keys while naming a search to save
+func (m *BrowserModel) updateSaveMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.saveMode = false
+		return m, nil
+
+	case tea.KeyEnter:
+		m.saveMode = false
+		result, err := commands.NewSaveSearchCommand(m.repo, m.saveInput.Value(), m.saveQuery).Execute(context.Background())
+		if err != nil {
+			m.SetMessage(ErrorStatus(err), true)
+			return m, nil
+		}
+		m.saved.expanded[strings.ToLower(result.Search.Name)] = true
+		m.SetMessage(result.Message, false)
+		return m, loadSavedSearches(m.repo)
+	}
+
+	var cmd tea.Cmd
+	m.saveInput, cmd = m.saveInput.Update(msg)
+	return m, cmd
+}
+
+// renderSavedRow renders a saved search heading, or a result, which shows the
+// ID of its folder after a file name
+func (m *BrowserModel) renderSavedRow(node *application.TreeNode, selected bool) string {
+	h := m.saved.heading(node)
+	if h == nil {
+		if node.Type != application.IDTypeFile {
+			return m.renderNode(node, selected)
+		}
+		line := m.renderNode(node, selected)
+		if node.ID != "" {
+			line += styles.MutedText.Render("  " + node.ID)
+		}
+		return line
+	}
+
+	prefix := styles.TreeCollapsed
+	if node.IsExpanded {
+		prefix = styles.TreeExpanded
+	}
+	text := node.Name
+	if selected {
+		text = styles.NodeSelected.Render(text)
+	} else {
+		text = styles.NodeSavedSearch.Render(text)
+	}
+
+	var detail string
+	switch {
+	case h.err != "":
+		detail = styles.ErrorMsg.Render(" [" + h.err + "]")
+	case h.total > savedSearchLimit:
+		detail = styles.MutedText.Render(fmt.Sprintf(" (%d, first %d shown)", h.total, savedSearchLimit))
+	default:
+		detail = styles.MutedText.Render(fmt.Sprintf(" (%d)", h.total))
+	}
+	return fmt.Sprintf("%s%s%s%s", strings.Repeat("  ", node.Depth()), styles.TreeBranch.Render(prefix), text, detail)
+}
+
+// renderSavedHelpLine renders the keys for a saved search or one of its results
+func (m *BrowserModel) renderSavedHelpLine() string {
+	if m.saved.heading(m.selectedNode()) != nil {
+		return RenderHelpLine(
+			key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "results")),
+			key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "delete search")),
+			BrowserKeys.Search, BrowserKeys.Help,
+		)
+	}
+	return RenderHelpLine(
+		key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "go to")),
+		BrowserKeys.Links, BrowserKeys.Yank, BrowserKeys.Search, BrowserKeys.Help,
+	)
+}
+
+// rowKey identifies the row at index i across reloads: the path of a tree
+// node, or the search name and path of a saved search row. It is "" past the
+// rows.
+func (m *BrowserModel) rowKey(i int) string {
+	if i < 0 || i >= len(m.flatNodes) {
+		return ""
+	}
+	return m.rowKeyOf(m.flatNodes[i])
+}
+
+// rowKeyOf returns the row key of a visible node
+func (m *BrowserModel) rowKeyOf(node *application.TreeNode) string {
+	if node.Parent == m.saved.root {
+		return "\x00" + strings.ToLower(node.Name)
+	}
+	if h := node.Parent; h != nil && h.Parent == m.saved.root {
+		return "\x00" + strings.ToLower(h.Name) + "\x00" + node.Path
+	}
+	return node.Path
+}
+
+// indexOfRow returns the index of the row with key, or fallback clamped to
+// the rows if it is gone
+func (m *BrowserModel) indexOfRow(key string, fallback int) int {
+	for i := range m.flatNodes {
+		if key != "" && m.rowKey(i) == key {
+			return i
+		}
+	}
+	return max(min(fallback, len(m.flatNodes)-1), 0)
+}
@@ -2,6 +2,7 @@ package views
 
 import (
 	"testing"
+	"time"
 
 	"libraio/internal/application"
 	"libraio/internal/domain"
@@ -360,28 +361,79 @@ func (m *mockVaultRepository) LoadChildren(*domain.TreeNode) error  { return nil
 func (m *mockVaultRepository) Search(string) ([]domain.SearchResult, error) {
 	return nil, nil
 }
-func (m *mockVaultRepository) CreateScope(string) (*domain.Scope, error)       { return nil, nil }
-func (m *mockVaultRepository) CreateArea(string, string) (*domain.Area, error) { return nil, nil }
-func (m *mockVaultRepository) CreateCategory(string, string) (*domain.Category, error) {
+func (m *mockVaultRepository) SearchContent(string, int) ([]domain.SearchResult, error) {
 	return nil, nil
 }
-func (m *mockVaultRepository) CreateItem(string, string) (*domain.Item, error) { return nil, nil }
-func (m *mockVaultRepository) MoveItem(string, string) (*domain.Item, error)   { return nil, nil }
-func (m *mockVaultRepository) MoveCategory(string, string) (*domain.Category, error) {
+func (m *mockVaultRepository) SearchEntries(*domain.SearchQuery, time.Time) ([]domain.IndexNode, error) {
 	return nil, nil
 }
-func (m *mockVaultRepository) ArchiveItem(string) (*domain.Item, error)       { return nil, nil }
-func (m *mockVaultRepository) ArchiveCategory(string) ([]*domain.Item, error) { return nil, nil }
-func (m *mockVaultRepository) UnarchiveItems(string, string) ([]*domain.Item, error) {
+func (m *mockVaultRepository) CreateScope(string) (*domain.Scope, *domain.OperationReport, error) {
+	return nil, nil, nil
+}
+func (m *mockVaultRepository) CreateArea(string, string) (*domain.Area, *domain.OperationReport, error) {
+	return nil, nil, nil
+}
+func (m *mockVaultRepository) CreateCategory(string, string) (*domain.Category, *domain.OperationReport, error) {
+	return nil, nil, nil
+}
+func (m *mockVaultRepository) CreateItem(string, string) (*domain.Item, *domain.OperationReport, error) {
+	return nil, nil, nil
+}
+func (m *mockVaultRepository) MoveItem(string, string) (*domain.Item, *domain.OperationReport, error) {
+	return nil, nil, nil
+}
+func (m *mockVaultRepository) MoveCategory(string, string) (*domain.Category, *domain.OperationReport, error) {
+	return nil, nil, nil
+}
+func (m *mockVaultRepository) ArchiveItem(string) (*domain.Item, *domain.OperationReport, error) {
+	return nil, nil, nil
+}
+func (m *mockVaultRepository) ArchiveCategory(string) ([]*domain.Item, *domain.OperationReport, error) {
+	return nil, nil, nil
+}
+func (m *mockVaultRepository) UnarchiveItems(string, string) ([]*domain.Item, *domain.OperationReport, error) {
+	return nil, nil, nil
+}
+func (m *mockVaultRepository) RenameItem(string, string) (*domain.Item, *domain.OperationReport, error) {
+	return nil, nil, nil
+}
+func (m *mockVaultRepository) RenameCategory(string, string) (*domain.Category, *domain.OperationReport, error) {
+	return nil, nil, nil
+}
+func (m *mockVaultRepository) RenameArea(string, string) (*domain.Area, *domain.OperationReport, error) {
+	return nil, nil, nil
+}
+func (m *mockVaultRepository) RelinkMoves([]domain.NodeMove) (*domain.OperationReport, error) {
 	return nil, nil
 }
-func (m *mockVaultRepository) RenameItem(string, string) (*domain.Item, error) { return nil, nil }
-func (m *mockVaultRepository) RenameCategory(string, string) (*domain.Category, error) {
+func (m *mockVaultRepository) BrokenLinks() ([]domain.BrokenLink, error) { return nil, nil }
+func (m *mockVaultRepository) FixLinks([]domain.BrokenLink) (*domain.OperationReport, error) {
 	return nil, nil
 }
-func (m *mockVaultRepository) RenameArea(string, string) (*domain.Area, error) { return nil, nil }
-func (m *mockVaultRepository) Delete(string) error                             { return nil }
-func (m *mockVaultRepository) VaultPath() string                               { return "/mock/vault" }
+func (m *mockVaultRepository) NodeLinks(*domain.TreeNode) (*domain.NodeLinks, error) {
+	return &domain.NodeLinks{}, nil
+}
+func (m *mockVaultRepository) LinkGraph(domain.GraphOptions) (*domain.LinkGraph, error) {
+	return &domain.LinkGraph{}, nil
+}
+func (m *mockVaultRepository) FindStale(domain.StaleOptions) ([]domain.StaleEntry, error) {
+	return nil, nil
+}
+func (m *mockVaultRepository) Delete(string) (*domain.TrashEntry, *domain.OperationReport, error) {
+	return nil, nil, nil
+}
+func (m *mockVaultRepository) ListTrash() ([]domain.TrashEntry, error) { return nil, nil }
+func (m *mockVaultRepository) RestoreTrash(string) (*domain.TrashEntry, *domain.OperationReport, error) {
+	return nil, nil, nil
+}
+func (m *mockVaultRepository) PurgeTrash(string) error { return nil }
+func (m *mockVaultRepository) ListBackups() ([]domain.Snapshot, error) {
+	return nil, nil
+}
+func (m *mockVaultRepository) RestoreBackup(string) (*domain.Snapshot, *domain.OperationReport, error) {
+	return nil, nil, nil
+}
+func (m *mockVaultRepository) VaultPath() string { return "/mock/vault" }
 
 type mockAIAssistant struct {
 	suggestions []ports.CatalogSuggestion
@@ -580,3 +632,8 @@ func contains(s, substr string) bool {
 	}
 	return false
 }
+func (m *mockVaultRepository) ListSavedSearches() ([]domain.SavedSearch, error) { return nil, nil }
+func (m *mockVaultRepository) SaveSearch(domain.SavedSearch) error              { return nil }
+func (m *mockVaultRepository) DeleteSavedSearch(string) (*domain.SavedSearch, error) {
+	return nil, nil
+}
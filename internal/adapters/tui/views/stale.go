@@ -0,0 +1,358 @@
+package views
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"libraio/internal/adapters/tui/styles"
+	"libraio/internal/application/commands"
+	"libraio/internal/domain"
+	"libraio/internal/ports"
+)
+
+// staleMonths is how long an item goes without changes before the review
+// lists it as untouched
+const staleMonths = 12
+
+// StaleKeyMap defines key bindings for the stale review view
+type StaleKeyMap struct {
+	Up       key.Binding
+	Down     key.Binding
+	NextPage key.Binding
+	PrevPage key.Binding
+	Archive  key.Binding
+	Delete   key.Binding
+	Open     key.Binding
+	Confirm  key.Binding
+	Cancel   key.Binding
+}
+
+var StaleKeys = StaleKeyMap{
+	Up: key.NewBinding(
+		key.WithKeys("k", "up"),
+		key.WithHelp("k", "up"),
+	),
+	Down: key.NewBinding(
+		key.WithKeys("j", "down"),
+		key.WithHelp("j", "down"),
+	),
+	NextPage: key.NewBinding(
+		key.WithKeys("ctrl+f", "pgdown"),
+		key.WithHelp("ctrl+f", "next page"),
+	),
+	PrevPage: key.NewBinding(
+		key.WithKeys("ctrl+b", "pgup"),
+		key.WithHelp("ctrl+b", "prev page"),
+	),
+	Archive: key.NewBinding(
+		key.WithKeys("a"),
+		key.WithHelp("a", "archive"),
+	),
+	Delete: key.NewBinding(
+		key.WithKeys("d"),
+		key.WithHelp("d", "delete"),
+	),
+	Open: key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o", "obsidian"),
+	),
+	Confirm: key.NewBinding(
+		key.WithKeys("y"),
+		key.WithHelp("y", "confirm"),
+	),
+	Cancel: key.NewBinding(
+		key.WithKeys("esc", "q"),
+		key.WithHelp("esc", "back"),
+	),
+}
+
+// staleConfirm identifies a review action awaiting confirmation
+type staleConfirm int
+
+const (
+	staleConfirmNone staleConfirm = iota
+	staleConfirmArchive
+	staleConfirmDelete
+)
+
+// StaleModel is the model for reviewing stale entries during a clean-up
+type StaleModel struct {
+	ViewState
+	repo      ports.VaultRepository
+	entries   []domain.StaleEntry
+	loaded    bool
+	paginator *Paginator
+	confirm   staleConfirm
+}
+
+// NewStaleModel creates a new stale review view model
+func NewStaleModel(repo ports.VaultRepository) *StaleModel {
+	return &StaleModel{
+		repo:      repo,
+		paginator: NewPaginator(10),
+	}
+}
+
+type staleLoadedMsg struct {
+	entries []domain.StaleEntry
+	err     error
+}
+
+// staleActionMsg reports the outcome of archiving or deleting an entry
+type staleActionMsg struct {
+	message string
+	report  *domain.OperationReport
+	err     error
+}
+
+// Init loads the review
+func (m *StaleModel) Init() tea.Cmd {
+	m.entries = nil
+	m.loaded = false
+	m.confirm = staleConfirmNone
+	m.paginator.Reset()
+	m.ClearMessage()
+	return m.loadEntries
+}
+
+func (m *StaleModel) loadEntries() tea.Msg {
+	opts := domain.StaleOptions{Before: time.Now().AddDate(0, -staleMonths, 0)}
+	entries, err := commands.NewFindStaleCommand(m.repo, opts).Execute(context.Background())
+	return staleLoadedMsg{entries: entries, err: err}
+}
+
+// Update handles messages for the stale review view
+func (m *StaleModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.Width = msg.Width
+		m.Height = msg.Height
+		return m, nil
+
+	case staleLoadedMsg:
+		m.loaded = true
+		if msg.err != nil {
+			m.SetMessage(msg.err.Error(), true)
+			return m, nil
+		}
+		m.entries = msg.entries
+		m.paginator.SetTotal(len(m.entries))
+		return m, nil
+
+	case staleActionMsg:
+		if msg.err != nil {
+			m.SetMessage(ErrorStatus(msg.err), true)
+		} else {
+			m.SetMessage(OperationStatus(msg.message, msg.report))
+		}
+		return m, m.loadEntries
+
+	case tea.KeyMsg:
+		if m.confirm != staleConfirmNone {
+			return m.updateConfirm(msg)
+		}
+
+		m.ClearMessage()
+		switch {
+		case key.Matches(msg, StaleKeys.Cancel):
+			return m, func() tea.Msg { return SwitchToBrowserMsg{} }
+		case key.Matches(msg, StaleKeys.Up):
+			m.paginator.CursorUp()
+		case key.Matches(msg, StaleKeys.Down):
+			m.paginator.CursorDown()
+		case key.Matches(msg, StaleKeys.NextPage):
+			m.paginator.NextPage()
+		case key.Matches(msg, StaleKeys.PrevPage):
+			m.paginator.PrevPage()
+		case key.Matches(msg, StaleKeys.Archive):
+			if entry := m.selectedEntry(); entry != nil {
+				eligibility := commands.CheckArchiveEligibility(entry.ID, domain.ParseIDType(entry.ID))
+				if !eligibility.CanArchive {
+					m.SetMessage(eligibility.Reason, true)
+					return m, nil
+				}
+				m.confirm = staleConfirmArchive
+			}
+		case key.Matches(msg, StaleKeys.Delete):
+			if m.selectedEntry() != nil {
+				m.confirm = staleConfirmDelete
+			}
+		case key.Matches(msg, StaleKeys.Open):
+			if entry := m.selectedEntry(); entry != nil {
+				if domain.ParseIDType(entry.ID) != domain.IDTypeItem {
+					m.SetMessage("Only items open in Obsidian", true)
+					return m, nil
+				}
+				path := jdexPath(filepath.Join(m.repo.VaultPath(), entry.Path))
+				return m, func() tea.Msg { return OpenObsidianMsg{Path: path} }
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// updateConfirm handles the y/n prompt for archiving and deleting
+func (m *StaleModel) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	action := m.confirm
+	m.confirm = staleConfirmNone
+
+	entry := m.selectedEntry()
+	if !key.Matches(msg, StaleKeys.Confirm) || entry == nil {
+		return m, nil
+	}
+
+	switch action {
+	case staleConfirmArchive:
+		return m, m.archive(*entry)
+	case staleConfirmDelete:
+		return m, m.delete(*entry)
+	}
+	return m, nil
+}
+
+func (m *StaleModel) selectedEntry() *domain.StaleEntry {
+	cursor := m.paginator.Cursor()
+	if cursor < 0 || cursor >= len(m.entries) {
+		return nil
+	}
+	return &m.entries[cursor]
+}
+
+func (m *StaleModel) archive(entry domain.StaleEntry) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		if domain.ParseIDType(entry.ID) == domain.IDTypeCategory {
+			result, err := commands.NewArchiveCategoryCommand(m.repo, entry.ID).Execute(ctx)
+			if err != nil {
+				return staleActionMsg{err: err}
+			}
+			return staleActionMsg{message: result.Message, report: result.Report}
+		}
+		result, err := commands.NewArchiveItemCommand(m.repo, entry.ID).Execute(ctx)
+		if err != nil {
+			return staleActionMsg{err: err}
+		}
+		return staleActionMsg{message: result.Message, report: result.Report}
+	}
+}
+
+func (m *StaleModel) delete(entry domain.StaleEntry) tea.Cmd {
+	return func() tea.Msg {
+		result, err := commands.NewDeleteCommand(m.repo, entry.ID).Execute(context.Background())
+		if err != nil {
+			return staleActionMsg{err: err}
+		}
+		return staleActionMsg{message: result.Message, report: result.Report}
+	}
+}
+
+// View renders the stale review view
+func (m *StaleModel) View() string {
+	var b strings.Builder
+
+	b.WriteString(styles.Title.Render("Clean-up Review"))
+	b.WriteString("\n\n")
+
+	switch {
+	case !m.loaded:
+		b.WriteString(styles.MutedText.Render("Reviewing vault..."))
+		b.WriteString("\n")
+	case len(m.entries) == 0 && m.Message == "":
+		b.WriteString(styles.MutedText.Render("Nothing to clean up"))
+		b.WriteString("\n")
+	case len(m.entries) > 0:
+		start, end := m.paginator.VisibleRange()
+		cursor := m.paginator.Cursor()
+		for i := start; i < end; i++ {
+			entry := m.entries[i]
+			if i == cursor {
+				b.WriteString(styles.NodeSelected.Render(" > " + entry.FolderName() + " "))
+			} else {
+				b.WriteString("   " + entry.FolderName())
+			}
+			b.WriteString(styles.MutedText.Render("  " + formatStaleReasons(entry.Reasons)))
+			b.WriteString("\n")
+		}
+
+		if m.paginator.TotalPages() > 1 {
+			b.WriteString("\n")
+			b.WriteString(styles.MutedText.Render(fmt.Sprintf("Page %d/%d", m.paginator.CurrentPage(), m.paginator.TotalPages())))
+			b.WriteString("\n")
+		}
+
+		if entry := m.selectedEntry(); entry != nil {
+			b.WriteString("\n")
+			b.WriteString(renderStaleEntry(entry))
+		}
+	}
+
+	if m.Message != "" {
+		b.WriteString("\n")
+		b.WriteString(RenderMessage(m.Message, m.MessageErr))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	switch m.confirm {
+	case staleConfirmArchive:
+		b.WriteString(RenderConfirmPrompt("Archive this entry?"))
+	case staleConfirmDelete:
+		b.WriteString(RenderConfirmPrompt("Move this entry to the trash?"))
+	default:
+		bindings := []key.Binding{StaleKeys.Up, StaleKeys.Down}
+		if len(m.entries) > 0 {
+			bindings = append(bindings, StaleKeys.Archive, StaleKeys.Delete, StaleKeys.Open)
+		}
+		bindings = append(bindings, StaleKeys.Cancel)
+		b.WriteString(RenderHelpLine(bindings...))
+	}
+
+	return styles.App.Render(b.String())
+}
+
+// formatStaleReasons joins the reasons an entry is up for review
+func formatStaleReasons(reasons []domain.StaleReason) string {
+	names := make([]string, len(reasons))
+	for i, reason := range reasons {
+		names[i] = string(reason)
+	}
+	return strings.Join(names, ", ")
+}
+
+// renderStaleEntry explains why the selected entry is up for review
+func renderStaleEntry(entry *domain.StaleEntry) string {
+	var b strings.Builder
+
+	b.WriteString(styles.InputLabel.Render("Path:     "))
+	b.WriteString(entry.Path)
+	b.WriteString("\n")
+	b.WriteString(styles.InputLabel.Render("Modified: "))
+	b.WriteString(entry.Modified.Local().Format("2006-01-02"))
+	b.WriteString("\n")
+	for _, reason := range entry.Reasons {
+		var why string
+		switch reason {
+		case domain.StaleOrphan:
+			why = "No note outside it links to it"
+		case domain.StaleJDexOnly:
+			why = "No files besides its JDex note"
+		case domain.StaleUntouched:
+			why = fmt.Sprintf("Nothing modified in %d months", staleMonths)
+		case domain.StaleEmpty:
+			why = "Nothing filed in it"
+		}
+		b.WriteString(styles.MutedText.Render("  " + why))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// SwitchToStaleMsg requests switching to the stale review view
+type SwitchToStaleMsg struct{}
@@ -0,0 +1,133 @@
+package views
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"libraio/internal/adapters/tui/styles"
+	"libraio/internal/domain"
+	"libraio/internal/ports"
+)
+
+// SyncModel shows the progress of the index sync run at startup
+type SyncModel struct {
+	ViewState
+	progress domain.SyncProgress
+	updates  chan tea.Msg
+	cancel   context.CancelFunc // Stops delivering updates, nil until the sync starts
+	done     chan struct{}      // Closed once the sync has returned
+}
+
+// NewSyncModel creates a new sync view model
+func NewSyncModel() *SyncModel {
+	return &SyncModel{}
+}
+
+// SyncProgressMsg reports how far the sync has got
+type SyncProgressMsg struct {
+	Progress domain.SyncProgress
+}
+
+// SyncDoneMsg indicates the sync finished
+type SyncDoneMsg struct {
+	Stats *domain.SyncStats
+	Err   error
+}
+
+// Start runs the sync in the background. Progress arrives as SyncProgressMsg,
+// followed by a single SyncDoneMsg.
+func (m *SyncModel) Start(syncer ports.IndexSyncer) tea.Cmd {
+	ctx, cancel := context.WithCancel(context.Background())
+	updates := make(chan tea.Msg, 1)
+	done := make(chan struct{})
+	m.updates, m.cancel, m.done = updates, cancel, done
+
+	go func() {
+		defer close(done)
+		stats, err := syncer.Sync(func(p domain.SyncProgress) {
+			// Drop updates the view has not caught up with rather than slow the sync
+			select {
+			case updates <- SyncProgressMsg{Progress: p}:
+			default:
+			}
+		})
+		select {
+		case updates <- SyncDoneMsg{Stats: stats, Err: err}:
+		case <-ctx.Done():
+		}
+	}()
+	return m.wait()
+}
+
+// Stop waits for a sync in progress to finish, dropping its result if the
+// program no longer reads it, so the index can be closed after it. Call it
+// once the program has quit.
+func (m *SyncModel) Stop() {
+	if m.cancel == nil {
+		return
+	}
+	m.cancel()
+	<-m.done
+}
+
+// wait returns a command that delivers the next update from the sync
+func (m *SyncModel) wait() tea.Cmd {
+	updates := m.updates
+	return func() tea.Msg {
+		return <-updates
+	}
+}
+
+// Init initializes the sync view
+func (m *SyncModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages for the sync view
+func (m *SyncModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.Width = msg.Width
+		m.Height = msg.Height
+		return m, nil
+
+	case SyncProgressMsg:
+		m.progress = msg.Progress
+		return m, m.wait()
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" || msg.String() == "q" {
+			return m, tea.Quit
+		}
+	}
+
+	return m, nil
+}
+
+// View renders the sync view
+func (m *SyncModel) View() string {
+	var b strings.Builder
+
+	b.WriteString(styles.Title.Render("Updating Index"))
+	b.WriteString("\n\n")
+
+	barWidth := min(max(m.Width-20, 10), 50)
+	b.WriteString(RenderProgressBar(m.progress.Fraction(), barWidth))
+	b.WriteString("\n\n")
+
+	var status string
+	switch {
+	case m.progress.Phase == domain.SyncScanning && m.progress.Total > 0:
+		status = fmt.Sprintf("%s: %d of about %d entries", m.progress.Phase, m.progress.Done, m.progress.Total)
+	case m.progress.Phase == domain.SyncScanning:
+		status = fmt.Sprintf("%s: %d entries", m.progress.Phase, m.progress.Done)
+	default:
+		status = fmt.Sprintf("%s: %d of %s", m.progress.Phase, m.progress.Done, domain.Pluralize(m.progress.Total, "note"))
+	}
+	b.WriteString(styles.MutedText.Render(status))
+
+	return styles.App.Render(b.String())
+}
@@ -0,0 +1,54 @@
+package views
+
+import (
+	"testing"
+	"time"
+
+	"libraio/internal/domain"
+)
+
+// blockingSyncer reports progress, then finishes once release is closed
+type blockingSyncer struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (s *blockingSyncer) Sync(progress domain.SyncProgressFunc) (*domain.SyncStats, error) {
+	progress(domain.SyncProgress{Phase: domain.SyncScanning, Done: 1})
+	close(s.started)
+	<-s.release
+	return &domain.SyncStats{}, nil
+}
+
+func TestSyncModel_StopWaitsForSyncAfterQuit(t *testing.T) {
+	syncer := &blockingSyncer{started: make(chan struct{}), release: make(chan struct{})}
+	m := NewSyncModel()
+
+	// The command delivering updates is never run, as after quitting, so the
+	// progress update stays unread
+	m.Start(syncer)
+	<-syncer.started
+
+	stopped := make(chan struct{})
+	go func() {
+		m.Stop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		t.Fatal("Stop returned while the sync was still running")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(syncer.release)
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("Stop did not return once the sync finished with nobody reading its result")
+	}
+}
+
+func TestSyncModel_StopWithoutStart(t *testing.T) {
+	NewSyncModel().Stop()
+}
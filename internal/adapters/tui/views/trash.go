@@ -0,0 +1,349 @@
+package views
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"libraio/internal/adapters/tui/styles"
+	"libraio/internal/application"
+	"libraio/internal/application/commands"
+	"libraio/internal/ports"
+)
+
+// TrashKeyMap defines key bindings for the trash view
+type TrashKeyMap struct {
+	Up       key.Binding
+	Down     key.Binding
+	NextPage key.Binding
+	PrevPage key.Binding
+	Restore  key.Binding
+	Purge    key.Binding
+	Empty    key.Binding
+	Confirm  key.Binding
+	Cancel   key.Binding
+}
+
+var TrashKeys = TrashKeyMap{
+	Up: key.NewBinding(
+		key.WithKeys("k", "up"),
+		key.WithHelp("k", "up"),
+	),
+	Down: key.NewBinding(
+		key.WithKeys("j", "down"),
+		key.WithHelp("j", "down"),
+	),
+	NextPage: key.NewBinding(
+		key.WithKeys("ctrl+f", "pgdown"),
+		key.WithHelp("ctrl+f", "next page"),
+	),
+	PrevPage: key.NewBinding(
+		key.WithKeys("ctrl+b", "pgup"),
+		key.WithHelp("ctrl+b", "prev page"),
+	),
+	Restore: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "restore"),
+	),
+	Purge: key.NewBinding(
+		key.WithKeys("x"),
+		key.WithHelp("x", "delete forever"),
+	),
+	Empty: key.NewBinding(
+		key.WithKeys("E"),
+		key.WithHelp("E", "empty trash"),
+	),
+	Confirm: key.NewBinding(
+		key.WithKeys("y"),
+		key.WithHelp("y", "confirm"),
+	),
+	Cancel: key.NewBinding(
+		key.WithKeys("esc", "q"),
+		key.WithHelp("esc", "back"),
+	),
+}
+
+// trashConfirm identifies a destructive trash action awaiting confirmation
+type trashConfirm int
+
+const (
+	trashConfirmNone trashConfirm = iota
+	trashConfirmPurge
+	trashConfirmEmpty
+)
+
+// TrashModel is the model for browsing and restoring trashed entities
+type TrashModel struct {
+	ViewState
+	repo      ports.VaultRepository
+	entries   []application.TrashEntry
+	paginator *Paginator
+	confirm   trashConfirm
+}
+
+// NewTrashModel creates a new trash view model
+func NewTrashModel(repo ports.VaultRepository) *TrashModel {
+	return &TrashModel{
+		repo:      repo,
+		paginator: NewPaginator(10),
+	}
+}
+
+type trashLoadedMsg struct {
+	entries []application.TrashEntry
+	err     error
+}
+
+// trashActionMsg reports the outcome of a restore or purge
+type trashActionMsg struct {
+	message string
+	report  *application.OperationReport
+	err     error
+}
+
+// Init loads the trash contents
+func (m *TrashModel) Init() tea.Cmd {
+	m.entries = nil
+	m.confirm = trashConfirmNone
+	m.paginator.Reset()
+	m.ClearMessage()
+	return m.loadTrash
+}
+
+func (m *TrashModel) loadTrash() tea.Msg {
+	entries, err := commands.NewListTrashCommand(m.repo).Execute(context.Background())
+	return trashLoadedMsg{entries: entries, err: err}
+}
+
+// Update handles messages for the trash view
+func (m *TrashModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.Width = msg.Width
+		m.Height = msg.Height
+		return m, nil
+
+	case trashLoadedMsg:
+		if msg.err != nil {
+			m.SetMessage(msg.err.Error(), true)
+			return m, nil
+		}
+		m.entries = msg.entries
+		m.paginator.SetTotal(len(m.entries))
+		return m, nil
+
+	case trashActionMsg:
+		if msg.err != nil {
+			m.SetMessage(ErrorStatus(msg.err), true)
+		} else {
+			m.SetMessage(OperationStatus(msg.message, msg.report))
+		}
+		return m, m.loadTrash
+
+	case tea.KeyMsg:
+		if m.confirm != trashConfirmNone {
+			return m.updateConfirm(msg)
+		}
+
+		m.ClearMessage()
+		switch {
+		case key.Matches(msg, TrashKeys.Cancel):
+			return m, func() tea.Msg { return SwitchToBrowserMsg{} }
+		case key.Matches(msg, TrashKeys.Up):
+			m.paginator.CursorUp()
+		case key.Matches(msg, TrashKeys.Down):
+			m.paginator.CursorDown()
+		case key.Matches(msg, TrashKeys.NextPage):
+			m.paginator.NextPage()
+		case key.Matches(msg, TrashKeys.PrevPage):
+			m.paginator.PrevPage()
+		case key.Matches(msg, TrashKeys.Restore):
+			if entry := m.selectedEntry(); entry != nil {
+				return m, m.restore(entry.TrashID)
+			}
+		case key.Matches(msg, TrashKeys.Purge):
+			if m.selectedEntry() != nil {
+				m.confirm = trashConfirmPurge
+			}
+		case key.Matches(msg, TrashKeys.Empty):
+			if len(m.entries) > 0 {
+				m.confirm = trashConfirmEmpty
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// updateConfirm handles the y/n prompt for destructive actions
+func (m *TrashModel) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	action := m.confirm
+	m.confirm = trashConfirmNone
+
+	if !key.Matches(msg, TrashKeys.Confirm) {
+		return m, nil
+	}
+
+	switch action {
+	case trashConfirmPurge:
+		if entry := m.selectedEntry(); entry != nil {
+			return m, m.purge(*entry)
+		}
+	case trashConfirmEmpty:
+		return m, m.empty()
+	}
+	return m, nil
+}
+
+func (m *TrashModel) selectedEntry() *application.TrashEntry {
+	cursor := m.paginator.Cursor()
+	if cursor < 0 || cursor >= len(m.entries) {
+		return nil
+	}
+	return &m.entries[cursor]
+}
+
+func (m *TrashModel) restore(trashID string) tea.Cmd {
+	return func() tea.Msg {
+		result, err := commands.NewRestoreTrashCommand(m.repo, trashID).Execute(context.Background())
+		if err != nil {
+			return trashActionMsg{err: err}
+		}
+		return trashActionMsg{message: result.Message, report: result.Report}
+	}
+}
+
+func (m *TrashModel) purge(entry application.TrashEntry) tea.Cmd {
+	return func() tea.Msg {
+		if err := m.repo.PurgeTrash(entry.TrashID); err != nil {
+			return trashActionMsg{err: err}
+		}
+		return trashActionMsg{message: fmt.Sprintf("Permanently deleted %s %s", entry.ID, entry.Name)}
+	}
+}
+
+func (m *TrashModel) empty() tea.Cmd {
+	return func() tea.Msg {
+		result, err := commands.NewEmptyTrashCommand(m.repo, 0).Execute(context.Background())
+		if err != nil {
+			return trashActionMsg{err: err}
+		}
+		return trashActionMsg{message: result.Message}
+	}
+}
+
+// View renders the trash view
+func (m *TrashModel) View() string {
+	var b strings.Builder
+
+	b.WriteString(styles.Title.Render("Trash"))
+	b.WriteString("\n\n")
+
+	if len(m.entries) == 0 {
+		b.WriteString(styles.MutedText.Render("Trash is empty"))
+		b.WriteString("\n")
+	} else {
+		now := time.Now()
+		start, end := m.paginator.VisibleRange()
+		cursor := m.paginator.Cursor()
+		for i := start; i < end; i++ {
+			entry := m.entries[i]
+			age := styles.MutedText.Render("  " + formatAge(entry.Age(now)))
+			if i == cursor {
+				b.WriteString(styles.NodeSelected.Render(" > " + entry.Name + " "))
+			} else {
+				b.WriteString("   " + entry.Name)
+			}
+			b.WriteString(age)
+			b.WriteString("\n")
+		}
+
+		if m.paginator.TotalPages() > 1 {
+			b.WriteString("\n")
+			b.WriteString(styles.MutedText.Render(fmt.Sprintf("Page %d/%d", m.paginator.CurrentPage(), m.paginator.TotalPages())))
+			b.WriteString("\n")
+		}
+
+		if entry := m.selectedEntry(); entry != nil {
+			b.WriteString("\n")
+			b.WriteString(m.renderDetails(entry))
+		}
+	}
+
+	if m.Message != "" {
+		b.WriteString("\n")
+		b.WriteString(RenderMessage(m.Message, m.MessageErr))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	switch m.confirm {
+	case trashConfirmPurge:
+		b.WriteString(RenderConfirmPrompt("Permanently delete this entry?"))
+	case trashConfirmEmpty:
+		b.WriteString(RenderConfirmPrompt(fmt.Sprintf("Permanently delete all %d entries?", len(m.entries))))
+	default:
+		bindings := []key.Binding{TrashKeys.Up, TrashKeys.Down}
+		if len(m.entries) > 0 {
+			bindings = append(bindings, TrashKeys.Restore, TrashKeys.Purge, TrashKeys.Empty)
+		}
+		bindings = append(bindings, TrashKeys.Cancel)
+		b.WriteString(RenderHelpLine(bindings...))
+	}
+
+	return styles.App.Render(b.String())
+}
+
+// maxDetailLinks limits how many recorded incoming links are listed
+const maxDetailLinks = 5
+
+func (m *TrashModel) renderDetails(entry *application.TrashEntry) string {
+	var b strings.Builder
+
+	b.WriteString(styles.InputLabel.Render("Original: "))
+	b.WriteString(entry.OriginalPath)
+	b.WriteString("\n")
+	b.WriteString(styles.InputLabel.Render("Deleted:  "))
+	b.WriteString(entry.DeletedAt.Local().Format("2006-01-02 15:04"))
+	b.WriteString("\n")
+
+	if len(entry.IncomingLinks) == 0 {
+		b.WriteString(styles.MutedText.Render("No incoming links"))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	b.WriteString(styles.InputLabel.Render(fmt.Sprintf("Incoming links (%d):", len(entry.IncomingLinks))))
+	b.WriteString("\n")
+	for i, link := range entry.IncomingLinks {
+		if i == maxDetailLinks {
+			b.WriteString(styles.MutedText.Render(fmt.Sprintf("  ... and %d more", len(entry.IncomingLinks)-maxDetailLinks)))
+			b.WriteString("\n")
+			break
+		}
+		b.WriteString(styles.MutedText.Render(fmt.Sprintf("  %s  %s", link.SourcePath, link.LinkText)))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// formatAge renders a duration as a coarse "n units ago" string
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
+
+// SwitchToTrashMsg requests switching to the trash view
+type SwitchToTrashMsg struct{}
@@ -67,12 +67,14 @@ func (m *UnarchiveModel) doUnarchive() tea.Msg {
 
 	return UnarchiveSuccessMsg{
 		Message: result.Message,
+		Report:  result.Report,
 	}
 }
 
 // UnarchiveSuccessMsg indicates successful unarchiving
 type UnarchiveSuccessMsg struct {
 	Message string
+	Report  *application.OperationReport
 }
 
 // UnarchiveErrMsg indicates an error during unarchiving
@@ -0,0 +1,52 @@
+package watcher
+
+import (
+	"context"
+	"time"
+
+	"libraio/internal/domain"
+	"libraio/internal/ports"
+)
+
+// Poller implements ports.VaultWatcher by running an incremental sync at a
+// fixed interval. Polling needs no platform support and no extra
+// dependencies, and incremental syncs only list folders that changed.
+type Poller struct {
+	syncer   ports.IndexSyncer
+	interval time.Duration
+}
+
+// Ensure Poller implements VaultWatcher
+var _ ports.VaultWatcher = (*Poller)(nil)
+
+// NewPoller creates a watcher that checks the vault through syncer every interval
+func NewPoller(syncer ports.IndexSyncer, interval time.Duration) *Poller {
+	return &Poller{
+		syncer:   syncer,
+		interval: interval,
+	}
+}
+
+// Watch checks the vault every interval until ctx is cancelled. A check that
+// overruns the interval delays the next one rather than queueing more.
+func (p *Poller) Watch(ctx context.Context, onEvent func(domain.WatchEvent)) error {
+	timer := time.NewTimer(p.interval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		stats, err := p.syncer.Sync(nil)
+		switch {
+		case err != nil:
+			onEvent(domain.WatchEvent{Err: err})
+		case stats.HasChanges():
+			onEvent(domain.WatchEvent{Stats: stats})
+		}
+		timer.Reset(p.interval)
+	}
+}
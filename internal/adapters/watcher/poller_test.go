@@ -0,0 +1,77 @@
+package watcher
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"libraio/internal/domain"
+)
+
+// fakeSyncer replays a fixed sequence of sync results, then reports no changes
+type fakeSyncer struct {
+	results []domain.WatchEvent
+	calls   int
+}
+
+func (s *fakeSyncer) Sync(progress domain.SyncProgressFunc) (*domain.SyncStats, error) {
+	s.calls++
+	if len(s.results) == 0 {
+		return &domain.SyncStats{}, nil
+	}
+	next := s.results[0]
+	s.results = s.results[1:]
+	return next.Stats, next.Err
+}
+
+func TestPoller_ReportsChangesAndErrorsOnly(t *testing.T) {
+	syncer := &fakeSyncer{results: []domain.WatchEvent{
+		{Stats: &domain.SyncStats{}},
+		{Stats: &domain.SyncStats{NodesAdded: 1, Changed: []string{"notes.md"}}},
+		{Err: errors.New("vault locked")},
+		{Stats: &domain.SyncStats{NodesMoved: 1}},
+	}}
+	poller := NewPoller(syncer, time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var events []domain.WatchEvent
+	err := poller.Watch(ctx, func(e domain.WatchEvent) {
+		events = append(events, e)
+		if len(events) == 3 {
+			cancel()
+		}
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected Watch to stop with the context, got %v", err)
+	}
+
+	if len(events) != 3 {
+		t.Fatalf("expected 3 events, got %+v", events)
+	}
+	if events[0].Stats == nil || events[0].Stats.NodesAdded != 1 {
+		t.Errorf("first event should be the added note, got %+v", events[0])
+	}
+	if events[1].Err == nil {
+		t.Errorf("second event should be the failed check, got %+v", events[1])
+	}
+	if events[2].Stats == nil || events[2].Stats.NodesMoved != 1 {
+		t.Errorf("third event should be the move, got %+v", events[2])
+	}
+	if syncer.calls != 4 {
+		t.Errorf("expected 4 checks, got %d", syncer.calls)
+	}
+}
+
+func TestPoller_StopsBeforeFirstCheck(t *testing.T) {
+	syncer := &fakeSyncer{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := NewPoller(syncer, time.Hour).Watch(ctx, func(domain.WatchEvent) {}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if syncer.calls != 0 {
+		t.Errorf("cancelled watch should not check the vault, got %d checks", syncer.calls)
+	}
+}
@@ -14,6 +14,7 @@ import (
 type ArchiveItemResult struct {
 	OriginalID   string
 	ArchivedItem *domain.Item
+	Report       *domain.OperationReport
 	Message      string
 }
 
@@ -72,7 +73,7 @@ func (c *ArchiveItemCommand) Execute(ctx context.Context) (*ArchiveItemResult, e
 		return nil, err
 	}
 
-	archivedItem, err := c.repo.ArchiveItem(c.ItemID)
+	archivedItem, report, err := c.repo.ArchiveItem(c.ItemID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to archive item: %w", err)
 	}
@@ -80,6 +81,7 @@ func (c *ArchiveItemCommand) Execute(ctx context.Context) (*ArchiveItemResult, e
 	return &ArchiveItemResult{
 		OriginalID:   c.ItemID,
 		ArchivedItem: archivedItem,
+		Report:       report,
 		Message:      fmt.Sprintf("Archived %s -> %s", c.ItemID, archivedItem.ID),
 	}, nil
 }
@@ -88,6 +90,7 @@ func (c *ArchiveItemCommand) Execute(ctx context.Context) (*ArchiveItemResult, e
 type ArchiveCategoryResult struct {
 	OriginalCategoryID string
 	ArchivedItems      []*domain.Item
+	Report             *domain.OperationReport
 	Message            string
 }
 
@@ -138,7 +141,7 @@ func (c *ArchiveCategoryCommand) Execute(ctx context.Context) (*ArchiveCategoryR
 		return nil, err
 	}
 
-	archivedItems, err := c.repo.ArchiveCategory(c.CategoryID)
+	archivedItems, report, err := c.repo.ArchiveCategory(c.CategoryID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to archive category: %w", err)
 	}
@@ -146,6 +149,7 @@ func (c *ArchiveCategoryCommand) Execute(ctx context.Context) (*ArchiveCategoryR
 	return &ArchiveCategoryResult{
 		OriginalCategoryID: c.CategoryID,
 		ArchivedItems:      archivedItems,
+		Report:             report,
 		Message:            fmt.Sprintf("Archived %d items from %s", len(archivedItems), c.CategoryID),
 	}, nil
 }
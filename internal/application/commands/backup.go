@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"libraio/internal/application"
+	"libraio/internal/domain"
+	"libraio/internal/ports"
+)
+
+// ListBackupsCommand lists snapshots taken before bulk operations
+type ListBackupsCommand struct {
+	repo ports.VaultRepository
+}
+
+// NewListBackupsCommand creates a new ListBackupsCommand
+func NewListBackupsCommand(repo ports.VaultRepository) *ListBackupsCommand {
+	return &ListBackupsCommand{repo: repo}
+}
+
+// Execute runs the list backups command
+func (c *ListBackupsCommand) Execute(ctx context.Context) ([]domain.Snapshot, error) {
+	return c.repo.ListBackups()
+}
+
+// RestoreBackupResult contains the result of a snapshot restore
+type RestoreBackupResult struct {
+	Snapshot *domain.Snapshot
+	Report   *domain.OperationReport
+	Message  string
+}
+
+// RestoreBackupCommand writes the files in a snapshot back into the vault
+type RestoreBackupCommand struct {
+	repo       ports.VaultRepository
+	SnapshotID string
+}
+
+// NewRestoreBackupCommand creates a new RestoreBackupCommand
+func NewRestoreBackupCommand(repo ports.VaultRepository, snapshotID string) *RestoreBackupCommand {
+	return &RestoreBackupCommand{
+		repo:       repo,
+		SnapshotID: snapshotID,
+	}
+}
+
+// Validate checks if the restore operation is valid
+func (c *RestoreBackupCommand) Validate() error {
+	if c.SnapshotID == "" {
+		return &application.ValidationError{
+			Field:   "snapshot_id",
+			Message: "snapshot ID is required",
+		}
+	}
+	return nil
+}
+
+// Execute runs the restore backup command
+func (c *RestoreBackupCommand) Execute(ctx context.Context) (*RestoreBackupResult, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	snapshot, report, err := c.repo.RestoreBackup(c.SnapshotID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore %s: %w", c.SnapshotID, err)
+	}
+
+	return &RestoreBackupResult{
+		Snapshot: snapshot,
+		Report:   report,
+		Message:  fmt.Sprintf("Restored %d files from %s", len(snapshot.Files), snapshot.ID),
+	}, nil
+}
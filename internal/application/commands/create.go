@@ -41,6 +41,7 @@ func DetermineCreateMode(parentType domain.IDType) (CreateMode, error) {
 // CreateScopeResult contains the result of creating a scope
 type CreateScopeResult struct {
 	Scope   *domain.Scope
+	Report  *domain.OperationReport
 	Message string
 }
 
@@ -69,13 +70,14 @@ func (c *CreateScopeCommand) Execute(ctx context.Context) (*CreateScopeResult, e
 		return nil, err
 	}
 
-	scope, err := c.repo.CreateScope(c.Description)
+	scope, report, err := c.repo.CreateScope(c.Description)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create scope: %w", err)
 	}
 
 	return &CreateScopeResult{
 		Scope:   scope,
+		Report:  report,
 		Message: fmt.Sprintf("Created scope: %s %s", scope.ID, scope.Name),
 	}, nil
 }
@@ -83,6 +85,7 @@ func (c *CreateScopeCommand) Execute(ctx context.Context) (*CreateScopeResult, e
 // CreateAreaResult contains the result of creating an area
 type CreateAreaResult struct {
 	Area    *domain.Area
+	Report  *domain.OperationReport
 	Message string
 }
 
@@ -119,13 +122,14 @@ func (c *CreateAreaCommand) Execute(ctx context.Context) (*CreateAreaResult, err
 		return nil, err
 	}
 
-	area, err := c.repo.CreateArea(c.ScopeID, c.Description)
+	area, report, err := c.repo.CreateArea(c.ScopeID, c.Description)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create area: %w", err)
 	}
 
 	return &CreateAreaResult{
 		Area:    area,
+		Report:  report,
 		Message: fmt.Sprintf("Created area: %s %s", area.ID, area.Name),
 	}, nil
 }
@@ -133,6 +137,7 @@ func (c *CreateAreaCommand) Execute(ctx context.Context) (*CreateAreaResult, err
 // CreateItemResult contains the result of creating an item
 type CreateItemResult struct {
 	Item    *domain.Item
+	Report  *domain.OperationReport
 	Message string
 }
 
@@ -169,13 +174,14 @@ func (c *CreateItemCommand) Execute(ctx context.Context) (*CreateItemResult, err
 		return nil, err
 	}
 
-	item, err := c.repo.CreateItem(c.CategoryID, c.Description)
+	item, report, err := c.repo.CreateItem(c.CategoryID, c.Description)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create item: %w", err)
 	}
 
 	return &CreateItemResult{
 		Item:    item,
+		Report:  report,
 		Message: fmt.Sprintf("Created item: %s %s", item.ID, item.Name),
 	}, nil
 }
@@ -183,6 +189,7 @@ func (c *CreateItemCommand) Execute(ctx context.Context) (*CreateItemResult, err
 // CreateCategoryResult contains the result of creating a category
 type CreateCategoryResult struct {
 	Category *domain.Category
+	Report   *domain.OperationReport
 	Message  string
 }
 
@@ -219,13 +226,14 @@ func (c *CreateCategoryCommand) Execute(ctx context.Context) (*CreateCategoryRes
 		return nil, err
 	}
 
-	cat, err := c.repo.CreateCategory(c.AreaID, c.Description)
+	cat, report, err := c.repo.CreateCategory(c.AreaID, c.Description)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create category: %w", err)
 	}
 
 	return &CreateCategoryResult{
 		Category: cat,
+		Report:   report,
 		Message:  fmt.Sprintf("Created category: %s %s", cat.ID, cat.Name),
 	}, nil
 }
@@ -237,6 +245,7 @@ type CreateResult struct {
 	Message    string // Human-readable success message
 	Path       string // Path to the created entity's folder (only for items)
 	EntityType string // Type of entity created (scope, area, category, item)
+	Report     *domain.OperationReport
 }
 
 // CreateCommandFactory creates the appropriate create command based on parent type.
@@ -267,6 +276,7 @@ func (f *CreateCommandFactory) Execute(ctx context.Context, parentID, descriptio
 			ID:         result.Scope.ID,
 			Name:       result.Scope.Name,
 			Message:    result.Message,
+			Report:     result.Report,
 			EntityType: "scope",
 		}, nil
 	}
@@ -282,6 +292,7 @@ func (f *CreateCommandFactory) Execute(ctx context.Context, parentID, descriptio
 			ID:         result.Area.ID,
 			Name:       result.Area.Name,
 			Message:    result.Message,
+			Report:     result.Report,
 			EntityType: "area",
 		}, nil
 
@@ -295,6 +306,7 @@ func (f *CreateCommandFactory) Execute(ctx context.Context, parentID, descriptio
 			ID:         result.Category.ID,
 			Name:       result.Category.Name,
 			Message:    result.Message,
+			Report:     result.Report,
 			EntityType: "category",
 		}, nil
 
@@ -308,6 +320,7 @@ func (f *CreateCommandFactory) Execute(ctx context.Context, parentID, descriptio
 			ID:         result.Item.ID,
 			Name:       result.Item.Name,
 			Message:    result.Message,
+			Report:     result.Report,
 			Path:       result.Item.Path,
 			EntityType: "item",
 		}, nil
@@ -12,10 +12,12 @@ import (
 // DeleteResult contains the result of a delete operation
 type DeleteResult struct {
 	DeletedID string
+	Entry     *domain.TrashEntry
+	Report    *domain.OperationReport
 	Message   string
 }
 
-// DeleteCommand deletes an entity by ID
+// DeleteCommand moves an entity to the vault trash by ID
 type DeleteCommand struct {
 	repo ports.VaultRepository
 	ID   string
@@ -55,12 +57,20 @@ func (c *DeleteCommand) Execute(ctx context.Context) (*DeleteResult, error) {
 		return nil, err
 	}
 
-	if err := c.repo.Delete(c.ID); err != nil {
+	entry, report, err := c.repo.Delete(c.ID)
+	if err != nil {
 		return nil, fmt.Errorf("failed to delete %s: %w", c.ID, err)
 	}
 
+	message := fmt.Sprintf("Moved %s to trash", c.ID)
+	if n := len(entry.IncomingLinks); n > 0 {
+		message += fmt.Sprintf(" (%d incoming links recorded)", n)
+	}
+
 	return &DeleteResult{
 		DeletedID: c.ID,
-		Message:   fmt.Sprintf("Deleted %s", c.ID),
+		Entry:     entry,
+		Report:    report,
+		Message:   message,
 	}, nil
 }
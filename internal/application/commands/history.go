@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"context"
+
+	"libraio/internal/application"
+	"libraio/internal/domain"
+	"libraio/internal/ports"
+)
+
+// HistoryCommand lists version control commits created by vault operations
+type HistoryCommand struct {
+	vcs   ports.VersionControl
+	Limit int
+}
+
+// NewHistoryCommand creates a new HistoryCommand
+func NewHistoryCommand(vcs ports.VersionControl, limit int) *HistoryCommand {
+	return &HistoryCommand{
+		vcs:   vcs,
+		Limit: limit,
+	}
+}
+
+// Validate checks if the history query is valid
+func (c *HistoryCommand) Validate() error {
+	if c.Limit < 0 {
+		return &application.ValidationError{
+			Field:   "limit",
+			Message: "limit must not be negative",
+		}
+	}
+	return nil
+}
+
+// Execute runs the history command
+func (c *HistoryCommand) Execute(ctx context.Context) ([]domain.HistoryEntry, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c.vcs.History(c.Limit)
+}
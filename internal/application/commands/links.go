@@ -0,0 +1,156 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"libraio/internal/application"
+	"libraio/internal/domain"
+	"libraio/internal/ports"
+)
+
+// BrokenLinksCommand lists links whose target is gone or has changed identity
+type BrokenLinksCommand struct {
+	repo ports.VaultRepository
+}
+
+// NewBrokenLinksCommand creates a new BrokenLinksCommand
+func NewBrokenLinksCommand(repo ports.VaultRepository) *BrokenLinksCommand {
+	return &BrokenLinksCommand{repo: repo}
+}
+
+// Execute runs the broken links command
+func (c *BrokenLinksCommand) Execute(ctx context.Context) ([]domain.BrokenLink, error) {
+	return c.repo.BrokenLinks()
+}
+
+// FixLinksResult contains the result of a fix links operation
+type FixLinksResult struct {
+	Fixed   int // Links rewritten to their fix
+	Report  *domain.OperationReport
+	Message string
+}
+
+// FixLinksCommand repoints broken links at the fixes found for them
+type FixLinksCommand struct {
+	repo  ports.VaultRepository
+	Links []domain.BrokenLink
+}
+
+// NewFixLinksCommand creates a new FixLinksCommand; links without a fix are skipped
+func NewFixLinksCommand(repo ports.VaultRepository, links []domain.BrokenLink) *FixLinksCommand {
+	return &FixLinksCommand{
+		repo:  repo,
+		Links: links,
+	}
+}
+
+// Validate checks if the fix links operation is valid
+func (c *FixLinksCommand) Validate() error {
+	for _, link := range c.Links {
+		if link.Fixable() {
+			return nil
+		}
+	}
+	return &application.ValidationError{
+		Field:   "links",
+		Message: "no broken links with a known fix",
+	}
+}
+
+// Execute runs the fix links command
+func (c *FixLinksCommand) Execute(ctx context.Context) (*FixLinksResult, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	report, err := c.repo.FixLinks(c.Links)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fix links: %w", err)
+	}
+
+	fixed := report.LinksRewritten()
+	return &FixLinksResult{
+		Fixed:   fixed,
+		Report:  report,
+		Message: fmt.Sprintf("Fixed %s", domain.Pluralize(fixed, "broken link")),
+	}, nil
+}
+
+// NodeLinksCommand lists the links into and out of a tree node
+type NodeLinksCommand struct {
+	repo ports.VaultRepository
+	Node *domain.TreeNode
+}
+
+// NewNodeLinksCommand creates a new NodeLinksCommand
+func NewNodeLinksCommand(repo ports.VaultRepository, node *domain.TreeNode) *NodeLinksCommand {
+	return &NodeLinksCommand{
+		repo: repo,
+		Node: node,
+	}
+}
+
+// Validate checks if the node links query is valid
+func (c *NodeLinksCommand) Validate() error {
+	if c.Node == nil || c.Node.Path == "" {
+		return &application.ValidationError{
+			Field:   "node",
+			Message: "node is required",
+		}
+	}
+	return nil
+}
+
+// Execute runs the node links command
+func (c *NodeLinksCommand) Execute(ctx context.Context) (*domain.NodeLinks, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c.repo.NodeLinks(c.Node)
+}
+
+// LinkGraphCommand exports the links between the JD entities of the vault
+type LinkGraphCommand struct {
+	repo    ports.VaultRepository
+	Options domain.GraphOptions
+}
+
+// NewLinkGraphCommand creates a new LinkGraphCommand
+func NewLinkGraphCommand(repo ports.VaultRepository, opts domain.GraphOptions) *LinkGraphCommand {
+	return &LinkGraphCommand{
+		repo:    repo,
+		Options: opts,
+	}
+}
+
+// Validate checks if the link graph query is valid
+func (c *LinkGraphCommand) Validate() error {
+	if c.Options.Level < domain.IDTypeScope || c.Options.Level > domain.IDTypeItem {
+		return &application.ValidationError{
+			Field:   "level",
+			Message: "level must be scope, area, category or item",
+		}
+	}
+	if c.Options.Scope != "" && domain.ParseIDType(c.Options.Scope) == domain.IDTypeUnknown {
+		return &application.ValidationError{
+			Field:   "scope",
+			Message: fmt.Sprintf("invalid JD ID: %s", c.Options.Scope),
+		}
+	}
+	if c.Options.MinWeight < 0 {
+		return &application.ValidationError{
+			Field:   "min-weight",
+			Message: "minimum weight cannot be negative",
+		}
+	}
+	return nil
+}
+
+// Execute runs the link graph command
+func (c *LinkGraphCommand) Execute(ctx context.Context) (*domain.LinkGraph, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c.repo.LinkGraph(c.Options)
+}
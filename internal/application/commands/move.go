@@ -13,6 +13,7 @@ import (
 type MoveItemResult struct {
 	OriginalID string
 	MovedItem  *domain.Item
+	Report     *domain.OperationReport
 	Message    string
 }
 
@@ -75,7 +76,7 @@ func (c *MoveItemCommand) Execute(ctx context.Context) (*MoveItemResult, error)
 		return nil, err
 	}
 
-	item, err := c.repo.MoveItem(c.SourceItemID, c.DestinationCatID)
+	item, report, err := c.repo.MoveItem(c.SourceItemID, c.DestinationCatID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to move item: %w", err)
 	}
@@ -83,6 +84,7 @@ func (c *MoveItemCommand) Execute(ctx context.Context) (*MoveItemResult, error)
 	return &MoveItemResult{
 		OriginalID: c.SourceItemID,
 		MovedItem:  item,
+		Report:     report,
 		Message:    fmt.Sprintf("Moved to %s %s", item.ID, item.Name),
 	}, nil
 }
@@ -91,6 +93,7 @@ func (c *MoveItemCommand) Execute(ctx context.Context) (*MoveItemResult, error)
 type MoveCategoryResult struct {
 	OriginalID    string
 	MovedCategory *domain.Category
+	Report        *domain.OperationReport
 	Message       string
 }
 
@@ -153,7 +156,7 @@ func (c *MoveCategoryCommand) Execute(ctx context.Context) (*MoveCategoryResult,
 		return nil, err
 	}
 
-	cat, err := c.repo.MoveCategory(c.SourceCatID, c.DestinationArea)
+	cat, report, err := c.repo.MoveCategory(c.SourceCatID, c.DestinationArea)
 	if err != nil {
 		return nil, fmt.Errorf("failed to move category: %w", err)
 	}
@@ -161,6 +164,7 @@ func (c *MoveCategoryCommand) Execute(ctx context.Context) (*MoveCategoryResult,
 	return &MoveCategoryResult{
 		OriginalID:    c.SourceCatID,
 		MovedCategory: cat,
+		Report:        report,
 		Message:       fmt.Sprintf("Moved to %s %s", cat.ID, cat.Name),
 	}, nil
 }
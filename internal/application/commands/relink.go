@@ -0,0 +1,58 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"libraio/internal/application"
+	"libraio/internal/domain"
+	"libraio/internal/ports"
+)
+
+// RelinkResult contains the result of a relink operation
+type RelinkResult struct {
+	Report  *domain.OperationReport
+	Message string
+}
+
+// RelinkCommand rewrites links to folders moved or renamed outside libraio
+type RelinkCommand struct {
+	repo  ports.VaultRepository
+	Moves []domain.NodeMove
+}
+
+// NewRelinkCommand creates a new RelinkCommand for moves reported by an index sync
+func NewRelinkCommand(repo ports.VaultRepository, moves []domain.NodeMove) *RelinkCommand {
+	return &RelinkCommand{
+		repo:  repo,
+		Moves: moves,
+	}
+}
+
+// Validate checks if the relink operation is valid
+func (c *RelinkCommand) Validate() error {
+	if len(c.Moves) == 0 {
+		return &application.ValidationError{
+			Field:   "moves",
+			Message: "no moves to relink",
+		}
+	}
+	return nil
+}
+
+// Execute runs the relink command
+func (c *RelinkCommand) Execute(ctx context.Context) (*RelinkResult, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	report, err := c.repo.RelinkMoves(c.Moves)
+	if err != nil {
+		return nil, fmt.Errorf("failed to relink: %w", err)
+	}
+
+	return &RelinkResult{
+		Report:  report,
+		Message: "Updated links to moved folders",
+	}, nil
+}
@@ -14,6 +14,7 @@ import (
 type RenameResult struct {
 	OriginalID string
 	NewName    string
+	Report     *domain.OperationReport
 	Message    string
 }
 
@@ -75,14 +76,15 @@ func (c *RenameCommand) Execute(ctx context.Context) (*RenameResult, error) {
 	newDescription := strings.TrimSpace(c.NewDescription)
 	idType := domain.ParseIDType(c.ID)
 
+	var report *domain.OperationReport
 	var err error
 	switch idType {
 	case domain.IDTypeItem:
-		_, err = c.repo.RenameItem(c.ID, newDescription)
+		_, report, err = c.repo.RenameItem(c.ID, newDescription)
 	case domain.IDTypeCategory:
-		_, err = c.repo.RenameCategory(c.ID, newDescription)
+		_, report, err = c.repo.RenameCategory(c.ID, newDescription)
 	case domain.IDTypeArea:
-		_, err = c.repo.RenameArea(c.ID, newDescription)
+		_, report, err = c.repo.RenameArea(c.ID, newDescription)
 	}
 
 	if err != nil {
@@ -92,6 +94,7 @@ func (c *RenameCommand) Execute(ctx context.Context) (*RenameResult, error) {
 	return &RenameResult{
 		OriginalID: c.ID,
 		NewName:    newDescription,
+		Report:     report,
 		Message:    fmt.Sprintf("Renamed %s to %s", c.ID, newDescription),
 	}, nil
 }
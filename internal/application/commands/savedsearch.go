@@ -0,0 +1,174 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"libraio/internal/application"
+	"libraio/internal/domain"
+	"libraio/internal/ports"
+)
+
+// ListSavedSearchesCommand lists the saved searches of the vault
+type ListSavedSearchesCommand struct {
+	repo ports.VaultRepository
+}
+
+// NewListSavedSearchesCommand creates a new ListSavedSearchesCommand
+func NewListSavedSearchesCommand(repo ports.VaultRepository) *ListSavedSearchesCommand {
+	return &ListSavedSearchesCommand{repo: repo}
+}
+
+// Execute runs the list saved searches command
+func (c *ListSavedSearchesCommand) Execute(ctx context.Context) ([]domain.SavedSearch, error) {
+	return c.repo.ListSavedSearches()
+}
+
+// SavedSearchResult contains the result of saving or deleting a search
+type SavedSearchResult struct {
+	Search  domain.SavedSearch
+	Message string
+}
+
+// SaveSearchCommand saves a query under a name, replacing the search with
+// that name if any
+type SaveSearchCommand struct {
+	repo  ports.VaultRepository
+	Name  string
+	Query string
+}
+
+// NewSaveSearchCommand creates a new SaveSearchCommand
+func NewSaveSearchCommand(repo ports.VaultRepository, name, query string) *SaveSearchCommand {
+	return &SaveSearchCommand{
+		repo:  repo,
+		Name:  strings.TrimSpace(name),
+		Query: strings.TrimSpace(query),
+	}
+}
+
+// Validate checks if the search can be saved
+func (c *SaveSearchCommand) Validate() error {
+	if err := validateSavedSearchName(c.Name); err != nil {
+		return err
+	}
+	query, err := application.ParseQuery(c.Query)
+	if err != nil {
+		return err
+	}
+	if query.Root == nil {
+		return &application.ValidationError{
+			Field:   "query",
+			Message: "query is required",
+		}
+	}
+	return nil
+}
+
+// Execute runs the save search command
+func (c *SaveSearchCommand) Execute(ctx context.Context) (*SavedSearchResult, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	search := domain.SavedSearch{Name: c.Name, Query: c.Query}
+	if err := c.repo.SaveSearch(search); err != nil {
+		return nil, err
+	}
+	return &SavedSearchResult{
+		Search:  search,
+		Message: fmt.Sprintf("Saved search %q: %s", search.Name, search.Query),
+	}, nil
+}
+
+// DeleteSavedSearchCommand deletes a saved search by name, ignoring case
+type DeleteSavedSearchCommand struct {
+	repo ports.VaultRepository
+	Name string
+}
+
+// NewDeleteSavedSearchCommand creates a new DeleteSavedSearchCommand
+func NewDeleteSavedSearchCommand(repo ports.VaultRepository, name string) *DeleteSavedSearchCommand {
+	return &DeleteSavedSearchCommand{
+		repo: repo,
+		Name: strings.TrimSpace(name),
+	}
+}
+
+// Validate checks if the delete operation is valid
+func (c *DeleteSavedSearchCommand) Validate() error {
+	return validateSavedSearchName(c.Name)
+}
+
+// Execute runs the delete saved search command
+func (c *DeleteSavedSearchCommand) Execute(ctx context.Context) (*SavedSearchResult, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	search, err := c.repo.DeleteSavedSearch(c.Name)
+	if err != nil {
+		return nil, err
+	}
+	return &SavedSearchResult{
+		Search:  *search,
+		Message: fmt.Sprintf("Deleted saved search %q: %s", search.Name, search.Query),
+	}, nil
+}
+
+// RunSavedSearchCommand runs a saved search by name, ignoring case, against
+// the index, see QuerySearchCommand
+type RunSavedSearchCommand struct {
+	repo  ports.VaultRepository
+	Name  string
+	Limit int // Maximum results; 0 for all
+}
+
+// NewRunSavedSearchCommand creates a new RunSavedSearchCommand
+func NewRunSavedSearchCommand(repo ports.VaultRepository, name string, limit int) *RunSavedSearchCommand {
+	return &RunSavedSearchCommand{
+		repo:  repo,
+		Name:  strings.TrimSpace(name),
+		Limit: limit,
+	}
+}
+
+// Validate checks if the saved search can run
+func (c *RunSavedSearchCommand) Validate() error {
+	return validateSavedSearchName(c.Name)
+}
+
+// Execute runs the saved search
+func (c *RunSavedSearchCommand) Execute(ctx context.Context) ([]SearchResult, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	searches, err := c.repo.ListSavedSearches()
+	if err != nil {
+		return nil, err
+	}
+	i := domain.FindSavedSearch(searches, c.Name)
+	if i < 0 {
+		return nil, fmt.Errorf("saved search not found: %s", c.Name)
+	}
+	return NewQuerySearchCommand(c.repo, searches[i].Query, c.Limit).Execute(ctx)
+}
+
+// validateSavedSearchName checks a saved search name is given and fits on a line
+func validateSavedSearchName(name string) error {
+	if name == "" {
+		return &application.ValidationError{
+			Field:   "name",
+			Message: "name is required",
+		}
+	}
+	if strings.ContainsAny(name, "\r\n") {
+		return &application.ValidationError{
+			Field:   "name",
+			Message: "name must fit on one line",
+		}
+	}
+	return nil
+}
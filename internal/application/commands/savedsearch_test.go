@@ -0,0 +1,34 @@
+package commands
+
+import "testing"
+
+func TestSaveSearchCommand_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		search  string
+		query   string
+		wantErr string
+	}{
+		{name: "valid", search: "Tagged #todo", query: "tag:todo"},
+		{name: "plain words", search: "Theatre", query: "theatre season"},
+		{name: "missing name", search: "  ", query: "tag:todo", wantErr: "name is required"},
+		{name: "multi-line name", search: "Tagged\n#todo", query: "tag:todo", wantErr: "one line"},
+		{name: "missing query", search: "Everything", query: "", wantErr: "query is required"},
+		{name: "invalid query", search: "Broken", query: "type:folder", wantErr: "type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewSaveSearchCommand(nil, tt.search, tt.query).Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -2,9 +2,13 @@ package commands
 
 import (
 	"context"
+	"fmt"
+	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
+	"libraio/internal/application"
 	"libraio/internal/domain"
 	"libraio/internal/ports"
 )
@@ -15,13 +19,16 @@ type SearchResult struct {
 	Score int
 }
 
-// SearchCommand searches the vault with fuzzy matching
+// SearchCommand searches the vault with fuzzy matching on names,
+// or with full-text matching on markdown bodies when Content is set
 type SearchCommand struct {
-	repo  ports.VaultRepository
-	Query string
+	repo    ports.VaultRepository
+	Query   string
+	Content bool
+	Limit   int // Maximum content results; 0 uses the repository default
 }
 
-// NewSearchCommand creates a new SearchCommand
+// NewSearchCommand creates a new SearchCommand that matches names
 func NewSearchCommand(repo ports.VaultRepository, query string) *SearchCommand {
 	return &SearchCommand{
 		repo:  repo,
@@ -29,12 +36,26 @@ func NewSearchCommand(repo ports.VaultRepository, query string) *SearchCommand {
 	}
 }
 
+// NewContentSearchCommand creates a new SearchCommand that matches file contents
+func NewContentSearchCommand(repo ports.VaultRepository, query string, limit int) *SearchCommand {
+	return &SearchCommand{
+		repo:    repo,
+		Query:   query,
+		Content: true,
+		Limit:   limit,
+	}
+}
+
 // Execute runs the search command and returns scored, sorted results
 func (c *SearchCommand) Execute(ctx context.Context) ([]SearchResult, error) {
 	if len(c.Query) < 2 {
 		return nil, nil
 	}
 
+	if c.Content {
+		return c.searchContent()
+	}
+
 	results, err := c.repo.Search(c.Query)
 	if err != nil {
 		return nil, err
@@ -43,6 +64,168 @@ func (c *SearchCommand) Execute(ctx context.Context) ([]SearchResult, error) {
 	return FuzzySort(results, c.Query), nil
 }
 
+// searchContent keeps the repository's ranking, exposing it as descending scores
+func (c *SearchCommand) searchContent() ([]SearchResult, error) {
+	results, err := c.repo.SearchContent(c.Query, c.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	scored := make([]SearchResult, len(results))
+	for i, r := range results {
+		scored[i] = SearchResult{
+			SearchResult: r,
+			Score:        len(results) - i,
+		}
+	}
+	return scored, nil
+}
+
+// ItemSearchCommand finds the items whose notes carry some tags and
+// properties, optionally narrowed to names fuzzily matching Query. It runs
+// as the query "type:item in:SCOPE tag:TAG key:value WORDS".
+type ItemSearchCommand struct {
+	repo      ports.VaultRepository
+	ItemQuery domain.ItemQuery
+	Query     string
+}
+
+// NewItemSearchCommand creates a new ItemSearchCommand
+func NewItemSearchCommand(repo ports.VaultRepository, itemQuery domain.ItemQuery, query string) *ItemSearchCommand {
+	return &ItemSearchCommand{
+		repo:      repo,
+		ItemQuery: itemQuery,
+		Query:     query,
+	}
+}
+
+// Validate checks if the item search is valid
+func (c *ItemSearchCommand) Validate() error {
+	if c.ItemQuery.Scope != "" && domain.ParseIDType(c.ItemQuery.Scope) == domain.IDTypeUnknown {
+		return &application.ValidationError{
+			Field:   "scope",
+			Message: fmt.Sprintf("invalid JD ID: %s", c.ItemQuery.Scope),
+		}
+	}
+	if c.ItemQuery.IsEmpty() && c.Query == "" {
+		return &application.ValidationError{
+			Field:   "query",
+			Message: "give a tag, a property or a name to search for",
+		}
+	}
+	return nil
+}
+
+// Execute runs the item search. Without a name, items come in vault order.
+func (c *ItemSearchCommand) Execute(ctx context.Context) ([]SearchResult, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return NewQuerySearchCommand(c.repo, "", 0).run(c.searchQuery())
+}
+
+// searchQuery returns the query the item search runs. Its terms are built
+// rather than parsed, so property keys such as "type" stay properties.
+func (c *ItemSearchCommand) searchQuery() *domain.SearchQuery {
+	terms := []domain.QueryNode{&domain.TypeFilter{Type: domain.IDTypeItem}}
+	if c.ItemQuery.Scope != "" {
+		terms = append(terms, &domain.InFilter{ID: c.ItemQuery.Scope})
+	}
+	for _, tag := range c.ItemQuery.Tags {
+		tag = strings.Trim(strings.TrimPrefix(strings.TrimSpace(tag), "#"), "/")
+		terms = append(terms, &domain.TagFilter{Tag: tag})
+	}
+	for _, p := range c.ItemQuery.Properties {
+		terms = append(terms, &domain.PropertyFilter{Property: p})
+	}
+	for _, word := range strings.Fields(c.Query) {
+		terms = append(terms, &domain.TextNode{Text: word})
+	}
+	return &domain.SearchQuery{Root: &domain.AndNode{Terms: terms}}
+}
+
+// QuerySearchCommand searches the index with a query such as
+// "type:item tag:finance -archived", see application.ParseQuery. Matches are
+// ranked by how well their names match the query's words, then in vault order.
+type QuerySearchCommand struct {
+	repo  ports.VaultRepository
+	Query string
+	Limit int // Maximum results; 0 for all
+}
+
+// NewQuerySearchCommand creates a new QuerySearchCommand
+func NewQuerySearchCommand(repo ports.VaultRepository, query string, limit int) *QuerySearchCommand {
+	return &QuerySearchCommand{
+		repo:  repo,
+		Query: query,
+		Limit: limit,
+	}
+}
+
+// Validate checks if the query parses
+func (c *QuerySearchCommand) Validate() error {
+	_, err := application.ParseQuery(c.Query)
+	return err
+}
+
+// Execute runs the query against the index
+func (c *QuerySearchCommand) Execute(ctx context.Context) ([]SearchResult, error) {
+	query, err := application.ParseQuery(c.Query)
+	if err != nil {
+		return nil, err
+	}
+	return c.run(query)
+}
+
+// run searches the index with a parsed query
+func (c *QuerySearchCommand) run(query *domain.SearchQuery) ([]SearchResult, error) {
+	if query.Root == nil {
+		return nil, nil
+	}
+
+	nodes, err := c.repo.SearchEntries(query, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	words := query.Words()
+	var results []SearchResult
+	for i := range nodes {
+		r := SearchResult{SearchResult: entryResult(c.repo.VaultPath(), &nodes[i])}
+		for _, word := range words {
+			r.Score += max(FuzzyScore(r.Name, word), FuzzyScore(r.ID, word))
+		}
+		results = append(results, r)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	if c.Limit > 0 && len(results) > c.Limit {
+		results = results[:c.Limit]
+	}
+	return results, nil
+}
+
+// entryResult converts an indexed entry to a search result. A file takes the
+// ID of the JD folder holding it, for navigation.
+func entryResult(vaultPath string, node *domain.IndexNode) domain.SearchResult {
+	result := domain.SearchResult{
+		Type:        node.JDType,
+		ID:          domain.PathEntity(node.Path),
+		Name:        filepath.Base(node.Path),
+		Path:        filepath.Join(vaultPath, node.Path),
+		MatchedText: filepath.Base(node.Path),
+	}
+	switch {
+	case !node.IsDir:
+		result.Type = domain.IDTypeFile
+	case node.JDType != domain.IDTypeUnknown:
+		result.Name = node.Name
+	}
+	return result
+}
+
 // FuzzyScore calculates a relevance score for how well target matches query
 func FuzzyScore(target, query string) int {
 	target = strings.ToLower(target)
@@ -0,0 +1,44 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"libraio/internal/application"
+	"libraio/internal/domain"
+	"libraio/internal/ports"
+)
+
+// FindStaleCommand finds the items, categories and areas a vault clean-up
+// should look at
+type FindStaleCommand struct {
+	repo    ports.VaultRepository
+	Options domain.StaleOptions
+}
+
+// NewFindStaleCommand creates a new FindStaleCommand
+func NewFindStaleCommand(repo ports.VaultRepository, opts domain.StaleOptions) *FindStaleCommand {
+	return &FindStaleCommand{
+		repo:    repo,
+		Options: opts,
+	}
+}
+
+// Validate checks if the stale review is valid
+func (c *FindStaleCommand) Validate() error {
+	if c.Options.Scope != "" && domain.ParseIDType(c.Options.Scope) == domain.IDTypeUnknown {
+		return &application.ValidationError{
+			Field:   "scope",
+			Message: fmt.Sprintf("invalid JD ID: %s", c.Options.Scope),
+		}
+	}
+	return nil
+}
+
+// Execute runs the stale review
+func (c *FindStaleCommand) Execute(ctx context.Context) ([]domain.StaleEntry, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c.repo.FindStale(c.Options)
+}
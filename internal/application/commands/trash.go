@@ -0,0 +1,150 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"libraio/internal/application"
+	"libraio/internal/domain"
+	"libraio/internal/ports"
+)
+
+// ListTrashCommand lists entities in the vault trash
+type ListTrashCommand struct {
+	repo ports.VaultRepository
+}
+
+// NewListTrashCommand creates a new ListTrashCommand
+func NewListTrashCommand(repo ports.VaultRepository) *ListTrashCommand {
+	return &ListTrashCommand{repo: repo}
+}
+
+// Execute runs the list trash command
+func (c *ListTrashCommand) Execute(ctx context.Context) ([]domain.TrashEntry, error) {
+	return c.repo.ListTrash()
+}
+
+// RestoreTrashResult contains the result of a restore operation
+type RestoreTrashResult struct {
+	Entry   *domain.TrashEntry
+	Report  *domain.OperationReport
+	Message string
+}
+
+// RestoreTrashCommand restores a trashed entity to its original location
+type RestoreTrashCommand struct {
+	repo    ports.VaultRepository
+	TrashID string
+}
+
+// NewRestoreTrashCommand creates a new RestoreTrashCommand
+func NewRestoreTrashCommand(repo ports.VaultRepository, trashID string) *RestoreTrashCommand {
+	return &RestoreTrashCommand{
+		repo:    repo,
+		TrashID: trashID,
+	}
+}
+
+// Validate checks if the restore operation is valid
+func (c *RestoreTrashCommand) Validate() error {
+	if c.TrashID == "" {
+		return &application.ValidationError{
+			Field:   "trash_id",
+			Message: "trash ID is required",
+		}
+	}
+	return nil
+}
+
+// Execute runs the restore command
+func (c *RestoreTrashCommand) Execute(ctx context.Context) (*RestoreTrashResult, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	entry, report, err := c.repo.RestoreTrash(c.TrashID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RestoreTrashResult{
+		Entry:   entry,
+		Report:  report,
+		Message: fmt.Sprintf("Restored %s to %s", entry.ID, entry.OriginalPath),
+	}, nil
+}
+
+// EmptyTrashResult contains the result of an empty trash operation
+type EmptyTrashResult struct {
+	Purged  []domain.TrashEntry
+	Message string
+}
+
+// EmptyTrashCommand permanently removes entries from the vault trash.
+// When OlderThan is zero every entry is removed; otherwise only entries
+// that have been in the trash longer than OlderThan are purged.
+type EmptyTrashCommand struct {
+	repo      ports.VaultRepository
+	OlderThan time.Duration
+	now       func() time.Time
+}
+
+// NewEmptyTrashCommand creates a new EmptyTrashCommand
+func NewEmptyTrashCommand(repo ports.VaultRepository, olderThan time.Duration) *EmptyTrashCommand {
+	return &EmptyTrashCommand{
+		repo:      repo,
+		OlderThan: olderThan,
+		now:       time.Now,
+	}
+}
+
+// Validate checks if the empty trash operation is valid
+func (c *EmptyTrashCommand) Validate() error {
+	if c.OlderThan < 0 {
+		return &application.ValidationError{
+			Field:   "older_than",
+			Message: "age must not be negative",
+		}
+	}
+	return nil
+}
+
+// Execute runs the empty trash command
+func (c *EmptyTrashCommand) Execute(ctx context.Context) (*EmptyTrashResult, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	entries, err := c.repo.ListTrash()
+	if err != nil {
+		return nil, err
+	}
+
+	if c.OlderThan > 0 {
+		entries = domain.ExpiredTrashEntries(entries, c.OlderThan, c.now())
+	}
+
+	var purged []domain.TrashEntry
+	for _, entry := range entries {
+		if err := c.repo.PurgeTrash(entry.TrashID); err != nil {
+			return &EmptyTrashResult{
+				Purged:  purged,
+				Message: fmt.Sprintf("Purged %d of %d trash entries", len(purged), len(entries)),
+			}, fmt.Errorf("failed to purge %s: %w", entry.TrashID, err)
+		}
+		purged = append(purged, entry)
+	}
+
+	message := "Trash is already empty"
+	if len(purged) > 0 {
+		message = fmt.Sprintf("Purged %d trash entries", len(purged))
+	} else if c.OlderThan > 0 {
+		message = "No trash entries older than " + c.OlderThan.String()
+	}
+
+	return &EmptyTrashResult{
+		Purged:  purged,
+		Message: message,
+	}, nil
+}
@@ -0,0 +1,36 @@
+package commands
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRestoreTrashCommand_Validate(t *testing.T) {
+	if err := (&RestoreTrashCommand{}).Validate(); err == nil || !contains(err.Error(), "trash ID is required") {
+		t.Errorf("expected missing trash ID error, got %v", err)
+	}
+	if err := (&RestoreTrashCommand{TrashID: "20250102T150405-S01.11.15"}).Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestEmptyTrashCommand_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		olderThan time.Duration
+		wantErr   bool
+	}{
+		{name: "empty everything", olderThan: 0, wantErr: false},
+		{name: "age based purge", olderThan: 30 * 24 * time.Hour, wantErr: false},
+		{name: "negative age", olderThan: -time.Hour, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&EmptyTrashCommand{OlderThan: tt.olderThan}).Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -13,6 +13,7 @@ import (
 type UnarchiveItemResult struct {
 	ArchiveItemID string
 	RestoredItems []string
+	Report        *domain.OperationReport
 	Message       string
 }
 
@@ -68,7 +69,7 @@ func (c *UnarchiveItemCommand) Execute(ctx context.Context) (*UnarchiveItemResul
 		return nil, fmt.Errorf("failed to determine destination: %w", err)
 	}
 
-	restoredItems, err := c.repo.UnarchiveItems(c.ArchiveItemID, dstCategoryID)
+	restoredItems, report, err := c.repo.UnarchiveItems(c.ArchiveItemID, dstCategoryID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to unarchive: %w", err)
 	}
@@ -81,6 +82,7 @@ func (c *UnarchiveItemCommand) Execute(ctx context.Context) (*UnarchiveItemResul
 	return &UnarchiveItemResult{
 		ArchiveItemID: c.ArchiveItemID,
 		RestoredItems: names,
+		Report:        report,
 		Message:       fmt.Sprintf("Restored %d items from %s", len(restoredItems), c.ArchiveItemID),
 	}, nil
 }
@@ -0,0 +1,359 @@
+package application
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"libraio/internal/domain"
+)
+
+// A search query is made of terms, all of which must match unless joined by
+// OR. A term is a word, a "quoted phrase", a filter such as type:item, or a
+// group in parentheses; -term or NOT term negates it. AND binds tighter than
+// OR, so "a b OR c" is "(a b) OR c".
+//
+// Filters:
+//
+//	type:item       scope, area, category, item or file
+//	in:S01.11       within the folder of a JD ID
+//	scope:S02       within a scope
+//	ext:pdf         files with an extension
+//	tag:finance     tagged, or tagged with a tag nested under it
+//	modified:>DATE  modified after (>), from (>=), before (<), up to (<=) or on a
+//	                date: 2025-06-01, today, yesterday, or a while ago such as
+//	                7d, 2w, 3m or 1y, which compares with that moment
+//	has:backlinks   linked to from elsewhere; also has:tags and has:properties
+//	key:value       any other key matches a frontmatter property: status:active
+//
+// A word holding a colon is only a property filter when its key starts with a
+// letter and a value follows, so "10:30" and "Meeting:" stay words.
+
+// hasValues lists what has: accepts
+var hasValues = []string{"backlinks", "tags", "properties"}
+
+// ParseQuery parses a search query
+func ParseQuery(input string) (*domain.SearchQuery, error) {
+	tokens, err := lexQuery(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &queryParser{tokens: tokens}
+	if len(tokens) == 0 {
+		return &domain.SearchQuery{}, nil
+	}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, queryError("unexpected %q", p.tokens[p.pos].text)
+	}
+	return &domain.SearchQuery{Root: root}, nil
+}
+
+// queryError returns a ValidationError for an invalid query
+func queryError(format string, args ...any) error {
+	return &ValidationError{Field: "query", Message: fmt.Sprintf(format, args...)}
+}
+
+// queryTokenKind is the kind of a query token
+type queryTokenKind int
+
+const (
+	tokenWord queryTokenKind = iota
+	tokenPhrase
+	tokenFilter
+	tokenNot
+	tokenOr
+	tokenOpen
+	tokenClose
+)
+
+// queryToken is a token of a query. A filter keeps its key lower-cased and its
+// value unquoted.
+type queryToken struct {
+	kind  queryTokenKind
+	text  string
+	key   string
+	value string
+}
+
+// lexQuery splits a query into tokens
+func lexQuery(input string) ([]queryToken, error) {
+	var tokens []queryToken
+	runes := []rune(input)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, queryToken{kind: tokenOpen, text: "("})
+			i++
+		case r == ')':
+			tokens = append(tokens, queryToken{kind: tokenClose, text: ")"})
+			i++
+		case r == '-' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) && runes[i+1] != ')':
+			tokens = append(tokens, queryToken{kind: tokenNot, text: "-"})
+			i++
+		case r == '"':
+			text, next, err := lexQuoted(runes, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, queryToken{kind: tokenPhrase, text: text})
+			i = next
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && runes[i] != '(' && runes[i] != ')' && runes[i] != '"' {
+				i++
+			}
+			word := string(runes[start:i])
+			key, value, isFilter := strings.Cut(word, ":")
+			quoted := i < len(runes) && runes[i] == '"'
+			switch {
+			case isFilter && isFilterKey(key, value != "" || quoted):
+				if value == "" && quoted {
+					quoted, next, err := lexQuoted(runes, i)
+					if err != nil {
+						return nil, err
+					}
+					value, i = quoted, next
+				}
+				if value == "" {
+					return nil, queryError("%s: needs a value", key)
+				}
+				tokens = append(tokens, queryToken{kind: tokenFilter, text: word, key: strings.ToLower(key), value: value})
+			case word == "OR":
+				tokens = append(tokens, queryToken{kind: tokenOr, text: word})
+			case word == "NOT":
+				tokens = append(tokens, queryToken{kind: tokenNot, text: word})
+			case word == "AND":
+				// Terms are joined by AND anyway
+			default:
+				tokens = append(tokens, queryToken{kind: tokenWord, text: word})
+			}
+		}
+	}
+	return tokens, nil
+}
+
+// filterKeys lists the keys of the filters other than properties
+var filterKeys = []string{"type", "in", "scope", "ext", "tag", "has", "modified"}
+
+// isFilterKey reports whether key starts a filter: one of filterKeys, or a
+// property key given a value. Other words holding a colon, such as "10:30" or
+// "Meeting:", are words.
+func isFilterKey(key string, hasValue bool) bool {
+	if slices.Contains(filterKeys, strings.ToLower(key)) {
+		return true
+	}
+	first, _ := utf8.DecodeRuneInString(key)
+	return hasValue && unicode.IsLetter(first)
+}
+
+// lexQuoted reads the quoted text opening at runes[i], returning it and the
+// position after its closing quote
+func lexQuoted(runes []rune, i int) (string, int, error) {
+	for j := i + 1; j < len(runes); j++ {
+		if runes[j] == '"' {
+			return string(runes[i+1 : j]), j + 1, nil
+		}
+	}
+	return "", 0, queryError("unclosed quote")
+}
+
+// queryParser parses query tokens by recursive descent
+type queryParser struct {
+	tokens []queryToken
+	pos    int
+}
+
+// peek returns the kind of the next token, or -1 at the end
+func (p *queryParser) peek() queryTokenKind {
+	if p.pos >= len(p.tokens) {
+		return -1
+	}
+	return p.tokens[p.pos].kind
+}
+
+// parseOr parses terms joined by OR
+func (p *queryParser) parseOr() (domain.QueryNode, error) {
+	var terms []domain.QueryNode
+	for {
+		term, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+		if p.peek() != tokenOr {
+			break
+		}
+		p.pos++
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return &domain.OrNode{Terms: terms}, nil
+}
+
+// parseAnd parses terms up to an OR, a closing parenthesis or the end
+func (p *queryParser) parseAnd() (domain.QueryNode, error) {
+	var terms []domain.QueryNode
+	for kind := p.peek(); kind != -1 && kind != tokenOr && kind != tokenClose; kind = p.peek() {
+		term, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+	}
+	switch len(terms) {
+	case 0:
+		if p.pos < len(p.tokens) {
+			return nil, queryError("missing term before %q", p.tokens[p.pos].text)
+		}
+		return nil, queryError("missing term at the end")
+	case 1:
+		return terms[0], nil
+	}
+	return &domain.AndNode{Terms: terms}, nil
+}
+
+// parseUnary parses a term, negated or not
+func (p *queryParser) parseUnary() (domain.QueryNode, error) {
+	if p.peek() == tokenNot {
+		p.pos++
+		if kind := p.peek(); kind == -1 || kind == tokenOr || kind == tokenClose {
+			return nil, queryError("nothing to negate")
+		}
+		term, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &domain.NotNode{Term: term}, nil
+	}
+	return p.parsePrimary()
+}
+
+// parsePrimary parses a word, phrase, filter or group
+func (p *queryParser) parsePrimary() (domain.QueryNode, error) {
+	token := p.tokens[p.pos]
+	p.pos++
+	switch token.kind {
+	case tokenOpen:
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != tokenClose {
+			return nil, queryError("unclosed parenthesis")
+		}
+		p.pos++
+		return node, nil
+	case tokenPhrase:
+		if strings.TrimSpace(token.text) == "" {
+			return nil, queryError("empty phrase")
+		}
+		return &domain.TextNode{Text: token.text, Phrase: true}, nil
+	case tokenFilter:
+		return parseFilter(token.key, token.value)
+	default:
+		return &domain.TextNode{Text: token.text}, nil
+	}
+}
+
+// relativeDatePattern matches a while before now: 7d, 2w, 3m, 1y
+var relativeDatePattern = regexp.MustCompile(`^(\d+)([dwmy])$`)
+
+// parseFilter parses the value of a filter
+func parseFilter(key, value string) (domain.QueryNode, error) {
+	switch key {
+	case "type":
+		for _, t := range []domain.IDType{domain.IDTypeScope, domain.IDTypeArea, domain.IDTypeCategory, domain.IDTypeItem, domain.IDTypeFile} {
+			if strings.EqualFold(value, t.String()) {
+				return &domain.TypeFilter{Type: t}, nil
+			}
+		}
+		return nil, queryError("type: wants scope, area, category, item or file, not %q", value)
+	case "in":
+		if domain.ParseIDType(value) == domain.IDTypeUnknown {
+			return nil, queryError("in: invalid JD ID %q", value)
+		}
+		return &domain.InFilter{ID: value}, nil
+	case "scope":
+		if domain.ParseIDType(value) != domain.IDTypeScope {
+			return nil, queryError("scope: invalid scope ID %q", value)
+		}
+		return &domain.ScopeFilter{ID: value}, nil
+	case "ext":
+		return &domain.ExtFilter{Ext: strings.ToLower(strings.TrimPrefix(value, "."))}, nil
+	case "tag":
+		tag := strings.Trim(strings.TrimPrefix(value, "#"), "/")
+		if tag == "" {
+			return nil, queryError("tag: needs a tag")
+		}
+		return &domain.TagFilter{Tag: tag}, nil
+	case "has":
+		for _, what := range hasValues {
+			if strings.EqualFold(value, what) {
+				return &domain.HasFilter{What: what}, nil
+			}
+		}
+		return nil, queryError("has: wants %s, not %q", strings.Join(hasValues, ", "), value)
+	case "modified":
+		return parseModified(value)
+	default:
+		return &domain.PropertyFilter{Property: domain.Property{Key: key, Value: value}}, nil
+	}
+}
+
+// parseModified parses the comparison and date of a modified: filter
+func parseModified(value string) (domain.QueryNode, error) {
+	f := &domain.ModifiedFilter{Op: "="}
+	for _, op := range []string{">=", "<=", ">", "<", "="} {
+		if rest, ok := strings.CutPrefix(value, op); ok {
+			f.Op, value = op, rest
+			break
+		}
+	}
+	f.Value = value
+
+	if m := relativeDatePattern.FindStringSubmatch(value); m != nil {
+		n, err := strconv.Atoi(m[1])
+		if err != nil {
+			return nil, queryError("modified: invalid date %q", value)
+		}
+		f.Relative = true
+		switch m[2] {
+		case "d":
+			f.Ago.Days = n
+		case "w":
+			f.Ago.Days = 7 * n
+		case "m":
+			f.Ago.Months = n
+		case "y":
+			f.Ago.Years = n
+		}
+		return f, nil
+	}
+	switch strings.ToLower(value) {
+	case "today":
+		f.Relative, f.WholeDay = true, true
+		return f, nil
+	case "yesterday":
+		f.Relative, f.WholeDay, f.Ago.Days = true, true, 1
+		return f, nil
+	}
+	date, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return nil, queryError("modified: wants a date such as 2025-06-01, today or 7d, not %q", value)
+	}
+	f.Date = date
+	return f, nil
+}
@@ -0,0 +1,137 @@
+package application
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"libraio/internal/domain"
+)
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string // The parsed query in query syntax
+		plain bool
+	}{
+		{name: "empty", input: "  ", want: "", plain: true},
+		{name: "words", input: "theatre season", want: "theatre season", plain: true},
+		{name: "ID", input: "S01.10-19", want: "S01.10-19", plain: true},
+		{name: "phrase", input: `"opening night" cast`, want: `"opening night" cast`},
+		{name: "filters", input: "type:Item in:S01.11 scope:S02 ext:.PDF tag:#finance/", want: "type:item in:S01.11 scope:S02 ext:pdf tag:finance"},
+		{name: "quoted value", input: `status:"on hold"`, want: `status:"on hold"`},
+		{name: "negation", input: "-tag:draft NOT ext:md", want: "-tag:draft -ext:md"},
+		{name: "OR binds looser than AND", input: "a b OR c", want: "(a b OR c)"},
+		{name: "groups", input: "type:file (ext:pdf OR ext:png) -(in:S01.11)", want: "type:file (ext:pdf OR ext:png) -in:S01.11"},
+		{name: "explicit AND", input: "a AND b", want: "a b", plain: true},
+		{name: "modified", input: "modified:>=2025-06-01 modified:<7d modified:today", want: "modified:>=2025-06-01 modified:<7d modified:today"},
+		{name: "has", input: "has:Backlinks", want: "has:backlinks"},
+		{name: "colons in words", input: "Meeting: Q1 10:30", want: "Meeting: Q1 10:30", plain: true},
+		{name: "property", input: "Status:active", want: "status:active"},
+		{name: "parentheses in names", input: "Report (draft)", want: "Report draft", plain: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := ParseQuery(tt.input)
+			if err != nil {
+				t.Fatalf("ParseQuery(%q) error = %v", tt.input, err)
+			}
+			if got := q.String(); got != tt.want {
+				t.Errorf("ParseQuery(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+			if got := q.Plain(); got != tt.plain {
+				t.Errorf("Plain() = %v, want %v", got, tt.plain)
+			}
+		})
+	}
+}
+
+func TestParseQuery_Errors(t *testing.T) {
+	for _, input := range []string{
+		"type:",
+		"type:folder",
+		"in:theatre",
+		"scope:S01.11",
+		"has:children",
+		"modified:>june",
+		`"unclosed`,
+		"(a OR b",
+		"a OR",
+		"OR a",
+		"a )",
+		"NOT",
+		`""`,
+	} {
+		t.Run(input, func(t *testing.T) {
+			_, err := ParseQuery(input)
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Errorf("ParseQuery(%q) error = %v, want a ValidationError", input, err)
+			}
+		})
+	}
+}
+
+func TestQuery_Match(t *testing.T) {
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.Local)
+	theatre := filepath.Join("S01 Personal", "S01.10-19 Lifestyle", "S01.11 Entertainment", "S01.11.15 Theatre")
+	item := &domain.SearchEntry{
+		IndexNode:  domain.IndexNode{Path: theatre, JDID: "S01.11.15", JDType: domain.IDTypeItem, Name: "Theatre", IsDir: true, Mtime: now.AddDate(0, 0, -3).UnixNano()},
+		Tags:       []string{"project/stage"},
+		Properties: []domain.Property{{Key: "status", Value: "Active"}},
+		Backlinks:  2,
+	}
+	poster := &domain.SearchEntry{
+		IndexNode: domain.IndexNode{Path: filepath.Join(theatre, "Opening night.PDF"), Mtime: time.Date(2025, 6, 1, 9, 0, 0, 0, time.Local).UnixNano()},
+	}
+
+	tests := []struct {
+		query  string
+		item   bool
+		poster bool
+	}{
+		{"theatre", true, false},
+		{"thtr", true, false},
+		{`"opening night"`, false, true},
+		{`"night opening"`, false, false},
+		{"S01.11.15", true, false},
+		{"type:item", true, false},
+		{"type:file", false, true},
+		{"in:S01.11", true, true},
+		{"in:S01.12", false, false},
+		{"scope:S01", true, true},
+		{"ext:pdf", false, true},
+		{"tag:project", true, false},
+		{"tag:proj", false, false},
+		{"status:active", true, false},
+		{"has:backlinks", true, false},
+		{"has:tags OR ext:pdf", true, true},
+		{"-has:backlinks", false, true},
+		{"in:S01.11 -type:item", false, true},
+		{"modified:2025-06-01", false, true},
+		{"modified:>2025-06-01", true, false},
+		{"modified:>=2025-06-01", true, true},
+		{"modified:<2025-06-01", false, false},
+		{"modified:<=2025-06-01", false, true},
+		{"modified:>7d", true, false},
+		{"modified:<1w", false, true},
+		{"modified:3d", true, false},
+		{"modified:today", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, err := ParseQuery(tt.query)
+			if err != nil {
+				t.Fatalf("ParseQuery(%q) error = %v", tt.query, err)
+			}
+			if got := q.Match(item, now); got != tt.item {
+				t.Errorf("Match(item) = %v, want %v", got, tt.item)
+			}
+			if got := q.Match(poster, now); got != tt.poster {
+				t.Errorf("Match(poster) = %v, want %v", got, tt.poster)
+			}
+		})
+	}
+}
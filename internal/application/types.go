@@ -16,12 +16,16 @@ const (
 
 // Re-export domain types for use by adapters
 type (
-	TreeNode     = domain.TreeNode
-	SearchResult = domain.SearchResult
-	Scope        = domain.Scope
-	Area         = domain.Area
-	Category     = domain.Category
-	Item         = domain.Item
+	TreeNode        = domain.TreeNode
+	SearchResult    = domain.SearchResult
+	Scope           = domain.Scope
+	Area            = domain.Area
+	Category        = domain.Category
+	Item            = domain.Item
+	OperationReport = domain.OperationReport
+	TrashEntry      = domain.TrashEntry
+	Snapshot        = domain.Snapshot
+	HistoryEntry    = domain.HistoryEntry
 )
 
 // ParseIDType determines the type of a Johnny Decimal ID string
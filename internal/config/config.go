@@ -1,9 +1,18 @@
 package config
 
-import "os"
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"libraio/internal/domain"
+)
 
 const DefaultVaultPath = "~/Documents/bag_of_holding"
 
+// DefaultWatchInterval is how often the vault is checked for outside changes
+const DefaultWatchInterval = 2 * time.Second
+
 // VaultPath returns the vault path from LIBRAIO_VAULT env var,
 // falling back to DefaultVaultPath.
 func VaultPath() string {
@@ -12,3 +21,54 @@ func VaultPath() string {
 	}
 	return DefaultVaultPath
 }
+
+// BackupsEnabled reports whether LIBRAIO_BACKUP turns on automatic
+// snapshots before bulk operations.
+func BackupsEnabled() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv("LIBRAIO_BACKUP"))
+	return enabled
+}
+
+// BackupDir returns the snapshot directory from LIBRAIO_BACKUP_DIR,
+// or "" to use the default inside the vault.
+func BackupDir() string {
+	return os.Getenv("LIBRAIO_BACKUP_DIR")
+}
+
+// BackupRetention returns the snapshot retention policy, overriding the
+// defaults with LIBRAIO_BACKUP_KEEP (count) and LIBRAIO_BACKUP_MAX_AGE (days).
+func BackupRetention() domain.RetentionPolicy {
+	policy := domain.DefaultRetentionPolicy
+	if keep, err := strconv.Atoi(os.Getenv("LIBRAIO_BACKUP_KEEP")); err == nil && keep >= 0 {
+		policy.Keep = keep
+	}
+	if days, err := strconv.Atoi(os.Getenv("LIBRAIO_BACKUP_MAX_AGE")); err == nil && days >= 0 {
+		policy.MaxAge = time.Duration(days) * 24 * time.Hour
+	}
+	return policy
+}
+
+// GitEnabled reports whether LIBRAIO_GIT turns on committing each vault
+// change when the vault is inside a git work tree.
+func GitEnabled() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv("LIBRAIO_GIT"))
+	return enabled
+}
+
+// KeepMtime reports whether LIBRAIO_KEEP_MTIME keeps the modification time
+// of files whose links are rewritten.
+func KeepMtime() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv("LIBRAIO_KEEP_MTIME"))
+	return enabled
+}
+
+// WatchInterval returns how often the TUI checks the vault for changes made
+// outside libraio, from LIBRAIO_WATCH_INTERVAL in seconds. Zero turns
+// watching off.
+func WatchInterval() time.Duration {
+	seconds, err := strconv.ParseFloat(os.Getenv("LIBRAIO_WATCH_INTERVAL"), 64)
+	if err != nil || seconds < 0 {
+		return DefaultWatchInterval
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Snapshot describes a compressed backup of vault files taken before a bulk operation
+type Snapshot struct {
+	ID        string     `json:"id"`        // Archive base name, e.g. "20250102T150405-archive-S01.11"
+	Operation string     `json:"operation"` // Operation that triggered the snapshot, e.g. "archive"
+	TargetID  string     `json:"target_id"` // Johnny Decimal ID the operation acted on
+	CreatedAt time.Time  `json:"created_at"`
+	Files     []string   `json:"files"`           // Vault-relative paths of the files in the archive
+	Size      int64      `json:"size"`            // Compressed size in bytes
+	Moves     []NodeMove `json:"moves,omitempty"` // Entries the operation moved, in order, undone on restore
+}
+
+// NewSnapshotID builds a sortable, filesystem-safe snapshot name
+func NewSnapshotID(operation, targetID string, createdAt time.Time) string {
+	return fmt.Sprintf("%s-%s-%s", createdAt.UTC().Format("20060102T150405"), operation, targetID)
+}
+
+// RetentionPolicy limits how many snapshots are kept.
+// A zero Keep or MaxAge disables that limit.
+type RetentionPolicy struct {
+	Keep   int           // Maximum number of snapshots to keep
+	MaxAge time.Duration // Maximum age of a snapshot
+}
+
+// DefaultRetentionPolicy keeps the 20 most recent snapshots from the last 30 days
+var DefaultRetentionPolicy = RetentionPolicy{
+	Keep:   20,
+	MaxAge: 30 * 24 * time.Hour,
+}
+
+// Expired returns the snapshots that fall outside the policy, oldest first
+func (p RetentionPolicy) Expired(snapshots []Snapshot, now time.Time) []Snapshot {
+	sorted := slices.Clone(snapshots)
+	slices.SortFunc(sorted, func(a, b Snapshot) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+
+	var expired []Snapshot
+	for i := len(sorted) - 1; i >= 0; i-- {
+		s := sorted[i]
+		tooMany := p.Keep > 0 && i >= p.Keep
+		tooOld := p.MaxAge > 0 && now.Sub(s.CreatedAt) > p.MaxAge
+		if tooMany || tooOld {
+			expired = append(expired, s)
+		}
+	}
+	return expired
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetentionPolicy_Expired(t *testing.T) {
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	snapshots := []Snapshot{
+		{ID: "a", CreatedAt: now.Add(-1 * day)},
+		{ID: "b", CreatedAt: now.Add(-40 * day)},
+		{ID: "c", CreatedAt: now.Add(-2 * day)},
+		{ID: "d", CreatedAt: now.Add(-3 * day)},
+	}
+
+	tests := []struct {
+		name   string
+		policy RetentionPolicy
+		want   []string
+	}{
+		{name: "no limits", policy: RetentionPolicy{}, want: nil},
+		{name: "keep count", policy: RetentionPolicy{Keep: 2}, want: []string{"b", "d"}},
+		{name: "max age", policy: RetentionPolicy{MaxAge: 30 * day}, want: []string{"b"}},
+		{name: "both", policy: RetentionPolicy{Keep: 3, MaxAge: 2*day + time.Hour}, want: []string{"b", "d"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.policy.Expired(snapshots, now)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expired() returned %d snapshots, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i, s := range got {
+				if s.ID != tt.want[i] {
+					t.Errorf("Expired()[%d] = %s, want %s", i, s.ID, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestNewSnapshotID(t *testing.T) {
+	createdAt := time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)
+	if got := NewSnapshotID("archive", "S01.11", createdAt); got != "20250102T150405-archive-S01.11" {
+		t.Errorf("NewSnapshotID() = %q", got)
+	}
+}
@@ -0,0 +1,33 @@
+package domain
+
+// BrokenLinkKind is why a link no longer leads where it was written to
+type BrokenLinkKind string
+
+const (
+	BrokenLinkMissing  BrokenLinkKind = "missing"  // Nothing has the JD ID, note name or vault path linked to
+	BrokenLinkArchived BrokenLinkKind = "archived" // Links to an [Archived] name whose folder is gone
+	BrokenLinkReused   BrokenLinkKind = "reused"   // The JD ID now belongs to a folder with another description
+)
+
+// BrokenLink is a link, as indexed, whose target is gone or has changed
+// identity. Fix is where the rename history or a folder with the same
+// description says the link should point now.
+type BrokenLink struct {
+	SourcePath string         `json:"source_path"` // File holding the link
+	Line       int            `json:"line"`        // 1-based line of the link in SourcePath
+	LinkText   string         `json:"link_text"`   // Link as written, as in Edge.LinkText
+	Kind       BrokenLinkKind `json:"kind"`
+	Target     string         `json:"target"`            // Note name or vault path the link points to
+	Current    string         `json:"current,omitempty"` // Folder name now holding the ID, for reused IDs
+	Fix        string         `json:"fix,omitempty"`     // Vault path of the folder or file to link to instead; empty if unknown
+}
+
+// Fixable reports whether a replacement target is known
+func (l BrokenLink) Fixable() bool {
+	return l.Fix != ""
+}
+
+// FixName returns the note name a wiki link is retargeted to when fixed
+func (l BrokenLink) FixName() string {
+	return NoteName(l.Fix)
+}
@@ -0,0 +1,226 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// canvasString is a JSON string value in a canvas, spanning content[start:end]
+// inside its quotes
+type canvasString struct {
+	start, end int
+}
+
+// canvasNode holds the string members of a node in a canvas's "nodes" array
+type canvasNode map[string]canvasString
+
+// canvasNodes returns the nodes of an Obsidian canvas, or nil if the content
+// is not valid JSON
+func canvasNodes(content []byte) []canvasNode {
+	if !json.Valid(content) {
+		return nil
+	}
+	s := &jsonScanner{content: content}
+	var nodes []canvasNode
+	s.object(func(key string) {
+		if key != "nodes" || s.peek() != '[' {
+			s.value()
+			return
+		}
+		s.array(func() {
+			if s.peek() != '{' {
+				s.value()
+				return
+			}
+			node := canvasNode{}
+			s.object(func(key string) {
+				if s.peek() == '"' {
+					start, end := s.str()
+					node[key] = canvasString{start + 1, end - 1}
+					return
+				}
+				s.value()
+			})
+			nodes = append(nodes, node)
+		})
+	})
+	return nodes
+}
+
+// canvasParts returns the markdown of a canvas's text nodes and the paths of
+// its file nodes
+func canvasParts(content []byte) ([]linkText, []linkFile) {
+	var texts []linkText
+	var files []linkFile
+	for _, node := range canvasNodes(content) {
+		typ, ok := node["type"]
+		if !ok {
+			continue
+		}
+		switch string(decodeJSON(content, typ).text) {
+		case "text":
+			if text, ok := node["text"]; ok {
+				texts = append(texts, decodeJSON(content, text))
+			}
+		case "file":
+			if file, ok := node["file"]; ok {
+				files = append(files, linkFile{start: file.start, end: file.end, path: string(decodeJSON(content, file).text)})
+			}
+		}
+	}
+	return texts, files
+}
+
+// CanvasText returns the markdown of a canvas's text nodes, separated by blank
+// lines, for indexing its content
+func CanvasText(content []byte) string {
+	texts, _ := canvasParts(content)
+	parts := make([]string, len(texts))
+	for i, t := range texts {
+		parts[i] = string(t.text)
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// decodeJSON decodes a JSON string value of a canvas
+func decodeJSON(content []byte, s canvasString) linkText {
+	t := decodeQuoted(content, s.start, s.end, '\\', unescapeJSON)
+	t.quote = quoteJSON
+	return t
+}
+
+// unescapeJSON decodes an escape in a JSON string, joining surrogate pairs
+func unescapeJSON(content []byte, i int) ([]byte, int) {
+	switch c := content[i+1]; c {
+	case 'b':
+		return []byte{'\b'}, 2
+	case 'f':
+		return []byte{'\f'}, 2
+	case 'n':
+		return []byte{'\n'}, 2
+	case 'r':
+		return []byte{'\r'}, 2
+	case 't':
+		return []byte{'\t'}, 2
+	case 'u':
+		r, _ := hexRune(content, i+2, 4)
+		if r >= 0xd800 && r < 0xdc00 && i+11 < len(content) && content[i+6] == '\\' && content[i+7] == 'u' {
+			if low, ok := hexRune(content, i+8, 4); ok && low >= 0xdc00 && low < 0xe000 {
+				return runeBytes((r-0xd800)<<10 + (low - 0xdc00) + 0x10000), 12
+			}
+		}
+		return runeBytes(r), 6
+	default:
+		return []byte{c}, 2
+	}
+}
+
+// quoteJSON escapes s for a JSON string the way JSON.stringify does, as
+// Obsidian writes canvases
+func quoteJSON(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r == '\b':
+			b.WriteString(`\b`)
+		case r == '\f':
+			b.WriteString(`\f`)
+		case r < 0x20:
+			fmt.Fprintf(&b, `\u%04x`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// jsonScanner walks JSON already checked to be valid, keeping byte offsets
+type jsonScanner struct {
+	content []byte
+	pos     int
+}
+
+// peek skips whitespace and returns the next byte
+func (s *jsonScanner) peek() byte {
+	for s.pos < len(s.content) && strings.IndexByte(" \t\r\n", s.content[s.pos]) >= 0 {
+		s.pos++
+	}
+	if s.pos == len(s.content) {
+		return 0
+	}
+	return s.content[s.pos]
+}
+
+// str skips a string and returns its span, quotes included
+func (s *jsonScanner) str() (int, int) {
+	s.peek()
+	start := s.pos
+	for s.pos++; s.content[s.pos] != '"'; s.pos++ {
+		if s.content[s.pos] == '\\' {
+			s.pos++
+		}
+	}
+	s.pos++
+	return start, s.pos
+}
+
+// object walks an object, calling member with each key; member must consume
+// the value
+func (s *jsonScanner) object(member func(key string)) {
+	if s.peek() != '{' {
+		s.value()
+		return
+	}
+	s.pos++
+	for s.peek() != '}' {
+		start, end := s.str()
+		key := string(decodeJSON(s.content, canvasString{start + 1, end - 1}).text)
+		s.peek()
+		s.pos++ // ':'
+		member(key)
+		if s.peek() == ',' {
+			s.pos++
+		}
+	}
+	s.pos++
+}
+
+// array walks an array, calling element for each item; element must consume
+// the item
+func (s *jsonScanner) array(element func()) {
+	s.peek()
+	s.pos++
+	for s.peek() != ']' {
+		element()
+		if s.peek() == ',' {
+			s.pos++
+		}
+	}
+	s.pos++
+}
+
+// value skips any value
+func (s *jsonScanner) value() {
+	switch s.peek() {
+	case '{':
+		s.object(func(string) { s.value() })
+	case '[':
+		s.array(s.value)
+	case '"':
+		s.str()
+	default:
+		for s.pos < len(s.content) && strings.IndexByte(",]} \t\r\n", s.content[s.pos]) < 0 {
+			s.pos++
+		}
+	}
+}
@@ -0,0 +1,303 @@
+package domain
+
+import (
+	"bytes"
+	"strings"
+)
+
+// splitFrontmatter returns the span of a note's YAML frontmatter, between its
+// "---" delimiter lines, and the offset where the body starts. A note without
+// closed frontmatter has its body start at 0.
+func splitFrontmatter(content []byte) (start, end, body int) {
+	first := lineEndAt(content, 0)
+	if string(bytes.TrimRight(content[:first], " \t\r")) != "---" || first == len(content) {
+		return 0, 0, 0
+	}
+	for pos := first + 1; pos < len(content); {
+		lineEnd := lineEndAt(content, pos)
+		if line := string(bytes.TrimRight(content[pos:lineEnd], " \t\r")); line == "---" || line == "..." {
+			return first + 1, pos, min(lineEnd+1, len(content))
+		}
+		pos = lineEnd + 1
+	}
+	return 0, 0, 0
+}
+
+// frontmatterTexts returns the parts of a note's frontmatter links may be
+// written in, and the offset where the body starts. Quoted YAML scalars, as
+// Obsidian writes link properties (related: "[[S01.11.15 Theatre]]"), are
+// decoded; plain values and block scalars are taken as written. Keys are
+// skipped, as are comments.
+func frontmatterTexts(content []byte) ([]linkText, int) {
+	start, end, body := splitFrontmatter(content)
+	if body == 0 {
+		return nil, 0
+	}
+
+	var texts []linkText
+	plain := func(from, to int) {
+		if from < to {
+			texts = append(texts, linkText{text: content[from:to], base: from})
+		}
+	}
+
+	blockIndent := -1 // Indentation of the key owning a block scalar being read
+	for pos := start; pos < end; pos = lineEndAt(content, pos) + 1 {
+		lineEnd := lineEndAt(content, pos)
+		indent := 0
+		for pos+indent < lineEnd && content[pos+indent] == ' ' {
+			indent++
+		}
+		if blockIndent >= 0 {
+			if pos+indent == lineEnd || indent > blockIndent {
+				plain(pos+indent, lineEnd)
+				continue
+			}
+			blockIndent = -1
+		}
+
+		valueStart, segment := true, pos+indent
+		for i := pos + indent; i < lineEnd; {
+			c := content[i]
+			switch {
+			case valueStart && (c == '"' || c == '\''):
+				closing := closingQuote(content, i, lineEnd)
+				if closing < 0 {
+					i = lineEnd
+					continue
+				}
+				plain(segment, i)
+				texts = append(texts, yamlQuoted(content, i, closing))
+				i, segment, valueStart = closing+1, closing+1, false
+			case valueStart && (c == '|' || c == '>') && isBlockHeader(content[i+1:lineEnd]):
+				blockIndent = indent
+				plain(segment, i)
+				i, segment = lineEnd, lineEnd
+			case c == '#' && (i == pos || content[i-1] == ' ' || content[i-1] == '\t'):
+				plain(segment, i)
+				i, segment = lineEnd, lineEnd
+			case c == ' ' || c == '\t':
+				i++
+			case c == '-' && valueStart:
+				// A sequence entry marker keeps the value open; "-1" does not
+				valueStart = i+1 == lineEnd || content[i+1] == ' '
+				i++
+			case c == ':' && (i+1 == lineEnd || content[i+1] == ' '):
+				if segment < i && strings.TrimSpace(string(content[segment:i])) != "" && isKeyStart(content, pos+indent, segment) {
+					segment = i + 1 // The key is not a link
+				}
+				valueStart = true
+				i++
+			case c == '[' || c == '{' || c == ',':
+				valueStart = true
+				i++
+			default:
+				valueStart = false
+				i++
+			}
+		}
+		plain(segment, lineEnd)
+	}
+	return texts, body
+}
+
+// isKeyStart reports whether the text from segment up to a ": " is a mapping
+// key: it starts the line, after any sequence markers
+func isKeyStart(content []byte, lineStart, segment int) bool {
+	prefix := strings.TrimSpace(string(content[lineStart:segment]))
+	return strings.Trim(prefix, "- ") == ""
+}
+
+// isBlockHeader reports whether the rest of a line after "|" or ">" completes
+// a block scalar header: chomping and indentation indicators, then a comment
+func isBlockHeader(rest []byte) bool {
+	s := strings.TrimLeft(string(rest), "+-0123456789")
+	s = strings.TrimLeft(s, " \t")
+	return s == "" || s[0] == '#'
+}
+
+// closingQuote returns the offset of the quote closing the YAML scalar that
+// opens at i, or -1 if it does not close before lineEnd
+func closingQuote(content []byte, i, lineEnd int) int {
+	quote := content[i]
+	for j := i + 1; j < lineEnd; j++ {
+		switch {
+		case quote == '"' && content[j] == '\\':
+			j++
+		case content[j] == quote && quote == '\'' && j+1 < lineEnd && content[j+1] == '\'':
+			j++ // '' is an escaped quote
+		case content[j] == quote:
+			return j
+		}
+	}
+	return -1
+}
+
+// yamlQuoted decodes the YAML scalar quoted from open to closing
+func yamlQuoted(content []byte, open, closing int) linkText {
+	if content[open] == '\'' {
+		t := decodeQuoted(content, open+1, closing, '\'', func(content []byte, i int) ([]byte, int) {
+			return []byte{'\''}, 2
+		})
+		t.quote = func(s string) string { return strings.ReplaceAll(s, "'", "''") }
+		return t
+	}
+	t := decodeQuoted(content, open+1, closing, '\\', unescapeYAML)
+	t.quote = quoteYAML
+	return t
+}
+
+// unescapeYAML decodes an escape in a double-quoted YAML scalar
+func unescapeYAML(content []byte, i int) ([]byte, int) {
+	if i+1 >= len(content) {
+		return []byte{'\\'}, 1
+	}
+	switch c := content[i+1]; c {
+	case 'n':
+		return []byte{'\n'}, 2
+	case 't':
+		return []byte{'\t'}, 2
+	case 'r':
+		return []byte{'\r'}, 2
+	case '0':
+		return []byte{0}, 2
+	case 'x', 'u', 'U':
+		n := map[byte]int{'x': 2, 'u': 4, 'U': 8}[c]
+		if r, ok := hexRune(content, i+2, n); ok {
+			return runeBytes(r), 2 + n
+		}
+		return []byte{c}, 2
+	default:
+		return []byte{c}, 2
+	}
+}
+
+// quoteYAML escapes s for a double-quoted YAML scalar
+func quoteYAML(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '"', '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Property is a value a note's frontmatter gives a key. A list gives its key
+// a property per item.
+type Property struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// FrontmatterProperties returns the top-level properties of a note's
+// frontmatter, in order. Lists may be in block or flow style; quoted values
+// are decoded. Keys without a value, nested mappings and block scalars give
+// no properties, and empty values are dropped.
+func FrontmatterProperties(content []byte) []Property {
+	start, end, body := splitFrontmatter(content)
+	if body == 0 {
+		return nil
+	}
+
+	var properties []Property
+	key := ""
+	add := func(value string) {
+		if value = yamlScalar(value); value != "" {
+			properties = append(properties, Property{Key: key, Value: value})
+		}
+	}
+	inList := false // Reading the block sequence under key
+	for pos := start; pos < end; pos = lineEndAt(content, pos) + 1 {
+		line := strings.TrimRight(string(content[pos:lineEndAt(content, pos)]), " \t\r")
+		if inList {
+			item := strings.TrimLeft(line, " ")
+			if value, ok := strings.CutPrefix(item, "-"); ok && (value == "" || value[0] == ' ') {
+				add(value)
+				continue
+			}
+			if item == "" || item[0] == '#' || len(item) < len(line) {
+				continue
+			}
+			inList = false
+		}
+
+		k, value, ok := strings.Cut(line, ":")
+		if !ok || line[0] == ' ' || line[0] == '-' || line[0] == '#' {
+			continue
+		}
+		key = strings.Trim(strings.TrimSpace(k), `"'`)
+		value = strings.TrimSpace(value)
+		switch {
+		case value == "" || value[0] == '#':
+			inList = true
+		case value[0] == '[':
+			for _, item := range splitFlowSequence(value) {
+				add(item)
+			}
+		case value[0] == '{' || (value[0] == '|' || value[0] == '>') && isBlockHeader([]byte(value[1:])):
+		default:
+			add(value)
+		}
+	}
+	return properties
+}
+
+// FrontmatterAliases returns the other names a note's frontmatter gives it
+// under "aliases", or the older "alias": a list in block or flow style, or a
+// single value
+func FrontmatterAliases(content []byte) []string {
+	var aliases []string
+	for _, p := range FrontmatterProperties(content) {
+		if strings.EqualFold(p.Key, "aliases") || strings.EqualFold(p.Key, "alias") {
+			aliases = append(aliases, p.Value)
+		}
+	}
+	return aliases
+}
+
+// yamlScalar decodes a quoted YAML scalar, or trims a plain one of any
+// trailing comment
+func yamlScalar(value string) string {
+	value = strings.TrimSpace(value)
+	if value != "" && (value[0] == '"' || value[0] == '\'') {
+		if closing := closingQuote([]byte(value), 0, len(value)); closing > 0 {
+			return string(yamlQuoted([]byte(value), 0, closing).text)
+		}
+	}
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = value[:i]
+	}
+	return strings.TrimSpace(value)
+}
+
+// splitFlowSequence splits a YAML flow sequence, [a, "b, c"], into its items,
+// leaving commas inside quotes alone
+func splitFlowSequence(value string) []string {
+	value = strings.TrimPrefix(value, "[")
+	if i := strings.LastIndexByte(value, ']'); i >= 0 {
+		value = value[:i]
+	}
+	var items []string
+	start := 0
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '"', '\'':
+			if closing := closingQuote([]byte(value), i, len(value)); closing > 0 {
+				i = closing
+			}
+		case ',':
+			items = append(items, value[start:i])
+			start = i + 1
+		}
+	}
+	return append(items, value[start:])
+}
@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"cmp"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// GraphOptions selects and shapes the link graph between JD entities
+type GraphOptions struct {
+	Level     IDType // Level entities are collapsed to: scope, area, category or item
+	Scope     string // JD ID both ends of a link must lie within; empty for the whole vault
+	MinWeight int    // Edges with fewer links are dropped
+}
+
+// ParseGraphLevel parses a level name such as "category" for GraphOptions
+func ParseGraphLevel(name string) (IDType, error) {
+	for _, t := range []IDType{IDTypeScope, IDTypeArea, IDTypeCategory, IDTypeItem} {
+		if strings.EqualFold(name, t.String()) {
+			return t, nil
+		}
+	}
+	return IDTypeUnknown, fmt.Errorf("unknown graph level %q: want scope, area, category or item", name)
+}
+
+// GraphNode is a JD entity in the link graph
+type GraphNode struct {
+	ID   string `json:"id"`
+	Name string `json:"name"` // Description of the entity's folder, empty if it is not indexed
+	Type string `json:"type"` // "Scope", "Area", "Category" or "Item"
+}
+
+// Label returns the entity's folder name, or its ID if it has no description
+func (n GraphNode) Label() string {
+	if n.Name == "" {
+		return n.ID
+	}
+	return FormatFolderName(n.ID, n.Name)
+}
+
+// GraphEdge counts the links from notes in one entity to another
+type GraphEdge struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Weight int    `json:"weight"`
+}
+
+// LinkGraph is the directed graph of links between JD entities. Nodes are the
+// entities at either end of an edge, sorted by ID; edges are sorted by source,
+// then target.
+type LinkGraph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// BuildLinkGraph counts the links between the entities of the vault. A link
+// runs from the innermost JD folder holding its note to the entity it names,
+// or the innermost JD folder holding the path it points to; both ends are
+// then collapsed to opts.Level. Links within an entity, and links with an end
+// outside any JD folder, are left out. names maps JD IDs to descriptions.
+func BuildLinkGraph(edges []Edge, names map[string]string, opts GraphOptions) *LinkGraph {
+	if opts.Level == IDTypeUnknown {
+		opts.Level = IDTypeItem
+	}
+
+	weights := make(map[[2]string]int)
+	for _, edge := range edges {
+		source := PathEntity(edge.SourcePath)
+		target := edge.TargetJDID
+		if edge.TargetPath != "" {
+			target = PathEntity(edge.TargetPath)
+		}
+		if source == "" || target == "" {
+			continue
+		}
+		if opts.Scope != "" && (!IsWithin(source, opts.Scope) || !IsWithin(target, opts.Scope)) {
+			continue
+		}
+		source, target = collapseID(source, opts.Level), collapseID(target, opts.Level)
+		if source != target {
+			weights[[2]string{source, target}]++
+		}
+	}
+
+	graph := &LinkGraph{Nodes: []GraphNode{}, Edges: []GraphEdge{}}
+	ids := make(map[string]bool)
+	for ends, weight := range weights {
+		if weight < opts.MinWeight {
+			continue
+		}
+		graph.Edges = append(graph.Edges, GraphEdge{Source: ends[0], Target: ends[1], Weight: weight})
+		ids[ends[0]], ids[ends[1]] = true, true
+	}
+	slices.SortFunc(graph.Edges, func(a, b GraphEdge) int {
+		return cmp.Or(strings.Compare(a.Source, b.Source), strings.Compare(a.Target, b.Target))
+	})
+	for id := range ids {
+		graph.Nodes = append(graph.Nodes, GraphNode{ID: id, Name: names[id], Type: ParseIDType(id).String()})
+	}
+	slices.SortFunc(graph.Nodes, func(a, b GraphNode) int { return strings.Compare(a.ID, b.ID) })
+	return graph
+}
+
+// PathEntity returns the ID of the innermost JD folder a vault path lies in,
+// or "" if it is in none
+func PathEntity(relPath string) string {
+	parts := strings.Split(relPath, string(filepath.Separator))
+	for i := len(parts) - 1; i >= 0; i-- {
+		if id := ExtractID(parts[i]); ParseIDType(id) != IDTypeUnknown {
+			return id
+		}
+	}
+	return ""
+}
+
+// collapseID returns the ancestor of id at level, or id itself if it is at
+// that level or above it
+func collapseID(id string, level IDType) string {
+	hierarchy := GetIDHierarchy(id)
+	if i := int(level - IDTypeScope); i < len(hierarchy) {
+		return hierarchy[i]
+	}
+	return id
+}
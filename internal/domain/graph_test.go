@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestBuildLinkGraph(t *testing.T) {
+	theatre := filepath.Join("S01 Personal", "S01.10-19 Lifestyle", "S01.11 Entertainment", "S01.11.15 Theatre")
+	japan := filepath.Join("S01 Personal", "S01.10-19 Lifestyle", "S01.12 Travel", "S01.12.11 Japan")
+	tax := filepath.Join("S02 Work", "S02.10-19 Admin", "S02.11 Finance", "S02.11.11 Tax")
+	edges := []Edge{
+		{SourcePath: filepath.Join(theatre, "README.md"), TargetJDID: "S01.12.11", LinkText: "[[S01.12.11]]"},
+		{SourcePath: filepath.Join(theatre, "cast.md"), TargetJDID: "S01.12.11", LinkText: "[[S01.12.11 Japan]]"},
+		{SourcePath: filepath.Join(theatre, "cast.md"), TargetJDID: "S01.11.15", TargetPath: filepath.Join(theatre, "README.md"), LinkText: "[x](README.md)"},
+		{SourcePath: filepath.Join(japan, "plan.md"), TargetJDID: "S01.11", LinkText: "[[S01.11 Entertainment]]"},
+		{SourcePath: filepath.Join(japan, "plan.md"), TargetJDID: "S02.11.11", TargetPath: filepath.Join(tax, "receipts.md"), LinkText: "[r](receipts.md)"},
+		{SourcePath: "notes.md", TargetJDID: "S01.11.15", LinkText: "[[S01.11.15]]"},
+		{SourcePath: filepath.Join(japan, "plan.md"), LinkText: "[[[Archived] Opera]]"},
+	}
+	names := map[string]string{"S01.11.15": "Theatre", "S01.12.11": "Japan", "S01.11": "Entertainment", "S01.12": "Travel", "S02.11.11": "Tax"}
+
+	tests := []struct {
+		name      string
+		opts      GraphOptions
+		wantNodes []string
+		wantEdges []GraphEdge
+	}{
+		{
+			name:      "items",
+			opts:      GraphOptions{Level: IDTypeItem},
+			wantNodes: []string{"S01.11", "S01.11.15", "S01.12.11", "S02.11.11"},
+			wantEdges: []GraphEdge{
+				{Source: "S01.11.15", Target: "S01.12.11", Weight: 2},
+				{Source: "S01.12.11", Target: "S01.11", Weight: 1},
+				{Source: "S01.12.11", Target: "S02.11.11", Weight: 1},
+			},
+		},
+		{
+			name:      "categories",
+			opts:      GraphOptions{Level: IDTypeCategory},
+			wantNodes: []string{"S01.11", "S01.12", "S02.11"},
+			wantEdges: []GraphEdge{
+				{Source: "S01.11", Target: "S01.12", Weight: 2},
+				{Source: "S01.12", Target: "S01.11", Weight: 1},
+				{Source: "S01.12", Target: "S02.11", Weight: 1},
+			},
+		},
+		{
+			name:      "scopes",
+			opts:      GraphOptions{Level: IDTypeScope},
+			wantNodes: []string{"S01", "S02"},
+			wantEdges: []GraphEdge{{Source: "S01", Target: "S02", Weight: 1}},
+		},
+		{
+			name:      "scope and minimum weight",
+			opts:      GraphOptions{Level: IDTypeCategory, Scope: "S01", MinWeight: 2},
+			wantNodes: []string{"S01.11", "S01.12"},
+			wantEdges: []GraphEdge{{Source: "S01.11", Target: "S01.12", Weight: 2}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			graph := BuildLinkGraph(edges, names, tt.opts)
+			var nodes []string
+			for _, node := range graph.Nodes {
+				nodes = append(nodes, node.ID)
+			}
+			if !slices.Equal(nodes, tt.wantNodes) {
+				t.Errorf("nodes = %q, want %q", nodes, tt.wantNodes)
+			}
+			if !slices.Equal(graph.Edges, tt.wantEdges) {
+				t.Errorf("edges = %+v, want %+v", graph.Edges, tt.wantEdges)
+			}
+		})
+	}
+
+	graph := BuildLinkGraph(edges, names, GraphOptions{Level: IDTypeCategory})
+	if got := graph.Nodes[0]; got.Label() != "S01.11 Entertainment" || got.Type != "Category" {
+		t.Errorf("first node = %+v, label %q", got, got.Label())
+	}
+	if got := graph.Nodes[2].Label(); got != "S02.11" {
+		t.Errorf("unindexed category label = %q, want its ID", got)
+	}
+}
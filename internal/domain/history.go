@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+// HistoryEntry describes a version control commit created by a vault operation
+type HistoryEntry struct {
+	Hash      string    `json:"hash"`
+	Operation string    `json:"operation"` // Operation that created the commit, e.g. "move"
+	Subject   string    `json:"subject"`   // e.g. "move S01.11.15 Theatre -> S01.12.11"
+	Author    string    `json:"author"`
+	Date      time.Time `json:"date"`
+}
+
+// ShortHash returns the abbreviated commit hash
+func (e HistoryEntry) ShortHash() string {
+	if len(e.Hash) > 7 {
+		return e.Hash[:7]
+	}
+	return e.Hash
+}
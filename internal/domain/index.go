@@ -1,30 +1,258 @@
 package domain
 
-import "time"
+import (
+	"cmp"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
 
-// IndexNode represents a cached vault entry (JD node or markdown file)
+// IndexNode represents a cached vault entry: the vault root ("."), a folder or a file
 type IndexNode struct {
 	Path   string // Relative path from vault root (primary key)
-	JDID   string // Johnny Decimal ID (empty for non-JD files)
+	JDID   string // Johnny Decimal ID (empty for non-JD entries)
 	JDType IDType // Scope, Area, Category, Item, or IDTypeUnknown
-	Name   string // Description/filename
-	Mtime  int64  // Unix timestamp for incremental sync
+	Name   string // Description for JD folders, otherwise the base name
+	IsDir  bool   // True for folders
+	Mtime  int64  // Modification time in Unix nanoseconds, for staleness checks
+	Hash   string // Content hash of markdown files, for change and move detection
 }
 
-// Edge represents an Obsidian wiki link between files
+// SearchEntry is an indexed file or folder with what search queries filter it
+// by besides its node. A note has its own tags and properties; an item or
+// category folder has those of the notes filed in it, outside any item it
+// holds.
+type SearchEntry struct {
+	IndexNode
+	Tags       []string
+	Properties []Property
+	Backlinks  int // Links to it from files outside it
+}
+
+// EntityID returns the JD ID of the entry's folder, or of the nearest JD
+// folder holding it, or "" outside any
+func (e SearchEntry) EntityID() string {
+	return PathEntity(e.Path)
+}
+
+// LinkKind is how a link is written
+type LinkKind string
+
+const (
+	LinkWiki       LinkKind = "wiki"     // [[S01.11.15 Theatre]]
+	LinkEmbed      LinkKind = "embed"    // ![[S01.11.15 Theatre]] or ![poster](poster.png)
+	LinkMarkdown   LinkKind = "markdown" // [text](../S01.11.15%20Theatre/notes.md)
+	LinkCanvasFile LinkKind = "file"     // File node of a canvas
+)
+
+// Edge represents a link between files: an Obsidian wiki link to a JD folder,
+// an archived one or a note by name, or a Markdown link or canvas file node
+// pointing to a vault path. A link written several times in a file is an edge
+// each time.
 type Edge struct {
-	SourcePath string // File containing the link
-	TargetJDID string // Referenced JD ID
-	LinkText   string // Original [[link]] text
+	SourcePath  string   // File containing the link
+	TargetJDID  string   // Referenced JD ID, empty for [Archived] names; for path and note links, the category or item the target lies in, if any
+	TargetPath  string   // Vault path a Markdown link or canvas file node resolves to, or the note a note link was resolved to; empty for other wiki links
+	TargetName  string   // Note a wiki link names when it is neither a JD name nor an [Archived] one, as written without a .md extension: "Projects/Meeting notes"
+	LinkText    string   // Original [[link]] or [text](link) text, or the file path of a canvas node
+	Kind        LinkKind // How the link is written
+	Line        int      // 1-based line the link starts on in SourcePath
+	Column      int      // 1-based column, in characters, the link starts at on Line
+	Context     string   // The line holding the link, trimmed, or the stretch of a long line around it; empty for canvas file nodes
+	Occurrence  int      // 1 for the first time LinkText appears in SourcePath, 2 for the second, and so on
+	Occurrences int      // Times LinkText appears in SourcePath
+}
+
+// ParseLinks extracts the wiki links and embeds, and the Markdown links and
+// images that point into the vault, resolved relative to the file at
+// sourcePath. Wiki links to a category, an item or an [Archived] folder get
+// its ID or name; links to other notes get a TargetName, left for the index
+// to resolve since that takes the whole vault. Links in frontmatter values
+// count, and a canvas contributes the links in its text nodes and its file
+// nodes. Edges are in the order they appear in the file.
+func ParseLinks(content []byte, sourcePath string) []Edge {
+	var edges []Edge
+	lines := newLinePositions(content)
+	add := func(edge Edge, offset int) {
+		edge.SourcePath = sourcePath
+		edge.Line, edge.Column = lines.at(offset)
+		edges = append(edges, edge)
+	}
+	pathEdge := func(linkPath string, edge Edge, offset int) {
+		if target, ok := ResolveLinkPath(sourcePath, linkPath); ok {
+			edge.TargetJDID, edge.TargetPath = PathJDID(target), target
+			add(edge, offset)
+		}
+	}
+
+	texts, files := linkParts(sourcePath, content)
+	for _, t := range texts {
+		for _, link := range ScanWikiLinks(t.text) {
+			if link.Name() == "" {
+				continue
+			}
+			edge := Edge{
+				TargetJDID: link.JDID(),
+				LinkText:   link.String(),
+				Kind:       LinkWiki,
+				Context:    t.context(link.Start, link.End),
+			}
+			if link.Embed {
+				edge.Kind = LinkEmbed
+			}
+			if edge.TargetJDID == "" && !IsArchivedFolder(link.Name()) {
+				edge.TargetName = link.NotePath()
+			}
+			add(edge, t.offset(link.Start))
+		}
+		for _, link := range ScanMarkdownLinks(t.text) {
+			if linkPath, ok := link.Path(); ok {
+				kind := LinkMarkdown
+				if link.Image {
+					kind = LinkEmbed
+				}
+				pathEdge(linkPath, Edge{
+					LinkText: link.String(),
+					Kind:     kind,
+					Context:  t.context(link.Start, link.End),
+				}, t.offset(link.Start))
+			}
+		}
+	}
+	for _, file := range files {
+		pathEdge("/"+file.path, Edge{LinkText: file.path, Kind: LinkCanvasFile}, file.start)
+	}
+
+	slices.SortStableFunc(edges, func(a, b Edge) int {
+		return cmp.Or(cmp.Compare(a.Line, b.Line), cmp.Compare(a.Column, b.Column))
+	})
+	seen := make(map[string]int)
+	for i := range edges {
+		seen[edges[i].LinkText]++
+		edges[i].Occurrence = seen[edges[i].LinkText]
+	}
+	for i := range edges {
+		edges[i].Occurrences = seen[edges[i].LinkText]
+	}
+	return edges
 }
 
 // SyncStats holds statistics from a sync operation
 type SyncStats struct {
-	NodesAdded   int
-	NodesUpdated int
-	NodesDeleted int
-	EdgesAdded   int
-	EdgesDeleted int
-	FilesScanned int
-	Duration     time.Duration
+	NodesAdded    int
+	NodesUpdated  int
+	NodesDeleted  int
+	NodesMoved    int
+	EdgesAdded    int
+	EdgesDeleted  int
+	FilesScanned  int
+	DirsUnchanged int  // Folders whose listing was taken from the index instead of the disk
+	Rebuilt       bool // The index was rebuilt from scratch
+	Duration      time.Duration
+	Moves         []NodeMove // Entries moved or renamed outside libraio since the last sync
+	Changed       []string   // Relative paths added, updated, deleted or moved; not listed for rebuilds
+}
+
+// HasChanges reports whether the sync found anything new in the vault
+func (s *SyncStats) HasChanges() bool {
+	return s.Rebuilt || s.NodesAdded > 0 || s.NodesUpdated > 0 || s.NodesDeleted > 0 || s.NodesMoved > 0
+}
+
+// WatchEvent is a check of a watched vault that found changes or failed
+type WatchEvent struct {
+	Stats *SyncStats
+	Err   error
+}
+
+// SyncPhase is a stage of a sync
+type SyncPhase int
+
+const (
+	SyncScanning SyncPhase = iota // Walking the vault for new, changed and removed entries
+	SyncIndexing                  // Reading changed markdown files for links and content
+)
+
+// String returns a label for the phase
+func (p SyncPhase) String() string {
+	if p == SyncIndexing {
+		return "Indexing"
+	}
+	return "Scanning"
+}
+
+// SyncProgress reports how far a sync has got in its current phase. Total is
+// an estimate while scanning, and 0 if not even an estimate is known.
+type SyncProgress struct {
+	Phase SyncPhase
+	Done  int
+	Total int
+}
+
+// Fraction returns the completed share of the phase, between 0 and 1
+func (p SyncProgress) Fraction() float64 {
+	if p.Total <= 0 {
+		return 0
+	}
+	return min(float64(p.Done)/float64(p.Total), 1)
+}
+
+// SyncProgressFunc receives progress updates from a sync. It is called from
+// the syncing goroutine, so it should hand updates off rather than block.
+type SyncProgressFunc func(SyncProgress)
+
+// NodeMove is an entry a sync found at a new path: a folder that kept its
+// identity on disk, or a markdown file that kept its content
+type NodeMove struct {
+	OldPath string `json:"old_path"`
+	NewPath string `json:"new_path"`
+	IsDir   bool   `json:"is_dir,omitempty"`
+}
+
+// LinkChange returns the JD ID and description of a moved folder before and
+// after the move. It returns false unless the move changes how wiki links
+// name the folder, i.e. both names are JD folder names and they differ.
+func (m NodeMove) LinkChange() (oldID, oldDescription, newID, newDescription string, ok bool) {
+	if !m.IsDir {
+		return "", "", "", "", false
+	}
+	oldName, newName := filepath.Base(m.OldPath), filepath.Base(m.NewPath)
+	oldID, newID = ExtractID(oldName), ExtractID(newName)
+	if oldName == newName || ParseIDType(oldID) == IDTypeUnknown || ParseIDType(newID) == IDTypeUnknown {
+		return "", "", "", "", false
+	}
+	return oldID, ExtractDescription(oldName), newID, ExtractDescription(newName), true
+}
+
+// Reverse returns the move taking the entry back to its old path
+func (m NodeMove) Reverse() NodeMove {
+	return NodeMove{OldPath: m.NewPath, NewPath: m.OldPath, IsDir: m.IsDir}
+}
+
+// Apply returns where a path at or beneath the moved entry is after the move,
+// or false if the move does not affect it
+func (m NodeMove) Apply(path string) (string, bool) {
+	rest, ok := strings.CutPrefix(path, m.OldPath)
+	if !ok || (rest != "" && rest[0] != filepath.Separator) {
+		return path, false
+	}
+	return m.NewPath + rest, true
+}
+
+// Highlight markers wrap matched terms in ContentMatch snippets
+const (
+	HighlightStart = "\x02"
+	HighlightEnd   = "\x03"
+)
+
+// ContentMatch is a full-text search hit inside a file body
+type ContentMatch struct {
+	Path    string  // Relative path from vault root
+	Snippet string  // Excerpt around the match, terms wrapped in highlight markers
+	Rank    float64 // Relevance; lower is better
+}
+
+// HighlightSnippet replaces the highlight markers in a snippet with open and close
+func HighlightSnippet(snippet, open, close string) string {
+	return strings.NewReplacer(HighlightStart, open, HighlightEnd, close).Replace(snippet)
 }
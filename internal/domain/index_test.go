@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestNodeMove_LinkChange(t *testing.T) {
+	category := filepath.Join("S01 Personal", "S01.10-19 Lifestyle", "S01.11 Entertainment")
+	tests := []struct {
+		name    string
+		move    NodeMove
+		wantOK  bool
+		wantOld string
+		wantNew string
+	}{
+		{"renamed item", NodeMove{filepath.Join(category, "S01.11.15 Theatre"), filepath.Join(category, "S01.11.15 Drama"), true}, true, "S01.11.15 Theatre", "S01.11.15 Drama"},
+		{"renumbered item", NodeMove{filepath.Join(category, "S01.11.15 Theatre"), filepath.Join("S01.12 Travel", "S01.12.11 Theatre"), true}, true, "S01.11.15 Theatre", "S01.12.11 Theatre"},
+		{"moved without renaming", NodeMove{filepath.Join(category, "S01.11.15 Theatre"), filepath.Join("Elsewhere", "S01.11.15 Theatre"), true}, false, "", ""},
+		{"archived by hand", NodeMove{filepath.Join(category, "S01.11.15 Theatre"), filepath.Join(category, "[Archived] Theatre"), true}, false, "", ""},
+		{"plain folder", NodeMove{"attachments", "files", true}, false, "", ""},
+		{"file", NodeMove{"S01.11.15 a.md", "S01.11.16 a.md", false}, false, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldID, oldDescription, newID, newDescription, ok := tt.move.LinkChange()
+			if ok != tt.wantOK {
+				t.Fatalf("LinkChange() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if got := oldID + " " + oldDescription; got != tt.wantOld {
+				t.Errorf("old = %q, want %q", got, tt.wantOld)
+			}
+			if got := newID + " " + newDescription; got != tt.wantNew {
+				t.Errorf("new = %q, want %q", got, tt.wantNew)
+			}
+		})
+	}
+}
+
+func TestParseLinks_RecordsEachOccurrence(t *testing.T) {
+	content := []byte("---\nrelated: \"[[S01.11.15]]\"\n---\n# Theatre\n\nCafé [[S01.11.15]] tonight, then ![[S01.11.15]]\n  [[S01.11.15]]\n")
+	edges := ParseLinks(content, "notes.md")
+
+	type position struct {
+		kind             LinkKind
+		line, column     int
+		context          string
+		occurrence, seen int
+	}
+	var got []position
+	for _, e := range edges {
+		got = append(got, position{e.Kind, e.Line, e.Column, e.Context, e.Occurrence, e.Occurrences})
+	}
+	want := []position{
+		{LinkWiki, 2, 11, "[[S01.11.15]]", 1, 3},
+		{LinkWiki, 6, 6, "Café [[S01.11.15]] tonight, then ![[S01.11.15]]", 2, 3},
+		{LinkEmbed, 6, 34, "Café [[S01.11.15]] tonight, then ![[S01.11.15]]", 1, 1},
+		{LinkWiki, 7, 3, "[[S01.11.15]]", 3, 3},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ParseLinks = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("edge %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -3,6 +3,7 @@ package domain
 import (
 	"fmt"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 )
@@ -490,3 +491,9 @@ func GetIDHierarchy(id string) []string {
 		return nil
 	}
 }
+
+// IsWithin reports whether id equals ancestorID or lies beneath it in the hierarchy.
+// For example, "S01.11.15" is within "S01", "S01.10-19", "S01.11" and itself.
+func IsWithin(id, ancestorID string) bool {
+	return slices.Contains(GetIDHierarchy(id), ancestorID)
+}
@@ -410,3 +410,31 @@ func TestJDexFileName(t *testing.T) {
 		})
 	}
 }
+
+func TestIsWithin(t *testing.T) {
+	tests := []struct {
+		id       string
+		ancestor string
+		want     bool
+	}{
+		{"S01.11.15", "S01.11.15", true},
+		{"S01.11.15", "S01.11", true},
+		{"S01.11.15", "S01.10-19", true},
+		{"S01.11.15", "S01", true},
+		{"S01.11", "S01.10-19", true},
+		{"S01.11.15", "S01.12", false},
+		{"S01.21.15", "S01.10-19", false},
+		{"S01.11", "S01.11.15", false},
+		{"S02.11.15", "S01", false},
+		{"invalid", "S01", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id+" in "+tt.ancestor, func(t *testing.T) {
+			got := IsWithin(tt.id, tt.ancestor)
+			if got != tt.want {
+				t.Errorf("IsWithin(%q, %q) = %v, want %v", tt.id, tt.ancestor, got, tt.want)
+			}
+		})
+	}
+}
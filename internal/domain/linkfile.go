@@ -0,0 +1,252 @@
+package domain
+
+import (
+	"bytes"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// IsLinkSource reports whether a file can hold links libraio indexes and
+// rewrites: a markdown note or an Obsidian canvas
+func IsLinkSource(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	return ext == ".md" || ext == ".canvas"
+}
+
+// isCanvas reports whether a file is an Obsidian canvas
+func isCanvas(name string) bool {
+	return strings.EqualFold(filepath.Ext(name), ".canvas")
+}
+
+// FileLinks holds the links in a note or canvas: in the note body and its
+// frontmatter values, or in the text and file nodes of a canvas. Offsets of
+// the wiki and Markdown links are relative to the text they were found in.
+type FileLinks struct {
+	Wiki     []WikiLink
+	Markdown []MarkdownLink
+	Files    []string // Vault paths of canvas file nodes, with "/" separators
+}
+
+// ScanFileLinks returns the links in the note or canvas at sourcePath
+func ScanFileLinks(sourcePath string, content []byte) FileLinks {
+	var links FileLinks
+	texts, files := linkParts(sourcePath, content)
+	for _, t := range texts {
+		links.Wiki = append(links.Wiki, ScanWikiLinks(t.text)...)
+		links.Markdown = append(links.Markdown, ScanMarkdownLinks(t.text)...)
+	}
+	for _, f := range files {
+		links.Files = append(links.Files, f.path)
+	}
+	return links
+}
+
+// LinkRewriter rewrites the links in a note or canvas. Each function returns
+// the replacement for a link and true, or false to leave the link alone; a nil
+// function leaves every link of its kind alone.
+type LinkRewriter struct {
+	Wiki     func(WikiLink) (WikiLink, bool)
+	Markdown func(MarkdownLink) (MarkdownLink, bool)
+	File     func(path string) (string, bool) // Canvas file nodes, as vault paths with "/" separators
+}
+
+// Rewrite applies the rewriter to the note or canvas at sourcePath. Only the
+// links that change are touched: the rest of the file, including its YAML or
+// JSON formatting, is kept byte for byte, and replacements in quoted strings
+// are escaped for them. It returns the new content and how many links changed.
+func (rw LinkRewriter) Rewrite(sourcePath string, content []byte) ([]byte, int) {
+	texts, files := linkParts(sourcePath, content)
+
+	var edits []linkEdit
+	for _, t := range texts {
+		if rw.Wiki != nil {
+			for _, link := range ScanWikiLinks(t.text) {
+				if next, ok := rw.Wiki(link); ok && next.String() != link.String() {
+					edits = append(edits, t.edit(link.Start, link.End, next.String()))
+				}
+			}
+		}
+		if rw.Markdown != nil {
+			for _, link := range ScanMarkdownLinks(t.text) {
+				if next, ok := rw.Markdown(link); ok && next.String() != link.String() {
+					edits = append(edits, t.edit(link.Start, link.End, next.String()))
+				}
+			}
+		}
+	}
+	if rw.File != nil {
+		for _, f := range files {
+			if next, ok := rw.File(f.path); ok && next != f.path {
+				edits = append(edits, linkEdit{start: f.start, end: f.end, text: quoteJSON(next)})
+			}
+		}
+	}
+	if len(edits) == 0 {
+		return content, 0
+	}
+
+	slices.SortFunc(edits, func(a, b linkEdit) int { return a.start - b.start })
+	var out strings.Builder
+	last := 0
+	for _, e := range edits {
+		out.Write(content[last:e.start])
+		out.WriteString(e.text)
+		last = e.end
+	}
+	out.Write(content[last:])
+	return []byte(out.String()), len(edits)
+}
+
+// linkEdit replaces content[start:end] with text
+type linkEdit struct {
+	start, end int
+	text       string
+}
+
+// linkText is markdown that links are looked for in: a note body, written as-is,
+// or the decoded value of a quoted YAML or JSON string
+type linkText struct {
+	text  []byte
+	base  int                 // Offset of text in the file when written as-is
+	raw   []int               // Offset in the file of each byte of a decoded text, and of its end
+	quote func(string) string // Escapes replacement text for a quoted string
+}
+
+// edit replaces text[start:end] with s in the file
+func (t linkText) edit(start, end int, s string) linkEdit {
+	if t.raw != nil {
+		s = t.quote(s)
+	}
+	return linkEdit{start: t.offset(start), end: t.offset(end), text: s}
+}
+
+// offset returns the offset in the file of text[i]
+func (t linkText) offset(i int) int {
+	if t.raw == nil {
+		return t.base + i
+	}
+	return t.raw[i]
+}
+
+// linkContextMargin is how many characters of a long line are kept on either
+// side of a link for its context
+const linkContextMargin = 60
+
+// context returns the line of text holding text[start:end], trimmed. Of a
+// line running more than linkContextMargin characters before or after the
+// link, only that many are kept on that side, marked cut with "…".
+func (t linkText) context(start, end int) string {
+	from := bytes.LastIndexByte(t.text[:start], '\n') + 1
+	to := lineEndAt(t.text, end)
+	var before, after string
+	if utf8.RuneCount(t.text[from:start]) > linkContextMargin {
+		from, before = start, "…"
+		for range linkContextMargin {
+			_, size := utf8.DecodeLastRune(t.text[:from])
+			from -= size
+		}
+	}
+	if utf8.RuneCount(t.text[end:to]) > linkContextMargin {
+		to, after = end, "…"
+		for range linkContextMargin {
+			_, size := utf8.DecodeRune(t.text[to:])
+			to += size
+		}
+	}
+	return before + strings.TrimSpace(string(t.text[from:to])) + after
+}
+
+// linePositions finds the line and column of offsets in a file
+type linePositions struct {
+	content []byte
+	starts  []int // Offset of each line's first byte
+}
+
+// newLinePositions indexes the lines of content
+func newLinePositions(content []byte) linePositions {
+	starts := []int{0}
+	for i, c := range content {
+		if c == '\n' {
+			starts = append(starts, i+1)
+		}
+	}
+	return linePositions{content: content, starts: starts}
+}
+
+// at returns the 1-based line and column, in characters, of an offset
+func (p linePositions) at(offset int) (int, int) {
+	line, _ := slices.BinarySearch(p.starts, offset+1)
+	start := p.starts[line-1]
+	return line, utf8.RuneCount(p.content[start:offset]) + 1
+}
+
+// linkFile is the path of a canvas file node, found inside the quotes at
+// content[start:end]
+type linkFile struct {
+	start, end int
+	path       string
+}
+
+// linkParts splits a note into its frontmatter values and body, or a canvas
+// into its text and file nodes
+func linkParts(sourcePath string, content []byte) ([]linkText, []linkFile) {
+	if isCanvas(sourcePath) {
+		return canvasParts(content)
+	}
+	texts, body := frontmatterTexts(content)
+	return append(texts, linkText{text: content[body:], base: body}), nil
+}
+
+// unescapeFunc decodes the escape sequence starting at content[i], returning
+// the decoded bytes and the length of the sequence
+type unescapeFunc func(content []byte, i int) ([]byte, int)
+
+// decodeQuoted decodes content[start:end], the inside of a quoted string
+// whose escapes start with esc, keeping where each decoded byte came from
+func decodeQuoted(content []byte, start, end int, esc byte, unescape unescapeFunc) linkText {
+	var t linkText
+	for i := start; i < end; {
+		if content[i] != esc {
+			t.text = append(t.text, content[i])
+			t.raw = append(t.raw, i)
+			i++
+			continue
+		}
+		decoded, n := unescape(content, i)
+		for range decoded {
+			t.raw = append(t.raw, i)
+		}
+		t.text = append(t.text, decoded...)
+		i += max(n, 1)
+	}
+	t.raw = append(t.raw, end)
+	return t
+}
+
+// hexRune decodes n hex digits at content[i], or returns false
+func hexRune(content []byte, i, n int) (rune, bool) {
+	if i+n > len(content) {
+		return 0, false
+	}
+	var r rune
+	for _, c := range content[i : i+n] {
+		switch {
+		case c >= '0' && c <= '9':
+			r = r<<4 | rune(c-'0')
+		case c >= 'a' && c <= 'f':
+			r = r<<4 | rune(c-'a'+10)
+		case c >= 'A' && c <= 'F':
+			r = r<<4 | rune(c-'A'+10)
+		default:
+			return 0, false
+		}
+	}
+	return r, true
+}
+
+// runeBytes encodes r as UTF-8
+func runeBytes(r rune) []byte {
+	return utf8.AppendRune(nil, r)
+}
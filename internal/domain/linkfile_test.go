@@ -0,0 +1,188 @@
+package domain
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestScanFileLinks_Frontmatter(t *testing.T) {
+	content := "---\n" +
+		"related: \"[[S01.11.15 Theatre]]\"\n" +
+		"see: '[[S01.11.16 Links|Mum''s links]]'\n" +
+		"up:\n" +
+		"  - \"[[S01.11.17]]\"\n" +
+		"  - [[S01.11.18]]\n" +
+		"flow: [\"[[S01.11.19]]\", '[[S01.11.20]]']\n" +
+		"escaped: \"[[S01.11.21|\\\"Cats\\\"]]\"\n" +
+		"title: Don't [[S01.11.22]] # [[S01.11.99]]\n" +
+		"# [[S01.11.98]]\n" +
+		"note: |\n" +
+		"  See [[S01.11.23]]\n" +
+		"cover: \"[poster](poster%20a.png)\"\n" +
+		"---\n" +
+		"Body [[S01.11.24]]\n"
+
+	links := ScanFileLinks("note.md", []byte(content))
+	var got []string
+	for _, link := range links.Wiki {
+		got = append(got, link.String())
+	}
+	want := []string{
+		"[[S01.11.15 Theatre]]",
+		"[[S01.11.16 Links|Mum's links]]",
+		"[[S01.11.17]]",
+		"[[S01.11.18]]",
+		"[[S01.11.19]]",
+		"[[S01.11.20]]",
+		`[[S01.11.21|"Cats"]]`,
+		"[[S01.11.22]]",
+		"[[S01.11.23]]",
+		"[[S01.11.24]]",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("wiki links = %q, want %q", got, want)
+	}
+	if len(links.Markdown) != 1 || links.Markdown[0].Dest != "poster%20a.png" {
+		t.Errorf("Markdown links = %+v, want the cover image", links.Markdown)
+	}
+}
+
+func TestFrontmatterAliases(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"block list", "---\naliases:\n  - Plays\n  - \"Drama, stage\" # quoted\n  -\ntags: [x]\n---\n", []string{"Plays", "Drama, stage"}},
+		{"unindented list", "---\naliases:\n- Plays\n- 'Mum''s plays'\ntitle: x\n---\n", []string{"Plays", "Mum's plays"}},
+		{"flow list", "---\nAliases: [Plays, \"Drama, stage\", '']\n---\n", []string{"Plays", "Drama, stage"}},
+		{"single value", "---\nalias: Plays # old key\n---\n", []string{"Plays"}},
+		{"nested key", "---\nmeta:\n  aliases: [Plays]\n---\n", nil},
+		{"in the body", "# Theatre\n\naliases: [Plays]\n", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FrontmatterAliases([]byte(tt.content)); !slices.Equal(got, tt.want) {
+				t.Errorf("FrontmatterAliases = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFrontmatterProperties(t *testing.T) {
+	content := "---\nstatus: active # for now\ntags:\n  - theatre\n  - \"to do\"\nrating: [4, 5]\nmeta:\n  owner: me\nsummary: |\n  Long text\n\"due date\": 2026-05-01\nempty:\n---\nbody: no\n"
+	want := []Property{
+		{Key: "status", Value: "active"},
+		{Key: "tags", Value: "theatre"},
+		{Key: "tags", Value: "to do"},
+		{Key: "rating", Value: "4"},
+		{Key: "rating", Value: "5"},
+		{Key: "due date", Value: "2026-05-01"},
+	}
+	if got := FrontmatterProperties([]byte(content)); !slices.Equal(got, want) {
+		t.Errorf("FrontmatterProperties = %q\nwant %q", got, want)
+	}
+}
+
+func TestScanFileLinks_UnclosedFrontmatterIsBody(t *testing.T) {
+	links := ScanFileLinks("note.md", []byte("---\nsee: '[[S01.11.15]]'\n"))
+	if len(links.Wiki) != 1 || links.Wiki[0].String() != "[[S01.11.15]]" {
+		t.Errorf("wiki links = %+v", links.Wiki)
+	}
+}
+
+const testCanvas = `{
+	"nodes":[
+		{"id":"a","type":"text","text":"See [[S01.11.15 Theatre]]\n\n[cast](S01%20Personal/cast.md)","x":0,"y":0,"width":250,"height":60},
+		{"id":"b","x":300,"y":0,"width":400,"height":400,"file":"S01 Personal/S01.11.15 Theatre/S01.11.15 Theatre.md","type":"file","subpath":"#Cast"},
+		{"id":"c","type":"link","url":"https://example.com/[[S01.11.16]]","x":0,"y":100,"width":250,"height":60},
+		{"id":"d","type":"group","label":"[[S01.11.17]]","x":0,"y":0,"width":1,"height":1}
+	],
+	"edges":[
+		{"id":"e","fromNode":"a","fromSide":"right","toNode":"b","toSide":"left","label":"[[S01.11.18]]"}
+	]
+}`
+
+func TestScanFileLinks_Canvas(t *testing.T) {
+	links := ScanFileLinks("board.canvas", []byte(testCanvas))
+	if len(links.Wiki) != 1 || links.Wiki[0].String() != "[[S01.11.15 Theatre]]" {
+		t.Errorf("wiki links = %+v, want only the text node's", links.Wiki)
+	}
+	if len(links.Markdown) != 1 || links.Markdown[0].Dest != "S01%20Personal/cast.md" {
+		t.Errorf("Markdown links = %+v", links.Markdown)
+	}
+	if want := []string{"S01 Personal/S01.11.15 Theatre/S01.11.15 Theatre.md"}; !slices.Equal(links.Files, want) {
+		t.Errorf("files = %q, want %q", links.Files, want)
+	}
+
+	if links := ScanFileLinks("broken.canvas", []byte(`{"nodes":[{"type":"text","text":"[[S01.11.15]]"}`)); len(links.Wiki) != 0 {
+		t.Errorf("invalid JSON should have no links, got %+v", links)
+	}
+	if got := CanvasText([]byte(testCanvas)); got != "See [[S01.11.15 Theatre]]\n\n[cast](S01%20Personal/cast.md)" {
+		t.Errorf("CanvasText = %q", got)
+	}
+}
+
+func TestParseLinks_IndexesCanvasFiles(t *testing.T) {
+	source := filepath.Join("S01 Personal", "board.canvas")
+	edges := ParseLinks([]byte(testCanvas), source)
+	// Positions are in the canvas file; context is the line of the text node
+	want := []Edge{
+		{SourcePath: source, TargetJDID: "S01.11.15", LinkText: "[[S01.11.15 Theatre]]", Kind: LinkWiki, Line: 3, Column: 39, Context: "See [[S01.11.15 Theatre]]", Occurrence: 1, Occurrences: 1},
+		{SourcePath: source, TargetPath: filepath.Join("S01 Personal", "S01 Personal", "cast.md"), LinkText: "[cast](S01%20Personal/cast.md)", Kind: LinkMarkdown, Line: 3, Column: 64, Context: "[cast](S01%20Personal/cast.md)", Occurrence: 1, Occurrences: 1},
+		{SourcePath: source, TargetJDID: "S01.11.15", TargetPath: filepath.Join("S01 Personal", "S01.11.15 Theatre", "S01.11.15 Theatre.md"), LinkText: "S01 Personal/S01.11.15 Theatre/S01.11.15 Theatre.md", Kind: LinkCanvasFile, Line: 4, Column: 60, Occurrence: 1, Occurrences: 1},
+	}
+	if !slices.Equal(edges, want) {
+		t.Errorf("ParseLinks = %+v, want %+v", edges, want)
+	}
+}
+
+func TestLinkRewriter_KeepsFormatting(t *testing.T) {
+	rename := LinkRewriter{
+		Wiki: func(link WikiLink) (WikiLink, bool) {
+			return link.Retarget(`S01.11.15 Mum's "Play"`), link.JDID() == "S01.11.15"
+		},
+		Markdown: func(link MarkdownLink) (MarkdownLink, bool) {
+			return link.WithPath("moved/" + strings.TrimPrefix(link.Dest, "S01%20Personal/")), true
+		},
+		File: func(path string) (string, bool) {
+			return strings.Replace(path, "Theatre", `Mum's "Play"`, 1), true
+		},
+	}
+
+	tests := []struct {
+		name    string
+		source  string
+		content string
+		want    string
+		count   int
+	}{
+		{
+			name:    "frontmatter",
+			source:  "note.md",
+			content: "---\na: \"[[S01.11.15]]\" # keep\nb: '[[S01.11.15|x]]'\nc:\n    - [[S01.11.15]]\nd: \"\\u00e9 [[S01.11.16]]\"\n---\n[[S01.11.15]]\n",
+			want:    "---\na: \"[[S01.11.15 Mum's \\\"Play\\\"]]\" # keep\nb: '[[S01.11.15 Mum''s \"Play\"|x]]'\nc:\n    - [[S01.11.15 Mum's \"Play\"]]\nd: \"\\u00e9 [[S01.11.16]]\"\n---\n[[S01.11.15 Mum's \"Play\"]]\n",
+			count:   4,
+		},
+		{
+			name:    "canvas",
+			source:  "board.canvas",
+			content: strings.ReplaceAll(testCanvas, `"text":"See`, `"text":"é See`),
+			want: strings.NewReplacer(
+				`"text":"See [[S01.11.15 Theatre]]\n\n[cast](S01%20Personal/cast.md)"`, `"text":"é See [[S01.11.15 Mum's \"Play\"]]\n\n[cast](moved/cast.md)"`,
+				`"file":"S01 Personal/S01.11.15 Theatre/`, `"file":"S01 Personal/S01.11.15 Mum's \"Play\"/`,
+			).Replace(testCanvas),
+			count: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, count := rename.Rewrite(tt.source, []byte(tt.content))
+			if string(got) != tt.want || count != tt.count {
+				t.Errorf("Rewrite = %d links\n%s\nwant %d links\n%s", count, got, tt.count, tt.want)
+			}
+		})
+	}
+}
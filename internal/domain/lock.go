@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrVaultLocked is returned when another process holds the vault lock
+var ErrVaultLocked = errors.New("vault is locked")
+
+// LockInfo describes the process holding the vault lock
+type LockInfo struct {
+	PID        int       `json:"pid"`
+	Host       string    `json:"host"`
+	Command    string    `json:"command"`
+	AcquiredAt time.Time `json:"acquired_at"`
+}
+
+// String returns a human-readable description of the lock holder,
+// e.g. "libraio-cli (pid 4242 on laptop) since 15:04:05"
+func (l LockInfo) String() string {
+	return fmt.Sprintf("%s (pid %d on %s) since %s",
+		l.Command, l.PID, l.Host, l.AcquiredAt.Local().Format("15:04:05"))
+}
+
+// VaultLockedError reports that a mutation could not proceed because
+// another process holds the vault lock
+type VaultLockedError struct {
+	Holder LockInfo
+}
+
+func (e *VaultLockedError) Error() string {
+	return fmt.Sprintf("vault is locked by %s; try again when it finishes", e.Holder)
+}
+
+func (e *VaultLockedError) Is(target error) bool {
+	return target == ErrVaultLocked
+}
@@ -0,0 +1,273 @@
+package domain
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// MarkdownLink is a standard Markdown link, [text](destination "title"), or an
+// image when written ![alt](destination). Obsidian writes these instead of wiki
+// links when "Use [[Wikilinks]]" is off. Its fields keep the exact text of
+// each part, so String reproduces the link as written.
+type MarkdownLink struct {
+	Start int    // Byte offset of the link in the scanned content, including any "!"
+	End   int    // Byte offset just past the closing ")"
+	Image bool   // Written ![...](...) to embed the target
+	Text  string // Link text or image description, between the brackets
+	Dest  string // Destination as written, without angle brackets: "../S01.11.15%20Theatre/a.md#Cast"
+	Angle bool   // Destination written in angle brackets: <../S01.11.15 Theatre/a.md>
+	Title string // Everything after the destination up to ")", e.g. ` "Poster"`
+	lead  string // Whitespace between "(" and the destination
+}
+
+// urlScheme matches destinations that are URLs rather than vault paths
+var urlScheme = regexp.MustCompile(`^(?:[A-Za-z][A-Za-z0-9+.-]*:|//)`)
+
+// String renders the link as Markdown
+func (l MarkdownLink) String() string {
+	var b strings.Builder
+	if l.Image {
+		b.WriteByte('!')
+	}
+	b.WriteByte('[')
+	b.WriteString(l.Text)
+	b.WriteString("](")
+	b.WriteString(l.lead)
+	if l.Angle {
+		b.WriteByte('<')
+	}
+	b.WriteString(l.Dest)
+	if l.Angle {
+		b.WriteByte('>')
+	}
+	b.WriteString(l.Title)
+	b.WriteByte(')')
+	return b.String()
+}
+
+func (l MarkdownLink) bounds() (int, int) { return l.Start, l.End }
+
+// Path returns the decoded vault path the link points to, with "/" separators:
+// relative to the linking file, or to the vault root when it starts with "/".
+// It returns false for URLs, anchors within the same note and bad escapes.
+func (l MarkdownLink) Path() (string, bool) {
+	dest := l.Dest[:len(l.Dest)-len(l.Fragment())]
+	if dest == "" || urlScheme.MatchString(dest) {
+		return "", false
+	}
+	p, err := url.PathUnescape(dest)
+	if err != nil {
+		return "", false
+	}
+	return p, true
+}
+
+// Fragment returns the heading, block reference or query ending the
+// destination, with its leading "#" or "?", e.g. "#Cast"
+func (l MarkdownLink) Fragment() string {
+	if i := strings.IndexAny(l.Dest, "#?"); i >= 0 {
+		return l.Dest[i:]
+	}
+	return ""
+}
+
+// WithPath returns the link pointed at another vault path, given with "/"
+// separators. The path is percent-encoded unless the destination was written
+// in angle brackets; the text, fragment and title are kept.
+func (l MarkdownLink) WithPath(p string) MarkdownLink {
+	if !l.Angle {
+		p = EncodeLinkPath(p)
+	}
+	l.Dest = p + l.Fragment()
+	return l
+}
+
+// EncodeLinkPath percent-encodes the characters of a path that cannot appear
+// in a Markdown link destination as-is, the way Obsidian does: spaces become %20
+func EncodeLinkPath(p string) string {
+	var b strings.Builder
+	for i := 0; i < len(p); i++ {
+		switch c := p[i]; {
+		case c <= ' ' || c == 0x7f || strings.IndexByte(`%#?()<>\`, c) >= 0:
+			fmt.Fprintf(&b, "%%%02X", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// ResolveLinkPath resolves a link path found in the file at sourcePath, both
+// relative to the vault root, to the vault-relative path it points to. It
+// returns false for paths leading out of the vault.
+func ResolveLinkPath(sourcePath, linkPath string) (string, bool) {
+	var p string
+	if rooted, ok := strings.CutPrefix(linkPath, "/"); ok {
+		p = path.Clean(rooted)
+	} else {
+		p = path.Join(path.Dir(filepath.ToSlash(sourcePath)), linkPath)
+	}
+	if p == "." || p == ".." || strings.HasPrefix(p, "../") {
+		return "", false
+	}
+	return filepath.FromSlash(p), true
+}
+
+// RelativeLinkPath returns the link path, with "/" separators, from the file at
+// sourcePath to targetPath, both relative to the vault root
+func RelativeLinkPath(sourcePath, targetPath string) string {
+	rel, err := filepath.Rel(filepath.Dir(sourcePath), targetPath)
+	if err != nil {
+		return filepath.ToSlash(targetPath)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// PathJDID returns the ID of the innermost category or item a vault path lies
+// in, or "" if it is in neither
+func PathJDID(relPath string) string {
+	parts := strings.Split(relPath, string(filepath.Separator))
+	for i := len(parts) - 1; i >= 0; i-- {
+		id := ExtractID(parts[i])
+		if t := ParseIDType(id); t == IDTypeCategory || t == IDTypeItem {
+			return id
+		}
+	}
+	return ""
+}
+
+// ScanMarkdownLinks returns the inline Markdown links and images in content,
+// in order, skipping code like ScanWikiLinks does. Wiki links are not
+// Markdown links, even when followed by a parenthesis.
+func ScanMarkdownLinks(content []byte) []MarkdownLink {
+	var links []MarkdownLink
+	scanOutsideCode(content, '[', func(i int) int {
+		if wiki, ok := parseWikiLink(content, i); ok {
+			return wiki.End
+		}
+		link, ok := parseMarkdownLink(content, i)
+		if !ok {
+			return 0
+		}
+		links = append(links, link)
+		return link.End
+	})
+	return links
+}
+
+// RewriteMarkdownLinks replaces each link for which fn returns true with the
+// link it returns, leaving the rest of the content untouched. It returns the
+// new content and how many links actually changed.
+func RewriteMarkdownLinks(content []byte, fn func(MarkdownLink) (MarkdownLink, bool)) ([]byte, int) {
+	return rewriteLinks(content, ScanMarkdownLinks(content), fn)
+}
+
+// parseMarkdownLink parses the link whose "[" starts at i. The text may hold
+// balanced brackets; the link must fit on one line.
+func parseMarkdownLink(content []byte, i int) (MarkdownLink, bool) {
+	link := MarkdownLink{Start: i}
+	if i > 0 && content[i-1] == '!' {
+		link.Image = true
+		link.Start = i - 1
+	}
+
+	// Link text, up to the matching "]"
+	j, depth := i+1, 0
+	for ; j < len(content); j++ {
+		c := content[j]
+		if c == '\n' {
+			return MarkdownLink{}, false
+		}
+		if c == '\\' {
+			j++
+			continue
+		}
+		if c == '[' {
+			depth++
+		} else if c == ']' {
+			if depth == 0 {
+				break
+			}
+			depth--
+		}
+	}
+	if j+1 >= len(content) || content[j+1] != '(' {
+		return MarkdownLink{}, false
+	}
+	link.Text = string(content[i+1 : j])
+	j += 2
+
+	lead := j
+	for j < len(content) && (content[j] == ' ' || content[j] == '\t') {
+		j++
+	}
+	link.lead = string(content[lead:j])
+
+	// Destination, in angle brackets or up to whitespace or an unbalanced ")"
+	if j < len(content) && content[j] == '<' {
+		end := j + 1
+		for end < len(content) && content[end] != '>' {
+			if content[end] == '\n' || content[end] == '<' {
+				return MarkdownLink{}, false
+			}
+			if content[end] == '\\' {
+				end++
+			}
+			end++
+		}
+		if end >= len(content) {
+			return MarkdownLink{}, false
+		}
+		link.Angle = true
+		link.Dest = string(content[j+1 : end])
+		j = end + 1
+	} else {
+		start, parens := j, 0
+	dest:
+		for ; j < len(content); j++ {
+			switch c := content[j]; {
+			case c <= ' ':
+				break dest
+			case c == '\\':
+				j++
+			case c == '(':
+				parens++
+			case c == ')':
+				if parens == 0 {
+					break dest
+				}
+				parens--
+			}
+		}
+		link.Dest = string(content[start:min(j, len(content))])
+	}
+
+	// Optional quoted title, then the closing parenthesis
+	tail := j
+	for j < len(content) && (content[j] == ' ' || content[j] == '\t') {
+		j++
+	}
+	if j < len(content) && (content[j] == '"' || content[j] == '\'') {
+		quote := content[j]
+		for j++; j < len(content) && content[j] != quote; j++ {
+			if content[j] == '\n' {
+				return MarkdownLink{}, false
+			}
+			if content[j] == '\\' {
+				j++
+			}
+		}
+		for j++; j < len(content) && (content[j] == ' ' || content[j] == '\t'); j++ {
+		}
+	}
+	if j >= len(content) || content[j] != ')' {
+		return MarkdownLink{}, false
+	}
+	link.Title = string(content[tail:j])
+	link.End = j + 1
+	return link, true
+}
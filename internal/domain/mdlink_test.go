@@ -0,0 +1,143 @@
+package domain
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestScanMarkdownLinks(t *testing.T) {
+	type parts struct {
+		image bool
+		text  string
+		dest  string
+		angle bool
+		title string
+		path  string // "" when Path returns false
+	}
+	tests := []struct {
+		name    string
+		content string
+		want    []parts
+	}{
+		{"relative", "[Theatre](../S01.11.15%20Theatre/S01.11.15%20Theatre.md)", []parts{{text: "Theatre", dest: "../S01.11.15%20Theatre/S01.11.15%20Theatre.md", path: "../S01.11.15 Theatre/S01.11.15 Theatre.md"}}},
+		{"sibling", "see [notes](notes.md).", []parts{{text: "notes", dest: "notes.md", path: "notes.md"}}},
+		{"dot slash", "[n](./notes.md)", []parts{{text: "n", dest: "./notes.md", path: "./notes.md"}}},
+		{"vault root", "[n](/S01%20Personal/notes.md)", []parts{{text: "n", dest: "/S01%20Personal/notes.md", path: "/S01 Personal/notes.md"}}},
+		{"image", "![Poster](S01.11.15%20Theatre/poster.png)", []parts{{image: true, text: "Poster", dest: "S01.11.15%20Theatre/poster.png", path: "S01.11.15 Theatre/poster.png"}}},
+		{"heading", "[Cast](Theatre.md#Cast%20list)", []parts{{text: "Cast", dest: "Theatre.md#Cast%20list", path: "Theatre.md"}}},
+		{"block ref", "[q](Theatre.md#^quote)", []parts{{text: "q", dest: "Theatre.md#^quote", path: "Theatre.md"}}},
+		{"angle brackets", "[T](<../S01.11.15 Theatre/a.md>)", []parts{{text: "T", dest: "../S01.11.15 Theatre/a.md", angle: true, path: "../S01.11.15 Theatre/a.md"}}},
+		{"title", `[T](a.md "The theatre")`, []parts{{text: "T", dest: "a.md", title: ` "The theatre"`, path: "a.md"}}},
+		{"title with parenthesis", `[T](a.md 'Act (2)')`, []parts{{text: "T", dest: "a.md", title: ` 'Act (2)'`, path: "a.md"}}},
+		{"balanced parentheses", "[T](Theatre%20(old).md)", []parts{{text: "T", dest: "Theatre%20(old).md", path: "Theatre (old).md"}}},
+		{"nested brackets", "[[draft] notes](notes.md)", []parts{{text: "[draft] notes", dest: "notes.md", path: "notes.md"}}},
+		{"empty text", "[](notes.md)", []parts{{dest: "notes.md", path: "notes.md"}}},
+		{"URL", "[site](https://example.com/a.md)", []parts{{text: "site", dest: "https://example.com/a.md"}}},
+		{"obsidian URL", "[open](obsidian://open?vault=v)", []parts{{text: "open", dest: "obsidian://open?vault=v"}}},
+		{"mail", "[me](mailto:me@example.com)", []parts{{text: "me", dest: "mailto:me@example.com"}}},
+		{"anchor only", "[up](#Summary)", []parts{{text: "up", dest: "#Summary"}}},
+		{"empty destination", "[x]()", []parts{{text: "x"}}},
+		{"bad escape", "[x](a%zz.md)", []parts{{text: "x", dest: "a%zz.md"}}},
+		{"several", "[a](a.md) and ![b](b.png)", []parts{{text: "a", dest: "a.md", path: "a.md"}, {image: true, text: "b", dest: "b.png", path: "b.png"}}},
+		{"beside wiki link", "[[S01.11.15]] [a](a.md)", []parts{{text: "a", dest: "a.md", path: "a.md"}}},
+
+		{"wiki link with parenthesis", "[[S01.11.15]](x)", nil},
+		{"reference link", "[a][ref]", nil},
+		{"task", "- [ ] call (later)", nil},
+		{"space before parenthesis", "[a] (a.md)", nil},
+		{"unclosed", "[a](a.md", nil},
+		{"split across lines", "[a](a.md\n)", nil},
+		{"text after destination", "[a](a.md b)", nil},
+		{"escaped", "\\[a](a.md)", nil},
+		{"inline code", "`[a](a.md)`", nil},
+		{"fenced code", "```\n[a](a.md)\n```", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			links := ScanMarkdownLinks([]byte(tt.content))
+			if len(links) != len(tt.want) {
+				t.Fatalf("ScanMarkdownLinks(%q) found %d links, want %d: %+v", tt.content, len(links), len(tt.want), links)
+			}
+			for i, link := range links {
+				path, _ := link.Path()
+				got := parts{link.Image, link.Text, link.Dest, link.Angle, link.Title, path}
+				if got != tt.want[i] {
+					t.Errorf("link %d = %+v, want %+v", i, got, tt.want[i])
+				}
+				if raw := tt.content[link.Start:link.End]; link.String() != raw {
+					t.Errorf("link %d renders as %q, written %q", i, link.String(), raw)
+				}
+			}
+		})
+	}
+}
+
+func TestMarkdownLink_WithPath(t *testing.T) {
+	tests := []struct {
+		content string
+		path    string
+		want    string
+	}{
+		{"[T](a.md)", "../S01.12.11 Theatre/a.md", "[T](../S01.12.11%20Theatre/a.md)"},
+		{"[T](a.md#Cast \"Title\")", "b c.md", "[T](b%20c.md#Cast \"Title\")"},
+		{"![P](<a b.png>)", "x y/a b.png", "![P](<x y/a b.png>)"},
+		{"[T](a.md)", "100% #1 (draft).md", "[T](100%25%20%231%20%28draft%29.md)"},
+		{"[T](a.md)", "Théâtre.md", "[T](Théâtre.md)"},
+	}
+	for _, tt := range tests {
+		link := ScanMarkdownLinks([]byte(tt.content))[0]
+		if got := link.WithPath(tt.path).String(); got != tt.want {
+			t.Errorf("%s WithPath(%q) = %s, want %s", tt.content, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestResolveLinkPath(t *testing.T) {
+	source := filepath.Join("S01 Personal", "S01.11 Entertainment", "S01.11.16 Links", "links.md")
+	tests := []struct {
+		linkPath string
+		want     string
+		wantOK   bool
+	}{
+		{"notes.md", filepath.Join("S01 Personal", "S01.11 Entertainment", "S01.11.16 Links", "notes.md"), true},
+		{"../S01.11.15 Theatre/a.md", filepath.Join("S01 Personal", "S01.11 Entertainment", "S01.11.15 Theatre", "a.md"), true},
+		{"./../../top.md", filepath.Join("S01 Personal", "top.md"), true},
+		{"/notes.md", "notes.md", true},
+		{"../../../notes.md", "notes.md", true},
+		{"../../../../outside.md", "", false},
+		{"../../../", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := ResolveLinkPath(source, tt.linkPath)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("ResolveLinkPath(%q) = %q, %v; want %q, %v", tt.linkPath, got, ok, tt.want, tt.wantOK)
+		}
+	}
+
+	if got := RelativeLinkPath(source, filepath.Join("S01 Personal", "S01.12 Travel", "plan.md")); got != "../../S01.12 Travel/plan.md" {
+		t.Errorf("RelativeLinkPath = %q", got)
+	}
+}
+
+func TestParseLinks_IndexesMarkdownLinks(t *testing.T) {
+	source := filepath.Join("S01 Personal", "S01.11 Entertainment", "S01.11.16 Links", "links.md")
+	content := []byte("[T](../S01.11.15%20Theatre/README.md#Cast), ![p](poster.png), [w](https://example.com), [o](../../../../x.md)")
+
+	want := []Edge{
+		// More than 60 characters follow the first link, so its context is cut
+		{SourcePath: source, TargetJDID: "S01.11.15", TargetPath: filepath.Join("S01 Personal", "S01.11 Entertainment", "S01.11.15 Theatre", "README.md"), LinkText: "[T](../S01.11.15%20Theatre/README.md#Cast)",
+			Kind: LinkMarkdown, Line: 1, Column: 1, Context: "[T](../S01.11.15%20Theatre/README.md#Cast), ![p](poster.png), [w](https://example.com), [o](../../../.…", Occurrence: 1, Occurrences: 1},
+		{SourcePath: source, TargetJDID: "S01.11.16", TargetPath: filepath.Join("S01 Personal", "S01.11 Entertainment", "S01.11.16 Links", "poster.png"), LinkText: "![p](poster.png)",
+			Kind: LinkEmbed, Line: 1, Column: 45, Context: string(content), Occurrence: 1, Occurrences: 1},
+	}
+	edges := ParseLinks(content, source)
+	if len(edges) != len(want) {
+		t.Fatalf("ParseLinks = %+v, want %+v", edges, want)
+	}
+	for i := range want {
+		if edges[i] != want[i] {
+			t.Errorf("edge %d = %+v, want %+v", i, edges[i], want[i])
+		}
+	}
+}
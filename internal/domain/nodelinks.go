@@ -0,0 +1,21 @@
+package domain
+
+// NodeLink is a link into or out of a scope, area, category, item or file, as
+// shown beside the tree: the note holding it, the line it is written on and
+// where it leads
+type NodeLink struct {
+	SourcePath string // File holding the link, relative to the vault
+	Line       int    // 1-based line of the link in SourcePath
+	Column     int    // 1-based column, in characters, of the link on Line
+	Context    string // Text around the link, as in Edge.Context
+	LinkText   string // Link as written, as in Edge.LinkText
+	TargetID   string // JD ID the link points to, or of the category or item its target lies in
+	TargetPath string // Vault path of the linked folder or file; empty if nothing has it
+}
+
+// NodeLinks holds the links between a node and the rest of the vault. Links
+// from a node to itself or to what it holds are in neither list.
+type NodeLinks struct {
+	Incoming []NodeLink // Links from elsewhere pointing at the node or into it
+	Outgoing []NodeLink // Links in the node's notes pointing elsewhere
+}
@@ -0,0 +1,346 @@
+package domain
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SearchQuery is a parsed search query: words, phrases and filters joined by
+// AND, OR and NOT. Index adapters translate it into their own query language;
+// Match is how every one of them must behave.
+type SearchQuery struct {
+	Root QueryNode // nil for an empty query
+}
+
+// QueryNode is a node of a parsed search query
+type QueryNode interface {
+	// Match reports whether an indexed entry satisfies the node, with
+	// relative dates taken from now
+	Match(entry *SearchEntry, now time.Time) bool
+	// String returns the node in query syntax
+	String() string
+}
+
+// AndNode matches entries every one of its terms matches
+type AndNode struct{ Terms []QueryNode }
+
+// OrNode matches entries any of its terms matches
+type OrNode struct{ Terms []QueryNode }
+
+// NotNode matches entries its term does not match
+type NotNode struct{ Term QueryNode }
+
+// TextNode matches entries by name or ID, ignoring case: a word when its
+// letters appear in order, as fuzzy search matches them, a phrase as written
+type TextNode struct {
+	Text   string
+	Phrase bool
+}
+
+// TypeFilter matches entries of a type; files are IDTypeFile
+type TypeFilter struct{ Type IDType }
+
+// InFilter matches the folder of a JD ID and everything within it
+type InFilter struct{ ID string }
+
+// ScopeFilter matches a scope and everything within it
+type ScopeFilter struct{ ID string }
+
+// ExtFilter matches files by extension, without its dot
+type ExtFilter struct{ Ext string }
+
+// TagFilter matches entries tagged with a tag, or a tag nested under it
+type TagFilter struct{ Tag string }
+
+// PropertyFilter matches entries with a frontmatter property
+type PropertyFilter struct{ Property Property }
+
+// HasFilter matches entries with backlinks, tags or properties
+type HasFilter struct{ What string }
+
+// ModifiedFilter compares when entries were last modified with a day, or
+// with a while before now for relative dates
+type ModifiedFilter struct {
+	Op    string    // ">", ">=", "<", "<=" or "=" for the same day
+	Date  time.Time // Start of the day compared with, in local time; zero for relative dates
+	Value string    // The date as written
+
+	Relative bool   // Compare with Ago before now instead of Date
+	Ago      Period // How long before now
+	WholeDay bool   // Compare with the whole day Ago before now falls on, as for today
+}
+
+// Period is a span of days, months and years
+type Period struct{ Days, Months, Years int }
+
+// Match reports whether an entry satisfies the query; an empty query
+// matches nothing
+func (q *SearchQuery) Match(entry *SearchEntry, now time.Time) bool {
+	return q.Root != nil && q.Root.Match(entry, now)
+}
+
+// String returns the query in query syntax
+func (q *SearchQuery) String() string {
+	if q.Root == nil {
+		return ""
+	}
+	return q.Root.String()
+}
+
+// Plain reports whether the query is nothing but words, which a plain name
+// search handles as well
+func (q *SearchQuery) Plain() bool {
+	isWord := func(n QueryNode) bool {
+		t, ok := n.(*TextNode)
+		return ok && !t.Phrase
+	}
+	switch n := q.Root.(type) {
+	case nil:
+		return true
+	case *AndNode:
+		for _, term := range n.Terms {
+			if !isWord(term) {
+				return false
+			}
+		}
+		return true
+	default:
+		return isWord(n)
+	}
+}
+
+// Words returns the words and phrases the query looks for, outside negations,
+// for ranking matches
+func (q *SearchQuery) Words() []string {
+	var words []string
+	var walk func(n QueryNode)
+	walk = func(n QueryNode) {
+		switch n := n.(type) {
+		case *AndNode:
+			for _, term := range n.Terms {
+				walk(term)
+			}
+		case *OrNode:
+			for _, term := range n.Terms {
+				walk(term)
+			}
+		case *TextNode:
+			words = append(words, n.Text)
+		}
+	}
+	walk(q.Root)
+	return words
+}
+
+// Match reports whether every term matches
+func (n *AndNode) Match(entry *SearchEntry, now time.Time) bool {
+	for _, term := range n.Terms {
+		if !term.Match(entry, now) {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *AndNode) String() string {
+	terms := make([]string, len(n.Terms))
+	for i, term := range n.Terms {
+		terms[i] = term.String()
+	}
+	return strings.Join(terms, " ")
+}
+
+// Match reports whether any term matches
+func (n *OrNode) Match(entry *SearchEntry, now time.Time) bool {
+	for _, term := range n.Terms {
+		if term.Match(entry, now) {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *OrNode) String() string {
+	terms := make([]string, len(n.Terms))
+	for i, term := range n.Terms {
+		terms[i] = term.String()
+	}
+	return "(" + strings.Join(terms, " OR ") + ")"
+}
+
+// Match reports whether the term does not match
+func (n *NotNode) Match(entry *SearchEntry, now time.Time) bool {
+	return !n.Term.Match(entry, now)
+}
+
+func (n *NotNode) String() string { return "-" + n.Term.String() }
+
+// Match reports whether the entry's name or ID holds the text
+func (n *TextNode) Match(entry *SearchEntry, now time.Time) bool {
+	return n.MatchName(entryName(entry), entry.JDID)
+}
+
+// MatchName reports whether a name or JD ID holds the text
+func (n *TextNode) MatchName(name, jdID string) bool {
+	if n.Phrase {
+		text := strings.ToLower(n.Text)
+		return strings.Contains(strings.ToLower(name), text) || strings.Contains(strings.ToLower(jdID), text)
+	}
+	return fuzzyMatch(name, n.Text) || fuzzyMatch(jdID, n.Text)
+}
+
+func (n *TextNode) String() string {
+	if n.Phrase {
+		return strconv.Quote(n.Text)
+	}
+	return n.Text
+}
+
+// Match reports whether the entry is of the type
+func (f *TypeFilter) Match(entry *SearchEntry, now time.Time) bool {
+	if f.Type == IDTypeFile {
+		return !entry.IsDir
+	}
+	return entry.IsDir && entry.JDType == f.Type
+}
+
+func (f *TypeFilter) String() string { return "type:" + strings.ToLower(f.Type.String()) }
+
+// Match reports whether the entry lies within the folder of the ID
+func (f *InFilter) Match(entry *SearchEntry, now time.Time) bool {
+	return IsWithin(entry.EntityID(), f.ID)
+}
+
+func (f *InFilter) String() string { return "in:" + f.ID }
+
+// Match reports whether the entry lies within the scope
+func (f *ScopeFilter) Match(entry *SearchEntry, now time.Time) bool {
+	return IsWithin(entry.EntityID(), f.ID)
+}
+
+func (f *ScopeFilter) String() string { return "scope:" + f.ID }
+
+// Match reports whether the entry is a file with the extension
+func (f *ExtFilter) Match(entry *SearchEntry, now time.Time) bool {
+	return !entry.IsDir && strings.EqualFold(strings.TrimPrefix(filepath.Ext(entry.Path), "."), f.Ext)
+}
+
+func (f *ExtFilter) String() string { return "ext:" + f.Ext }
+
+// Match reports whether the entry has the tag or one nested under it
+func (f *TagFilter) Match(entry *SearchEntry, now time.Time) bool {
+	for _, tag := range entry.Tags {
+		if strings.EqualFold(tag, f.Tag) || len(tag) > len(f.Tag) && tag[len(f.Tag)] == '/' && strings.EqualFold(tag[:len(f.Tag)], f.Tag) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *TagFilter) String() string { return "tag:" + f.Tag }
+
+// Match reports whether the entry has the property
+func (f *PropertyFilter) Match(entry *SearchEntry, now time.Time) bool {
+	for _, p := range entry.Properties {
+		if strings.EqualFold(p.Key, f.Property.Key) && strings.EqualFold(p.Value, f.Property.Value) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *PropertyFilter) String() string {
+	return quoteQueryValue(f.Property.Key) + ":" + quoteQueryValue(f.Property.Value)
+}
+
+// Match reports whether the entry has what the filter asks for
+func (f *HasFilter) Match(entry *SearchEntry, now time.Time) bool {
+	switch f.What {
+	case "backlinks":
+		return entry.Backlinks > 0
+	case "tags":
+		return len(entry.Tags) > 0
+	default:
+		return len(entry.Properties) > 0
+	}
+}
+
+func (f *HasFilter) String() string { return "has:" + f.What }
+
+// Match compares the entry's modification time with the date
+func (f *ModifiedFilter) Match(entry *SearchEntry, now time.Time) bool {
+	start, end := f.Bounds(now)
+	mtime := entry.Mtime
+	switch f.Op {
+	case ">":
+		return mtime >= end.UnixNano()
+	case ">=":
+		return mtime >= start.UnixNano()
+	case "<":
+		return mtime < start.UnixNano()
+	case "<=":
+		return mtime < end.UnixNano()
+	default:
+		return mtime >= start.UnixNano() && mtime < end.UnixNano()
+	}
+}
+
+// Bounds returns when the day or moment compared with starts and ends. A
+// moment a while before now starts and ends at once.
+func (f *ModifiedFilter) Bounds(now time.Time) (time.Time, time.Time) {
+	if !f.Relative {
+		return f.Date, f.Date.AddDate(0, 0, 1)
+	}
+	start := now.AddDate(-f.Ago.Years, -f.Ago.Months, -f.Ago.Days)
+	if f.WholeDay || f.Op == "=" {
+		start = startOfDay(start)
+		return start, start.AddDate(0, 0, 1)
+	}
+	return start, start
+}
+
+func (f *ModifiedFilter) String() string {
+	op := f.Op
+	if op == "=" {
+		op = ""
+	}
+	return "modified:" + op + f.Value
+}
+
+// entryName returns the name an entry is searched by: the description of a
+// JD folder, otherwise its base name
+func entryName(entry *SearchEntry) string {
+	if entry.JDType != IDTypeUnknown {
+		return entry.Name
+	}
+	return filepath.Base(entry.Path)
+}
+
+// fuzzyMatch reports whether the bytes of word appear in target in order,
+// ignoring case
+func fuzzyMatch(target, word string) bool {
+	target, word = strings.ToLower(target), strings.ToLower(word)
+	i := 0
+	for j := 0; j < len(target) && i < len(word); j++ {
+		if target[j] == word[i] {
+			i++
+		}
+	}
+	return i == len(word)
+}
+
+// startOfDay returns midnight, local time, of the day t falls on
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Local().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+}
+
+// quoteQueryValue quotes a filter key or value holding spaces or quotes
+func quoteQueryValue(s string) string {
+	if strings.ContainsAny(s, " \t\"()") {
+		return strconv.Quote(s)
+	}
+	return s
+}
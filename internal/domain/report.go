@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FileRewrite records the link rewrites applied to a single file
+type FileRewrite struct {
+	Path  string `json:"path"`  // Relative path from vault root
+	Links int    `json:"links"` // Number of links rewritten in this file
+}
+
+// OperationReport describes the side effects of a vault mutation:
+// which files had their links rewritten, and what was skipped or failed along the way.
+// Warnings are non-fatal (e.g. an unreadable file was skipped); errors mean the vault
+// may be left inconsistent (e.g. a rewrite could not be written back).
+type OperationReport struct {
+	FilesRewritten []FileRewrite `json:"files_rewritten"`
+	Warnings       []string      `json:"warnings"`
+	Errors         []string      `json:"errors"`
+	Snapshot       string        `json:"snapshot,omitempty"` // Backup taken before the operation, if any
+}
+
+// NewOperationReport creates an empty report
+func NewOperationReport() *OperationReport {
+	return &OperationReport{
+		FilesRewritten: []FileRewrite{},
+		Warnings:       []string{},
+		Errors:         []string{},
+	}
+}
+
+// AddRewrite records that links were rewritten in a file.
+// Repeated rewrites of the same file are accumulated into a single entry.
+func (r *OperationReport) AddRewrite(path string, links int) {
+	for i := range r.FilesRewritten {
+		if r.FilesRewritten[i].Path == path {
+			r.FilesRewritten[i].Links += links
+			return
+		}
+	}
+	r.FilesRewritten = append(r.FilesRewritten, FileRewrite{Path: path, Links: links})
+}
+
+// Warnf records a non-fatal warning
+func (r *OperationReport) Warnf(format string, args ...any) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+// Errorf records an error that did not abort the operation
+func (r *OperationReport) Errorf(format string, args ...any) {
+	r.Errors = append(r.Errors, fmt.Sprintf(format, args...))
+}
+
+// Merge folds another report into this one
+func (r *OperationReport) Merge(other *OperationReport) {
+	if other == nil {
+		return
+	}
+	for _, fr := range other.FilesRewritten {
+		r.AddRewrite(fr.Path, fr.Links)
+	}
+	r.Warnings = append(r.Warnings, other.Warnings...)
+	r.Errors = append(r.Errors, other.Errors...)
+	if r.Snapshot == "" {
+		r.Snapshot = other.Snapshot
+	}
+}
+
+// LinksRewritten returns the total number of links rewritten across all files
+func (r *OperationReport) LinksRewritten() int {
+	if r == nil {
+		return 0
+	}
+	total := 0
+	for _, fr := range r.FilesRewritten {
+		total += fr.Links
+	}
+	return total
+}
+
+// HasErrors returns true if any errors were recorded
+func (r *OperationReport) HasErrors() bool {
+	return r != nil && len(r.Errors) > 0
+}
+
+// Summary returns a one-line human-readable summary,
+// e.g. "4 links in 2 files, 1 warning"
+func (r *OperationReport) Summary() string {
+	if r == nil {
+		return ""
+	}
+
+	var parts []string
+	if links := r.LinksRewritten(); links > 0 {
+		parts = append(parts, fmt.Sprintf("%s in %s",
+			Pluralize(links, "link"), Pluralize(len(r.FilesRewritten), "file")))
+	} else {
+		parts = append(parts, "no links rewritten")
+	}
+	if len(r.Warnings) > 0 {
+		parts = append(parts, Pluralize(len(r.Warnings), "warning"))
+	}
+	if len(r.Errors) > 0 {
+		parts = append(parts, Pluralize(len(r.Errors), "error"))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Pluralize formats a count with a singular or plural noun
+func Pluralize(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,92 @@
+package domain
+
+import "testing"
+
+func TestOperationReport_AddRewriteAccumulatesPerFile(t *testing.T) {
+	r := NewOperationReport()
+	r.AddRewrite("a.md", 2)
+	r.AddRewrite("b.md", 1)
+	r.AddRewrite("a.md", 3)
+
+	if len(r.FilesRewritten) != 2 {
+		t.Fatalf("expected 2 files, got %d", len(r.FilesRewritten))
+	}
+	if r.FilesRewritten[0].Links != 5 {
+		t.Errorf("expected 5 links in a.md, got %d", r.FilesRewritten[0].Links)
+	}
+	if r.LinksRewritten() != 6 {
+		t.Errorf("LinksRewritten() = %d, expected 6", r.LinksRewritten())
+	}
+}
+
+func TestOperationReport_Merge(t *testing.T) {
+	r := NewOperationReport()
+	r.AddRewrite("a.md", 1)
+
+	other := NewOperationReport()
+	other.AddRewrite("a.md", 1)
+	other.Warnf("skipped %s", "x.md")
+	other.Errorf("failed %s", "y.md")
+
+	r.Merge(other)
+	r.Merge(nil)
+
+	if r.LinksRewritten() != 2 || len(r.FilesRewritten) != 1 {
+		t.Errorf("unexpected rewrites after merge: %+v", r.FilesRewritten)
+	}
+	if len(r.Warnings) != 1 || r.Warnings[0] != "skipped x.md" {
+		t.Errorf("unexpected warnings: %v", r.Warnings)
+	}
+	if !r.HasErrors() {
+		t.Error("expected HasErrors() after merging an error")
+	}
+}
+
+func TestOperationReport_Summary(t *testing.T) {
+	tests := []struct {
+		name     string
+		build    func() *OperationReport
+		expected string
+	}{
+		{
+			name:     "nil report",
+			build:    func() *OperationReport { return nil },
+			expected: "",
+		},
+		{
+			name:     "empty report",
+			build:    NewOperationReport,
+			expected: "no links rewritten",
+		},
+		{
+			name: "single link",
+			build: func() *OperationReport {
+				r := NewOperationReport()
+				r.AddRewrite("a.md", 1)
+				return r
+			},
+			expected: "1 link in 1 file",
+		},
+		{
+			name: "links, warnings and errors",
+			build: func() *OperationReport {
+				r := NewOperationReport()
+				r.AddRewrite("a.md", 3)
+				r.AddRewrite("b.md", 1)
+				r.Warnf("w1")
+				r.Warnf("w2")
+				r.Errorf("e1")
+				return r
+			},
+			expected: "4 links in 2 files, 2 warnings, 1 error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.build().Summary(); got != tt.expected {
+				t.Errorf("Summary() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+)
+
+// SavedSearch is a named search query kept with the vault, such as
+// "Tagged #todo" for "tag:todo". Names are unique regardless of case.
+type SavedSearch struct {
+	Name  string `json:"name"`
+	Query string `json:"query"`
+}
+
+// FindSavedSearch returns the index of the search named name, ignoring case,
+// or -1
+func FindSavedSearch(searches []SavedSearch, name string) int {
+	return slices.IndexFunc(searches, func(s SavedSearch) bool {
+		return strings.EqualFold(s.Name, name)
+	})
+}
+
+// PutSavedSearch returns searches with search added at the end, or in place of
+// the search with its name
+func PutSavedSearch(searches []SavedSearch, search SavedSearch) []SavedSearch {
+	if i := FindSavedSearch(searches, search.Name); i >= 0 {
+		searches[i] = search
+		return searches
+	}
+	return append(searches, search)
+}
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPutSavedSearch(t *testing.T) {
+	searches := []SavedSearch{{Name: "Inbox everywhere", Query: "type:item inbox"}}
+
+	searches = PutSavedSearch(searches, SavedSearch{Name: "Tagged #todo", Query: "tag:todo"})
+	searches = PutSavedSearch(searches, SavedSearch{Name: "inbox EVERYWHERE", Query: "type:item in:S01 inbox"})
+
+	want := []SavedSearch{
+		{Name: "inbox EVERYWHERE", Query: "type:item in:S01 inbox"},
+		{Name: "Tagged #todo", Query: "tag:todo"},
+	}
+	if !slices.Equal(searches, want) {
+		t.Errorf("PutSavedSearch() = %+v, want %+v", searches, want)
+	}
+	if i := FindSavedSearch(searches, "tagged #TODO"); i != 1 {
+		t.Errorf("FindSavedSearch() = %d, want 1", i)
+	}
+	if i := FindSavedSearch(searches, "Tagged"); i != -1 {
+		t.Errorf("FindSavedSearch(partial name) = %d, want -1", i)
+	}
+}
@@ -0,0 +1,181 @@
+package domain
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// StaleReason is why a JD folder is up for review in a vault clean-up
+type StaleReason string
+
+const (
+	StaleOrphan    StaleReason = "orphan"    // Item nothing outside it links to
+	StaleJDexOnly  StaleReason = "jdex-only" // Item holding no files besides its JDex note
+	StaleUntouched StaleReason = "untouched" // Item with nothing in it modified since the cut-off
+	StaleEmpty     StaleReason = "empty"     // Category holding no items, or area holding no categories, besides the standard ones
+)
+
+// StaleReasons lists every reason, in the order they are reported
+var StaleReasons = []StaleReason{StaleOrphan, StaleJDexOnly, StaleUntouched, StaleEmpty}
+
+// ParseStaleReason parses a reason name such as "orphan"
+func ParseStaleReason(name string) (StaleReason, error) {
+	for _, reason := range StaleReasons {
+		if strings.EqualFold(name, string(reason)) {
+			return reason, nil
+		}
+	}
+	return "", fmt.Errorf("unknown reason %q: want orphan, jdex-only, untouched or empty", name)
+}
+
+// StaleOptions selects what a vault review looks for
+type StaleOptions struct {
+	Before  time.Time     // Items with nothing modified since are untouched; zero to skip the check
+	Scope   string        // JD ID to review within; empty for the whole vault
+	Reasons []StaleReason // Reasons to look for; empty for all
+}
+
+// StaleEntry is an item, category or area up for review, with every reason found
+type StaleEntry struct {
+	ID       string        `json:"id"`
+	Name     string        `json:"name"`     // Description of the folder
+	Type     string        `json:"type"`     // "Item", "Category" or "Area"
+	Path     string        `json:"path"`     // Vault path of the folder
+	Reasons  []StaleReason `json:"reasons"`  // In the order of StaleReasons
+	Modified time.Time     `json:"modified"` // Latest modification of the folder or anything in it
+}
+
+// Has reports whether the entry is up for review for a reason
+func (e StaleEntry) Has(reason StaleReason) bool {
+	return slices.Contains(e.Reasons, reason)
+}
+
+// FolderName returns the folder name of the entry
+func (e StaleEntry) FolderName() string {
+	return FormatFolderName(e.ID, e.Name)
+}
+
+// staleFolder gathers what the index holds in and about a JD folder
+type staleFolder struct {
+	node     IndexNode
+	modified int64 // Latest mtime in the folder's subtree
+	files    int   // Files in the subtree besides the JDex note
+	children int   // Items in a category, or categories in an area, besides standard ones
+	linked   bool  // Whether a link from outside leads into an item
+}
+
+// FindStale reviews the indexed JD folders: items nothing links to from outside
+// them, items holding nothing but their JDex note, items with nothing modified
+// since opts.Before, and categories and areas with nothing in them. Standard
+// zero items and management categories and areas are never up for review.
+// Links are counted as BuildLinkGraph counts them. Entries are sorted by path.
+func FindStale(nodes []IndexNode, edges []Edge, opts StaleOptions) []StaleEntry {
+	folders := make(map[string]*staleFolder) // By vault path
+	for _, node := range nodes {
+		if !node.IsDir || !reviewable(node) {
+			continue
+		}
+		if opts.Scope != "" && !IsWithin(node.JDID, opts.Scope) {
+			continue
+		}
+		folders[node.Path] = &staleFolder{node: node, modified: node.Mtime}
+	}
+
+	for _, node := range nodes {
+		if node.IsDir && reviewable(node) {
+			if parent, ok := folders[filepath.Dir(node.Path)]; ok {
+				parent.children++
+			}
+		}
+		for dir := filepath.Dir(node.Path); dir != "." && dir != string(filepath.Separator); dir = filepath.Dir(dir) {
+			folder, ok := folders[dir]
+			if !ok || folder.node.JDType != IDTypeItem {
+				continue
+			}
+			folder.modified = max(folder.modified, node.Mtime)
+			if !node.IsDir && !isJDexFile(folder.node.Path, node.Path) {
+				folder.files++
+			}
+		}
+	}
+
+	items := make(map[string]*staleFolder)
+	for _, folder := range folders {
+		if folder.node.JDType == IDTypeItem {
+			items[folder.node.JDID] = folder
+		}
+	}
+	for _, edge := range edges {
+		target := edge.TargetJDID
+		if edge.TargetPath != "" {
+			target = PathEntity(edge.TargetPath)
+		}
+		if item, ok := items[target]; ok && PathEntity(edge.SourcePath) != target {
+			item.linked = true
+		}
+	}
+
+	wanted := func(reason StaleReason) bool {
+		return len(opts.Reasons) == 0 || slices.Contains(opts.Reasons, reason)
+	}
+	var entries []StaleEntry
+	for _, folder := range folders {
+		var reasons []StaleReason
+		if folder.node.JDType == IDTypeItem {
+			if !folder.linked && wanted(StaleOrphan) {
+				reasons = append(reasons, StaleOrphan)
+			}
+			if folder.files == 0 && wanted(StaleJDexOnly) {
+				reasons = append(reasons, StaleJDexOnly)
+			}
+			if !opts.Before.IsZero() && folder.modified < opts.Before.UnixNano() && wanted(StaleUntouched) {
+				reasons = append(reasons, StaleUntouched)
+			}
+		} else if folder.children == 0 && wanted(StaleEmpty) {
+			reasons = append(reasons, StaleEmpty)
+		}
+		if len(reasons) == 0 {
+			continue
+		}
+		entries = append(entries, StaleEntry{
+			ID:       folder.node.JDID,
+			Name:     folder.node.Name,
+			Type:     folder.node.JDType.String(),
+			Path:     folder.node.Path,
+			Reasons:  reasons,
+			Modified: time.Unix(0, folder.modified),
+		})
+	}
+	slices.SortFunc(entries, func(a, b StaleEntry) int {
+		return slices.Compare(strings.Split(a.Path, string(filepath.Separator)), strings.Split(b.Path, string(filepath.Separator)))
+	})
+	return entries
+}
+
+// reviewable reports whether a folder is an item, category or area a review
+// looks at: not a standard zero item, management category or management area
+func reviewable(node IndexNode) bool {
+	switch node.JDType {
+	case IDTypeItem:
+		return !IsStandardZeroItem(node.JDID)
+	case IDTypeCategory:
+		return !IsAreaManagementCategory(node.JDID)
+	case IDTypeArea:
+		return !IsManagementArea(node.JDID)
+	default:
+		return false
+	}
+}
+
+// isJDexFile reports whether a file is the JDex note of the item folder at
+// itemPath, or its legacy README.md
+func isJDexFile(itemPath, filePath string) bool {
+	if filepath.Dir(filePath) != itemPath {
+		return false
+	}
+	name := filepath.Base(filePath)
+	return name == JDexFileName(filepath.Base(itemPath)) || name == "README.md"
+}
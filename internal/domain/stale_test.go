@@ -0,0 +1,134 @@
+package domain
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestFindStale(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	old := now.AddDate(-2, 0, 0).UnixNano()
+	recent := now.AddDate(0, -1, 0).UnixNano()
+
+	area := filepath.Join("S01 Personal", "S01.10-19 Lifestyle")
+	entertainment := filepath.Join(area, "S01.11 Entertainment")
+	theatre := filepath.Join(entertainment, "S01.11.15 Theatre")
+	films := filepath.Join(entertainment, "S01.11.16 Films")
+	games := filepath.Join(entertainment, "S01.11.17 Games")
+	travel := filepath.Join(area, "S01.12 Travel")
+	empty := filepath.Join("S01 Personal", "S01.20-29 Empty")
+	dir := func(path, id string, mtime int64) IndexNode {
+		return IndexNode{Path: path, JDID: id, JDType: ParseIDType(id), Name: ExtractDescription(filepath.Base(path)), IsDir: true, Mtime: mtime}
+	}
+	file := func(path string, mtime int64) IndexNode {
+		return IndexNode{Path: path, Name: filepath.Base(path), Mtime: mtime}
+	}
+	nodes := []IndexNode{
+		dir("S01 Personal", "S01", old),
+		dir(filepath.Join("S01 Personal", "S01.00-09 Management"), "S01.00-09", old),
+		dir(area, "S01.10-19", old),
+		dir(filepath.Join(area, "S01.10 Lifestyle Management"), "S01.10", old),
+		dir(entertainment, "S01.11", old),
+		dir(filepath.Join(entertainment, "S01.11.01 Inbox for S01.11"), "S01.11.01", old),
+		dir(theatre, "S01.11.15", old),
+		file(filepath.Join(theatre, "S01.11.15 Theatre.md"), old),
+		file(filepath.Join(theatre, "cast.md"), recent),
+		dir(films, "S01.11.16", old),
+		file(filepath.Join(films, "S01.11.16 Films.md"), old),
+		dir(games, "S01.11.17", recent),
+		dir(filepath.Join(games, "saves"), "", old),
+		file(filepath.Join(games, "saves", "slot1.md"), old),
+		dir(travel, "S01.12", old),
+		dir(filepath.Join(travel, "S01.12.09 Archive for S01.12"), "S01.12.09", old),
+		dir(empty, "S01.20-29", old),
+	}
+	edges := []Edge{
+		{SourcePath: filepath.Join(games, "saves", "slot1.md"), TargetJDID: "S01.11.15", LinkText: "[[S01.11.15]]"},
+		{SourcePath: filepath.Join(films, "S01.11.16 Films.md"), TargetJDID: "S01.11.16", LinkText: "[[S01.11.16]]"},
+		{SourcePath: "notes.md", TargetJDID: "S01.11.17", TargetPath: filepath.Join(games, "saves", "slot1.md"), LinkText: "[s](slot1.md)"},
+	}
+	before := now.AddDate(-1, 0, 0)
+
+	tests := []struct {
+		name string
+		opts StaleOptions
+		want map[string][]StaleReason
+	}{
+		{
+			name: "all",
+			opts: StaleOptions{Before: before},
+			want: map[string][]StaleReason{
+				"S01.11.16": {StaleOrphan, StaleJDexOnly, StaleUntouched},
+				"S01.12":    {StaleEmpty},
+				"S01.20-29": {StaleEmpty},
+			},
+		},
+		{
+			name: "no cut-off",
+			opts: StaleOptions{},
+			want: map[string][]StaleReason{
+				"S01.11.16": {StaleOrphan, StaleJDexOnly},
+				"S01.12":    {StaleEmpty},
+				"S01.20-29": {StaleEmpty},
+			},
+		},
+		{
+			name: "untouched only",
+			opts: StaleOptions{Before: now, Reasons: []StaleReason{StaleUntouched}},
+			want: map[string][]StaleReason{
+				"S01.11.15": {StaleUntouched},
+				"S01.11.16": {StaleUntouched},
+				"S01.11.17": {StaleUntouched},
+			},
+		},
+		{
+			name: "scope",
+			opts: StaleOptions{Before: before, Scope: "S01.12"},
+			want: map[string][]StaleReason{
+				"S01.12": {StaleEmpty},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := FindStale(nodes, edges, tt.opts)
+			got := make(map[string][]StaleReason)
+			var paths []string
+			for _, entry := range entries {
+				got[entry.ID] = entry.Reasons
+				paths = append(paths, entry.Path)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("FindStale() = %v, want %v", got, tt.want)
+			}
+			for id, reasons := range tt.want {
+				if !slices.Equal(got[id], reasons) {
+					t.Errorf("reasons for %s = %v, want %v", id, got[id], reasons)
+				}
+			}
+			if !slices.IsSorted(paths) {
+				t.Errorf("entries not sorted by path: %v", paths)
+			}
+		})
+	}
+
+	films16 := FindStale(nodes, edges, StaleOptions{Scope: "S01.11.16"})
+	if len(films16) != 1 || films16[0].FolderName() != "S01.11.16 Films" || films16[0].Type != "Item" {
+		t.Fatalf("FindStale(S01.11.16) = %+v", films16)
+	}
+	if !films16[0].Modified.Equal(time.Unix(0, old)) {
+		t.Errorf("Modified = %v, want %v", films16[0].Modified, time.Unix(0, old))
+	}
+}
+
+func TestParseStaleReason(t *testing.T) {
+	if reason, err := ParseStaleReason("JDex-Only"); err != nil || reason != StaleJDexOnly {
+		t.Errorf("ParseStaleReason(JDex-Only) = %q, %v", reason, err)
+	}
+	if _, err := ParseStaleReason("old"); err == nil {
+		t.Error("ParseStaleReason(old) should fail")
+	}
+}
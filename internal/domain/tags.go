@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// NoteTags returns the tags of a note, without their "#", in the order they
+// first appear: those its frontmatter gives under "tags" or "tag", then the
+// #tags written in its body outside code. A tag is made of letters, digits,
+// "_", "-" and "/" for nesting, and is more than a number. Tags differing only
+// in case are the same tag, as in Obsidian; the first spelling is kept.
+func NoteTags(content []byte) []string {
+	var tags []string
+	seen := make(map[string]bool)
+	add := func(tag string) {
+		tag = strings.Trim(tag, "/")
+		if !isTag(tag) || seen[strings.ToLower(tag)] {
+			return
+		}
+		seen[strings.ToLower(tag)] = true
+		tags = append(tags, tag)
+	}
+
+	for _, p := range FrontmatterProperties(content) {
+		if !strings.EqualFold(p.Key, "tags") && !strings.EqualFold(p.Key, "tag") {
+			continue
+		}
+		// Older notes list several tags in one value, "tags: draft, idea"
+		for _, tag := range strings.FieldsFunc(p.Value, func(r rune) bool { return r == ',' || unicode.IsSpace(r) }) {
+			add(strings.TrimPrefix(tag, "#"))
+		}
+	}
+
+	_, _, body := splitFrontmatter(content)
+	text := content[body:]
+	scanOutsideCode(text, '#', func(i int) int {
+		if i > 0 {
+			if r, _ := utf8.DecodeLastRune(text[:i]); !unicode.IsSpace(r) {
+				return 0
+			}
+		}
+		end := i + 1
+		for end < len(text) {
+			r, size := utf8.DecodeRune(text[end:])
+			if !isTagRune(r) {
+				break
+			}
+			end += size
+		}
+		add(string(text[i+1 : end]))
+		return end
+	})
+	return tags
+}
+
+// isTag reports whether s is a valid tag name: tag characters only, and not
+// all digits
+func isTag(s string) bool {
+	if s == "" || strings.IndexFunc(s, func(r rune) bool { return !isTagRune(r) }) >= 0 {
+		return false
+	}
+	return strings.IndexFunc(s, func(r rune) bool { return !unicode.IsDigit(r) }) >= 0
+}
+
+// isTagRune reports whether r may appear in a tag
+func isTagRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' || r == '/'
+}
+
+// ItemQuery selects items by the notes they hold: every tag and property
+// given must be found on some note of the item. A tag also matches the tags
+// nested under it, so "project" matches "project/active".
+type ItemQuery struct {
+	Scope      string     // JD ID the items must lie within; empty for the whole vault
+	Tags       []string   // Tags, with or without their "#"
+	Properties []Property // Frontmatter values, compared ignoring case
+}
+
+// IsEmpty reports whether the query has no tag or property to match
+func (q ItemQuery) IsEmpty() bool {
+	return len(q.Tags) == 0 && len(q.Properties) == 0
+}
+
+// ParseProperty parses a "key=value" property condition
+func ParseProperty(s string) (Property, error) {
+	key, value, ok := strings.Cut(s, "=")
+	key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+	if !ok || key == "" || value == "" {
+		return Property{}, fmt.Errorf("invalid property %q: expected key=value", s)
+	}
+	return Property{Key: key, Value: value}, nil
+}
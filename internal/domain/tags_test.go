@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNoteTags(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"body", "# Title\n\nSee #theatre and #Project/Alpha, not a#b or #2026.\n", []string{"theatre", "Project/Alpha"}},
+		{"frontmatter first", "---\ntags: [review, \"#draft\"]\n---\n#idea and #Review\n", []string{"review", "draft", "idea"}},
+		{"old style list", "---\ntag: draft, idea later\n---\n", []string{"draft", "idea", "later"}},
+		{"code", "`#not` a tag\n\n```\n#neither\n```\n\\#escaped #yes\n", []string{"yes"}},
+		{"headings and links", "## Heading\n[[Note#Cast]] [x](y.md#z) #ünïcode_tag-1/\n", []string{"ünïcode_tag-1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NoteTags([]byte(tt.content)); !slices.Equal(got, tt.want) {
+				t.Errorf("NoteTags = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// TrashedLink records a link that pointed into a trashed entity at deletion time
+type TrashedLink struct {
+	SourcePath string `json:"source_path"` // Relative path of the linking file
+	LinkText   string `json:"link_text"`   // Original [[link]] or [text](link) text
+}
+
+// TrashEntry describes an entity that was moved to the vault trash
+type TrashEntry struct {
+	TrashID       string        `json:"trash_id"`      // Folder name inside the trash
+	ID            string        `json:"id"`            // Johnny Decimal ID at deletion time
+	Name          string        `json:"name"`          // Original folder name
+	OriginalPath  string        `json:"original_path"` // Relative path from vault root
+	DeletedAt     time.Time     `json:"deleted_at"`
+	IncomingLinks []TrashedLink `json:"incoming_links"`
+	Snapshot      string        `json:"snapshot,omitempty"` // Backup taken before deletion, if any
+}
+
+// Type returns the ID type of the trashed entity
+func (e TrashEntry) Type() IDType {
+	return ParseIDType(e.ID)
+}
+
+// Age returns how long the entry has been in the trash
+func (e TrashEntry) Age(now time.Time) time.Duration {
+	return now.Sub(e.DeletedAt)
+}
+
+// NewTrashID builds a sortable, filesystem-safe trash folder name for an entity,
+// e.g. "20250102T150405-S01.11.15"
+func NewTrashID(id string, deletedAt time.Time) string {
+	return fmt.Sprintf("%s-%s", deletedAt.UTC().Format("20060102T150405"), id)
+}
+
+// ExpiredTrashEntries returns the entries that have been in the trash longer than maxAge
+func ExpiredTrashEntries(entries []TrashEntry, maxAge time.Duration, now time.Time) []TrashEntry {
+	var expired []TrashEntry
+	for _, e := range entries {
+		if e.Age(now) > maxAge {
+			expired = append(expired, e)
+		}
+	}
+	return expired
+}
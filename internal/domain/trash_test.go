@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewTrashID(t *testing.T) {
+	deletedAt := time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)
+	if got := NewTrashID("S01.11.15", deletedAt); got != "20250102T150405-S01.11.15" {
+		t.Errorf("NewTrashID() = %q, want %q", got, "20250102T150405-S01.11.15")
+	}
+}
+
+func TestExpiredTrashEntries(t *testing.T) {
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	entries := []TrashEntry{
+		{TrashID: "old", DeletedAt: now.Add(-40 * 24 * time.Hour)},
+		{TrashID: "recent", DeletedAt: now.Add(-2 * 24 * time.Hour)},
+		{TrashID: "ancient", DeletedAt: now.Add(-400 * 24 * time.Hour)},
+	}
+
+	expired := ExpiredTrashEntries(entries, 30*24*time.Hour, now)
+	if len(expired) != 2 {
+		t.Fatalf("expected 2 expired entries, got %d", len(expired))
+	}
+	if expired[0].TrashID != "old" || expired[1].TrashID != "ancient" {
+		t.Errorf("unexpected expired entries: %+v", expired)
+	}
+}
@@ -0,0 +1,336 @@
+package domain
+
+import (
+	"bytes"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// WikiLink is an Obsidian wiki link, [[target#subpath|alias]], or an embed when
+// written ![[...]]. Its fields keep the exact text of each part, so String
+// reproduces the link as written.
+type WikiLink struct {
+	Start    int    // Byte offset of the link in the scanned content, including any "!"
+	End      int    // Byte offset just past the closing "]]"
+	Embed    bool   // Written ![[...]] to embed the target
+	Target   string // Note linked to, with any folder prefix and extension: "S01 Life/S01.11.15 Theatre"
+	Subpath  string // Heading or block reference, with its leading "#" or "^": "#Cast", "#^a1b2", "^a1b2"
+	AliasSep string // "|", or `\|` inside a table; empty without an alias
+	Alias    string // Display text after the separator
+}
+
+// String renders the link as Markdown
+func (l WikiLink) String() string {
+	var b strings.Builder
+	if l.Embed {
+		b.WriteByte('!')
+	}
+	b.WriteString("[[")
+	b.WriteString(l.Target)
+	b.WriteString(l.Subpath)
+	b.WriteString(l.AliasSep)
+	b.WriteString(l.Alias)
+	b.WriteString("]]")
+	return b.String()
+}
+
+// Name returns the note name Obsidian resolves the link by: the target without
+// its folder prefix or .md extension, e.g. "S01.11.15 Theatre"
+func (l WikiLink) Name() string {
+	name := l.Target
+	if i := strings.LastIndexByte(name, '/'); i >= 0 {
+		name = name[i+1:]
+	}
+	if ext := len(name) - len(".md"); ext >= 0 && strings.EqualFold(name[ext:], ".md") {
+		name = name[:ext]
+	}
+	return strings.TrimSpace(name)
+}
+
+// NotePath returns the note the link names, with any folder prefix but
+// without a .md extension: "Projects/Meeting notes"
+func (l WikiLink) NotePath() string {
+	name := strings.TrimSpace(l.Target)
+	if ext := len(name) - len(".md"); ext >= 0 && strings.EqualFold(name[ext:], ".md") {
+		name = name[:ext]
+	}
+	return name
+}
+
+// NoteName returns the name a wiki link to the file at a vault path uses: its
+// base name, without the extension if it is a note
+func NoteName(relPath string) string {
+	name := filepath.Base(relPath)
+	if ext := len(name) - len(".md"); ext > 0 && strings.EqualFold(name[ext:], ".md") {
+		name = name[:ext]
+	}
+	return name
+}
+
+// JDID returns the category or item ID the link points to, whether by ID alone
+// ([[S01.11.15]]) or by full name ([[S01.11.15 Theatre]]), or "" for other links
+func (l WikiLink) JDID() string {
+	id := ExtractID(l.Name())
+	if t := ParseIDType(id); t == IDTypeCategory || t == IDTypeItem {
+		return id
+	}
+	return ""
+}
+
+// Retarget returns the link pointed at another note by name. The embed marker,
+// subpath and alias are kept. The folder prefix of a link to a JD ID is
+// dropped, since JD names are unique in a vault and the old prefix would no
+// longer lead to the note; other links keep theirs, which may be needed to
+// tell notes of the same name apart, and have only their last segment replaced.
+func (l WikiLink) Retarget(name string) WikiLink {
+	target := name
+	if i := strings.LastIndexByte(l.Target, '/'); i >= 0 && l.JDID() == "" {
+		target = l.Target[:i+1] + name
+	}
+	if ext := len(l.Target) - len(".md"); ext >= 0 && strings.EqualFold(l.Target[ext:], ".md") {
+		target += l.Target[ext:]
+	}
+	l.Target = target
+	return l
+}
+
+// Relocate follows a move with a link written with a folder prefix, whose
+// prefix runs through the moved folder: [[S01.11.15 Theatre/Cast]] when
+// "S01.11.15 Theatre" moves. The link is pointed at the note's new path from
+// the vault root; the subpath, alias and any .md extension are kept. Links
+// without a prefix, or whose prefix lies beneath the moved folder, still lead
+// to the note and are left alone.
+func (l WikiLink) Relocate(move NodeMove) (WikiLink, bool) {
+	parts := strings.Split(strings.Trim(l.Target, "/"), "/")
+	old := strings.Split(filepath.ToSlash(move.OldPath), "/")
+	for k := min(len(old), len(parts)-1); k > 0; k-- {
+		if !slices.EqualFunc(parts[:k], old[len(old)-k:], strings.EqualFold) {
+			continue
+		}
+		l.Target = filepath.ToSlash(move.NewPath) + "/" + strings.Join(parts[k:], "/")
+		return l, true
+	}
+	return l, false
+}
+
+// ScanWikiLinks returns the wiki links and embeds in markdown content, in order.
+// Links inside fenced code blocks and inline code are not links to Obsidian and
+// are skipped, as are escaped \[[ brackets and [[ ]] pairs split across lines.
+func ScanWikiLinks(content []byte) []WikiLink {
+	// Quick check for [[ before scanning
+	if !bytes.Contains(content, []byte("[[")) {
+		return nil
+	}
+
+	var links []WikiLink
+	scanOutsideCode(content, '[', func(i int) int {
+		link, ok := parseWikiLink(content, i)
+		if !ok {
+			return 0
+		}
+		links = append(links, link)
+		return link.End
+	})
+	return links
+}
+
+// RewriteWikiLinks replaces each link for which fn returns true with the link
+// it returns, leaving the rest of the content untouched. It returns the new
+// content and how many links actually changed.
+func RewriteWikiLinks(content []byte, fn func(WikiLink) (WikiLink, bool)) ([]byte, int) {
+	return rewriteLinks(content, ScanWikiLinks(content), fn)
+}
+
+func (l WikiLink) bounds() (int, int) { return l.Start, l.End }
+
+// linkToken is a link found by one of the scanners
+type linkToken interface {
+	bounds() (int, int)
+	String() string
+}
+
+// rewriteLinks replaces scanned links, given in order, with those fn returns
+func rewriteLinks[L linkToken](content []byte, links []L, fn func(L) (L, bool)) ([]byte, int) {
+	var out bytes.Buffer
+	count, last := 0, 0
+	for _, link := range links {
+		replacement, ok := fn(link)
+		if !ok {
+			continue
+		}
+		start, end := link.bounds()
+		text := replacement.String()
+		if text == string(content[start:end]) {
+			continue
+		}
+		out.Write(content[last:start])
+		out.WriteString(text)
+		last = end
+		count++
+	}
+	if count == 0 {
+		return content, 0
+	}
+	out.Write(content[last:])
+	return out.Bytes(), count
+}
+
+// scanOutsideCode calls match at each unescaped trigger byte outside fenced code
+// blocks and inline code. match returns the offset just past what it found
+// there, or 0.
+func scanOutsideCode(content []byte, trigger byte, match func(i int) int) {
+	lineStart := true
+	for i := 0; i < len(content); {
+		if lineStart {
+			lineStart = false
+			if end, ok := skipFence(content, i); ok {
+				i = end
+				lineStart = true
+				continue
+			}
+		}
+
+		switch c := content[i]; {
+		case c == '\n':
+			lineStart = true
+			i++
+		case c == '\\' && i+1 < len(content) && content[i+1] != '\n':
+			i += 2 // The next character is escaped
+		case c == '`':
+			i = skipCodeSpan(content, i)
+		case c == trigger:
+			if end := match(i); end > i {
+				i = end
+			} else {
+				i++
+			}
+		default:
+			i++
+		}
+	}
+}
+
+// parseWikiLink parses the link whose "[[" starts at i
+func parseWikiLink(content []byte, i int) (WikiLink, bool) {
+	if i+1 >= len(content) || content[i+1] != '[' {
+		return WikiLink{}, false
+	}
+	open := i + 2
+	closing := -1
+	for j := open; j+1 < len(content); j++ {
+		if content[j] == '\n' {
+			return WikiLink{}, false
+		}
+		if content[j] == ']' && content[j+1] == ']' {
+			closing = j
+			break
+		}
+	}
+	if closing < 0 {
+		return WikiLink{}, false
+	}
+
+	link := WikiLink{Start: i, End: closing + 2}
+	if i > 0 && content[i-1] == '!' {
+		link.Embed = true
+		link.Start = i - 1
+	}
+
+	inner := string(content[open:closing])
+	if pipe := strings.IndexByte(inner, '|'); pipe >= 0 {
+		sep := pipe
+		if pipe > 0 && inner[pipe-1] == '\\' {
+			sep = pipe - 1
+		}
+		link.AliasSep = inner[sep : pipe+1]
+		link.Alias = inner[pipe+1:]
+		inner = inner[:sep]
+	}
+	if sub := strings.IndexAny(inner, "#^"); sub >= 0 {
+		link.Subpath = inner[sub:]
+		inner = inner[:sub]
+	}
+	link.Target = inner
+
+	if strings.TrimSpace(link.Target) == "" && link.Subpath == "" {
+		return WikiLink{}, false
+	}
+	return link, true
+}
+
+// skipFence returns the end of the fenced code block opening on the line at i,
+// or false if the line does not open one. An unclosed fence runs to the end.
+func skipFence(content []byte, i int) (int, bool) {
+	indent := 0
+	for indent < 3 && i+indent < len(content) && content[i+indent] == ' ' {
+		indent++
+	}
+	marker, n := fenceRun(content, i+indent)
+	if n < 3 {
+		return 0, false
+	}
+	// A backtick fence's info string may not contain backticks
+	lineEnd := lineEndAt(content, i)
+	if marker == '`' && bytes.IndexByte(content[i+indent+n:lineEnd], '`') >= 0 {
+		return 0, false
+	}
+
+	for pos := lineEnd; pos < len(content); {
+		pos++ // Past the newline
+		line := pos
+		for line < len(content) && line-pos < 3 && content[line] == ' ' {
+			line++
+		}
+		if m, closeN := fenceRun(content, line); m == marker && closeN >= n &&
+			len(bytes.TrimSpace(content[line+closeN:lineEndAt(content, line)])) == 0 {
+			return lineEndAt(content, line), true
+		}
+		pos = lineEndAt(content, pos)
+	}
+	return len(content), true
+}
+
+// fenceRun returns the fence character at i and how many times it repeats
+func fenceRun(content []byte, i int) (byte, int) {
+	if i >= len(content) || (content[i] != '`' && content[i] != '~') {
+		return 0, 0
+	}
+	n := 0
+	for i+n < len(content) && content[i+n] == content[i] {
+		n++
+	}
+	return content[i], n
+}
+
+// lineEndAt returns the offset of the newline ending the line containing i, or
+// the end of the content
+func lineEndAt(content []byte, i int) int {
+	if end := bytes.IndexByte(content[i:], '\n'); end >= 0 {
+		return i + end
+	}
+	return len(content)
+}
+
+// skipCodeSpan returns the offset past the inline code span opening at i. A
+// backtick run without a matching closing run in the same paragraph is literal.
+func skipCodeSpan(content []byte, i int) int {
+	_, n := fenceRun(content, i)
+	for j := i + n; j < len(content); {
+		if content[j] == '\n' {
+			if next := j + 1; next >= len(content) || len(bytes.TrimSpace(content[next:lineEndAt(content, next)])) == 0 {
+				break
+			}
+		}
+		if content[j] != '`' {
+			j++
+			continue
+		}
+		_, m := fenceRun(content, j)
+		if m == n {
+			return j + m
+		}
+		j += m
+	}
+	return i + n
+}
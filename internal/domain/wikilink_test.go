@@ -0,0 +1,194 @@
+package domain
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestScanWikiLinks(t *testing.T) {
+	type parts struct {
+		embed    bool
+		target   string
+		subpath  string
+		aliasSep string
+		alias    string
+		name     string
+		jdID     string
+	}
+	tests := []struct {
+		name    string
+		content string
+		want    []parts
+	}{
+		{"full name", "See [[S01.11.15 Theatre]].", []parts{{target: "S01.11.15 Theatre", name: "S01.11.15 Theatre", jdID: "S01.11.15"}}},
+		{"ID only", "[[S01.11.15]]", []parts{{target: "S01.11.15", name: "S01.11.15", jdID: "S01.11.15"}}},
+		{"category", "[[S01.11 Entertainment]]", []parts{{target: "S01.11 Entertainment", name: "S01.11 Entertainment", jdID: "S01.11"}}},
+		{"heading", "[[S01.11.15 Theatre#Cast list]]", []parts{{target: "S01.11.15 Theatre", subpath: "#Cast list", name: "S01.11.15 Theatre", jdID: "S01.11.15"}}},
+		{"nested heading", "[[S01.11.15 Theatre#Plays#2024]]", []parts{{target: "S01.11.15 Theatre", subpath: "#Plays#2024", name: "S01.11.15 Theatre", jdID: "S01.11.15"}}},
+		{"block ref", "[[S01.11.15 Theatre#^a1b2c3]]", []parts{{target: "S01.11.15 Theatre", subpath: "#^a1b2c3", name: "S01.11.15 Theatre", jdID: "S01.11.15"}}},
+		{"bare block ref", "[[S01.11.15^block]]", []parts{{target: "S01.11.15", subpath: "^block", name: "S01.11.15", jdID: "S01.11.15"}}},
+		{"embed", "![[S01.11.15 Theatre]]", []parts{{embed: true, target: "S01.11.15 Theatre", name: "S01.11.15 Theatre", jdID: "S01.11.15"}}},
+		{"embed with heading", "![[S01.11.15 Theatre#Cast]]", []parts{{embed: true, target: "S01.11.15 Theatre", subpath: "#Cast", name: "S01.11.15 Theatre", jdID: "S01.11.15"}}},
+		{"alias", "[[S01.11.15|My theatre]]", []parts{{target: "S01.11.15", aliasSep: "|", alias: "My theatre", name: "S01.11.15", jdID: "S01.11.15"}}},
+		{"empty alias", "[[S01.11.15 Theatre|]]", []parts{{target: "S01.11.15 Theatre", aliasSep: "|", name: "S01.11.15 Theatre", jdID: "S01.11.15"}}},
+		{"alias with pipe", "[[S01.11.15|a|b]]", []parts{{target: "S01.11.15", aliasSep: "|", alias: "a|b", name: "S01.11.15", jdID: "S01.11.15"}}},
+		{"alias with hash", "[[S01.11.15|Act #2]]", []parts{{target: "S01.11.15", aliasSep: "|", alias: "Act #2", name: "S01.11.15", jdID: "S01.11.15"}}},
+		{"table escaped pipe", "| [[S01.11.15 Theatre\\|Theatre]] |", []parts{{target: "S01.11.15 Theatre", aliasSep: "\\|", alias: "Theatre", name: "S01.11.15 Theatre", jdID: "S01.11.15"}}},
+		{"everything", "![[S01.11.15 Theatre#^quote|Quote]]", []parts{{embed: true, target: "S01.11.15 Theatre", subpath: "#^quote", aliasSep: "|", alias: "Quote", name: "S01.11.15 Theatre", jdID: "S01.11.15"}}},
+		{"path prefix", "[[S01 Life/S01.10-19 Lifestyle/S01.11 Entertainment/S01.11.15 Theatre]]", []parts{{target: "S01 Life/S01.10-19 Lifestyle/S01.11 Entertainment/S01.11.15 Theatre", name: "S01.11.15 Theatre", jdID: "S01.11.15"}}},
+		{"path prefix to JDex note", "[[S01.11 Entertainment/S01.11.15 Theatre/S01.11.15 Theatre#Cast|Cast]]", []parts{{target: "S01.11 Entertainment/S01.11.15 Theatre/S01.11.15 Theatre", subpath: "#Cast", aliasSep: "|", alias: "Cast", name: "S01.11.15 Theatre", jdID: "S01.11.15"}}},
+		{"note inside item", "[[S01.11.15 Theatre/tickets]]", []parts{{target: "S01.11.15 Theatre/tickets", name: "tickets"}}},
+		{"md extension", "[[S01.11.15 Theatre.md]]", []parts{{target: "S01.11.15 Theatre.md", name: "S01.11.15 Theatre", jdID: "S01.11.15"}}},
+		{"archived", "[[[Archived] Theatre]]", []parts{{target: "[Archived] Theatre", name: "[Archived] Theatre"}}},
+		{"archived with alias", "[[[Archived] Theatre|Old]]", []parts{{target: "[Archived] Theatre", aliasSep: "|", alias: "Old", name: "[Archived] Theatre"}}},
+		{"plain note", "[[Shopping list]]", []parts{{target: "Shopping list", name: "Shopping list"}}},
+		{"heading in same note", "[[#Summary]]", []parts{{subpath: "#Summary"}}},
+		{"area is not indexed", "[[S01.10-19 Lifestyle]]", []parts{{target: "S01.10-19 Lifestyle", name: "S01.10-19 Lifestyle"}}},
+		{"ID must end at a space", "[[S01.11.15.1 Notes]] [[S01.115]]", []parts{{target: "S01.11.15.1 Notes", name: "S01.11.15.1 Notes"}, {target: "S01.115", name: "S01.115"}}},
+		{"several on a line", "[[S01.11.15]] and ![[S01.12 Travel]]", []parts{{target: "S01.11.15", name: "S01.11.15", jdID: "S01.11.15"}, {embed: true, target: "S01.12 Travel", name: "S01.12 Travel", jdID: "S01.12"}}},
+		{"adjacent", "[[S01.11]][[S01.12]]", []parts{{target: "S01.11", name: "S01.11", jdID: "S01.11"}, {target: "S01.12", name: "S01.12", jdID: "S01.12"}}},
+		{"bracket before link", "[[[S01.11.15]]]", []parts{{target: "[S01.11.15", name: "[S01.11.15"}}},
+		{"in frontmatter", "---\nrelated: \"[[S01.11.15 Theatre]]\"\n---\n", []parts{{target: "S01.11.15 Theatre", name: "S01.11.15 Theatre", jdID: "S01.11.15"}}},
+		{"after unmatched backtick", "a ` b\n\n[[S01.11.15]]", []parts{{target: "S01.11.15", name: "S01.11.15", jdID: "S01.11.15"}}},
+		{"after code span", "`x` [[S01.11.15]]", []parts{{target: "S01.11.15", name: "S01.11.15", jdID: "S01.11.15"}}},
+		{"after fence", "```\n[[S01.11.14]]\n```\n[[S01.11.15]]", []parts{{target: "S01.11.15", name: "S01.11.15", jdID: "S01.11.15"}}},
+
+		{"empty", "[[]]", nil},
+		{"blank", "[[  ]]", nil},
+		{"unclosed", "[[S01.11.15 Theatre", nil},
+		{"split across lines", "[[S01.11.15\nTheatre]]", nil},
+		{"single brackets", "[S01.11.15](S01.11.15.md)", nil},
+		{"escaped", "\\[[S01.11.15]]", nil},
+		{"inline code", "Use `[[S01.11.15]]` to link", nil},
+		{"double backtick code", "``a ` [[S01.11.15]]``", nil},
+		{"fenced code", "```md\n[[S01.11.15]]\n```", nil},
+		{"tilde fence", "~~~\n[[S01.11.15]]\n~~~", nil},
+		{"indented fence", "  ```\n[[S01.11.15]]\n  ```", nil},
+		{"longer closing fence", "```\n[[S01.11.15]]\n`````", nil},
+		{"unclosed fence", "```\n[[S01.11.15]]", nil},
+		{"fence needs matching marker", "```\n~~~\n[[S01.11.15]]\n```", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			links := ScanWikiLinks([]byte(tt.content))
+			if len(links) != len(tt.want) {
+				t.Fatalf("ScanWikiLinks(%q) found %d links, want %d: %+v", tt.content, len(links), len(tt.want), links)
+			}
+			for i, link := range links {
+				got := parts{link.Embed, link.Target, link.Subpath, link.AliasSep, link.Alias, link.Name(), link.JDID()}
+				if got != tt.want[i] {
+					t.Errorf("link %d = %+v, want %+v", i, got, tt.want[i])
+				}
+				if raw := tt.content[link.Start:link.End]; link.String() != raw {
+					t.Errorf("link %d renders as %q, written %q", i, link.String(), raw)
+				}
+			}
+		})
+	}
+}
+
+func TestRewriteWikiLinks(t *testing.T) {
+	renames := map[string]string{
+		"s01.11.15":         "S01.12.11 Theatre",
+		"s01.11.15 theatre": "S01.12.11 Theatre",
+		"meeting notes":     "Standup notes",
+	}
+	retarget := func(link WikiLink) (WikiLink, bool) {
+		name, ok := renames[strings.ToLower(link.Name())]
+		if !ok {
+			return link, false
+		}
+		return link.Retarget(name), true
+	}
+
+	tests := []struct {
+		name      string
+		content   string
+		want      string
+		wantCount int
+	}{
+		{"full name", "See [[S01.11.15 Theatre]].", "See [[S01.12.11 Theatre]].", 1},
+		{"ID only gains description", "[[S01.11.15]]", "[[S01.12.11 Theatre]]", 1},
+		{"heading kept", "[[S01.11.15 Theatre#Cast]]", "[[S01.12.11 Theatre#Cast]]", 1},
+		{"block ref kept", "[[S01.11.15^block]] [[S01.11.15 Theatre#^quote]]", "[[S01.12.11 Theatre^block]] [[S01.12.11 Theatre#^quote]]", 2},
+		{"embed kept", "![[S01.11.15 Theatre]]", "![[S01.12.11 Theatre]]", 1},
+		{"alias kept", "[[S01.11.15|My theatre]]", "[[S01.12.11 Theatre|My theatre]]", 1},
+		{"table pipe kept", "| [[S01.11.15 Theatre\\|T]] |", "| [[S01.12.11 Theatre\\|T]] |", 1},
+		{"everything kept", "![[S01.11.15 Theatre#Cast|Cast]]", "![[S01.12.11 Theatre#Cast|Cast]]", 1},
+		{"path prefix dropped", "[[S01 Life/S01.11 Entertainment/S01.11.15 Theatre#Cast]]", "[[S01.12.11 Theatre#Cast]]", 1},
+		{"note prefix kept", "[[Projects/Meeting notes#Agenda]]", "[[Projects/Standup notes#Agenda]]", 1},
+		{"note prefix and extension kept", "[[Projects/Meeting notes.md|notes]]", "[[Projects/Standup notes.md|notes]]", 1},
+		{"extension kept", "[[S01.11.15 Theatre.md|T]]", "[[S01.12.11 Theatre.md|T]]", 1},
+		{"case-insensitive", "[[s01.11.15 theatre]]", "[[S01.12.11 Theatre]]", 1},
+		{"other description untouched", "[[S01.11.15 Drama]]", "[[S01.11.15 Drama]]", 0},
+		{"note inside item untouched", "[[S01.11.15 Theatre/tickets]]", "[[S01.11.15 Theatre/tickets]]", 0},
+		{"code untouched", "`[[S01.11.15]]`\n```\n[[S01.11.15]]\n```\n[[S01.11.15]]", "`[[S01.11.15]]`\n```\n[[S01.11.15]]\n```\n[[S01.12.11 Theatre]]", 1},
+		{"already current", "[[S01.12.11 Theatre]]", "[[S01.12.11 Theatre]]", 0},
+		{"surrounding text kept", "a [[S01.11.15]] b [[Other]] c [[S01.11.15 Theatre]] d", "a [[S01.12.11 Theatre]] b [[Other]] c [[S01.12.11 Theatre]] d", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, count := RewriteWikiLinks([]byte(tt.content), retarget)
+			if string(got) != tt.want || count != tt.wantCount {
+				t.Errorf("RewriteWikiLinks(%q) = %q, %d; want %q, %d", tt.content, got, count, tt.want, tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestParseLinks_IndexesWikiTargets(t *testing.T) {
+	content := []byte("[[S01.11.15#Cast]], ![[S01.12 Travel]], [[Lists/Shopping.md|list]], [[#Top]]")
+	edges := ParseLinks(content, "notes.md")
+
+	want := []Edge{
+		{SourcePath: "notes.md", TargetJDID: "S01.11.15", LinkText: "[[S01.11.15#Cast]]", Kind: LinkWiki, Line: 1, Column: 1, Context: string(content), Occurrence: 1, Occurrences: 1},
+		{SourcePath: "notes.md", TargetJDID: "S01.12", LinkText: "![[S01.12 Travel]]", Kind: LinkEmbed, Line: 1, Column: 21, Context: string(content), Occurrence: 1, Occurrences: 1},
+		{SourcePath: "notes.md", TargetName: "Lists/Shopping", LinkText: "[[Lists/Shopping.md|list]]", Kind: LinkWiki, Line: 1, Column: 41, Context: string(content), Occurrence: 1, Occurrences: 1},
+	}
+	if len(edges) != len(want) {
+		t.Fatalf("ParseLinks = %+v, want %+v", edges, want)
+	}
+	for i := range want {
+		if edges[i] != want[i] {
+			t.Errorf("edge %d = %+v, want %+v", i, edges[i], want[i])
+		}
+	}
+}
+
+func TestWikiLink_Relocate(t *testing.T) {
+	move := NodeMove{
+		OldPath: filepath.Join("S01 Personal", "S01.10-19 Lifestyle", "S01.11 Entertainment", "S01.11.15 Theatre"),
+		NewPath: filepath.Join("S01 Personal", "S01.10-19 Lifestyle", "S01.12 Travel", "S01.12.11 Theatre"),
+	}
+	tests := []struct {
+		name string
+		link string
+		want string
+	}{
+		{"from the vault root", "[[S01 Personal/S01.10-19 Lifestyle/S01.11 Entertainment/S01.11.15 Theatre/Cast]]", "[[S01 Personal/S01.10-19 Lifestyle/S01.12 Travel/S01.12.11 Theatre/Cast]]"},
+		{"shortest prefix", "![[s01.11.15 theatre/Cast.md#Leads|cast]]", "![[S01 Personal/S01.10-19 Lifestyle/S01.12 Travel/S01.12.11 Theatre/Cast.md#Leads|cast]]"},
+		{"through a parent", "[[S01.11 Entertainment/S01.11.15 Theatre/tickets/stub]]", "[[S01 Personal/S01.10-19 Lifestyle/S01.12 Travel/S01.12.11 Theatre/tickets/stub]]"},
+		{"beneath the moved folder", "[[tickets/stub]]", ""},
+		{"no prefix", "[[Cast]]", ""},
+		{"elsewhere", "[[S01.11.16 Links/links]]", ""},
+		{"the folder itself", "[[S01.11 Entertainment/S01.11.15 Theatre]]", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			links := ScanWikiLinks([]byte(tt.link))
+			if len(links) != 1 {
+				t.Fatalf("ScanWikiLinks(%q) = %+v", tt.link, links)
+			}
+			got, ok := links[0].Relocate(move)
+			if ok != (tt.want != "") {
+				t.Fatalf("Relocate(%q) ok = %v, want %v", tt.link, ok, tt.want != "")
+			}
+			if ok && got.String() != tt.want {
+				t.Errorf("Relocate(%q) = %q, want %q", tt.link, got.String(), tt.want)
+			}
+		})
+	}
+}
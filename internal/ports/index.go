@@ -1,6 +1,10 @@
 package ports
 
-import "libraio/internal/domain"
+import (
+	"time"
+
+	"libraio/internal/domain"
+)
 
 // VaultIndex provides cached access to vault structure and link graph.
 // All query operations should be O(1) or O(log n) via database indexes.
@@ -10,6 +14,7 @@ type VaultIndex interface {
 	Close() error
 
 	// Sync operations
+	IndexSyncer
 	NeedsFullRebuild() bool
 	SyncIncremental() (*domain.SyncStats, error)
 	SyncFull() (*domain.SyncStats, error)
@@ -17,23 +22,46 @@ type VaultIndex interface {
 	// Node queries
 	GetNode(path string) (*domain.IndexNode, error)
 	GetNodeByJDID(jdID string) (*domain.IndexNode, error)
+	ListChildren(parentPath string) ([]domain.IndexNode, error)
+	SearchNames(query string) ([]domain.IndexNode, error)
 	GetNextAvailableItemID(categoryID string) (int, error)
 	GetNextAvailableCategoryID(areaID string) (int, error)
 
 	// Edge queries (link graph)
 	FindLinksToID(targetJDID string) ([]domain.Edge, error)
+	FindLinksToName(name string) ([]domain.Edge, error)
+	FindLinksToPath(targetPath string) ([]domain.Edge, error)
+	FindUnresolvedNoteLinks() ([]domain.Edge, error)
 	FindLinksFromFile(sourcePath string) ([]domain.Edge, error)
+	FindLinksFromPath(sourcePath string) ([]domain.Edge, error)
+	FindBrokenLinks() ([]domain.BrokenLink, error)
+	LinkGraph(opts domain.GraphOptions) (*domain.LinkGraph, error)
+	FindStale(opts domain.StaleOptions) ([]domain.StaleEntry, error)
+
+	// Content queries (full-text search over markdown bodies)
+	SearchContent(query string, limit int) ([]domain.ContentMatch, error)
+
+	// Metadata queries (frontmatter properties and tags of notes)
+	FindEntries(query *domain.SearchQuery, now time.Time) ([]domain.IndexNode, error)
 
 	// Batch updates (for move/archive operations)
 	BeginTx() (IndexTx, error)
 }
 
+// IndexSyncer brings an index up to date with the vault
+type IndexSyncer interface {
+	// Sync rebuilds the index if it needs a full rebuild and updates it
+	// incrementally otherwise. progress may be nil.
+	Sync(progress domain.SyncProgressFunc) (*domain.SyncStats, error)
+}
+
 // IndexTx represents a transaction for atomic cache updates
 type IndexTx interface {
-	// Node operations
+	// Node operations. DeleteNode and RenameNode apply to the whole subtree.
 	UpsertNode(node *domain.IndexNode) error
 	DeleteNode(path string) error
 	RenameNode(oldPath, newPath string) error
+	UpdateContent(path string, content []byte) error
 
 	// Edge operations
 	DeleteEdgesFromFile(sourcePath string) error
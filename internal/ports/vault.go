@@ -1,6 +1,10 @@
 package ports
 
-import "libraio/internal/domain"
+import (
+	"time"
+
+	"libraio/internal/domain"
+)
 
 // TreeReader provides read-only access to the vault tree structure
 type TreeReader interface {
@@ -22,50 +26,104 @@ type VaultLister interface {
 	ListItems(categoryID string) ([]domain.Item, error)
 }
 
-// VaultSearcher provides search functionality
+// VaultSearcher provides search functionality.
+// Search matches file and folder names; SearchContent matches markdown bodies
+// and returns results best first, with a snippet in MatchedText. SearchEntries
+// returns the indexed entries a search query matches, in vault order.
 type VaultSearcher interface {
 	Search(query string) ([]domain.SearchResult, error)
+	SearchContent(query string, limit int) ([]domain.SearchResult, error)
+	SearchEntries(query *domain.SearchQuery, now time.Time) ([]domain.IndexNode, error)
 }
 
 // VaultCreator provides creation operations
 type VaultCreator interface {
-	CreateScope(description string) (*domain.Scope, error)
-	CreateArea(scopeID, description string) (*domain.Area, error)
-	CreateCategory(areaID, description string) (*domain.Category, error)
-	CreateItem(categoryID, description string) (*domain.Item, error)
+	CreateScope(description string) (*domain.Scope, *domain.OperationReport, error)
+	CreateArea(scopeID, description string) (*domain.Area, *domain.OperationReport, error)
+	CreateCategory(areaID, description string) (*domain.Category, *domain.OperationReport, error)
+	CreateItem(categoryID, description string) (*domain.Item, *domain.OperationReport, error)
 }
 
 // VaultMover provides move operations
 type VaultMover interface {
-	MoveItem(srcItemID, dstCategoryID string) (*domain.Item, error)
-	MoveCategory(srcCategoryID, dstAreaID string) (*domain.Category, error)
+	MoveItem(srcItemID, dstCategoryID string) (*domain.Item, *domain.OperationReport, error)
+	MoveCategory(srcCategoryID, dstAreaID string) (*domain.Category, *domain.OperationReport, error)
 }
 
 // VaultArchiver provides archive operations
 type VaultArchiver interface {
-	ArchiveItem(srcItemID string) (*domain.Item, error)
-	ArchiveCategory(srcCategoryID string) ([]*domain.Item, error)
+	ArchiveItem(srcItemID string) (*domain.Item, *domain.OperationReport, error)
+	ArchiveCategory(srcCategoryID string) ([]*domain.Item, *domain.OperationReport, error)
 }
 
 // VaultUnarchiver provides unarchive operations
 type VaultUnarchiver interface {
-	UnarchiveItems(archiveItemID, dstCategoryID string) ([]*domain.Item, error)
+	UnarchiveItems(archiveItemID, dstCategoryID string) ([]*domain.Item, *domain.OperationReport, error)
 }
 
 // VaultRenamer provides rename operations
 type VaultRenamer interface {
-	RenameItem(itemID, newDescription string) (*domain.Item, error)
-	RenameCategory(categoryID, newDescription string) (*domain.Category, error)
-	RenameArea(areaID, newDescription string) (*domain.Area, error)
+	RenameItem(itemID, newDescription string) (*domain.Item, *domain.OperationReport, error)
+	RenameCategory(categoryID, newDescription string) (*domain.Category, *domain.OperationReport, error)
+	RenameArea(areaID, newDescription string) (*domain.Area, *domain.OperationReport, error)
+}
+
+// VaultRelinker repairs links after folders were moved or renamed outside
+// libraio, as found by an index sync
+type VaultRelinker interface {
+	RelinkMoves(moves []domain.NodeMove) (*domain.OperationReport, error)
+}
+
+// VaultLinkChecker reports links whose target is gone or has changed
+// identity, and repoints them where the index says their target went
+type VaultLinkChecker interface {
+	BrokenLinks() ([]domain.BrokenLink, error)
+	FixLinks(links []domain.BrokenLink) (*domain.OperationReport, error)
+}
+
+// VaultLinkGraph provides the links between a tree node and the rest of the
+// vault, and between the JD entities of the vault, as recorded in the index
+type VaultLinkGraph interface {
+	NodeLinks(node *domain.TreeNode) (*domain.NodeLinks, error)
+	LinkGraph(opts domain.GraphOptions) (*domain.LinkGraph, error)
 }
 
-// VaultDeleter provides delete operations
+// VaultReviewer finds the items, categories and areas a vault clean-up should
+// look at, as recorded in the index
+type VaultReviewer interface {
+	FindStale(opts domain.StaleOptions) ([]domain.StaleEntry, error)
+}
+
+// VaultDeleter provides delete operations.
+// Deleted entities are moved to the vault trash rather than removed.
 type VaultDeleter interface {
-	Delete(id string) error
+	Delete(id string) (*domain.TrashEntry, *domain.OperationReport, error)
+}
+
+// VaultTrash provides access to entities moved to the vault trash
+type VaultTrash interface {
+	ListTrash() ([]domain.TrashEntry, error)
+	RestoreTrash(trashID string) (*domain.TrashEntry, *domain.OperationReport, error)
+	PurgeTrash(trashID string) error
+}
+
+// VaultBackups provides access to snapshots taken before bulk operations
+type VaultBackups interface {
+	ListBackups() ([]domain.Snapshot, error)
+	RestoreBackup(snapshotID string) (*domain.Snapshot, *domain.OperationReport, error)
+}
+
+// VaultSavedSearches stores the named search queries of a vault
+type VaultSavedSearches interface {
+	ListSavedSearches() ([]domain.SavedSearch, error)
+	SaveSearch(search domain.SavedSearch) error
+	DeleteSavedSearch(name string) (*domain.SavedSearch, error)
 }
 
 // VaultRepository defines the full interface for vault storage operations.
 // It composes all the smaller interfaces for backwards compatibility.
+// Move, archive, unarchive and rename operations return an OperationReport
+// describing the link rewrites, warnings and errors that occurred along the way.
 type VaultRepository interface {
 	TreeReader
 	PathResolver
@@ -76,5 +134,12 @@ type VaultRepository interface {
 	VaultArchiver
 	VaultUnarchiver
 	VaultRenamer
+	VaultRelinker
+	VaultLinkChecker
+	VaultLinkGraph
+	VaultReviewer
 	VaultDeleter
+	VaultTrash
+	VaultBackups
+	VaultSavedSearches
 }
@@ -0,0 +1,15 @@
+package ports
+
+import "libraio/internal/domain"
+
+// VersionControl records vault changes in a version control system
+type VersionControl interface {
+	// Commit stages exactly the given vault-relative paths (including deletions)
+	// and commits them with message. Other pending changes are left untouched.
+	// It is a no-op if none of the paths changed.
+	Commit(operation, message string, paths []string) error
+
+	// History returns commits created by Commit, most recent first.
+	// A limit of 0 returns every commit.
+	History(limit int) ([]domain.HistoryEntry, error)
+}
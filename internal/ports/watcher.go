@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	"libraio/internal/domain"
+)
+
+// VaultWatcher notices changes made to the vault outside libraio
+type VaultWatcher interface {
+	// Watch keeps the index up to date until ctx is cancelled. onEvent is
+	// called from the watching goroutine for every check that found changes
+	// or failed; a failed check does not stop the watch.
+	Watch(ctx context.Context, onEvent func(domain.WatchEvent)) error
+}
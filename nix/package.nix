@@ -9,6 +9,9 @@ pkgs.buildGoModule {
 
   subPackages = [ "cmd/libraio" "cmd/libraio-cli" ];
 
+  # FTS5 ranking for content search
+  tags = [ "sqlite_fts5" ];
+
   meta = with pkgs.lib; {
     description = "TUI and CLI for managing Obsidian vaults with Johnny Decimal";
     homepage = "https://github.com/emiliopalmerini/libraio";